@@ -18,7 +18,10 @@ import (
 func main() {
 	ec, err := cli.Start(os.Args[1:], nil)
 	if err != nil {
-		logrus.Fatalf("%s", err)
+		logrus.Errorf("%s", err)
+		if ec == 0 {
+			ec = 1
+		}
 	}
 	os.Exit(ec)
 }
@@ -1,3 +1,3 @@
-// +build go1.5
+// +build go1.9
 
 package goversion
@@ -1,7 +1,7 @@
-// +build !go1.5
+// +build !go1.9
 
 package goversion
 
 func error() {
-	`Bad go version, please install a version greater than or equal to 1.5`
+	`Bad go version, please install a version greater than or equal to 1.9`
 }
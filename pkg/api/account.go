@@ -0,0 +1,173 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// The account API is served by its own endpoint, see AccountAPIURL, and wraps every object
+// in an envelope named after its kind, i.e: {"token": {...}} or {"tokens": [...]}
+
+// GetTokens returns the tokens of the user
+func (s *ScalewayAPI) GetTokens() ([]ScalewayTokenDefinition, error) {
+	resp, err := s.GetResponsePaginate(s.accountAPI, "tokens", url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := s.handleHTTPError([]int{http.StatusOK}, resp)
+	if err != nil {
+		return nil, err
+	}
+	var tokens ScalewayGetTokens
+
+	if err = json.Unmarshal(body, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens.Tokens, nil
+}
+
+// CreateToken creates a token with the credentials of connect and returns it
+func (s *ScalewayAPI) CreateToken(connect ScalewayConnectInterface) (*ScalewayTokenDefinition, error) {
+	resp, err := s.PostResponse(s.accountAPI, "tokens", connect)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := s.handleHTTPError([]int{http.StatusCreated}, resp)
+	if err != nil {
+		return nil, err
+	}
+	var token ScalewayConnectResponse
+
+	if err = json.Unmarshal(body, &token); err != nil {
+		return nil, err
+	}
+	return &token.Token, nil
+}
+
+// DeleteToken revokes a token of the user
+func (s *ScalewayAPI) DeleteToken(tokenID string) error {
+	resp, err := s.DeleteResponse(s.accountAPI, fmt.Sprintf("tokens/%s", tokenID))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if _, err := s.handleHTTPError([]int{http.StatusNoContent}, resp); err != nil {
+		return err
+	}
+	return nil
+}
+
+// RenewToken pushes back the expiration date of a token and returns it
+func (s *ScalewayAPI) RenewToken(tokenID string) (*ScalewayTokenDefinition, error) {
+	resp, err := s.PatchResponse(s.accountAPI, fmt.Sprintf("tokens/%s", tokenID), map[string]bool{"expires": true})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := s.handleHTTPError([]int{http.StatusOK}, resp)
+	if err != nil {
+		return nil, err
+	}
+	var token ScalewayTokensDefinition
+
+	if err = json.Unmarshal(body, &token); err != nil {
+		return nil, err
+	}
+	return &token.Token, nil
+}
+
+// ExpiresAt returns the expiration date of the token, false when it never expires
+func (t ScalewayTokenDefinition) ExpiresAt() (time.Time, bool) {
+	date, err := time.Parse(time.RFC3339, t.Expires)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+// GetOrganizations returns the organizations of the user
+func (s *ScalewayAPI) GetOrganizations() ([]ScalewayOrganizationDefinition, error) {
+	organizations, err := s.GetOrganization()
+	if err != nil {
+		return nil, err
+	}
+	return organizations.Organizations, nil
+}
+
+// GetSSHKeys returns the SSH public keys of the user
+func (s *ScalewayAPI) GetSSHKeys() ([]ScalewayKeyDefinition, error) {
+	user, err := s.GetUser()
+	if err != nil {
+		return nil, err
+	}
+	return user.SSHPublicKeys, nil
+}
+
+// AddSSHKey adds an SSH public key to the user, nothing is done when the key is already there
+func (s *ScalewayAPI) AddSSHKey(key string) error {
+	key = strings.TrimSpace(key)
+	user, err := s.GetUser()
+	if err != nil {
+		return err
+	}
+	keys := make([]ScalewayKeyDefinition, 0, len(user.SSHPublicKeys)+1)
+	for _, existing := range user.SSHPublicKeys {
+		if strings.TrimSpace(existing.Key) == key {
+			return nil
+		}
+		keys = append(keys, ScalewayKeyDefinition{Key: existing.Key})
+	}
+	keys = append(keys, ScalewayKeyDefinition{Key: key})
+	return s.PatchUserSSHKey(user.ID, ScalewayUserPatchSSHKeyDefinition{SSHPublicKeys: keys})
+}
+
+// DeleteSSHKey removes the SSH public keys of the user matching the key or its fingerprint
+func (s *ScalewayAPI) DeleteSSHKey(keyOrFingerprint string) error {
+	keyOrFingerprint = strings.TrimSpace(keyOrFingerprint)
+	user, err := s.GetUser()
+	if err != nil {
+		return err
+	}
+	keys := make([]ScalewayKeyDefinition, 0, len(user.SSHPublicKeys))
+	for _, existing := range user.SSHPublicKeys {
+		if strings.TrimSpace(existing.Key) == keyOrFingerprint || sshKeyFingerprintMatches(existing.Fingerprint, keyOrFingerprint) {
+			continue
+		}
+		keys = append(keys, ScalewayKeyDefinition{Key: existing.Key})
+	}
+	if len(keys) == len(user.SSHPublicKeys) {
+		return fmt.Errorf("no SSH key matching %q", keyOrFingerprint)
+	}
+	return s.PatchUserSSHKey(user.ID, ScalewayUserPatchSSHKeyDefinition{SSHPublicKeys: keys})
+}
+
+// sshKeyFingerprintMatches reports whether fingerprint, as returned by the account API
+// i.e: "2048 ab:cd:... user@host (RSA)", contains the hash wanted
+func sshKeyFingerprintMatches(fingerprint, wanted string) bool {
+	if fingerprint == "" || !strings.Contains(wanted, ":") {
+		return false
+	}
+	for _, field := range strings.Fields(fingerprint) {
+		if !strings.Contains(field, ":") {
+			continue
+		}
+		if strings.TrimPrefix(field, "MD5:") == strings.TrimPrefix(wanted, "MD5:") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSSHKeyFingerprintMatches(t *testing.T) {
+	Convey("Testing sshKeyFingerprintMatches()", t, func() {
+		fingerprint := "2048 f3:a1:0b:9c:5e:77:21:d4:c2:8e:91:3a:6b:0f:44:e2 user@host (RSA)"
+		So(sshKeyFingerprintMatches(fingerprint, "f3:a1:0b:9c:5e:77:21:d4:c2:8e:91:3a:6b:0f:44:e2"), ShouldBeTrue)
+		So(sshKeyFingerprintMatches(fingerprint, "MD5:f3:a1:0b:9c:5e:77:21:d4:c2:8e:91:3a:6b:0f:44:e2"), ShouldBeTrue)
+		So(sshKeyFingerprintMatches(fingerprint, "00:11"), ShouldBeFalse)
+		So(sshKeyFingerprintMatches(fingerprint, "2048"), ShouldBeFalse)
+		So(sshKeyFingerprintMatches("", "00:11"), ShouldBeFalse)
+	})
+}
+
+func TestTokenExpiresAt(t *testing.T) {
+	Convey("Testing ScalewayTokenDefinition.ExpiresAt()", t, func() {
+		expires, ok := ScalewayTokenDefinition{Expires: "2016-03-24T08:26:50.557425+00:00"}.ExpiresAt()
+		So(ok, ShouldBeTrue)
+		So(expires.Unix(), ShouldEqual, 1458808010)
+
+		_, ok = ScalewayTokenDefinition{}.ExpiresAt()
+		So(ok, ShouldBeFalse)
+	})
+}
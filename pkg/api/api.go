@@ -9,6 +9,7 @@ package api
 
 import (
 	"bytes"
+	"compress/gzip"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
@@ -20,13 +21,17 @@ import (
 	"net/http/httputil"
 	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"text/tabwriter"
 	"text/template"
 	"time"
 
+	"github.com/scaleway/scaleway-cli/pkg/utils"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -40,6 +45,9 @@ var (
 
 	URLPublicDNS  = ".pub.cloud.scaleway.com"
 	URLPrivateDNS = ".priv.cloud.scaleway.com"
+
+	// Regions lists the regions accepted by NewScalewayAPI
+	Regions = []string{"par1", "ams1"}
 )
 
 func init() {
@@ -64,7 +72,8 @@ const (
 	perPage = 50
 )
 
-// ScalewayAPI is the interface used to communicate with the Scaleway API
+// ScalewayAPI is the interface used to communicate with the Scaleway API.
+// Once configured, a ScalewayAPI is safe for concurrent use by multiple goroutines
 type ScalewayAPI struct {
 	// Organization is the identifier of the Scaleway organization
 	Organization string
@@ -75,16 +84,71 @@ type ScalewayAPI struct {
 	// Password is the authentication password
 	password string
 
+	// lock guards password, which may be set while the client is shared
+	lock sync.RWMutex
+
 	userAgent string
 
 	// Cache is used to quickly resolve identifiers from names
 	Cache *ScalewayCache
 
+	// ResponseCache keeps the bodies of bootscripts, marketplace images and of the GET responses
+	// carrying an ETag or a Last-Modified header, nil disables it
+	ResponseCache *ResponseCache
+
+	// RefreshResponses fetches again the responses kept in ResponseCache
+	RefreshResponses bool
+
+	breaker *circuitBreaker
+
 	client     *http.Client
+	timeout    *time.Duration
+	proxy      *url.URL
+	tlsConfig  *tls.Config
 	verbose    bool
 	computeAPI string
+	accountAPI string
+	apiVersion string
 
 	Region string
+
+	// WaitConflicts makes server actions wait for the conflicting task and retry when the API answers 409
+	WaitConflicts bool
+
+	// WaitTransitions makes server actions wait for a starting, stopping or allocating server to settle before being posted,
+	// the actions rejected by the API on such a server fail with a ServerTransitionError otherwise
+	WaitTransitions bool
+
+	// UniqueNames makes creating a server or an image named like an existing one fail with a NameCollisionError,
+	// a warning is logged otherwise
+	UniqueNames bool
+
+	// MaxRateWait is the total time a request waits when the API answers 429, then the 429 is returned
+	MaxRateWait time.Duration
+
+	// Interactive makes an ambiguous name prompt for a choice on the terminal instead of failing
+	Interactive bool
+
+	// ImageAliases are the image aliases of the configuration, see ResolveImageAlias
+	ImageAliases map[string]map[string]string
+
+	// DryRun receives the method, URL and payload of the requests modifying resources instead of the API,
+	// they fail with a DryRunError. Nil disables it
+	DryRun io.Writer
+
+	// ShowCurl receives the curl command equivalent to each API request, the token replaced by $SCW_TOKEN.
+	// Nil disables it
+	ShowCurl io.Writer
+
+	// Tracer records the requests, resolutions and decisions of the command, nil disables it
+	Tracer *Tracer
+
+	// BeforeRequest is called before sending each API request, i.e: to start a span
+	BeforeRequest func(req *http.Request)
+
+	// AfterRequest is called when the response headers of each API request are received,
+	// resp is nil and err is set when the request failed
+	AfterRequest func(req *http.Request, resp *http.Response, err error, duration time.Duration)
 	//
 	Logger
 }
@@ -105,6 +169,9 @@ type ScalewayAPIError struct {
 
 	// Message
 	Message string `json:"-"`
+
+	// RequestID is the X-Request-Id of the response, to mention when contacting the support
+	RequestID string `json:"-"`
 }
 
 // Error returns a string representing the error
@@ -117,9 +184,92 @@ func (e ScalewayAPIError) Error() string {
 	if len(e.Fields) > 0 {
 		fmt.Fprintf(&b, ", Details: %v", e.Fields)
 	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, ", RequestID: %v", e.RequestID)
+	}
 	return b.String()
 }
 
+// IsCapacityError returns true if err is the API refusing to boot a server because no hypervisor
+// has room left for its commercial type
+func IsCapacityError(err error) bool {
+	apiErr, ok := asAPIError(err)
+	if !ok {
+		return false
+	}
+	if apiErr.Type == "out_of_stock" {
+		return true
+	}
+	message := strings.ToLower(apiErr.APIMessage)
+	return strings.Contains(message, "out of stock") || strings.Contains(message, "insufficient capacity") || strings.Contains(message, "no hypervisor")
+}
+
+// MaxResponseSize is the maximum size of an API response body
+var MaxResponseSize int64 = 32 << 20
+
+// ScalewayMalformedResponseError is returned when the API answers something else than the expected JSON,
+// i.e: an HTML error page from a proxy or a truncated body
+type ScalewayMalformedResponseError struct {
+	// StatusCode is the HTTP status code received
+	StatusCode int
+
+	// ContentType is the content-type announced by the response
+	ContentType string
+
+	// Snippet is the beginning of the body
+	Snippet string
+
+	// Reason explains why the body was rejected
+	Reason string
+}
+
+// Error returns a string representing the error
+func (e ScalewayMalformedResponseError) Error() string {
+	return fmt.Sprintf("unexpected response from the Scaleway API (status %d, content-type %q): %s: %q",
+		e.StatusCode, e.ContentType, e.Reason, e.Snippet)
+}
+
+// newMalformedResponseError builds a ScalewayMalformedResponseError with a one-line snippet of body
+func newMalformedResponseError(resp *http.Response, body []byte, reason string) ScalewayMalformedResponseError {
+	snippet := strings.Join(strings.Fields(string(body)), " ")
+	if len(snippet) > 200 {
+		snippet = snippet[:200] + "..."
+	}
+	return ScalewayMalformedResponseError{
+		StatusCode:  resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+		Snippet:     snippet,
+		Reason:      reason,
+	}
+}
+
+// readResponseBody reads a response body up to MaxResponseSize and detects truncated bodies
+func readResponseBody(resp *http.Response) ([]byte, error) {
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, MaxResponseSize+1))
+	if err != nil {
+		return nil, newMalformedResponseError(resp, body, fmt.Sprintf("truncated body after %d bytes (%v)", len(body), err))
+	}
+	if int64(len(body)) > MaxResponseSize {
+		return nil, newMalformedResponseError(resp, body, fmt.Sprintf("body larger than %d bytes", MaxResponseSize))
+	}
+	if resp.ContentLength > 0 && int64(len(body)) < resp.ContentLength {
+		return nil, newMalformedResponseError(resp, body, fmt.Sprintf("truncated body, got %d of %d bytes", len(body), resp.ContentLength))
+	}
+	return body, nil
+}
+
+// checkJSONBody ensures a non-empty body is valid JSON
+func checkJSONBody(resp *http.Response, body []byte) error {
+	if len(bytes.TrimSpace(body)) == 0 || json.Valid(body) {
+		return nil
+	}
+	reason := "invalid JSON"
+	if strings.Contains(resp.Header.Get("Content-Type"), "html") || bytes.HasPrefix(bytes.TrimSpace(body), []byte("<")) {
+		reason = "got an HTML page instead of JSON"
+	}
+	return newMalformedResponseError(resp, body, reason)
+}
+
 // HideAPICredentials removes API credentials from a string
 func (s *ScalewayAPI) HideAPICredentials(input string) string {
 	output := input
@@ -129,8 +279,11 @@ func (s *ScalewayAPI) HideAPICredentials(input string) string {
 	if s.Organization != "" {
 		output = strings.Replace(output, s.Organization, "00000000-0000-5000-9000-000000000000", -1)
 	}
-	if s.password != "" {
-		output = strings.Replace(output, s.password, "XX-XX-XX-XX", -1)
+	s.lock.RLock()
+	password := s.password
+	s.lock.RUnlock()
+	if password != "" {
+		output = strings.Replace(output, password, "XX-XX-XX-XX", -1)
 	}
 	return output
 }
@@ -156,10 +309,10 @@ type ScalewayVolume struct {
 	Size uint64 `json:"size,omitempty"`
 
 	// CreationDate is the creation date of the volume
-	CreationDate string `json:"creation_date,omitempty"`
+	CreationDate *ScalewayTime `json:"creation_date,omitempty"`
 
 	// ModificationDate is the date of the last modification of the volume
-	ModificationDate string `json:"modification_date,omitempty"`
+	ModificationDate *ScalewayTime `json:"modification_date,omitempty"`
 
 	// Organization is the organization owning the volume
 	Organization string `json:"organization,omitempty"`
@@ -204,6 +357,9 @@ type ScalewayVolumeDefinition struct {
 
 	// Organization is the owner of the volume
 	Organization string `json:"organization"`
+
+	// BaseSnapshot is the snapshot used to initialize the volume content
+	BaseSnapshot string `json:"base_snapshot,omitempty"`
 }
 
 // ScalewayVolumePutDefinition represents a Scaleway volume with nullable fields (for PUT)
@@ -231,10 +387,10 @@ type ScalewayImage struct {
 	Name string `json:"name,omitempty"`
 
 	// CreationDate is the creation date of the image
-	CreationDate string `json:"creation_date,omitempty"`
+	CreationDate *ScalewayTime `json:"creation_date,omitempty"`
 
 	// ModificationDate is the date of the last modification of the image
-	ModificationDate string `json:"modification_date,omitempty"`
+	ModificationDate *ScalewayTime `json:"modification_date,omitempty"`
 
 	// RootVolume is the root volume bound to the image
 	RootVolume ScalewayVolume `json:"root_volume,omitempty"`
@@ -324,10 +480,10 @@ type ScalewaySnapshot struct {
 	Name string `json:"name,omitempty"`
 
 	// CreationDate is the creation date of the snapshot
-	CreationDate string `json:"creation_date,omitempty"`
+	CreationDate *ScalewayTime `json:"creation_date,omitempty"`
 
 	// ModificationDate is the date of the last modification of the snapshot
-	ModificationDate string `json:"modification_date,omitempty"`
+	ModificationDate *ScalewayTime `json:"modification_date,omitempty"`
 
 	// Size is the allocated size of the volume
 	Size uint64 `json:"size,omitempty"`
@@ -378,7 +534,11 @@ type ScalewayBootscript struct {
 	// Public is true for public bootscripts and false for user bootscripts
 	Public bool `json:"public,omitempty"`
 
+	// Default is true for the bootscript used when none is specified
 	Default bool `json:"default,omitempty"`
+
+	// Deprecated is true for bootscripts which are kept for compatibility only
+	Deprecated bool `json:"deprecated,omitempty"`
 }
 
 // ScalewayOneBootscript represents the response of a GET /bootscripts/UUID API call
@@ -398,10 +558,10 @@ type ScalewayTask struct {
 	Identifier string `json:"id,omitempty"`
 
 	// StartDate is the start date of the task
-	StartDate string `json:"started_at,omitempty"`
+	StartDate *ScalewayTime `json:"started_at,omitempty"`
 
 	// TerminationDate is the termination date of the task
-	TerminationDate string `json:"terminated_at,omitempty"`
+	TerminationDate *ScalewayTime `json:"terminated_at,omitempty"`
 
 	HrefFrom string `json:"href_from,omitempty"`
 
@@ -543,10 +703,10 @@ type ScalewayServer struct {
 	Name string `json:"name,omitempty"`
 
 	// CreationDate is the creation date of the server
-	CreationDate string `json:"creation_date,omitempty"`
+	CreationDate *ScalewayTime `json:"creation_date,omitempty"`
 
 	// ModificationDate is the date of the last modification of the server
-	ModificationDate string `json:"modification_date,omitempty"`
+	ModificationDate *ScalewayTime `json:"modification_date,omitempty"`
 
 	// Image is the image used by the server
 	Image ScalewayImage `json:"image,omitempty"`
@@ -666,6 +826,17 @@ type ScalewayServerVolumeDefinitionFromId string
 func (ScalewayServerVolumeDefinitionFromId) isScalewayServerVolumeDefinition() {
 }
 
+// ScalewayServerVolumeDefinitionFromSnapshot is a new volume holding a copy of a snapshot
+type ScalewayServerVolumeDefinitionFromSnapshot struct {
+	Name           string `json:"name"`
+	OrganizationId string `json:"organization"`
+	VolumeType     string `json:"volume_type"`
+	BaseSnapshot   string `json:"base_snapshot"`
+}
+
+func (*ScalewayServerVolumeDefinitionFromSnapshot) isScalewayServerVolumeDefinition() {
+}
+
 // ScalewayServerDefinition represents a Scaleway server with image definition
 type ScalewayServerDefinition struct {
 	// Name is the user-defined name of the server
@@ -926,14 +1097,14 @@ type MarketLocalImage struct {
 
 // MarketVersionDefinition represents version of marketplace image
 type MarketVersionDefinition struct {
-	CreationDate string `json:"creation_date"`
-	ID           string `json:"id"`
+	CreationDate ScalewayTime `json:"creation_date"`
+	ID           string       `json:"id"`
 	Image        struct {
 		ID   string `json:"id"`
 		Name string `json:"name"`
 	} `json:"image"`
-	ModificationDate string `json:"modification_date"`
-	Name             string `json:"name"`
+	ModificationDate ScalewayTime `json:"modification_date"`
+	Name             string       `json:"name"`
 	MarketLocalImages
 }
 
@@ -949,14 +1120,14 @@ type MarketVersion struct {
 
 // MarketImage represents MarketPlace image
 type MarketImage struct {
-	Categories           []string `json:"categories"`
-	CreationDate         string   `json:"creation_date"`
-	CurrentPublicVersion string   `json:"current_public_version"`
-	Description          string   `json:"description"`
-	ID                   string   `json:"id"`
-	Logo                 string   `json:"logo"`
-	ModificationDate     string   `json:"modification_date"`
-	Name                 string   `json:"name"`
+	Categories           []string     `json:"categories"`
+	CreationDate         ScalewayTime `json:"creation_date"`
+	CurrentPublicVersion string       `json:"current_public_version"`
+	Description          string       `json:"description"`
+	ID                   string       `json:"id"`
+	Logo                 string       `json:"logo"`
+	ModificationDate     ScalewayTime `json:"modification_date"`
+	Name                 string       `json:"name"`
 	Organization         struct {
 		ID   string `json:"id"`
 		Name string `json:"name"`
@@ -970,6 +1141,71 @@ type MarketImages struct {
 	Images []MarketImage `json:"images"`
 }
 
+// DefaultTimeout is the time limit of an API request, from dialing to reading the response body
+var DefaultTimeout = 2 * time.Minute
+
+// WithHTTPClient makes the ScalewayAPI send its requests with a copy of client, its Transport is kept if set
+func WithHTTPClient(client *http.Client) func(*ScalewayAPI) {
+	return func(s *ScalewayAPI) {
+		copied := *client
+		s.client = &copied
+	}
+}
+
+// WithTimeout sets the time limit of each API request, 0 disables it, whatever the order of the options
+func WithTimeout(timeout time.Duration) func(*ScalewayAPI) {
+	return func(s *ScalewayAPI) {
+		s.timeout = &timeout
+	}
+}
+
+// WithProxy sends the requests through proxy instead of the one configured by the
+// HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables
+func WithProxy(proxy *url.URL) func(*ScalewayAPI) {
+	return func(s *ScalewayAPI) {
+		s.proxy = proxy
+	}
+}
+
+// WithAPIVersion prefixes the paths of the compute and account APIs with version, i.e: "v2",
+// the unversioned paths are used when empty
+func WithAPIVersion(version string) func(*ScalewayAPI) {
+	return func(s *ScalewayAPI) {
+		s.apiVersion = strings.Trim(version, "/")
+	}
+}
+
+// apiVersionRegexp matches the versions accepted by WithAPIVersion, i.e: "v1", "v2beta1"
+var apiVersionRegexp = regexp.MustCompile(`^v[0-9]+((alpha|beta)[0-9]*)?$`)
+
+// VersionedURL returns the endpoint apiURL with the version of WithAPIVersion
+func (s *ScalewayAPI) VersionedURL(apiURL string) string {
+	if s.apiVersion == "" {
+		return apiURL
+	}
+	return fmt.Sprintf("%s/%s/", strings.TrimRight(apiURL, "/"), s.apiVersion)
+}
+
+// ParseProxyURL parses the address of an http, https or socks5 proxy, http is assumed without a scheme
+func ParseProxyURL(rawURL string) (*url.URL, error) {
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "http://" + rawURL
+	}
+	proxy, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy %q: %v", rawURL, err)
+	}
+	switch proxy.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("invalid proxy %q: unsupported scheme %s", rawURL, proxy.Scheme)
+	}
+	if proxy.Host == "" {
+		return nil, fmt.Errorf("invalid proxy %q: missing host", rawURL)
+	}
+	return proxy, nil
+}
+
 // NewScalewayAPI creates a ready-to-use ScalewayAPI client
 func NewScalewayAPI(organization, token, userAgent, region string, options ...func(*ScalewayAPI)) (*ScalewayAPI, error) {
 	s := &ScalewayAPI{
@@ -977,9 +1213,11 @@ func NewScalewayAPI(organization, token, userAgent, region string, options ...fu
 		Organization: organization,
 		Token:        token,
 		Logger:       NewDefaultLogger(),
+		MaxRateWait:  DefaultMaxRateWait,
 
 		// internal
-		client:    &http.Client{},
+		client:    &http.Client{Timeout: DefaultTimeout},
+		breaker:   newCircuitBreaker(),
 		verbose:   os.Getenv("SCW_VERBOSE_API") != "",
 		password:  "",
 		userAgent: userAgent,
@@ -987,15 +1225,44 @@ func NewScalewayAPI(organization, token, userAgent, region string, options ...fu
 	for _, option := range options {
 		option(s)
 	}
+	if s.timeout != nil {
+		s.client.Timeout = *s.timeout
+	}
+	if s.apiVersion != "" && !apiVersionRegexp.MatchString(s.apiVersion) {
+		return nil, fmt.Errorf("invalid API version %q, expected a version like v1 or v2beta1", s.apiVersion)
+	}
 	cache, err := NewScalewayCache(func() { s.Logger.Debugf("Writing cache file to disk") })
 	if err != nil {
 		return nil, err
 	}
 	s.Cache = cache
+	if s.ResponseCache == nil {
+		s.ResponseCache = NewResponseCache(filepath.Join(filepath.Dir(cache.Path), ".scw-cache.d"))
+	}
+	proxy := http.ProxyFromEnvironment
+	if s.proxy != nil {
+		proxy = http.ProxyURL(s.proxy)
+	}
+	transport := &http.Transport{
+		Proxy:               proxy,
+		DialContext:         happyEyeballsDialer(os.Getenv("SCW_API_PREFER_IPV6") == "1"),
+		MaxIdleConns:        100,
+		IdleConnTimeout:     90 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+	if s.tlsConfig != nil {
+		transport.TLSClientConfig = s.tlsConfig
+	}
 	if os.Getenv("SCW_TLSVERIFY") == "0" {
-		s.client.Transport = &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		} else {
+			transport.TLSClientConfig = transport.TLSClientConfig.Clone()
 		}
+		transport.TLSClientConfig.InsecureSkipVerify = true
+	}
+	if s.client.Transport == nil {
+		s.client.Transport = transport
 	}
 	switch region {
 	case "par1", "":
@@ -1009,6 +1276,8 @@ func NewScalewayAPI(organization, token, userAgent, region string, options ...fu
 	if url := os.Getenv("SCW_COMPUTE_API"); url != "" {
 		s.computeAPI = url
 	}
+	s.computeAPI = s.VersionedURL(s.computeAPI)
+	s.accountAPI = s.VersionedURL(AccountAPI)
 	return s, nil
 }
 
@@ -1022,33 +1291,284 @@ func (s *ScalewayAPI) Sync() {
 	s.Cache.Save()
 }
 
-func (s *ScalewayAPI) response(method, uri string, content io.Reader) (resp *http.Response, err error) {
-	var (
-		req *http.Request
-	)
+// response sends a request to uri, sending it again when the API fails transiently, see RetryMaxAttempts,
+// or when it is rate limited during at most MaxRateWait
+func (s *ScalewayAPI) response(method, uri string, content io.Reader) (*http.Response, error) {
+	return s.sendRequest(method, uri, content, true)
+}
 
-	req, err = http.NewRequest(method, uri, content)
-	if err != nil {
-		err = fmt.Errorf("response %s %s", method, uri)
+// sendRequest is response, the GET requests only go through the ResponseCache, which buffers their body, when cached.
+// The account API answers with tokens and permissions, its responses are never written to the disk cache
+func (s *ScalewayAPI) sendRequest(method, uri string, content io.Reader, cached bool) (resp *http.Response, err error) {
+	cached = cached && !strings.HasPrefix(uri, strings.TrimRight(s.accountAPI, "/")+"/")
+	var body []byte
+	if content != nil {
+		if body, err = ioutil.ReadAll(content); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.ShowCurl != nil {
+		fmt.Fprintln(s.ShowCurl, s.curlCommand(method, uri, body))
+	}
+	if s.DryRun != nil && method != "GET" && method != "HEAD" {
+		fmt.Fprintf(s.DryRun, "%s %s\n", method, uri)
+		var indented bytes.Buffer
+		if json.Indent(&indented, bytes.TrimSpace(body), "", "  ") == nil {
+			fmt.Fprintln(s.DryRun, indented.String())
+		} else if len(body) > 0 {
+			fmt.Fprintln(s.DryRun, string(body))
+		}
+		return nil, DryRunError{Method: method, URL: uri}
+	}
+
+	attempt, rateLimited := 1, 0
+	var rateWaited time.Duration
+	for {
+		var req *http.Request
+		req, err = http.NewRequest(method, uri, bytes.NewReader(body))
+		if err != nil {
+			err = fmt.Errorf("response %s %s", method, uri)
+			return
+		}
+		req.Header.Set("X-Auth-Token", s.Token)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", s.userAgent)
+		req.Header.Set("Accept-Encoding", "gzip")
+		validated := false
+		if cached && method == "GET" && s.ResponseCache != nil {
+			var etag, lastModified string
+			if etag, lastModified, validated = s.ResponseCache.GetValidators(uri); validated {
+				if etag != "" {
+					req.Header.Set("If-None-Match", etag)
+				}
+				if lastModified != "" {
+					req.Header.Set("If-Modified-Since", lastModified)
+				}
+			}
+		}
+		s.LogHTTP(req)
+		if s.verbose {
+			dump, _ := httputil.DumpRequest(req, true)
+			s.Debugf("%v", string(dump))
+		} else {
+			s.Debugf("[%s]: %v", method, uri)
+		}
+		resp, err = s.do(req)
+		if err == nil && cached && method == "GET" && s.ResponseCache != nil {
+			if resp, err = s.conditionalResponse(uri, resp, validated); err != nil {
+				return
+			}
+		}
+		if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+			rateLimited++
+			delay, ok := retryAfter(resp, time.Now())
+			if !ok {
+				delay = retryDelay(rateLimited)
+			}
+			if rateWaited+delay > s.MaxRateWait {
+				return
+			}
+			rateWaited += delay
+			s.Debugf("[%s]: %v rate limited, retrying in %v", method, uri, delay)
+			resp.Body.Close()
+			time.Sleep(delay)
+			continue
+		}
+		if attempt >= RetryMaxAttempts || !isRetryable(method, resp, err) {
+			return
+		}
+
+		delay := retryDelay(attempt)
+		if err != nil {
+			s.Debugf("[%s]: %v failed: %v, retrying in %v", method, uri, err, delay)
+		} else {
+			s.Debugf("[%s]: %v answered %s, retrying in %v", method, uri, resp.Status, delay)
+			resp.Body.Close()
+		}
+		attempt++
+		time.Sleep(delay)
+	}
+}
+
+// curlCommand returns the curl command sending the same request, reading the token from $SCW_TOKEN
+func (s *ScalewayAPI) curlCommand(method, uri string, body []byte) string {
+	words := []string{"curl", "-sS"}
+	if method == "HEAD" {
+		words = append(words, "-I")
+	} else if method != "GET" {
+		words = append(words, "-X", method)
+	}
+	words = append(words, "-H", `"X-Auth-Token: $SCW_TOKEN"`, "-H", utils.ShellQuote("Content-Type: application/json"))
+	if len(body) > 0 {
+		words = append(words, "-d", utils.ShellQuote(strings.TrimSpace(string(body))))
+	}
+	words = append(words, utils.ShellQuote(uri))
+	command := strings.Join(words, " ")
+	if s.Token != "" {
+		command = strings.Replace(command, s.Token, "$SCW_TOKEN", -1)
+	}
+	s.lock.RLock()
+	password := s.password
+	s.lock.RUnlock()
+	if password != "" {
+		command = strings.Replace(command, password, "XX-XX-XX-XX", -1)
+	}
+	return command
+}
+
+// conditionalResponse answers a 304 to a conditional GET on uri with the stored body,
+// and stores the body of a 200 carrying an ETag or a Last-Modified header for the next GET
+func (s *ScalewayAPI) conditionalResponse(uri string, resp *http.Response, validated bool) (*http.Response, error) {
+	switch {
+	case resp.StatusCode == http.StatusNotModified && validated:
+		body, _, ok := s.ResponseCache.Get(uri)
+		if !ok {
+			return resp, nil
+		}
+		s.Debugf("[GET]: %v not modified, using the cached response", uri)
+		resp.Body.Close()
+		resp.StatusCode = http.StatusOK
+		resp.Status = "200 OK"
+		resp.ContentLength = int64(len(body))
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	case resp.StatusCode == http.StatusOK:
+		etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+		if etag == "" && lastModified == "" {
+			return resp, nil
+		}
+		body, err := readResponseBody(resp)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if err = s.ResponseCache.PutValidated(uri, body, etag, lastModified); err != nil {
+			s.Debugf("Cannot cache %s: %v", uri, err)
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	return resp, nil
+}
+
+// do sends req, surrounded by the BeforeRequest and AfterRequest hooks,
+// once the API failed BreakerThreshold times in a row, it fails without sending anything
+func (s *ScalewayAPI) do(req *http.Request) (*http.Response, error) {
+	if s.breaker != nil {
+		if err := s.breaker.check(req.URL.Host); err != nil {
+			return nil, err
+		}
+	}
+	if s.BeforeRequest != nil {
+		s.BeforeRequest(req)
+	}
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	s.Tracer.Request(req, resp, err, time.Since(start))
+	if s.AfterRequest != nil {
+		s.AfterRequest(req, resp, err, time.Since(start))
+	}
+	if s.breaker != nil {
+		s.breaker.record(req.URL.Host, resp, err)
+	}
+	if err == nil {
+		decompressResponse(resp)
+	}
+	return resp, err
+}
+
+// decompressResponse makes the body of a gzip-encoded response readable as is.
+// Requesting gzip explicitly disables the transparent decompression of the http.Transport,
+// which also does not apply to a client set by WithHTTPClient with DisableCompression
+func decompressResponse(resp *http.Response) {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
 		return
 	}
-	req.Header.Set("X-Auth-Token", s.Token)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", s.userAgent)
-	s.LogHTTP(req)
-	if s.verbose {
-		dump, _ := httputil.DumpRequest(req, true)
-		s.Debugf("%v", string(dump))
-	} else {
-		s.Debugf("[%s]: %v", method, uri)
+	resp.Body = &gzipBody{body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+}
+
+// gzipBody decompresses body, its gzip header is read on the first Read so empty bodies, i.e: of HEAD requests, are not an error
+type gzipBody struct {
+	body   io.ReadCloser
+	reader *gzip.Reader
+}
+
+func (g *gzipBody) Read(p []byte) (int, error) {
+	if g.reader == nil {
+		reader, err := gzip.NewReader(g.body)
+		if err != nil {
+			return 0, err
+		}
+		g.reader = reader
 	}
-	resp, err = s.client.Do(req)
-	return
+	return g.reader.Read(p)
+}
+
+func (g *gzipBody) Close() error {
+	return g.body.Close()
+}
+
+// Ping measures the duration of a lightweight authenticated request on an API endpoint, it fails unless the API answers 200
+func (s *ScalewayAPI) Ping(apiURL, resource string) (time.Duration, error) {
+	start := time.Now()
+	resp, err := s.response("HEAD", fmt.Sprintf("%s/%s", strings.TrimRight(apiURL, "/"), resource), nil)
+	if err != nil {
+		return 0, err
+	}
+	duration := time.Since(start)
+	resp.Body.Close()
+	// a HEAD response has no body, the error is made of its status
+	if resp.StatusCode != http.StatusOK {
+		return 0, &ScalewayAPIError{
+			StatusCode: resp.StatusCode,
+			APIMessage: http.StatusText(resp.StatusCode),
+			RequestID:  resp.Header.Get("X-Request-Id"),
+		}
+	}
+	return duration, nil
+}
+
+// ComputeAPIURL returns the compute endpoint of the region used by the client
+func (s *ScalewayAPI) ComputeAPIURL() string {
+	return s.computeAPI
+}
+
+// AccountAPIURL returns the account endpoint used by the client
+func (s *ScalewayAPI) AccountAPIURL() string {
+	return s.accountAPI
 }
 
-// GetResponsePaginate fetchs all resources and returns an http.Response object for the requested resource
+// GetStream returns the body of a GET on a resource as it is received, without buffering it,
+// for large or long-lived responses. The caller must close it
+func (s *ScalewayAPI) GetStream(apiURL, resource string, values url.Values) (io.ReadCloser, error) {
+	uri := fmt.Sprintf("%s/%s", strings.TrimRight(apiURL, "/"), resource)
+	if len(values) > 0 {
+		uri = fmt.Sprintf("%s?%s", uri, values.Encode())
+	}
+	resp, err := s.sendRequest("GET", uri, nil, false)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		if _, err = s.handleHTTPError([]int{http.StatusOK}, resp); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	s.Debugf("[Response]: [%v] streaming %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	return resp.Body, nil
+}
+
+// GetResponsePaginate fetchs all resources and returns an http.Response object for the requested resource.
+// The pages counted by X-Total-Count are fetched in parallel, then the rel="next" Link headers are followed
+// so resources created in the meantime, or an API not sending X-Total-Count, do not truncate the list
 func (s *ScalewayAPI) GetResponsePaginate(apiURL, resource string, values url.Values) (*http.Response, error) {
-	resp, err := s.response("HEAD", fmt.Sprintf("%s/%s?%s", strings.TrimRight(apiURL, "/"), resource, values.Encode()), nil)
+	uri := fmt.Sprintf("%s/%s", strings.TrimRight(apiURL, "/"), resource)
+	resp, err := s.response("HEAD", fmt.Sprintf("%s?%s", uri, values.Encode()), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1069,69 +1589,177 @@ func (s *ScalewayAPI) GetResponsePaginate(apiURL, resource string, values url.Va
 		get++
 	}
 
+	fetchAll := !(values.Get("per_page") != "" || values.Get("page") != "")
+	if !fetchAll {
+		return s.response("GET", fmt.Sprintf("%s?%s", uri, values.Encode()), nil)
+	}
 	if get <= 1 { // If there is 0 or 1 page of result, the response is not paginated
 		if len(values) == 0 {
-			return s.response("GET", fmt.Sprintf("%s/%s", strings.TrimRight(apiURL, "/"), resource), nil)
+			resp, err = s.response("GET", uri, nil)
+		} else {
+			resp, err = s.response("GET", fmt.Sprintf("%s?%s", uri, values.Encode()), nil)
+		}
+		if err != nil || resp.StatusCode != http.StatusOK || nextPageURL(resp) == "" {
+			return resp, err
 		}
-		return s.response("GET", fmt.Sprintf("%s/%s?%s", strings.TrimRight(apiURL, "/"), resource, values.Encode()), nil)
+		return s.followPages([]*http.Response{resp})
 	}
 
-	fetchAll := !(values.Get("per_page") != "" || values.Get("page") != "")
-	if fetchAll {
-		var g errgroup.Group
-
-		ch := make(chan *http.Response, get)
-		for i := 1; i <= get; i++ {
-			i := i // closure tricks
-			g.Go(func() (err error) {
-				var resp *http.Response
-
-				val := url.Values{}
-				val.Set("per_page", fmt.Sprintf("%v", perPage))
-				val.Set("page", fmt.Sprintf("%v", i))
-				resp, err = s.response("GET", fmt.Sprintf("%s/%s?%s", strings.TrimRight(apiURL, "/"), resource, val.Encode()), nil)
-				ch <- resp
-				return
-			})
+	var g errgroup.Group
+	pages := make([]*http.Response, get)
+	for i := 1; i <= get; i++ {
+		i := i // closure tricks
+		g.Go(func() (err error) {
+			val := url.Values{}
+			for key, value := range values {
+				val[key] = value
+			}
+			val.Set("per_page", fmt.Sprintf("%v", perPage))
+			val.Set("page", fmt.Sprintf("%v", i))
+			pages[i-1], err = s.response("GET", fmt.Sprintf("%s?%s", uri, val.Encode()), nil)
+			return
+		})
+	}
+	if err = g.Wait(); err != nil {
+		closePages(pages)
+		return nil, err
+	}
+	return s.followPages(pages)
+}
+
+// nextPageURL returns the target of the rel="next" Link header of resp, or an empty string on the last page
+func nextPageURL(resp *http.Response) string {
+	for _, header := range resp.Header["Link"] {
+		for _, link := range strings.Split(header, ",") {
+			parts := strings.Split(link, ";")
+			target := strings.Trim(strings.TrimSpace(parts[0]), "<>")
+			for _, param := range parts[1:] {
+				param = strings.Replace(strings.TrimSpace(param), " ", "", -1)
+				if param != `rel="next"` && param != "rel=next" {
+					continue
+				}
+				next, err := url.Parse(target)
+				if err != nil {
+					return ""
+				}
+				if resp.Request != nil {
+					next = resp.Request.URL.ResolveReference(next)
+				}
+				return next.String()
+			}
+		}
+	}
+	return ""
+}
+
+// closePages closes the bodies of the fetched pages
+func closePages(pages []*http.Response) {
+	for _, page := range pages {
+		if page != nil {
+			page.Body.Close()
+		}
+	}
+}
+
+// followPages fetches the pages linked as rel="next" from the last one, then merges all of them,
+// a page which is not a 200 is returned as is
+func (s *ScalewayAPI) followPages(pages []*http.Response) (*http.Response, error) {
+	seen := map[string]bool{}
+	for _, page := range pages {
+		if page.StatusCode != http.StatusOK {
+			closePages(pages)
+			return page, nil
 		}
-		if err = g.Wait(); err != nil {
+		seen[page.Request.URL.String()] = true
+	}
+	for next := nextPageURL(pages[len(pages)-1]); next != "" && !seen[next]; next = nextPageURL(pages[len(pages)-1]) {
+		seen[next] = true
+		page, err := s.response("GET", next, nil)
+		if err != nil {
+			closePages(pages)
 			return nil, err
 		}
-		newBody := make(map[string][]json.RawMessage)
+		if page.StatusCode != http.StatusOK {
+			closePages(pages)
+			return page, nil
+		}
+		pages = append(pages, page)
+	}
+	if len(pages) == 1 {
+		return pages[0], nil
+	}
+	return mergePages(pages)
+}
+
+// mergePages concatenates the list of resources of each page in the body of the first one
+func mergePages(pages []*http.Response) (*http.Response, error) {
+	newBody := make(map[string][]json.RawMessage)
+	key := ""
+	for i, res := range pages {
+		content, err := readResponseBody(res)
+		res.Body.Close()
+		if err == nil {
+			err = checkJSONBody(res, content)
+		}
 		body := make(map[string][]json.RawMessage)
-		key := ""
-		for i := 0; i < get; i++ {
-			res := <-ch
-			if res.StatusCode != http.StatusOK {
-				return res, nil
-			}
-			content, err := ioutil.ReadAll(res.Body)
-			res.Body.Close()
-			if err != nil {
-				return nil, err
-			}
-			if err := json.Unmarshal(content, &body); err != nil {
-				return nil, err
+		if err == nil {
+			err = json.Unmarshal(content, &body)
+		}
+		if err != nil {
+			closePages(pages[i+1:])
+			return nil, err
+		}
+		if i == 0 {
+			for k := range body {
+				key = k
+				break
 			}
+		}
+		newBody[key] = append(newBody[key], body[key]...)
+	}
+	payload := new(bytes.Buffer)
+	if err := json.NewEncoder(payload).Encode(newBody); err != nil {
+		return nil, err
+	}
+	resp := pages[0]
+	resp.Body = ioutil.NopCloser(payload)
+	return resp, nil
+}
 
-			if i == 0 {
-				resp = res
-				for k := range body {
-					key = k
-					break
-				}
-			}
-			newBody[key] = append(newBody[key], body[key]...)
+// getCachedBody returns the body of a GET on resource, kept in ResponseCache during ttl.
+// A stale body is returned when the API cannot be reached, to work offline
+func (s *ScalewayAPI) getCachedBody(apiURL, resource string, ttl time.Duration) ([]byte, error) {
+	uri := fmt.Sprintf("%s/%s", strings.TrimRight(apiURL, "/"), resource)
+	var cached []byte
+	if s.ResponseCache != nil {
+		var age time.Duration
+		var ok bool
+		if cached, age, ok = s.ResponseCache.Get(uri); ok && age < ttl && !s.RefreshResponses {
+			s.Debugf("[GET]: %v (cached %v ago)", uri, age.Round(time.Second))
+			return cached, nil
 		}
-		payload := new(bytes.Buffer)
-		if err := json.NewEncoder(payload).Encode(newBody); err != nil {
-			return nil, err
+	}
+
+	resp, err := s.GetResponsePaginate(apiURL, resource, url.Values{})
+	if err != nil {
+		if cached != nil {
+			s.Warnf("Cannot reach the API, using a cached response: %v", err)
+			return cached, nil
 		}
-		resp.Body = ioutil.NopCloser(payload)
-	} else {
-		resp, err = s.response("GET", fmt.Sprintf("%s/%s?%s", strings.TrimRight(apiURL, "/"), resource, values.Encode()), nil)
+		return nil, err
 	}
-	return resp, err
+	defer resp.Body.Close()
+
+	body, err := s.handleHTTPError([]int{http.StatusOK}, resp)
+	if err != nil {
+		return nil, err
+	}
+	if s.ResponseCache != nil {
+		if err := s.ResponseCache.Put(uri, body); err != nil {
+			s.Debugf("Cannot cache %s: %v", uri, err)
+		}
+	}
+	return body, nil
 }
 
 // PostResponse returns an http.Response object for the updated resource
@@ -1168,10 +1796,15 @@ func (s *ScalewayAPI) DeleteResponse(apiURL, resource string) (*http.Response, e
 
 // handleHTTPError checks the statusCode and displays the error
 func (s *ScalewayAPI) handleHTTPError(goodStatusCode []int, resp *http.Response) ([]byte, error) {
-	body, err := ioutil.ReadAll(resp.Body)
+	body, err := readResponseBody(resp)
 	if err != nil {
 		return nil, err
 	}
+	requestID := resp.Header.Get("X-Request-Id")
+	status := fmt.Sprint(resp.StatusCode)
+	if requestID != "" {
+		status += ", X-Request-Id: " + requestID
+	}
 	if s.verbose {
 		resp.Body = ioutil.NopCloser(bytes.NewBuffer(body))
 		dump, err := httputil.DumpResponse(resp, true)
@@ -1180,18 +1813,30 @@ func (s *ScalewayAPI) handleHTTPError(goodStatusCode []int, resp *http.Response)
 
 			err = json.Indent(&js, body, "", "  ")
 			if err != nil {
-				s.Debugf("[Response]: [%v]\n%v", resp.StatusCode, string(dump))
+				s.Debugf("[Response]: [%v]\n%v", status, string(dump))
 			} else {
-				s.Debugf("[Response]: [%v]\n%v", resp.StatusCode, js.String())
+				s.Debugf("[Response]: [%v]\n%v", status, js.String())
 			}
 		}
 	} else {
-		s.Debugf("[Response]: [%v]\n%v", resp.StatusCode, string(body))
+		s.Debugf("[Response]: [%v]\n%v", status, string(body))
 	}
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("rate limited by the API (429), try again later or raise --max-rate-wait")
+	}
 	if resp.StatusCode >= http.StatusInternalServerError {
+		if err := checkJSONBody(resp, body); err != nil {
+			return nil, err
+		}
+		if requestID != "" {
+			return nil, fmt.Errorf("%s (X-Request-Id: %s)", body, requestID)
+		}
 		return nil, errors.New(string(body))
 	}
+	if err := checkJSONBody(resp, body); err != nil {
+		return nil, err
+	}
 	good := false
 	for _, code := range goodStatusCode {
 		if code == resp.StatusCode {
@@ -1205,8 +1850,9 @@ func (s *ScalewayAPI) handleHTTPError(goodStatusCode []int, resp *http.Response)
 			return nil, err
 		}
 		scwError.StatusCode = resp.StatusCode
+		scwError.RequestID = requestID
 		s.Debugf("%s", scwError.Error())
-		return nil, scwError
+		return nil, &scwError
 	}
 	return body, nil
 }
@@ -1239,19 +1885,14 @@ func (s *ScalewayAPI) GetServers(all bool, limit int) (*[]ScalewayServer, error)
 	if !all {
 		query.Set("state", "running")
 	}
-	if limit > 0 {
-		// FIXME: wait for the API to be ready
-		// query.Set("per_page", strconv.Itoa(limit))
-		panic("Not implemented yet")
-	}
 	if all && limit == 0 {
 		s.Cache.ClearServers()
 	}
 	var (
 		g    errgroup.Group
 		apis = []string{
-			ComputeAPIPar1,
-			ComputeAPIAms1,
+			s.VersionedURL(ComputeAPIPar1),
+			s.VersionedURL(ComputeAPIAms1),
 		}
 	)
 
@@ -1275,6 +1916,10 @@ func (s *ScalewayAPI) GetServers(all bool, limit int) (*[]ScalewayServer, error)
 		servers.Servers[i].DNSPrivate = server.Identifier + URLPrivateDNS
 		s.Cache.InsertServer(server.Identifier, server.Location.ZoneID, server.Arch, server.Organization, server.Name)
 	}
+	if limit > 0 && len(servers.Servers) > limit {
+		sort.Sort(ScalewaySortServers(servers.Servers))
+		servers.Servers = servers.Servers[:limit]
+	}
 	return &servers.Servers, nil
 }
 
@@ -1290,9 +1935,7 @@ func (s ScalewaySortServers) Swap(i, j int) {
 }
 
 func (s ScalewaySortServers) Less(i, j int) bool {
-	date1, _ := time.Parse("2006-01-02T15:04:05.000000+00:00", s[i].CreationDate)
-	date2, _ := time.Parse("2006-01-02T15:04:05.000000+00:00", s[j].CreationDate)
-	return date2.Before(date1)
+	return s[j].CreationDate.Value().Before(s[i].CreationDate.Value())
 }
 
 // GetServer gets a server from the ScalewayAPI
@@ -1323,19 +1966,140 @@ func (s *ScalewayAPI) GetServer(serverID string) (*ScalewayServer, error) {
 	return &oneServer.Server, nil
 }
 
+// maxConflictRetries is the number of times a server action is retried when WaitConflicts is enabled
+const maxConflictRetries = 5
+
 // PostServerAction posts an action on a server
 func (s *ScalewayAPI) PostServerAction(serverID, action string) error {
+	_, err := s.PostServerActionTask(serverID, action)
+	return err
+}
+
+// PostServerActionTask posts an action on a server and returns the task running it, see WaitForTask.
+// The task is nil when the API does not return it
+func (s *ScalewayAPI) PostServerActionTask(serverID, action string) (*ScalewayTask, error) {
+	if s.WaitTransitions {
+		if err := s.waitServerTransition(serverID, action); err != nil {
+			return nil, err
+		}
+	}
+	for attempt := 1; ; attempt++ {
+		task, err := s.postServerAction(serverID, action)
+		apiErr, ok := asAPIError(err)
+		if !s.WaitConflicts || !ok || apiErr.StatusCode != http.StatusConflict || attempt == maxConflictRetries {
+			// the API rejects the actions on a server in transition as an invalid request
+			if ok && !s.WaitTransitions && apiErr.StatusCode == http.StatusBadRequest && apiErr.Type == "invalid_request_error" {
+				return nil, s.serverTransitionError(serverID, action, err)
+			}
+			return task, err
+		}
+		s.Infof("Server %s is busy (%s), waiting for the conflicting task before retrying %s", serverID, apiErr.APIMessage, action)
+		if err := WaitForServerTasks(s, serverID, ConflictTimeout); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// waitServerTransition waits for a starting, stopping or allocating server to settle before action
+func (s *ScalewayAPI) waitServerTransition(serverID, action string) error {
+	server, err := s.GetServer(serverID)
+	if err != nil {
+		return err
+	}
+	if !IsTransitionalState(server) {
+		return nil
+	}
+	s.Infof("Server %s is %s, waiting for it to settle before %s", server.Name, server.State, action)
+	_, err = WaitForServerTransition(s, serverID, ConflictTimeout)
+	return err
+}
+
+// serverTransitionError returns a ServerTransitionError with the state and the task in progress of a server
+// which is starting, stopping or allocating when the API rejected action with the invalid request err,
+// and err otherwise
+func (s *ScalewayAPI) serverTransitionError(serverID, action string, err error) error {
+	server, getErr := s.GetServer(serverID)
+	if getErr != nil || !IsTransitionalState(server) {
+		return err
+	}
+	task, _ := s.GetServerPendingTask(serverID)
+	return ServerTransitionError{Server: server, Action: action, Task: task}
+}
+
+// checkNameCollision warns, or fails with UniqueNames, when a server or an image of the organization is already named name,
+// such duplicates make the names ambiguous later on
+func (s *ScalewayAPI) checkNameCollision(kind, name string) error {
+	var identifiers []string
+	switch kind {
+	case "server":
+		query := url.Values{}
+		query.Set("organization", s.Organization)
+		query.Set("name", name)
+		// the server is created in the region of the client, only its names are checked
+		serverChan := make(chan ScalewayServers, 1)
+		if err := s.fetchServers(s.computeAPI, query, serverChan)(); err != nil {
+			return s.nameCheckError(kind, err)
+		}
+		servers := <-serverChan
+		// the name filter of the API also matches the names containing name
+		for _, server := range servers.Servers {
+			if server.Name == name && server.Organization == s.Organization {
+				identifiers = append(identifiers, server.Identifier)
+			}
+		}
+	case "image":
+		images, err := s.GetOrganizationImages()
+		if err != nil {
+			return s.nameCheckError(kind, err)
+		}
+		for _, image := range *images {
+			if image.Name == name {
+				identifiers = append(identifiers, image.Identifier)
+			}
+		}
+	}
+	if len(identifiers) == 0 {
+		return nil
+	}
+	err := NameCollisionError{Kind: kind, Name: name, Identifiers: identifiers}
+	if s.UniqueNames {
+		return err
+	}
+	s.Warnf("%v", err)
+	return nil
+}
+
+// nameCheckError fails with UniqueNames when the names of the servers or the images cannot be listed,
+// the collisions are not checked otherwise
+func (s *ScalewayAPI) nameCheckError(kind string, err error) error {
+	if s.UniqueNames {
+		return Wrapf(err, "cannot check the names of the %ss", kind)
+	}
+	s.Warnf("Cannot check the names of the %ss: %v", kind, err)
+	return nil
+}
+
+func (s *ScalewayAPI) postServerAction(serverID, action string) (*ScalewayTask, error) {
 	data := ScalewayServerAction{
 		Action: action,
 	}
 	resp, err := s.PostResponse(s.computeAPI, fmt.Sprintf("servers/%s/action", serverID), data)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	_, err = s.handleHTTPError([]int{http.StatusAccepted}, resp)
-	return err
+	body, err := s.handleHTTPError([]int{http.StatusAccepted}, resp)
+	if err != nil {
+		return nil, err
+	}
+	var oneTask ScalewayOneTask
+
+	if err = json.Unmarshal(body, &oneTask); err != nil || oneTask.Task.Identifier == "" {
+		s.Debugf("No task in the response of the %s action", action)
+		return nil, nil
+	}
+	return &oneTask.Task, nil
 }
 
 // DeleteServer deletes a server
@@ -1356,6 +2120,9 @@ func (s *ScalewayAPI) DeleteServer(serverID string) error {
 // PostServer creates a new server
 func (s *ScalewayAPI) PostServer(definition ScalewayServerDefinition) (string, error) {
 	definition.Organization = s.Organization
+	if err := s.checkNameCollision("server", definition.Name); err != nil {
+		return "", err
+	}
 
 	resp, err := s.PostResponse(s.computeAPI, "servers", definition)
 	if err != nil {
@@ -1379,7 +2146,7 @@ func (s *ScalewayAPI) PostServer(definition ScalewayServerDefinition) (string, e
 
 // PatchUserSSHKey updates a user
 func (s *ScalewayAPI) PatchUserSSHKey(UserID string, definition ScalewayUserPatchSSHKeyDefinition) error {
-	resp, err := s.PatchResponse(AccountAPI, fmt.Sprintf("users/%s", UserID), definition)
+	resp, err := s.PatchResponse(s.accountAPI, fmt.Sprintf("users/%s", UserID), definition)
 	if err != nil {
 		return err
 	}
@@ -1435,6 +2202,9 @@ func (s *ScalewayAPI) PostSnapshot(volumeID string, name string) (string, error)
 
 // PostImage creates a new image
 func (s *ScalewayAPI) PostImage(volumeID string, name string, bootscript string, arch string) (string, error) {
+	if err := s.checkNameCollision("image", name); err != nil {
+		return "", err
+	}
 	definition := ScalewayImageDefinition{
 		SnapshotIDentifier: volumeID,
 		Name:               name,
@@ -1487,7 +2257,8 @@ func (s *ScalewayAPI) PostVolume(definition ScalewayVolumeDefinition) (string, e
 	if err = json.Unmarshal(body, &volume); err != nil {
 		return "", err
 	}
-	// FIXME: s.Cache.InsertVolume(volume.Volume.Identifier, volume.Volume.Name)
+	// a volume has no arch, it gets the one of the server it is attached to, so it is cached without one like in GetVolumes
+	s.Cache.InsertVolume(volume.Volume.Identifier, s.Region, "", s.Organization, volume.Volume.Name)
 	return volume.Volume.Identifier, nil
 }
 
@@ -1503,18 +2274,33 @@ func (s *ScalewayAPI) PutVolume(volumeID string, definition ScalewayVolumePutDef
 	return err
 }
 
+// inRegion keeps the results of the region of the client, identical names can be used in every region.
+// The results cached without region are kept
+func (s *ScalewayAPI) inRegion(results ScalewayResolverResults) ScalewayResolverResults {
+	kept := ScalewayResolverResults{}
+	for _, result := range results {
+		if result.Region == "" || result.Region == s.Region {
+			kept = append(kept, result)
+		}
+	}
+	return kept
+}
+
 // ResolveServer attempts to find a matching Identifier for the input string
 func (s *ScalewayAPI) ResolveServer(needle string) (ScalewayResolverResults, error) {
 	servers, err := s.Cache.LookUpServers(needle, true)
 	if err != nil {
 		return servers, err
 	}
+	servers = s.inRegion(servers)
 	if len(servers) == 0 {
 		if _, err = s.GetServers(true, 0); err != nil {
 			return nil, err
 		}
 		servers, err = s.Cache.LookUpServers(needle, true)
+		servers = s.inRegion(servers)
 	}
+	s.traceResolve("server", needle, servers, err)
 	return servers, err
 }
 
@@ -1524,27 +2310,51 @@ func (s *ScalewayAPI) ResolveVolume(needle string) (ScalewayResolverResults, err
 	if err != nil {
 		return volumes, err
 	}
+	volumes = s.inRegion(volumes)
 	if len(volumes) == 0 {
 		if _, err = s.GetVolumes(); err != nil {
 			return nil, err
 		}
 		volumes, err = s.Cache.LookUpVolumes(needle, true)
+		volumes = s.inRegion(volumes)
 	}
+	s.traceResolve("volume", needle, volumes, err)
 	return volumes, err
 }
 
+// ResolveIP attempts to find a matching Identifier for the input string
+func (s *ScalewayAPI) ResolveIP(needle string) (ScalewayResolverResults, error) {
+	ips, err := s.Cache.LookUpIPs(needle, true)
+	if err != nil {
+		return ips, err
+	}
+	ips = s.inRegion(ips)
+	if len(ips) == 0 {
+		if _, err = s.GetIPS(); err != nil {
+			return nil, err
+		}
+		ips, err = s.Cache.LookUpIPs(needle, true)
+		ips = s.inRegion(ips)
+	}
+	s.traceResolve("ip", needle, ips, err)
+	return ips, err
+}
+
 // ResolveSnapshot attempts to find a matching Identifier for the input string
 func (s *ScalewayAPI) ResolveSnapshot(needle string) (ScalewayResolverResults, error) {
 	snapshots, err := s.Cache.LookUpSnapshots(needle, true)
 	if err != nil {
 		return snapshots, err
 	}
+	snapshots = s.inRegion(snapshots)
 	if len(snapshots) == 0 {
 		if _, err = s.GetSnapshots(); err != nil {
 			return nil, err
 		}
 		snapshots, err = s.Cache.LookUpSnapshots(needle, true)
+		snapshots = s.inRegion(snapshots)
 	}
+	s.traceResolve("snapshot", needle, snapshots, err)
 	return snapshots, err
 }
 
@@ -1554,12 +2364,15 @@ func (s *ScalewayAPI) ResolveImage(needle string) (ScalewayResolverResults, erro
 	if err != nil {
 		return images, err
 	}
+	images = s.inRegion(images)
 	if len(images) == 0 {
 		if _, err = s.GetImages(); err != nil {
 			return nil, err
 		}
 		images, err = s.Cache.LookUpImages(needle, true)
+		images = s.inRegion(images)
 	}
+	s.traceResolve("image", needle, images, err)
 	return images, err
 }
 
@@ -1569,12 +2382,15 @@ func (s *ScalewayAPI) ResolveBootscript(needle string) (ScalewayResolverResults,
 	if err != nil {
 		return bootscripts, err
 	}
+	bootscripts = s.inRegion(bootscripts)
 	if len(bootscripts) == 0 {
 		if _, err = s.GetBootscripts(); err != nil {
 			return nil, err
 		}
 		bootscripts, err = s.Cache.LookUpBootscripts(needle, true)
+		bootscripts = s.inRegion(bootscripts)
 	}
+	s.traceResolve("bootscript", needle, bootscripts, err)
 	return bootscripts, err
 }
 
@@ -1618,17 +2434,17 @@ func (s *ScalewayAPI) GetImages() (*[]MarketImage, error) {
 	for _, orgaImage := range OrgaImages.Images {
 		images.Images = append(images.Images, MarketImage{
 			Categories:           []string{"MyImages"},
-			CreationDate:         orgaImage.CreationDate,
+			CreationDate:         ScalewayTime{orgaImage.CreationDate.Value()},
 			CurrentPublicVersion: orgaImage.Identifier,
-			ModificationDate:     orgaImage.ModificationDate,
+			ModificationDate:     ScalewayTime{orgaImage.ModificationDate.Value()},
 			Name:                 orgaImage.Name,
 			Public:               false,
 			MarketVersions: MarketVersions{
 				Versions: []MarketVersionDefinition{
 					{
-						CreationDate:     orgaImage.CreationDate,
+						CreationDate:     ScalewayTime{orgaImage.CreationDate.Value()},
 						ID:               orgaImage.Identifier,
-						ModificationDate: orgaImage.ModificationDate,
+						ModificationDate: ScalewayTime{orgaImage.ModificationDate.Value()},
 						MarketLocalImages: MarketLocalImages{
 							LocalImages: []MarketLocalImageDefinition{
 								{
@@ -1715,6 +2531,31 @@ func (s *ScalewayAPI) DeleteVolume(volumeID string) error {
 	return nil
 }
 
+// GetOrganizationImages gets the list of images owned by the organization from the ScalewayAPI
+func (s *ScalewayAPI) GetOrganizationImages() (*[]ScalewayImage, error) {
+	values := url.Values{}
+	values.Set("organization", s.Organization)
+	resp, err := s.GetResponsePaginate(s.computeAPI, "images", values)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := s.handleHTTPError([]int{http.StatusOK}, resp)
+	if err != nil {
+		return nil, err
+	}
+	var images ScalewayImages
+
+	if err = json.Unmarshal(body, &images); err != nil {
+		return nil, err
+	}
+	for _, image := range images.Images {
+		s.Cache.InsertImage(image.Identifier, s.Region, image.Arch, image.Organization, image.Name, "")
+	}
+	return &images.Images, nil
+}
+
 // GetSnapshots gets the list of snapshots from the ScalewayAPI
 func (s *ScalewayAPI) GetSnapshots() (*[]ScalewaySnapshot, error) {
 	query := url.Values{}
@@ -1816,19 +2657,11 @@ func (s *ScalewayAPI) GetVolume(volumeID string) (*ScalewayVolume, error) {
 
 // GetBootscripts gets the list of bootscripts from the ScalewayAPI
 func (s *ScalewayAPI) GetBootscripts() (*[]ScalewayBootscript, error) {
-	query := url.Values{}
-
-	s.Cache.ClearBootscripts()
-	resp, err := s.GetResponsePaginate(s.computeAPI, "bootscripts", query)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, err := s.handleHTTPError([]int{http.StatusOK}, resp)
+	body, err := s.getCachedBody(s.computeAPI, "bootscripts", BootscriptsTTL)
 	if err != nil {
 		return nil, err
 	}
+	s.Cache.ClearBootscripts()
 	var bootscripts ScalewayBootscripts
 
 	if err = json.Unmarshal(body, &bootscripts); err != nil {
@@ -1843,13 +2676,7 @@ func (s *ScalewayAPI) GetBootscripts() (*[]ScalewayBootscript, error) {
 
 // GetBootscript gets a bootscript from the ScalewayAPI
 func (s *ScalewayAPI) GetBootscript(bootscriptID string) (*ScalewayBootscript, error) {
-	resp, err := s.GetResponsePaginate(s.computeAPI, "bootscripts/"+bootscriptID, url.Values{})
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, err := s.handleHTTPError([]int{http.StatusOK}, resp)
+	body, err := s.getCachedBody(s.computeAPI, "bootscripts/"+bootscriptID, BootscriptTTL)
 	if err != nil {
 		return nil, err
 	}
@@ -1924,6 +2751,14 @@ func (s *ScalewayAPI) GetUserdata(serverID, key string, metadata bool) (*Scalewa
 	return &data, err
 }
 
+// GetUserdataStream gets a specific userdata for a server as it is received, the caller must close it
+func (s *ScalewayAPI) GetUserdataStream(serverID, key string, metadata bool) (io.ReadCloser, error) {
+	if metadata {
+		return s.GetStream(MetadataAPI, fmt.Sprintf("user_data/%s", key), url.Values{})
+	}
+	return s.GetStream(s.computeAPI, fmt.Sprintf("servers/%s/user_data/%s", serverID, key), url.Values{})
+}
+
 // PatchUserdata sets a user data
 func (s *ScalewayAPI) PatchUserdata(serverID, key string, value []byte, metadata bool) error {
 	var resource, endpoint string
@@ -1951,7 +2786,7 @@ func (s *ScalewayAPI) PatchUserdata(serverID, key string, value []byte, metadata
 
 	s.LogHTTP(req)
 
-	resp, err := s.client.Do(req)
+	resp, err := s.do(req)
 	if err != nil {
 		return err
 	}
@@ -2007,11 +2842,45 @@ func (s *ScalewayAPI) GetTasks() (*[]ScalewayTask, error) {
 	return &tasks.Tasks, nil
 }
 
+// GetTask gets a task from the ScalewayAPI
+func (s *ScalewayAPI) GetTask(taskID string) (*ScalewayTask, error) {
+	resp, err := s.GetResponsePaginate(s.computeAPI, fmt.Sprintf("tasks/%s", taskID), url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := s.handleHTTPError([]int{http.StatusOK}, resp)
+	if err != nil {
+		return nil, err
+	}
+	var oneTask ScalewayOneTask
+
+	if err = json.Unmarshal(body, &oneTask); err != nil {
+		return nil, err
+	}
+	return &oneTask.Task, nil
+}
+
+// DeleteTask cancels a pending or started task
+func (s *ScalewayAPI) DeleteTask(taskID string) error {
+	resp, err := s.DeleteResponse(s.computeAPI, fmt.Sprintf("tasks/%s", taskID))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if _, err := s.handleHTTPError([]int{http.StatusNoContent, http.StatusOK, http.StatusAccepted}, resp); err != nil {
+		return err
+	}
+	return nil
+}
+
 // CheckCredentials performs a dummy check to ensure we can contact the API
 func (s *ScalewayAPI) CheckCredentials() error {
 	query := url.Values{}
 
-	resp, err := s.GetResponsePaginate(AccountAPI, "tokens", query)
+	resp, err := s.GetResponsePaginate(s.accountAPI, "tokens", query)
 	if err != nil {
 		return err
 	}
@@ -2027,29 +2896,38 @@ func (s *ScalewayAPI) CheckCredentials() error {
 	return nil
 }
 
-// GetUserID returns the userID
-func (s *ScalewayAPI) GetUserID() (string, error) {
-	resp, err := s.GetResponsePaginate(AccountAPI, fmt.Sprintf("tokens/%s", s.Token), url.Values{})
+// GetToken returns the token used by the client
+func (s *ScalewayAPI) GetToken() (*ScalewayTokenDefinition, error) {
+	resp, err := s.GetResponsePaginate(s.accountAPI, fmt.Sprintf("tokens/%s", s.Token), url.Values{})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	body, err := s.handleHTTPError([]int{http.StatusOK}, resp)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	var token ScalewayTokensDefinition
 
 	if err = json.Unmarshal(body, &token); err != nil {
+		return nil, err
+	}
+	return &token.Token, nil
+}
+
+// GetUserID returns the userID
+func (s *ScalewayAPI) GetUserID() (string, error) {
+	token, err := s.GetToken()
+	if err != nil {
 		return "", err
 	}
-	return token.Token.UserID, nil
+	return token.UserID, nil
 }
 
 // GetOrganization returns Organization
 func (s *ScalewayAPI) GetOrganization() (*ScalewayOrganizationsDefinition, error) {
-	resp, err := s.GetResponsePaginate(AccountAPI, "organizations", url.Values{})
+	resp, err := s.GetResponsePaginate(s.accountAPI, "organizations", url.Values{})
 	if err != nil {
 		return nil, err
 	}
@@ -2067,13 +2945,45 @@ func (s *ScalewayAPI) GetOrganization() (*ScalewayOrganizationsDefinition, error
 	return &data, nil
 }
 
+// ScalewayOrganizationMember represents a user of an organization with its role
+type ScalewayOrganizationMember struct {
+	User ScalewayUserDefinition
+	Role string
+}
+
+// GetOrganizationMembers returns the users of an organization with their role, sorted by email
+func (s *ScalewayAPI) GetOrganizationMembers(organizationID string) ([]ScalewayOrganizationMember, error) {
+	organizations, err := s.GetOrganization()
+	if err != nil {
+		return nil, err
+	}
+	for _, organization := range organizations.Organizations {
+		if organization.ID != organizationID {
+			continue
+		}
+		members := make([]ScalewayOrganizationMember, 0, len(organization.Users))
+		for _, user := range organization.Users {
+			member := ScalewayOrganizationMember{User: user}
+			for _, role := range user.Roles {
+				if role.Organization.ID == organizationID {
+					member.Role = role.Role
+				}
+			}
+			members = append(members, member)
+		}
+		sort.SliceStable(members, func(i, j int) bool { return members[i].User.Email < members[j].User.Email })
+		return members, nil
+	}
+	return nil, fmt.Errorf("organization %s not found", organizationID)
+}
+
 // GetUser returns the user
 func (s *ScalewayAPI) GetUser() (*ScalewayUserDefinition, error) {
 	userID, err := s.GetUserID()
 	if err != nil {
 		return nil, err
 	}
-	resp, err := s.GetResponsePaginate(AccountAPI, fmt.Sprintf("users/%s", userID), url.Values{})
+	resp, err := s.GetResponsePaginate(s.accountAPI, fmt.Sprintf("users/%s", userID), url.Values{})
 	if err != nil {
 		return nil, err
 	}
@@ -2093,7 +3003,7 @@ func (s *ScalewayAPI) GetUser() (*ScalewayUserDefinition, error) {
 
 // GetPermissions returns the permissions
 func (s *ScalewayAPI) GetPermissions() (*ScalewayPermissionDefinition, error) {
-	resp, err := s.GetResponsePaginate(AccountAPI, fmt.Sprintf("tokens/%s/permissions", s.Token), url.Values{})
+	resp, err := s.GetResponsePaginate(s.accountAPI, fmt.Sprintf("tokens/%s/permissions", s.Token), url.Values{})
 	if err != nil {
 		return nil, err
 	}
@@ -2144,9 +3054,13 @@ func (s *ScalewayAPI) GetServerID(needle string) (string, error) {
 		return servers[0].Identifier, nil
 	}
 	if len(servers) == 0 {
-		return "", fmt.Errorf("No such server: %s", needle)
+		return "", newNotFoundError("No such server: %s", needle)
+	}
+	i, err := s.chooseResolverResult(needle, servers)
+	if err != nil {
+		return "", err
 	}
-	return "", showResolverResults(needle, servers)
+	return servers[i].Identifier, nil
 }
 
 func showResolverResults(needle string, results ScalewayResolverResults) error {
@@ -2176,9 +3090,35 @@ func (s *ScalewayAPI) GetVolumeID(needle string) (string, error) {
 		return volumes[0].Identifier, nil
 	}
 	if len(volumes) == 0 {
-		return "", fmt.Errorf("No such volume: %s", needle)
+		return "", newNotFoundError("No such volume: %s", needle)
+	}
+	i, err := s.chooseResolverResult(needle, volumes)
+	if err != nil {
+		return "", err
 	}
-	return "", showResolverResults(needle, volumes)
+	return volumes[i].Identifier, nil
+}
+
+// GetIPID returns exactly one IP matching an address or an identifier
+func (s *ScalewayAPI) GetIPID(needle string) (string, error) {
+	// Parses optional type prefix, i.e: "ip:51.15.1.2" -> "51.15.1.2"
+	_, needle = parseNeedle(needle)
+
+	ips, err := s.ResolveIP(needle)
+	if err != nil {
+		return "", fmt.Errorf("Unable to resolve IP %s: %s", needle, err)
+	}
+	if len(ips) == 1 {
+		return ips[0].Identifier, nil
+	}
+	if len(ips) == 0 {
+		return "", newNotFoundError("No such IP: %s", needle)
+	}
+	i, err := s.chooseResolverResult(needle, ips)
+	if err != nil {
+		return "", err
+	}
+	return ips[i].Identifier, nil
 }
 
 // GetSnapshotID returns exactly one snapshot matching
@@ -2194,9 +3134,13 @@ func (s *ScalewayAPI) GetSnapshotID(needle string) (string, error) {
 		return snapshots[0].Identifier, nil
 	}
 	if len(snapshots) == 0 {
-		return "", fmt.Errorf("No such snapshot: %s", needle)
+		return "", newNotFoundError("No such snapshot: %s", needle)
 	}
-	return "", showResolverResults(needle, snapshots)
+	i, err := s.chooseResolverResult(needle, snapshots)
+	if err != nil {
+		return "", err
+	}
+	return snapshots[i].Identifier, nil
 }
 
 // FilterImagesByArch removes entry that doesn't match with architecture
@@ -2225,10 +3169,27 @@ func FilterImagesByRegion(res ScalewayResolverResults, region string) (ret Scale
 	return
 }
 
+// ResolveImageAlias returns the image an alias points to for the region and arch, or name if it is not an alias
+func (s *ScalewayAPI) ResolveImageAlias(name, arch string) string {
+	alias, ok := s.ImageAliases[name]
+	if !ok {
+		return name
+	}
+	for _, key := range []string{s.Region + "/" + arch, s.Region, arch, "*"} {
+		if image, ok := alias[key]; ok && image != "" {
+			s.Logger.Debugf("Image alias %s resolved to %s (%s)", name, image, key)
+			return image
+		}
+	}
+	s.Logger.Warnf("Image alias %s has no image for region %s and arch %s", name, s.Region, arch)
+	return name
+}
+
 // GetImageID returns exactly one image matching
 func (s *ScalewayAPI) GetImageID(needle, arch string) (*ScalewayImageIdentifier, error) {
 	// Parses optional type prefix, i.e: "image:name" -> "name"
 	_, needle = parseNeedle(needle)
+	needle = s.ResolveImageAlias(needle, arch)
 
 	images, err := s.ResolveImage(needle)
 	if err != nil {
@@ -2236,19 +3197,22 @@ func (s *ScalewayAPI) GetImageID(needle, arch string) (*ScalewayImageIdentifier,
 	}
 	images = FilterImagesByArch(images, arch)
 	images = FilterImagesByRegion(images, s.Region)
-	if len(images) == 1 {
-		return &ScalewayImageIdentifier{
-			Identifier: images[0].Identifier,
-			Arch:       images[0].Arch,
-			// FIXME region, owner hardcoded
-			Region: images[0].Region,
-			Owner:  "",
-		}, nil
-	}
 	if len(images) == 0 {
-		return nil, fmt.Errorf("No such image (zone %s, arch %s) : %s", s.Region, arch, needle)
+		return nil, newNotFoundError("No such image (zone %s, arch %s) : %s", s.Region, arch, needle)
+	}
+	i := 0
+	if len(images) > 1 {
+		if i, err = s.chooseResolverResult(needle, images); err != nil {
+			return nil, err
+		}
 	}
-	return nil, showResolverResults(needle, images)
+	return &ScalewayImageIdentifier{
+		Identifier: images[i].Identifier,
+		Arch:       images[i].Arch,
+		// FIXME region, owner hardcoded
+		Region: images[i].Region,
+		Owner:  "",
+	}, nil
 }
 
 // GetSecurityGroups returns a ScalewaySecurityGroups
@@ -2460,6 +3424,10 @@ func (s *ScalewayAPI) GetIPS() (*ScalewayGetIPS, error) {
 	if err = json.Unmarshal(body, &ips); err != nil {
 		return nil, err
 	}
+	s.Cache.ClearIPs()
+	for _, ip := range ips.IPS {
+		s.Cache.InsertIP(ip.ID, s.Region, ip.Organization, ip.Address)
+	}
 	return &ips, nil
 }
 
@@ -2484,6 +3452,7 @@ func (s *ScalewayAPI) NewIP() (*ScalewayGetIP, error) {
 	if err = json.Unmarshal(body, &ip); err != nil {
 		return nil, err
 	}
+	s.Cache.InsertIP(ip.IP.ID, s.Region, ip.IP.Organization, ip.IP.Address)
 	return &ip, nil
 }
 
@@ -2529,6 +3498,38 @@ func (s *ScalewayAPI) DetachIP(ipID string) error {
 	return err
 }
 
+// SetIPReverse sets the reverse DNS of an IP, an empty reverse removes it
+func (s *ScalewayAPI) SetIPReverse(ipID, reverse string) error {
+	var update struct {
+		Address      string  `json:"address"`
+		ID           string  `json:"id"`
+		Reverse      *string `json:"reverse"`
+		Organization string  `json:"organization"`
+		Server       *string `json:"server"`
+	}
+
+	ip, err := s.GetIP(ipID)
+	if err != nil {
+		return err
+	}
+	update.Address = ip.IP.Address
+	update.ID = ip.IP.ID
+	update.Organization = ip.IP.Organization
+	if reverse != "" {
+		update.Reverse = &reverse
+	}
+	if ip.IP.Server != nil {
+		update.Server = &ip.IP.Server.Identifier
+	}
+	resp, err := s.PutResponse(s.computeAPI, fmt.Sprintf("ips/%s", ipID), update)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, err = s.handleHTTPError([]int{http.StatusOK}, resp)
+	return err
+}
+
 // DeleteIP deletes an IP
 func (s *ScalewayAPI) DeleteIP(ipID string) error {
 	resp, err := s.DeleteResponse(s.computeAPI, fmt.Sprintf("ips/%s", ipID))
@@ -2536,8 +3537,11 @@ func (s *ScalewayAPI) DeleteIP(ipID string) error {
 		return err
 	}
 	defer resp.Body.Close()
-	_, err = s.handleHTTPError([]int{http.StatusNoContent}, resp)
-	return err
+	if _, err = s.handleHTTPError([]int{http.StatusNoContent}, resp); err != nil {
+		return err
+	}
+	s.Cache.RemoveIP(ipID)
+	return nil
 }
 
 // GetIP returns a ScalewayGetIP
@@ -2557,12 +3561,13 @@ func (s *ScalewayAPI) GetIP(ipID string) (*ScalewayGetIP, error) {
 	if err = json.Unmarshal(body, &ip); err != nil {
 		return nil, err
 	}
+	s.Cache.InsertIP(ip.IP.ID, s.Region, ip.IP.Organization, ip.IP.Address)
 	return &ip, nil
 }
 
 // GetQuotas returns a ScalewayGetQuotas
 func (s *ScalewayAPI) GetQuotas() (*ScalewayGetQuotas, error) {
-	resp, err := s.GetResponsePaginate(AccountAPI, fmt.Sprintf("organizations/%s/quotas", s.Organization), url.Values{})
+	resp, err := s.GetResponsePaginate(s.accountAPI, fmt.Sprintf("organizations/%s/quotas", s.Organization), url.Values{})
 	if err != nil {
 		return nil, err
 	}
@@ -2580,7 +3585,7 @@ func (s *ScalewayAPI) GetQuotas() (*ScalewayGetQuotas, error) {
 	return &quotas, nil
 }
 
-// GetBootscriptID returns exactly one bootscript matching
+// GetBootscriptID returns exactly one bootscript matching, an empty arch matches any architecture
 func (s *ScalewayAPI) GetBootscriptID(needle, arch string) (string, error) {
 	// Parses optional type prefix, i.e: "bootscript:name" -> "name"
 	_, needle = parseNeedle(needle)
@@ -2589,14 +3594,20 @@ func (s *ScalewayAPI) GetBootscriptID(needle, arch string) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("Unable to resolve bootscript %s: %s", needle, err)
 	}
-	bootscripts.FilterByArch(arch)
+	if arch != "" {
+		bootscripts.FilterByArch(arch)
+	}
 	if len(bootscripts) == 1 {
 		return bootscripts[0].Identifier, nil
 	}
 	if len(bootscripts) == 0 {
-		return "", fmt.Errorf("No such bootscript: %s", needle)
+		return "", newNotFoundError("No such bootscript: %s", needle)
+	}
+	i, err := s.chooseResolverResult(needle, bootscripts)
+	if err != nil {
+		return "", err
 	}
-	return "", showResolverResults(needle, bootscripts)
+	return bootscripts[i].Identifier, nil
 }
 
 func rootNetDial(network, addr string) (net.Conn, error) {
@@ -2632,18 +3643,19 @@ func rootNetDial(network, addr string) (net.Conn, error) {
 
 // SetPassword register the password
 func (s *ScalewayAPI) SetPassword(password string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
 	s.password = password
 }
 
 // GetMarketPlaceImages returns images from marketplace
 func (s *ScalewayAPI) GetMarketPlaceImages(uuidImage string) (*MarketImages, error) {
-	resp, err := s.GetResponsePaginate(MarketplaceAPI, fmt.Sprintf("images/%s", uuidImage), url.Values{})
-	if err != nil {
-		return nil, err
+	ttl := MarketplaceTTL
+	if uuidImage != "" {
+		ttl = MarketImageTTL
 	}
-	defer resp.Body.Close()
-
-	body, err := s.handleHTTPError([]int{http.StatusOK}, resp)
+	body, err := s.getCachedBody(MarketplaceAPI, fmt.Sprintf("images/%s", uuidImage), ttl)
 	if err != nil {
 		return nil, err
 	}
@@ -2666,6 +3678,53 @@ func (s *ScalewayAPI) GetMarketPlaceImages(uuidImage string) (*MarketImages, err
 	return &ret, nil
 }
 
+// MarketLocalImageVersion describes the marketplace version a local image belongs to
+type MarketLocalImageVersion struct {
+	Image   *MarketImage
+	Version *MarketVersionDefinition
+	Local   MarketLocalImageDefinition
+
+	// Latest is the local image of the current public version for the same arch and zone, if any
+	Latest *MarketLocalImageDefinition
+}
+
+// Outdated returns true if a more recent version of the image is published for the same arch and zone
+func (m *MarketLocalImageVersion) Outdated() bool {
+	return m.Latest != nil && m.Latest.ID != m.Local.ID
+}
+
+// IndexMarketLocalImages indexes marketplace local images by identifier
+func IndexMarketLocalImages(images []MarketImage) map[string]MarketLocalImageVersion {
+	index := make(map[string]MarketLocalImageVersion)
+	for i := range images {
+		image := &images[i]
+		latest := make(map[string]MarketLocalImageDefinition)
+		for _, version := range image.Versions {
+			if version.ID != image.CurrentPublicVersion {
+				continue
+			}
+			for _, local := range version.LocalImages {
+				latest[local.Arch+"/"+local.Zone] = local
+			}
+		}
+		for j := range image.Versions {
+			version := &image.Versions[j]
+			for _, local := range version.LocalImages {
+				entry := MarketLocalImageVersion{
+					Image:   image,
+					Version: version,
+					Local:   local,
+				}
+				if current, ok := latest[local.Arch+"/"+local.Zone]; ok {
+					entry.Latest = &current
+				}
+				index[local.ID] = entry
+			}
+		}
+	}
+	return index
+}
+
 // GetMarketPlaceImageVersions returns image version
 func (s *ScalewayAPI) GetMarketPlaceImageVersions(uuidImage, uuidVersion string) (*MarketVersions, error) {
 	resp, err := s.GetResponsePaginate(MarketplaceAPI, fmt.Sprintf("images/%v/versions/%s", uuidImage, uuidVersion), url.Values{})
@@ -1,10 +1,24 @@
 package api
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/scaleway/scaleway-cli/pkg/scwversion"
 	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/sync/errgroup"
 )
 
 func TestNewScalewayAPI(t *testing.T) {
@@ -19,3 +33,529 @@ func TestNewScalewayAPI(t *testing.T) {
 		So(api.Logger, ShouldNotBeNil)
 	})
 }
+
+func TestWithAPIVersion(t *testing.T) {
+	Convey("Testing WithAPIVersion()", t, func() {
+		api, err := NewScalewayAPI("my-organization", "my-token", scwversion.UserAgent(), "par1", WithAPIVersion("/v2/"))
+		So(err, ShouldBeNil)
+		So(api.ComputeAPIURL(), ShouldEqual, strings.TrimRight(ComputeAPIPar1, "/")+"/v2/")
+		So(api.AccountAPIURL(), ShouldEqual, strings.TrimRight(AccountAPI, "/")+"/v2/")
+
+		_, err = NewScalewayAPI("my-organization", "my-token", scwversion.UserAgent(), "par1", WithAPIVersion("latest"))
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	Convey("Testing WithHTTPClient() and WithTimeout()", t, func() {
+		client := &http.Client{}
+		api, err := NewScalewayAPI("my-organization", "my-token", scwversion.UserAgent(), "par1", WithTimeout(5*time.Second), WithHTTPClient(client))
+		So(err, ShouldBeNil)
+		So(api.client.Timeout, ShouldEqual, 5*time.Second)
+		So(api.client.Transport, ShouldNotBeNil)
+		So(client.Timeout, ShouldEqual, 0)
+		So(client.Transport, ShouldBeNil)
+	})
+}
+
+func TestResolveImageAlias(t *testing.T) {
+	Convey("Testing ResolveImageAlias()", t, func() {
+		api := &ScalewayAPI{Region: "par1", Logger: NewDefaultLogger()}
+		api.ImageAliases = map[string]map[string]string{
+			"ubuntu": {
+				"par1/arm": "11111111-1111-1111-1111-111111111111",
+				"x86_64":   "ubuntu-xenial",
+				"*":        "ubuntu-trusty",
+			},
+		}
+		So(api.ResolveImageAlias("ubuntu", "arm"), ShouldEqual, "11111111-1111-1111-1111-111111111111")
+		So(api.ResolveImageAlias("ubuntu", "x86_64"), ShouldEqual, "ubuntu-xenial")
+		So(api.ResolveImageAlias("ubuntu", "arm64"), ShouldEqual, "ubuntu-trusty")
+		So(api.ResolveImageAlias("debian", "arm"), ShouldEqual, "debian")
+	})
+}
+
+func TestParseChoice(t *testing.T) {
+	Convey("Testing parseChoice()", t, func() {
+		choice, err := parseChoice("2\n", 3)
+		So(err, ShouldBeNil)
+		So(choice, ShouldEqual, 1)
+
+		_, err = parseChoice("\n", 3)
+		So(err, ShouldNotBeNil)
+		_, err = parseChoice("4", 3)
+		So(err, ShouldNotBeNil)
+		_, err = parseChoice("first", 3)
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestIsCapacityError(t *testing.T) {
+	Convey("Testing IsCapacityError()", t, func() {
+		So(IsCapacityError(ScalewayAPIError{Type: "out_of_stock"}), ShouldBeTrue)
+		So(IsCapacityError(ScalewayAPIError{APIMessage: "Out of stock for VC1S"}), ShouldBeTrue)
+		So(IsCapacityError(ScalewayAPIError{APIMessage: "server should be stopped"}), ShouldBeFalse)
+		So(IsCapacityError(nil), ShouldBeFalse)
+	})
+}
+
+func TestParseProxyURL(t *testing.T) {
+	Convey("Testing ParseProxyURL()", t, func() {
+		proxy, err := ParseProxyURL("proxy.example.com:3128")
+		So(err, ShouldBeNil)
+		So(proxy.String(), ShouldEqual, "http://proxy.example.com:3128")
+
+		proxy, err = ParseProxyURL("socks5://127.0.0.1:1080")
+		So(err, ShouldBeNil)
+		So(proxy.Scheme, ShouldEqual, "socks5")
+
+		_, err = ParseProxyURL("ftp://proxy.example.com")
+		So(err, ShouldNotBeNil)
+		_, err = ParseProxyURL("http://")
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	Convey("Testing NewTLSConfig()", t, func() {
+		config, err := NewTLSConfig(TLSOptions{InsecureSkipVerify: true})
+		So(err, ShouldBeNil)
+		So(config.InsecureSkipVerify, ShouldBeTrue)
+
+		_, err = NewTLSConfig(TLSOptions{CertFile: "client.pem"})
+		So(err, ShouldNotBeNil)
+		_, err = NewTLSConfig(TLSOptions{CAFile: "/nonexistent/ca.pem"})
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestErrorKinds(t *testing.T) {
+	Convey("Testing IsNotFound(), IsQuotaExceeded() and the other error kinds", t, func() {
+		quota := ScalewayAPIError{StatusCode: 403, Type: "quotas_exceeded"}
+		So(IsQuotaExceeded(quota), ShouldBeTrue)
+		So(IsQuotaExceeded(&quota), ShouldBeTrue)
+		So(IsPermissionDenied(quota), ShouldBeFalse)
+		So(IsQuotaExceeded(Wrapf(Wrapf(&quota, "cannot create server"), "cannot run web")), ShouldBeTrue)
+		// the kind is not guessed from the message
+		So(IsQuotaExceeded(fmt.Errorf("cannot create server: %v", quota)), ShouldBeFalse)
+		So(IsAuthFailure(ScalewayAPIError{StatusCode: 401}), ShouldBeTrue)
+		So(IsConflict(ScalewayAPIError{StatusCode: 404}), ShouldBeFalse)
+		So(IsNotFound(newNotFoundError("No such server: %s", "web")), ShouldBeTrue)
+		So(IsNotFound(errors.New("no such server")), ShouldBeFalse)
+		So(IsNotFound(nil), ShouldBeFalse)
+		So(IsDryRun(Wrapf(DryRunError{Method: "POST", URL: "servers"}, "cannot stop web")), ShouldBeTrue)
+		So(Wrapf(DryRunError{Method: "POST", URL: "servers"}, "cannot stop %s", "web").Error(), ShouldEqual, "cannot stop web: dry run, POST servers not sent")
+		So(IsDryRun(errors.New("dry run, POST servers not sent")), ShouldBeFalse)
+	})
+}
+
+// newTestAPI returns a ScalewayAPI whose compute and account APIs are served by handler
+func newTestAPI(handler http.HandlerFunc) (*ScalewayAPI, *httptest.Server) {
+	server := httptest.NewServer(handler)
+	api, err := NewScalewayAPI("my-organization", "my-token", scwversion.UserAgent(), "par1")
+	So(err, ShouldBeNil)
+	api.Logger = NewDisableLogger()
+	api.computeAPI = server.URL + "/"
+	api.accountAPI = server.URL + "/"
+	return api, server
+}
+
+func TestPing(t *testing.T) {
+	Convey("Testing Ping()", t, func() {
+		api, server := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-Auth-Token") != "my-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		})
+		defer server.Close()
+
+		_, err := api.Ping(server.URL, "servers")
+		So(err, ShouldBeNil)
+
+		api.Token = "expired-token"
+		_, err = api.Ping(server.URL, "servers")
+		So(IsAuthFailure(err), ShouldBeTrue)
+	})
+}
+
+func TestPostServerActionConflicts(t *testing.T) {
+	Convey("Testing PostServerAction() retrying on 409", t, func() {
+		defer func(interval time.Duration) { WaitPollInterval = interval }(WaitPollInterval)
+		WaitPollInterval = time.Millisecond
+
+		serverID := "11111111-1111-1111-1111-111111111111"
+		conflicts, attempts := 0, 0
+		api, server := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/servers/" + serverID:
+				fmt.Fprintf(w, `{"server": {"id": %q, "name": "web", "state": "running"}}`, serverID)
+			case "/tasks":
+				fmt.Fprint(w, `{"tasks": []}`)
+			case "/servers/" + serverID + "/action":
+				attempts++
+				if attempts <= conflicts {
+					w.WriteHeader(http.StatusConflict)
+					fmt.Fprint(w, `{"type": "conflict", "message": "a task is running on the server"}`)
+					return
+				}
+				w.WriteHeader(http.StatusAccepted)
+				fmt.Fprint(w, `{}`)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		})
+		defer server.Close()
+		api.WaitConflicts = true
+
+		Convey("409 then 202", func() {
+			conflicts = 1
+			err := api.PostServerAction(serverID, "poweroff")
+			So(err, ShouldBeNil)
+			So(attempts, ShouldEqual, 2)
+		})
+
+		Convey("409 until the retries are exhausted", func() {
+			conflicts = maxConflictRetries
+			err := api.PostServerAction(serverID, "poweroff")
+			So(IsConflict(err), ShouldBeTrue)
+			So(attempts, ShouldEqual, maxConflictRetries)
+		})
+
+		Convey("409 without WaitConflicts", func() {
+			conflicts = 1
+			api.WaitConflicts = false
+			err := api.PostServerAction(serverID, "poweroff")
+			So(IsConflict(err), ShouldBeTrue)
+			So(attempts, ShouldEqual, 1)
+		})
+	})
+}
+
+func TestPostServerActionTask(t *testing.T) {
+	Convey("Testing PostServerActionTask() parsing the task of the action", t, func() {
+		serverID := "11111111-1111-1111-1111-111111111111"
+		action := ""
+		api, server := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/servers/" + serverID:
+				fmt.Fprintf(w, `{"server": {"id": %q, "name": "web", "state": "running"}}`, serverID)
+			case "/servers/" + serverID + "/action":
+				w.WriteHeader(http.StatusAccepted)
+				fmt.Fprint(w, action)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		})
+		defer server.Close()
+
+		Convey("response with a task", func() {
+			action = `{"task": {"id": "22222222-2222-2222-2222-222222222222", "description": "server_poweroff", "status": "pending", "href_from": "/servers/11111111-1111-1111-1111-111111111111/action", "started_at": "2016-03-07T10:00:00.000000+00:00"}}`
+			task, err := api.PostServerActionTask(serverID, "poweroff")
+			So(err, ShouldBeNil)
+			So(task, ShouldNotBeNil)
+			So(task.Identifier, ShouldEqual, "22222222-2222-2222-2222-222222222222")
+			So(task.Description, ShouldEqual, "server_poweroff")
+			So(task.Status, ShouldEqual, "pending")
+			So(task.HrefFrom, ShouldEqual, "/servers/"+serverID+"/action")
+			So(task.StartDate.Value().Year(), ShouldEqual, 2016)
+			So(task.TerminationDate, ShouldBeNil)
+		})
+
+		Convey("response without a task", func() {
+			action = `{}`
+			task, err := api.PostServerActionTask(serverID, "poweroff")
+			So(err, ShouldBeNil)
+			So(task, ShouldBeNil)
+		})
+	})
+}
+
+func TestPostServerActionTransitions(t *testing.T) {
+	Convey("Testing PostServerAction() on a server in transition", t, func() {
+		serverID := "11111111-1111-1111-1111-111111111111"
+		state, gets := "", 0
+		api, server := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/servers/" + serverID:
+				gets++
+				fmt.Fprintf(w, `{"server": {"id": %q, "name": "web", "state": %q}}`, serverID, state)
+			case "/tasks":
+				fmt.Fprint(w, `{"tasks": []}`)
+			case "/servers/" + serverID + "/action":
+				if state == "stopping" {
+					w.WriteHeader(http.StatusBadRequest)
+					fmt.Fprint(w, `{"type": "invalid_request_error", "message": "server is being stopped or rebooted"}`)
+					return
+				}
+				if state == "locked" {
+					w.WriteHeader(http.StatusForbidden)
+					fmt.Fprint(w, `{"type": "authorization_required", "message": "server is locked"}`)
+					return
+				}
+				w.WriteHeader(http.StatusAccepted)
+				fmt.Fprint(w, `{}`)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		})
+		defer server.Close()
+
+		Convey("accepted action", func() {
+			state = "running"
+			err := api.PostServerAction(serverID, "poweroff")
+			So(err, ShouldBeNil)
+			So(gets, ShouldEqual, 0)
+		})
+
+		Convey("action rejected while the server is stopping", func() {
+			state = "stopping"
+			err := api.PostServerAction(serverID, "poweroff")
+			transition, ok := err.(ServerTransitionError)
+			So(ok, ShouldBeTrue)
+			So(transition.Server.State, ShouldEqual, "stopping")
+			So(IsConflict(err), ShouldBeTrue)
+		})
+
+		Convey("action rejected for another reason", func() {
+			state = "locked"
+			err := api.PostServerAction(serverID, "poweroff")
+			So(IsPermissionDenied(err), ShouldBeTrue)
+			So(gets, ShouldEqual, 0)
+		})
+	})
+}
+
+func TestMalformedResponses(t *testing.T) {
+	Convey("Testing the API responses which are not the expected JSON", t, func() {
+		serverID := "11111111-1111-1111-1111-111111111111"
+		contentType, body := "application/json", ""
+		api, server := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", contentType)
+			fmt.Fprint(w, body)
+		})
+		defer server.Close()
+
+		Convey("oversized body", func() {
+			defer func(size int64) { MaxResponseSize = size }(MaxResponseSize)
+			MaxResponseSize = 16
+			body = fmt.Sprintf(`{"server": {"id": %q}}`, serverID)
+			_, err := api.GetServer(serverID)
+			malformed, ok := err.(ScalewayMalformedResponseError)
+			So(ok, ShouldBeTrue)
+			So(malformed.Reason, ShouldEqual, "body larger than 16 bytes")
+		})
+
+		Convey("HTML page", func() {
+			contentType, body = "text/html", "<html><body>502 Bad Gateway</body></html>"
+			_, err := api.GetServer(serverID)
+			malformed, ok := err.(ScalewayMalformedResponseError)
+			So(ok, ShouldBeTrue)
+			So(malformed.StatusCode, ShouldEqual, http.StatusOK)
+			So(malformed.Reason, ShouldEqual, "got an HTML page instead of JSON")
+			So(malformed.Snippet, ShouldEqual, body)
+		})
+
+		Convey("invalid JSON", func() {
+			body = `{"server": {"id": `
+			_, err := api.GetServer(serverID)
+			malformed, ok := err.(ScalewayMalformedResponseError)
+			So(ok, ShouldBeTrue)
+			So(malformed.Reason, ShouldEqual, "invalid JSON")
+		})
+	})
+}
+
+func TestCurlCommand(t *testing.T) {
+	Convey("Testing curlCommand()", t, func() {
+		api := &ScalewayAPI{Token: "my-token"}
+		So(api.curlCommand("GET", "https://cp-par1.scaleway.com/servers", nil), ShouldEqual,
+			`curl -sS -H "X-Auth-Token: $SCW_TOKEN" -H 'Content-Type: application/json' 'https://cp-par1.scaleway.com/servers'`)
+		So(api.curlCommand("PATCH", "https://cp-par1.scaleway.com/servers/1234", []byte(`{"name": "it's"}`)), ShouldEqual,
+			`curl -sS -X PATCH -H "X-Auth-Token: $SCW_TOKEN" -H 'Content-Type: application/json' -d '{"name": "it'\''s"}' 'https://cp-par1.scaleway.com/servers/1234'`)
+	})
+}
+
+func TestScalewayAPIErrorRequestID(t *testing.T) {
+	Convey("Testing ScalewayAPIError.Error() with a request ID", t, func() {
+		err := ScalewayAPIError{StatusCode: 404, Type: "unknown_resource", APIMessage: "not found"}
+		So(err.Error(), ShouldNotContainSubstring, "RequestID")
+
+		err.RequestID = "f3a7c1d2"
+		So(err.Error(), ShouldEndWith, ", RequestID: f3a7c1d2")
+	})
+}
+
+func TestServerTransitionError(t *testing.T) {
+	Convey("Testing ServerTransitionError", t, func() {
+		server := &ScalewayServer{Name: "web", State: "starting", StateDetail: "allocating node"}
+		So(IsTransitionalState(server), ShouldBeTrue)
+		So(IsTransitionalState(&ScalewayServer{State: "running"}), ShouldBeFalse)
+
+		err := ServerTransitionError{Server: server, Action: "poweroff", Task: &ScalewayTask{Identifier: "1234", Description: "server_poweron", Status: "started"}}
+		So(err.Error(), ShouldEqual, "cannot poweroff server web while it is starting (allocating node), task 1234 (server_poweron) is started")
+		So(IsConflict(err), ShouldBeTrue)
+	})
+}
+
+func TestNameCollisionError(t *testing.T) {
+	Convey("Testing NameCollisionError", t, func() {
+		err := NameCollisionError{Kind: "server", Name: "web", Identifiers: []string{"1234", "5678"}}
+		So(err.Error(), ShouldEqual, `server name "web" is already used by 1234, 5678`)
+		So(IsConflict(err), ShouldBeTrue)
+	})
+}
+
+func TestCheckNameCollision(t *testing.T) {
+	Convey("Testing checkNameCollision() on servers", t, func() {
+		status, names := http.StatusOK, []string{}
+		api, server := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "GET" {
+				names = append(names, r.URL.Query().Get("name"))
+			}
+			w.WriteHeader(status)
+			if status != http.StatusOK {
+				fmt.Fprint(w, `{"type": "internal_error", "message": "internal error"}`)
+				return
+			}
+			fmt.Fprint(w, `{"servers": [
+				{"id": "11111111-1111-1111-1111-111111111111", "name": "web", "organization": "my-organization"},
+				{"id": "22222222-2222-2222-2222-222222222222", "name": "web-2", "organization": "my-organization"}
+			]}`)
+		})
+		defer server.Close()
+		otherRegion := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			names = append(names, "other region")
+		}))
+		defer otherRegion.Close()
+		defer func(par1, ams1 string) { ComputeAPIPar1, ComputeAPIAms1 = par1, ams1 }(ComputeAPIPar1, ComputeAPIAms1)
+		ComputeAPIPar1, ComputeAPIAms1 = otherRegion.URL+"/", otherRegion.URL+"/"
+		api.UniqueNames = true
+
+		Convey("name already used", func() {
+			err := api.checkNameCollision("server", "web")
+			collision, ok := err.(NameCollisionError)
+			So(ok, ShouldBeTrue)
+			So(collision.Identifiers, ShouldResemble, []string{"11111111-1111-1111-1111-111111111111"})
+			So(names, ShouldResemble, []string{"web"})
+		})
+
+		Convey("name not used", func() {
+			So(api.checkNameCollision("server", "we"), ShouldBeNil)
+		})
+
+		Convey("listing failure", func() {
+			status = http.StatusInternalServerError
+			So(api.checkNameCollision("server", "web"), ShouldNotBeNil)
+
+			api.UniqueNames = false
+			So(api.checkNameCollision("server", "web"), ShouldBeNil)
+		})
+	})
+}
+
+func TestDryRun(t *testing.T) {
+	Convey("Testing ScalewayAPI.DryRun", t, func() {
+		var out bytes.Buffer
+		s := &ScalewayAPI{DryRun: &out}
+		_, err := s.PostResponse("https://cp-par1.scaleway.com", "servers/abc/action", map[string]string{"action": "terminate"})
+		So(err, ShouldResemble, DryRunError{Method: "POST", URL: "https://cp-par1.scaleway.com/servers/abc/action"})
+		So(out.String(), ShouldEqual, "POST https://cp-par1.scaleway.com/servers/abc/action\n{\n  \"action\": \"terminate\"\n}\n")
+	})
+}
+
+func TestIsRetryable(t *testing.T) {
+	Convey("Testing isRetryable()", t, func() {
+		unavailable := &http.Response{StatusCode: http.StatusServiceUnavailable}
+		badGateway := &http.Response{StatusCode: http.StatusBadGateway}
+		notFound := &http.Response{StatusCode: http.StatusNotFound}
+		So(isRetryable("GET", unavailable, nil), ShouldBeTrue)
+		So(isRetryable("GET", badGateway, nil), ShouldBeTrue)
+		So(isRetryable("GET", notFound, nil), ShouldBeFalse)
+		So(isRetryable("POST", unavailable, nil), ShouldBeTrue)
+		So(isRetryable("POST", badGateway, nil), ShouldBeFalse)
+
+		reset := &url.Error{Op: "Get", URL: "https://cp-par1.scaleway.com/servers", Err: &net.OpError{Op: "read", Err: syscall.ECONNRESET}}
+		refused := &url.Error{Op: "Post", URL: "https://cp-par1.scaleway.com/servers", Err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}}
+		So(isRetryable("GET", nil, reset), ShouldBeTrue)
+		So(isRetryable("POST", nil, reset), ShouldBeFalse)
+		So(isRetryable("POST", nil, refused), ShouldBeTrue)
+		So(isRetryable("GET", nil, ScalewayUnreachableError{}), ShouldBeFalse)
+
+		timedOut := &url.Error{Op: "Get", URL: "https://cp-par1.scaleway.com/servers", Err: context.DeadlineExceeded}
+		So(isRetryable("GET", nil, timedOut), ShouldBeFalse)
+	})
+}
+
+func TestRetryDelay(t *testing.T) {
+	Convey("Testing retryDelay()", t, func() {
+		So(retryDelay(1), ShouldBeBetweenOrEqual, RetryBaseDelay/2, RetryBaseDelay)
+		So(retryDelay(2), ShouldBeBetweenOrEqual, RetryBaseDelay, 2*RetryBaseDelay)
+		So(retryDelay(30), ShouldBeLessThanOrEqualTo, RetryMaxDelay)
+
+		// concurrent requests draw their jitter from the same source
+		var g errgroup.Group
+		for i := 0; i < 10; i++ {
+			g.Go(func() error {
+				if delay := retryDelay(3); delay < 2*RetryBaseDelay || delay > 4*RetryBaseDelay {
+					return fmt.Errorf("unexpected delay %v", delay)
+				}
+				return nil
+			})
+		}
+		So(g.Wait(), ShouldBeNil)
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	Convey("Testing retryAfter()", t, func() {
+		now := time.Date(2017, 3, 1, 12, 0, 0, 0, time.UTC)
+		resp := &http.Response{Header: http.Header{}}
+		_, ok := retryAfter(resp, now)
+		So(ok, ShouldBeFalse)
+
+		resp.Header.Set("Retry-After", "7")
+		delay, ok := retryAfter(resp, now)
+		So(ok, ShouldBeTrue)
+		So(delay, ShouldEqual, 7*time.Second)
+
+		resp.Header.Set("Retry-After", now.Add(30*time.Second).Format(http.TimeFormat))
+		delay, ok = retryAfter(resp, now)
+		So(ok, ShouldBeTrue)
+		So(delay, ShouldEqual, 30*time.Second)
+	})
+}
+
+func TestNextPageURL(t *testing.T) {
+	Convey("Testing nextPageURL()", t, func() {
+		request, _ := http.NewRequest("GET", "https://cp-par1.scaleway.com/servers?page=1", nil)
+		resp := &http.Response{Header: http.Header{}, Request: request}
+		So(nextPageURL(resp), ShouldEqual, "")
+
+		resp.Header.Set("Link", `</servers?page=2&per_page=50>; rel="next", </servers?page=4&per_page=50>; rel="last"`)
+		So(nextPageURL(resp), ShouldEqual, "https://cp-par1.scaleway.com/servers?page=2&per_page=50")
+
+		resp.Header.Set("Link", `</servers?page=1&per_page=50>; rel="first"`)
+		So(nextPageURL(resp), ShouldEqual, "")
+	})
+}
+
+func TestDecompressResponse(t *testing.T) {
+	Convey("Testing decompressResponse()", t, func() {
+		var compressed bytes.Buffer
+		writer := gzip.NewWriter(&compressed)
+		writer.Write([]byte(`{"servers": []}`))
+		writer.Close()
+
+		resp := &http.Response{Header: http.Header{}, ContentLength: int64(compressed.Len()), Body: ioutil.NopCloser(&compressed)}
+		resp.Header.Set("Content-Encoding", "gzip")
+		decompressResponse(resp)
+		body, err := readResponseBody(resp)
+		So(err, ShouldBeNil)
+		So(string(body), ShouldEqual, `{"servers": []}`)
+		So(resp.Header.Get("Content-Encoding"), ShouldEqual, "")
+
+		resp = &http.Response{Header: http.Header{"Content-Encoding": {"gzip"}}, Body: ioutil.NopCloser(&bytes.Buffer{})}
+		decompressResponse(resp)
+		So(resp.Body.Close(), ShouldBeNil)
+	})
+}
@@ -0,0 +1,87 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// BreakerThreshold is the number of consecutive failed requests to a host after which
+// the next requests to this host fail immediately
+var BreakerThreshold = 3
+
+// BreakerCooldown is the delay after the last failure before a request is sent again to a host,
+// so long running commands recover once the API is back
+var BreakerCooldown = 30 * time.Second
+
+// ScalewayUnreachableError is returned without sending the request once the circuit breaker of a host tripped
+type ScalewayUnreachableError struct {
+	Host  string
+	Since time.Time
+	Err   error
+}
+
+func (e ScalewayUnreachableError) Error() string {
+	return fmt.Sprintf("API %s unreachable since %ds: %v", e.Host, int(time.Since(e.Since).Seconds()), e.Err)
+}
+
+// hostFailures are the consecutive failed requests to a host
+type hostFailures struct {
+	count    int
+	since    time.Time
+	lastTime time.Time
+	last     error
+}
+
+// circuitBreaker counts the consecutive failed requests per host, a request fails when no response
+// is received or the response is a 502, 503 or 504
+type circuitBreaker struct {
+	lock  sync.Mutex
+	hosts map[string]*hostFailures
+}
+
+func newCircuitBreaker() *circuitBreaker {
+	return &circuitBreaker{hosts: make(map[string]*hostFailures)}
+}
+
+// check returns a ScalewayUnreachableError when host failed too many times
+func (b *circuitBreaker) check(host string) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if failures, ok := b.hosts[host]; ok && failures.count >= BreakerThreshold && time.Since(failures.lastTime) < BreakerCooldown {
+		return ScalewayUnreachableError{Host: host, Since: failures.since, Err: failures.last}
+	}
+	return nil
+}
+
+// record updates the failures of host with the outcome of a request
+func (b *circuitBreaker) record(host string, resp *http.Response, err error) {
+	if err == nil {
+		switch resp.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			err = fmt.Errorf("%s", resp.Status)
+		}
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if err == nil {
+		delete(b.hosts, host)
+		return
+	}
+	failures, ok := b.hosts[host]
+	if !ok {
+		failures = &hostFailures{since: time.Now()}
+		b.hosts[host] = failures
+	}
+	failures.count++
+	failures.lastTime = time.Now()
+	failures.last = err
+}
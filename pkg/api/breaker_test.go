@@ -0,0 +1,37 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	Convey("Testing circuitBreaker", t, func() {
+		breaker := newCircuitBreaker()
+		timeout := fmt.Errorf("i/o timeout")
+
+		breaker.record("cp-par1.scaleway.com", nil, timeout)
+		breaker.record("cp-par1.scaleway.com", &http.Response{StatusCode: http.StatusGatewayTimeout, Status: "504 Gateway Timeout"}, nil)
+		So(breaker.check("cp-par1.scaleway.com"), ShouldBeNil)
+
+		breaker.record("cp-par1.scaleway.com", nil, timeout)
+		err := breaker.check("cp-par1.scaleway.com")
+		So(err, ShouldHaveSameTypeAs, ScalewayUnreachableError{})
+		So(err.Error(), ShouldContainSubstring, "API cp-par1.scaleway.com unreachable since 0s: i/o timeout")
+		So(breaker.check("account.scaleway.com"), ShouldBeNil)
+
+		breaker.hosts["cp-par1.scaleway.com"].lastTime = time.Now().Add(-BreakerCooldown)
+		So(breaker.check("cp-par1.scaleway.com"), ShouldBeNil)
+
+		breaker = newCircuitBreaker()
+		breaker.record("cp-par1.scaleway.com", nil, timeout)
+		breaker.record("cp-par1.scaleway.com", nil, timeout)
+		breaker.record("cp-par1.scaleway.com", &http.Response{StatusCode: http.StatusNotFound}, nil)
+		breaker.record("cp-par1.scaleway.com", nil, timeout)
+		So(breaker.check("cp-par1.scaleway.com"), ShouldBeNil)
+	})
+}
@@ -50,6 +50,9 @@ type ScalewayCache struct {
 	// Servers contains names of Scaleway servers indexed by identifier
 	Servers map[string][CacheMaxfield]string `json:"servers"`
 
+	// IPs contains addresses of Scaleway IPs indexed by identifier
+	IPs map[string][CacheMaxfield]string `json:"ips"`
+
 	// Path is the path to the cache file
 	Path string `json:"-"`
 
@@ -75,6 +78,8 @@ const (
 	IdentifierBootscript
 	// IdentifierVolume is the type key of cached volume objects
 	IdentifierVolume
+	// IdentifierIP is the type key of cached IP objects
+	IdentifierIP
 )
 
 // ScalewayResolverResult is a structure containing human-readable information
@@ -135,6 +140,8 @@ func identifierTypeName(kind int) string {
 		return "Volume"
 	case IdentifierBootscript:
 		return "Bootscript"
+	case IdentifierIP:
+		return "IP"
 	}
 	return ""
 }
@@ -210,21 +217,137 @@ func NewScalewayCache(hookSave func()) (*ScalewayCache, error) {
 	if cache.Bootscripts == nil {
 		cache.Bootscripts = make(map[string][CacheMaxfield]string)
 	}
+	if cache.IPs == nil {
+		cache.IPs = make(map[string][CacheMaxfield]string)
+	}
 	return &cache, nil
 }
 
 // Clear removes all information from the cache
 func (c *ScalewayCache) Clear() {
+	c.Lock.Lock()
+	defer c.Lock.Unlock()
+
 	c.Images = make(map[string][CacheMaxfield]string)
 	c.Snapshots = make(map[string][CacheMaxfield]string)
 	c.Volumes = make(map[string][CacheMaxfield]string)
 	c.Bootscripts = make(map[string][CacheMaxfield]string)
 	c.Servers = make(map[string][CacheMaxfield]string)
+	c.IPs = make(map[string][CacheMaxfield]string)
 	c.Modified = true
 }
 
+// typeEntries returns the cached objects of the given type key, the caller holds the lock
+func (c *ScalewayCache) typeEntries(kind int) *map[string][CacheMaxfield]string {
+	switch kind {
+	case IdentifierServer:
+		return &c.Servers
+	case IdentifierImage:
+		return &c.Images
+	case IdentifierSnapshot:
+		return &c.Snapshots
+	case IdentifierVolume:
+		return &c.Volumes
+	case IdentifierBootscript:
+		return &c.Bootscripts
+	case IdentifierIP:
+		return &c.IPs
+	}
+	return nil
+}
+
+// SwapType replaces all cached objects of the given type key by entries (an empty set if nil)
+// and returns the previous ones
+func (c *ScalewayCache) SwapType(kind int, entries map[string][CacheMaxfield]string) map[string][CacheMaxfield]string {
+	c.Lock.Lock()
+	defer c.Lock.Unlock()
+
+	if entries == nil {
+		entries = make(map[string][CacheMaxfield]string)
+	}
+	target := c.typeEntries(kind)
+	if target == nil {
+		return nil
+	}
+	previous := *target
+	*target = entries
+	c.Modified = true
+	return previous
+}
+
+// SwapRegion removes the cached objects of the given type key which are in region or have no region,
+// the objects of the other regions are kept. It returns all the previous ones, to be restored with SwapType
+func (c *ScalewayCache) SwapRegion(kind int, region string) map[string][CacheMaxfield]string {
+	c.Lock.Lock()
+	defer c.Lock.Unlock()
+
+	target := c.typeEntries(kind)
+	if target == nil {
+		return nil
+	}
+	previous := *target
+	kept := make(map[string][CacheMaxfield]string)
+	for identifier, fields := range previous {
+		if fields[CacheRegion] != "" && fields[CacheRegion] != region {
+			kept[identifier] = fields
+		}
+	}
+	*target = kept
+	c.Modified = true
+	return previous
+}
+
+// Merge adds the entries of shared which are not cached yet, the local entries win.
+// The merged entries do not mark the cache as modified, they are only saved along other changes
+func (c *ScalewayCache) Merge(shared *ScalewayCache) {
+	c.Lock.Lock()
+	defer c.Lock.Unlock()
+
+	for _, pair := range []struct {
+		local  map[string][CacheMaxfield]string
+		shared map[string][CacheMaxfield]string
+	}{
+		{c.Servers, shared.Servers},
+		{c.Images, shared.Images},
+		{c.Snapshots, shared.Snapshots},
+		{c.Volumes, shared.Volumes},
+		{c.Bootscripts, shared.Bootscripts},
+		{c.IPs, shared.IPs},
+	} {
+		for identifier, fields := range pair.shared {
+			if _, exists := pair.local[identifier]; !exists {
+				pair.local[identifier] = fields
+			}
+		}
+	}
+}
+
+// MarkModified makes the next Save write the cache file
+func (c *ScalewayCache) MarkModified() {
+	c.Lock.Lock()
+	defer c.Lock.Unlock()
+
+	c.Modified = true
+}
+
+// HasServerName returns true if a cached server is named name
+func (c *ScalewayCache) HasServerName(name string) bool {
+	c.Lock.Lock()
+	defer c.Lock.Unlock()
+
+	for _, fields := range c.Servers {
+		if fields[CacheTitle] == name {
+			return true
+		}
+	}
+	return false
+}
+
 // Flush flushes the cache database
 func (c *ScalewayCache) Flush() error {
+	c.Lock.Lock()
+	defer c.Lock.Unlock()
+
 	return os.Remove(c.Path)
 }
 
@@ -455,6 +578,52 @@ func (c *ScalewayCache) LookUpBootscripts(needle string, acceptUUID bool) (Scale
 	return removeDuplicatesResults(res), nil
 }
 
+// LookUpIPs attempts to return identifiers matching an IP address or identifier
+func (c *ScalewayCache) LookUpIPs(needle string, acceptUUID bool) (ScalewayResolverResults, error) {
+	c.Lock.Lock()
+	defer c.Lock.Unlock()
+
+	var res ScalewayResolverResults
+	var exactMatches ScalewayResolverResults
+
+	if acceptUUID && anonuuid.IsUUID(needle) == nil {
+		if fields, ok := c.IPs[needle]; ok {
+			entry, err := NewScalewayResolverResult(needle, fields[CacheTitle], fields[CacheArch], fields[CacheRegion], IdentifierIP)
+			if err != nil {
+				return ScalewayResolverResults{}, err
+			}
+			entry.ComputeRankMatch(needle)
+			res = append(res, entry)
+		}
+	}
+
+	// addresses are not fuzzy-matched, "1.2.3.4" must not match "1.2.3.45"
+	for identifier, fields := range c.IPs {
+		if fields[CacheTitle] == needle {
+			entry, err := NewScalewayResolverResult(identifier, fields[CacheTitle], fields[CacheArch], fields[CacheRegion], IdentifierIP)
+			if err != nil {
+				return ScalewayResolverResults{}, err
+			}
+			entry.ComputeRankMatch(needle)
+			exactMatches = append(exactMatches, entry)
+		}
+		if strings.HasPrefix(identifier, needle) {
+			entry, err := NewScalewayResolverResult(identifier, fields[CacheTitle], fields[CacheArch], fields[CacheRegion], IdentifierIP)
+			if err != nil {
+				return ScalewayResolverResults{}, err
+			}
+			entry.ComputeRankMatch(needle)
+			res = append(res, entry)
+		}
+	}
+
+	if len(exactMatches) == 1 {
+		return exactMatches, nil
+	}
+
+	return removeDuplicatesResults(append(res, exactMatches...)), nil
+}
+
 // LookUpServers attempts to return identifiers matching a pattern
 func (c *ScalewayCache) LookUpServers(needle string, acceptUUID bool) (ScalewayResolverResults, error) {
 	c.Lock.Lock()
@@ -537,6 +706,8 @@ func parseNeedle(input string) (identifierType int, needle string) {
 			return IdentifierBootscript, parts[1]
 		case "volume":
 			return IdentifierVolume, parts[1]
+		case "ip":
+			return IdentifierIP, parts[1]
 		}
 	}
 	return IdentifierUnknown, input
@@ -622,6 +793,14 @@ func (c *ScalewayCache) LookUpIdentifiers(needle string) (ScalewayResolverResult
 			results = append(results, entry)
 		}
 	}
+
+	if identifierType&(IdentifierUnknown|IdentifierIP) > 0 {
+		ips, err := c.LookUpIPs(needle, false)
+		if err != nil {
+			return ScalewayResolverResults{}, err
+		}
+		results = append(results, ips...)
+	}
 	return results, nil
 }
 
@@ -775,6 +954,36 @@ func (c *ScalewayCache) ClearBootscripts() {
 	c.Modified = true
 }
 
+// InsertIP registers an IP in the cache
+func (c *ScalewayCache) InsertIP(identifier, region, owner, address string) {
+	c.Lock.Lock()
+	defer c.Lock.Unlock()
+
+	fields, exists := c.IPs[identifier]
+	if !exists || fields[CacheTitle] != address {
+		c.IPs[identifier] = [CacheMaxfield]string{region, "", owner, address}
+		c.Modified = true
+	}
+}
+
+// RemoveIP removes an IP from the cache
+func (c *ScalewayCache) RemoveIP(identifier string) {
+	c.Lock.Lock()
+	defer c.Lock.Unlock()
+
+	delete(c.IPs, identifier)
+	c.Modified = true
+}
+
+// ClearIPs removes all IPs from the cache
+func (c *ScalewayCache) ClearIPs() {
+	c.Lock.Lock()
+	defer c.Lock.Unlock()
+
+	c.IPs = make(map[string][CacheMaxfield]string)
+	c.Modified = true
+}
+
 // GetNbServers returns the number of servers in the cache
 func (c *ScalewayCache) GetNbServers() int {
 	c.Lock.Lock()
@@ -814,3 +1023,11 @@ func (c *ScalewayCache) GetNbBootscripts() int {
 
 	return len(c.Bootscripts)
 }
+
+// GetNbIPs returns the number of IPs in the cache
+func (c *ScalewayCache) GetNbIPs() int {
+	c.Lock.Lock()
+	defer c.Lock.Unlock()
+
+	return len(c.IPs)
+}
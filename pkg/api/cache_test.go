@@ -0,0 +1,94 @@
+package api
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLookUpIPs(t *testing.T) {
+	Convey("Testing ScalewayCache.LookUpIPs()", t, func() {
+		cache := &ScalewayCache{hookSave: func() {}}
+		cache.Clear()
+		cache.InsertIP("a2e8a4cd-4e2c-4cb4-a8ee-90f1a6a7d1a1", "par1", "orga", "51.15.1.2")
+		cache.InsertIP("b5a1a8e6-ff5c-4d4f-a3a1-3c9f3a0c1f42", "par1", "orga", "51.15.1.23")
+
+		ips, err := cache.LookUpIPs("51.15.1.2", true)
+		So(err, ShouldBeNil)
+		So(len(ips), ShouldEqual, 1)
+		So(ips[0].Identifier, ShouldEqual, "a2e8a4cd-4e2c-4cb4-a8ee-90f1a6a7d1a1")
+		So(ips[0].Type, ShouldEqual, IdentifierIP)
+
+		ips, err = cache.LookUpIPs("b5a1", true)
+		So(err, ShouldBeNil)
+		So(len(ips), ShouldEqual, 1)
+		So(ips[0].Name, ShouldEqual, "51.15.1.23")
+
+		ips, err = cache.LookUpIPs("51.15.1", true)
+		So(err, ShouldBeNil)
+		So(len(ips), ShouldEqual, 0)
+	})
+}
+
+func TestMerge(t *testing.T) {
+	Convey("Testing ScalewayCache.Merge()", t, func() {
+		cache := &ScalewayCache{hookSave: func() {}}
+		cache.Clear()
+		cache.InsertIP("a2e8a4cd-4e2c-4cb4-a8ee-90f1a6a7d1a1", "par1", "orga", "51.15.1.2")
+		cache.Modified = false
+
+		shared := &ScalewayCache{hookSave: func() {}}
+		shared.Clear()
+		shared.InsertIP("a2e8a4cd-4e2c-4cb4-a8ee-90f1a6a7d1a1", "par1", "orga", "51.15.9.9")
+		shared.InsertIP("b5a1a8e6-ff5c-4d4f-a3a1-3c9f3a0c1f42", "par1", "orga", "51.15.1.23")
+
+		cache.Merge(shared)
+		So(len(cache.IPs), ShouldEqual, 2)
+		So(cache.IPs["a2e8a4cd-4e2c-4cb4-a8ee-90f1a6a7d1a1"][CacheTitle], ShouldEqual, "51.15.1.2")
+		So(cache.IPs["b5a1a8e6-ff5c-4d4f-a3a1-3c9f3a0c1f42"][CacheTitle], ShouldEqual, "51.15.1.23")
+		So(cache.Modified, ShouldBeFalse)
+	})
+}
+
+func TestSwapRegion(t *testing.T) {
+	Convey("Testing ScalewayCache.SwapRegion()", t, func() {
+		cache := &ScalewayCache{hookSave: func() {}}
+		cache.Clear()
+		cache.InsertServer("a2e8a4cd-4e2c-4cb4-a8ee-90f1a6a7d1a1", "par1", "x86_64", "orga", "web")
+		cache.InsertServer("b5a1a8e6-ff5c-4d4f-a3a1-3c9f3a0c1f42", "ams1", "x86_64", "orga", "web")
+
+		previous := cache.SwapRegion(IdentifierServer, "par1")
+		So(len(previous), ShouldEqual, 2)
+		So(len(cache.Servers), ShouldEqual, 1)
+		So(cache.Servers["b5a1a8e6-ff5c-4d4f-a3a1-3c9f3a0c1f42"][CacheRegion], ShouldEqual, "ams1")
+
+		cache.SwapType(IdentifierServer, previous)
+		So(len(cache.Servers), ShouldEqual, 2)
+	})
+}
+
+func TestCacheConcurrentUse(t *testing.T) {
+	Convey("Testing ScalewayCache from several goroutines", t, func() {
+		cache := &ScalewayCache{hookSave: func() {}}
+		cache.Clear()
+		wg := sync.WaitGroup{}
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 50; j++ {
+					cache.InsertServer(fmt.Sprintf("%08d-0000-4000-8000-%012d", i, j), "par1", "x86_64", "orga", fmt.Sprintf("web-%d-%d", i, j))
+					cache.LookUpServers("web", false)
+					cache.MarkModified()
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		servers, err := cache.LookUpServers("web", false)
+		So(err, ShouldBeNil)
+		So(len(servers), ShouldEqual, 400)
+	})
+}
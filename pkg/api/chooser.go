@@ -0,0 +1,88 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package api
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/scaleway/scaleway-cli/pkg/utils"
+)
+
+// promptLock prevents concurrent resolutions, i.e: 'scw rm a b', from prompting at the same time
+var promptLock sync.Mutex
+
+// candidateDetails returns the state and the creation date of a resolver result, when the API knows them
+func (s *ScalewayAPI) candidateDetails(result ScalewayResolverResult) (string, time.Time) {
+	switch result.Type {
+	case IdentifierServer:
+		if server, err := s.GetServer(result.Identifier); err == nil {
+			return server.State, server.CreationDate.Value()
+		}
+	case IdentifierSnapshot:
+		if snapshot, err := s.GetSnapshot(result.Identifier); err == nil {
+			return snapshot.State, snapshot.CreationDate.Value()
+		}
+	case IdentifierVolume:
+		if volume, err := s.GetVolume(result.Identifier); err == nil {
+			return "n/a", volume.CreationDate.Value()
+		}
+	case IdentifierImage:
+		if image, err := s.GetImage(result.Identifier); err == nil {
+			return "n/a", image.CreationDate.Value()
+		}
+	}
+	return "n/a", time.Time{}
+}
+
+// parseChoice returns the index of the candidate picked by answer, a number between 1 and count
+func parseChoice(answer string, count int) (int, error) {
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return -1, fmt.Errorf("no candidate chosen")
+	}
+	choice, err := strconv.Atoi(answer)
+	if err != nil || choice < 1 || choice > count {
+		return -1, fmt.Errorf("invalid choice %q, expected a number between 1 and %d", answer, count)
+	}
+	return choice - 1, nil
+}
+
+// chooseResolverResult returns the index of the result picked by the user when Interactive is set,
+// otherwise it lists the results and fails like before
+func (s *ScalewayAPI) chooseResolverResult(needle string, results ScalewayResolverResults) (int, error) {
+	if !s.Interactive {
+		return -1, showResolverResults(needle, results)
+	}
+	promptLock.Lock()
+	defer promptLock.Unlock()
+
+	sort.Sort(results)
+	fmt.Fprintf(os.Stderr, "Several resources match %q:\n", needle)
+	w := tabwriter.NewWriter(os.Stderr, 5, 1, 3, ' ', 0)
+	fmt.Fprintf(w, "  #\tID\tNAME\tSTATE\tCREATED\n")
+	for i, result := range results {
+		state, created := s.candidateDetails(result)
+		fmt.Fprintf(w, "  %d\t%s\t%s\t%s\t%s\n", i+1, result.TruncIdentifier(), result.CodeName(), state, utils.FormatTime(created, ""))
+	}
+	w.Flush()
+	fmt.Fprintf(os.Stderr, "Choose 1-%d: ", len(results))
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && answer == "" {
+		return -1, fmt.Errorf("Too many candidates for %s (%d)", needle, len(results))
+	}
+	choice, err := parseChoice(answer, len(results))
+	if err == nil {
+		s.Tracer.Event("choice", map[string]interface{}{"needle": needle, "identifier": results[choice].Identifier})
+	}
+	return choice, err
+}
@@ -0,0 +1,150 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package api
+
+import "time"
+
+// ScalewayAPIClient are the methods of ScalewayAPI consumed by the commands,
+// FakeScalewayAPI implements them in memory for the tests
+type ScalewayAPIClient interface {
+	Logger
+
+	// Settings
+	OrganizationID() string
+	AuthToken() string
+	CurrentRegion() string
+	ResolverCache() *ScalewayCache
+	RequestTracer() *Tracer
+	SetRefreshResponses(refresh bool)
+	HideAPICredentials(input string) string
+	Sync()
+
+	// Endpoints
+	ComputeAPIURL() string
+	AccountAPIURL() string
+	VersionedURL(apiURL string) string
+	ResolveTTYUrl() string
+	Ping(apiURL, resource string) (time.Duration, error)
+
+	// Servers
+	GetServers(all bool, limit int) (*[]ScalewayServer, error)
+	GetServer(serverID string) (*ScalewayServer, error)
+	GetServerID(needle string) (string, error)
+	ResolveServer(needle string) (ScalewayResolverResults, error)
+	PostServer(definition ScalewayServerDefinition) (string, error)
+	PatchServer(serverID string, definition ScalewayServerPatchDefinition) error
+	PostServerAction(serverID, action string) error
+	PostServerActionTask(serverID, action string) (*ScalewayTask, error)
+	GetServerPendingTask(serverID string) (*ScalewayTask, error)
+	GetSSHFingerprintFromServer(serverID string) []string
+	DeleteServer(serverID string) error
+	DeleteServerForce(serverID string) error
+
+	// Volumes
+	GetVolumes() (*[]ScalewayVolume, error)
+	GetVolume(volumeID string) (*ScalewayVolume, error)
+	GetVolumeID(needle string) (string, error)
+	PostVolume(definition ScalewayVolumeDefinition) (string, error)
+	PutVolume(volumeID string, definition ScalewayVolumePutDefinition) error
+	DeleteVolume(volumeID string) error
+
+	// Snapshots
+	GetSnapshots() (*[]ScalewaySnapshot, error)
+	GetSnapshot(snapshotID string) (*ScalewaySnapshot, error)
+	GetSnapshotID(needle string) (string, error)
+	PostSnapshot(volumeID string, name string) (string, error)
+	DeleteSnapshot(snapshotID string) error
+
+	// Images
+	GetImages() (*[]MarketImage, error)
+	GetOrganizationImages() (*[]ScalewayImage, error)
+	GetImage(imageID string) (*ScalewayImage, error)
+	GetImageID(needle, arch string) (*ScalewayImageIdentifier, error)
+	ResolveImageAlias(name, arch string) string
+	PostImage(volumeID string, name string, bootscript string, arch string) (string, error)
+	DeleteImage(imageID string) error
+
+	// Bootscripts
+	GetBootscripts() (*[]ScalewayBootscript, error)
+	GetBootscript(bootscriptID string) (*ScalewayBootscript, error)
+	GetBootscriptID(needle, arch string) (string, error)
+
+	// IPs
+	GetIPS() (*ScalewayGetIPS, error)
+	GetIP(ipID string) (*ScalewayGetIP, error)
+	GetIPID(needle string) (string, error)
+	NewIP() (*ScalewayGetIP, error)
+	AttachIP(ipID, serverID string) error
+	DetachIP(ipID string) error
+	SetIPReverse(ipID, reverse string) error
+	DeleteIP(ipID string) error
+
+	// Userdata
+	GetUserdatas(serverID string, metadata bool) (*ScalewayUserdatas, error)
+	GetUserdata(serverID, key string, metadata bool) (*ScalewayUserdata, error)
+	PatchUserdata(serverID, key string, value []byte, metadata bool) error
+	DeleteUserdata(serverID, key string, metadata bool) error
+
+	// Tasks
+	GetTasks() (*[]ScalewayTask, error)
+	GetTask(taskID string) (*ScalewayTask, error)
+	DeleteTask(taskID string) error
+
+	// Products
+	GetProductsServers() (*ScalewayProductsServers, error)
+
+	// Account
+	GetToken() (*ScalewayTokenDefinition, error)
+	GetUser() (*ScalewayUserDefinition, error)
+	GetPermissions() (*ScalewayPermissionDefinition, error)
+	GetOrganization() (*ScalewayOrganizationsDefinition, error)
+	GetOrganizationMembers(organizationID string) ([]ScalewayOrganizationMember, error)
+	GetDashboard() (*ScalewayDashboard, error)
+	GetQuotas() (*ScalewayGetQuotas, error)
+
+	// Marketplace
+	GetMarketPlaceImages(uuidImage string) (*MarketImages, error)
+	GetMarketPlaceImageVersions(uuidImage, uuidVersion string) (*MarketVersions, error)
+	GetMarketPlaceImageCurrentVersion(uuidImage string) (*MarketVersion, error)
+	GetMarketPlaceLocalImages(uuidImage, uuidVersion, uuidLocalImage string) (*MarketLocalImages, error)
+	PostMarketPlaceImage(images MarketImage) error
+	PostMarketPlaceImageVersion(uuidImage string, version MarketVersion) error
+	PostMarketPlaceLocalImage(uuidImage, uuidVersion, uuidLocalImage string, local MarketLocalImage) error
+	DeleteMarketPlaceImage(uudImage string) error
+	DeleteMarketPlaceImageVersion(uuidImage, uuidVersion string) error
+	DeleteMarketPlaceLocalImage(uuidImage, uuidVersion, uuidLocalImage string) error
+}
+
+var _ ScalewayAPIClient = (*ScalewayAPI)(nil)
+
+// OrganizationID returns the identifier of the organization
+func (s *ScalewayAPI) OrganizationID() string {
+	return s.Organization
+}
+
+// AuthToken returns the authentication token
+func (s *ScalewayAPI) AuthToken() string {
+	return s.Token
+}
+
+// CurrentRegion returns the region the requests are sent to
+func (s *ScalewayAPI) CurrentRegion() string {
+	return s.Region
+}
+
+// ResolverCache returns the cache used to resolve identifiers from names
+func (s *ScalewayAPI) ResolverCache() *ScalewayCache {
+	return s.Cache
+}
+
+// RequestTracer returns the Tracer, nil when tracing is disabled
+func (s *ScalewayAPI) RequestTracer() *Tracer {
+	return s.Tracer
+}
+
+// SetRefreshResponses makes the next requests fetch again the responses kept in ResponseCache
+func (s *ScalewayAPI) SetRefreshResponses(refresh bool) {
+	s.RefreshResponses = refresh
+}
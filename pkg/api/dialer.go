@@ -0,0 +1,110 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+// HappyEyeballsDelay is the head start given to the preferred address family before dialing the other one
+var HappyEyeballsDelay = 300 * time.Millisecond
+
+// happyEyeballsDialer returns a DialContext racing the IPv6 and IPv4 addresses of a host (RFC 6555),
+// the preferred family is dialed first and the other one HappyEyeballsDelay later or as soon as it fails
+func happyEyeballsDialer(preferIPv6 bool) func(ctx context.Context, network, address string) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		primary, fallback := splitAddressFamilies(addrs, preferIPv6)
+		if len(primary) == 0 {
+			primary, fallback = fallback, nil
+		}
+
+		type dialResult struct {
+			conn net.Conn
+			err  error
+		}
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		results := make(chan dialResult, 2)
+		dialAll := func(ips []net.IP) {
+			err := fmt.Errorf("no address for %s", host)
+			for _, ip := range ips {
+				var conn net.Conn
+				if conn, err = dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port)); err == nil {
+					results <- dialResult{conn: conn}
+					return
+				}
+			}
+			results <- dialResult{err: err}
+		}
+
+		go dialAll(primary)
+		pending := 1
+		var timer <-chan time.Time
+		if len(fallback) > 0 {
+			timer = time.After(HappyEyeballsDelay)
+		}
+		var firstErr error
+		for {
+			select {
+			case <-timer:
+				timer = nil
+				if fallback != nil {
+					go dialAll(fallback)
+					fallback = nil
+					pending++
+				}
+			case result := <-results:
+				pending--
+				if result.err == nil {
+					// a late connection of the other family is closed
+					go func(pending int) {
+						for ; pending > 0; pending-- {
+							if late := <-results; late.conn != nil {
+								late.conn.Close()
+							}
+						}
+					}(pending)
+					return result.conn, nil
+				}
+				if firstErr == nil {
+					firstErr = result.err
+				}
+				if fallback != nil {
+					go dialAll(fallback)
+					fallback = nil
+					pending++
+					continue
+				}
+				if pending == 0 {
+					return nil, firstErr
+				}
+			}
+		}
+	}
+}
+
+// splitAddressFamilies returns the addresses of the preferred family and the others, in resolver order
+func splitAddressFamilies(addrs []net.IPAddr, preferIPv6 bool) (preferred, others []net.IP) {
+	for _, addr := range addrs {
+		if (addr.IP.To4() == nil) == preferIPv6 {
+			preferred = append(preferred, addr.IP)
+		} else {
+			others = append(others, addr.IP)
+		}
+	}
+	return preferred, others
+}
@@ -0,0 +1,163 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// DryRunError is returned instead of sending a request which modifies a resource, see ScalewayAPI.DryRun
+type DryRunError struct {
+	Method string
+	URL    string
+}
+
+func (e DryRunError) Error() string {
+	return fmt.Sprintf("dry run, %s %s not sent", e.Method, e.URL)
+}
+
+// wrappedError is an error formatted with the message of another one, whose kind it keeps, see Wrapf
+type wrappedError struct {
+	message string
+	cause   error
+}
+
+// Wrapf returns err prefixed with a formatted message, the IsNotFound, IsDryRun, ... kinds of err are kept
+func Wrapf(err error, format string, a ...interface{}) error {
+	return wrappedError{message: fmt.Sprintf(format, a...), cause: err}
+}
+
+func (e wrappedError) Error() string {
+	return e.message + ": " + e.cause.Error()
+}
+
+// Cause returns the wrapped error
+func (e wrappedError) Cause() error {
+	return e.cause
+}
+
+// rootCause returns the error wrapped by err with Wrapf, or err itself
+func rootCause(err error) error {
+	for {
+		wrapped, ok := err.(interface {
+			Cause() error
+		})
+		if !ok {
+			return err
+		}
+		err = wrapped.Cause()
+	}
+}
+
+// asAPIError returns the ScalewayAPIError err is, or the one it wraps
+func asAPIError(err error) (*ScalewayAPIError, bool) {
+	switch e := rootCause(err).(type) {
+	case *ScalewayAPIError:
+		return e, e != nil
+	case ScalewayAPIError:
+		return &e, true
+	}
+	return nil, false
+}
+
+// IsNotFound reports whether err is a resource which does not exist, on the API or when resolving a name
+func IsNotFound(err error) bool {
+	if _, ok := rootCause(err).(notFoundError); ok {
+		return true
+	}
+	e, ok := asAPIError(err)
+	return ok && e.StatusCode == http.StatusNotFound
+}
+
+// IsAuthFailure reports whether err is an invalid or expired token
+func IsAuthFailure(err error) bool {
+	e, ok := asAPIError(err)
+	return ok && e.StatusCode == http.StatusUnauthorized
+}
+
+// IsPermissionDenied reports whether err is a token not allowed to perform the request
+func IsPermissionDenied(err error) bool {
+	e, ok := asAPIError(err)
+	return ok && e.StatusCode == http.StatusForbidden && !e.isQuota()
+}
+
+// IsQuotaExceeded reports whether err is a request exceeding a quota of the organization
+func IsQuotaExceeded(err error) bool {
+	e, ok := asAPIError(err)
+	return ok && e.StatusCode == http.StatusForbidden && e.isQuota()
+}
+
+// IsConflict reports whether err is a request rejected because of a task in progress on the resource,
+// or because of a name already used
+func IsConflict(err error) bool {
+	switch rootCause(err).(type) {
+	case ServerTransitionError, NameCollisionError:
+		return true
+	}
+	e, ok := asAPIError(err)
+	return ok && e.StatusCode == http.StatusConflict
+}
+
+// IsDryRun reports whether err is a request not sent because of ScalewayAPI.DryRun
+func IsDryRun(err error) bool {
+	_, ok := rootCause(err).(DryRunError)
+	return ok
+}
+
+func (e ScalewayAPIError) isQuota() bool {
+	return strings.Contains(strings.ToLower(e.Type+" "+e.APIMessage), "quota")
+}
+
+// ServerTransitionError is a server action rejected because the server is starting, stopping or allocating,
+// see ScalewayAPI.WaitTransitions
+type ServerTransitionError struct {
+	Server *ScalewayServer
+	Action string
+
+	// Task is the task in progress on the server, nil when it is unknown
+	Task *ScalewayTask
+}
+
+func (e ServerTransitionError) Error() string {
+	name := e.Server.Name
+	if name == "" {
+		name = e.Server.Identifier
+	}
+	state := e.Server.State
+	if e.Server.StateDetail != "" {
+		state = fmt.Sprintf("%s (%s)", state, e.Server.StateDetail)
+	}
+	message := fmt.Sprintf("cannot %s server %s while it is %s", e.Action, name, state)
+	if e.Task != nil {
+		message += fmt.Sprintf(", task %s (%s) is %s", e.Task.Identifier, e.Task.Description, e.Task.Status)
+	}
+	return message
+}
+
+// NameCollisionError is a server or an image not created because its name is already used, see ScalewayAPI.UniqueNames
+type NameCollisionError struct {
+	Kind        string
+	Name        string
+	Identifiers []string
+}
+
+func (e NameCollisionError) Error() string {
+	return fmt.Sprintf("%s name %q is already used by %s", e.Kind, e.Name, strings.Join(e.Identifiers, ", "))
+}
+
+// notFoundError is a name which does not resolve to any resource
+type notFoundError struct {
+	message string
+}
+
+func newNotFoundError(format string, a ...interface{}) error {
+	return notFoundError{message: fmt.Sprintf(format, a...)}
+}
+
+func (e notFoundError) Error() string {
+	return e.message
+}
@@ -0,0 +1,1085 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package api
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FakeScalewayAPI is an in-memory ScalewayAPIClient, the tests fill its resources then run the commands against it
+type FakeScalewayAPI struct {
+	Logger
+
+	Organization string
+	Token        string
+	Region       string
+
+	// Cache is the in-memory resolver cache, it is never saved
+	Cache *ScalewayCache
+
+	Servers      []ScalewayServer
+	Volumes      []ScalewayVolume
+	Snapshots    []ScalewaySnapshot
+	Images       []ScalewayImage
+	MarketImages []MarketImage
+	Bootscripts  []ScalewayBootscript
+	IPs          []ScalewayIPDefinition
+	Tasks        []ScalewayTask
+	Products     ScalewayProductsServers
+
+	// Userdatas are the user data of the servers, by server identifier then key
+	Userdatas map[string]map[string][]byte
+
+	// Actions records the server actions, i.e: "SERVERID poweron"
+	Actions []string
+
+	lock   sync.Mutex
+	lastID int
+}
+
+var _ ScalewayAPIClient = (*FakeScalewayAPI)(nil)
+
+// NewFakeScalewayAPI returns an empty FakeScalewayAPI
+func NewFakeScalewayAPI(organization string) *FakeScalewayAPI {
+	cache := &ScalewayCache{hookSave: func() {}}
+	cache.Clear()
+	return &FakeScalewayAPI{
+		Logger:       NewDisableLogger(),
+		Organization: organization,
+		Region:       "par1",
+		Cache:        cache,
+		Userdatas:    make(map[string]map[string][]byte),
+	}
+}
+
+// errFakeUnsupported is returned by the methods of FakeScalewayAPI the tests have no use for
+func errFakeUnsupported(method string) error {
+	return fmt.Errorf("FakeScalewayAPI does not implement %s", method)
+}
+
+// OrganizationID returns Organization
+func (f *FakeScalewayAPI) OrganizationID() string {
+	return f.Organization
+}
+
+// AuthToken returns Token
+func (f *FakeScalewayAPI) AuthToken() string {
+	return f.Token
+}
+
+// CurrentRegion returns Region
+func (f *FakeScalewayAPI) CurrentRegion() string {
+	return f.Region
+}
+
+// ResolverCache returns Cache
+func (f *FakeScalewayAPI) ResolverCache() *ScalewayCache {
+	return f.Cache
+}
+
+// RequestTracer returns nil, the fake traces nothing
+func (f *FakeScalewayAPI) RequestTracer() *Tracer {
+	return nil
+}
+
+// SetRefreshResponses does nothing, the fake keeps no responses
+func (f *FakeScalewayAPI) SetRefreshResponses(refresh bool) {
+}
+
+// HideAPICredentials replaces Token and Organization in input
+func (f *FakeScalewayAPI) HideAPICredentials(input string) string {
+	output := input
+	if f.Token != "" {
+		output = strings.Replace(output, f.Token, "00000000-0000-4000-8000-000000000000", -1)
+	}
+	if f.Organization != "" {
+		output = strings.Replace(output, f.Organization, "00000000-0000-5000-9000-000000000000", -1)
+	}
+	return output
+}
+
+// Sync does nothing, the fake sends no requests in the background
+func (f *FakeScalewayAPI) Sync() {
+}
+
+// ComputeAPIURL returns a placeholder URL, the fake sends no requests
+func (f *FakeScalewayAPI) ComputeAPIURL() string {
+	return "fake://compute"
+}
+
+// AccountAPIURL returns a placeholder URL, the fake sends no requests
+func (f *FakeScalewayAPI) AccountAPIURL() string {
+	return "fake://account"
+}
+
+// VersionedURL returns apiURL unchanged
+func (f *FakeScalewayAPI) VersionedURL(apiURL string) string {
+	return apiURL
+}
+
+// ResolveTTYUrl returns a placeholder URL, the fake has no serial consoles
+func (f *FakeScalewayAPI) ResolveTTYUrl() string {
+	return "fake://tty"
+}
+
+// Ping fails, the fake sends no requests
+func (f *FakeScalewayAPI) Ping(apiURL, resource string) (time.Duration, error) {
+	return 0, errFakeUnsupported("Ping")
+}
+
+// newID returns an identifier formatted as an UUID, the caller holds the lock
+func (f *FakeScalewayAPI) newID() string {
+	f.lastID++
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", f.lastID)
+}
+
+// fakeResolve returns the identifier of the only entry whose identifier starts with needle or whose name is needle
+func fakeResolve(kind, needle string, identifiers, names []string) (string, error) {
+	_, needle = parseNeedle(needle)
+	matches := []string{}
+	for i, identifier := range identifiers {
+		if identifier == needle || names[i] == needle {
+			return identifier, nil
+		}
+		if strings.HasPrefix(identifier, needle) {
+			matches = append(matches, identifier)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", newNotFoundError("No such %s: %s", kind, needle)
+	case 1:
+		return matches[0], nil
+	}
+	return "", fmt.Errorf("Too many candidates for %s (%d)", needle, len(matches))
+}
+
+// GetServers returns the servers, the stopped ones only when all is set
+func (f *FakeScalewayAPI) GetServers(all bool, limit int) (*[]ScalewayServer, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	servers := []ScalewayServer{}
+	for _, server := range f.Servers {
+		if !all && server.State == "stopped" {
+			continue
+		}
+		servers = append(servers, server)
+		if limit > 0 && len(servers) == limit {
+			break
+		}
+	}
+	return &servers, nil
+}
+
+func (f *FakeScalewayAPI) serverIndex(serverID string) (int, error) {
+	for i, server := range f.Servers {
+		if server.Identifier == serverID {
+			return i, nil
+		}
+	}
+	return -1, newNotFoundError("No such server: %s", serverID)
+}
+
+// GetServer returns the server serverID
+func (f *FakeScalewayAPI) GetServer(serverID string) (*ScalewayServer, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	i, err := f.serverIndex(serverID)
+	if err != nil {
+		return nil, err
+	}
+	server := f.Servers[i]
+	return &server, nil
+}
+
+// GetServerID resolves a server by identifier prefix or name
+func (f *FakeScalewayAPI) GetServerID(needle string) (string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	identifiers, names := make([]string, len(f.Servers)), make([]string, len(f.Servers))
+	for i, server := range f.Servers {
+		identifiers[i], names[i] = server.Identifier, server.Name
+	}
+	return fakeResolve("server", needle, identifiers, names)
+}
+
+// ResolveServer returns the servers whose identifier starts with needle or whose name is needle
+func (f *FakeScalewayAPI) ResolveServer(needle string) (ScalewayResolverResults, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	_, needle = parseNeedle(needle)
+	results := ScalewayResolverResults{}
+	for _, server := range f.Servers {
+		if server.Name != needle && !strings.HasPrefix(server.Identifier, needle) {
+			continue
+		}
+		result, err := NewScalewayResolverResult(server.Identifier, server.Name, server.Arch, f.Region, IdentifierServer)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// PostServer creates a stopped server, its root volume is created from the image when definition has one
+func (f *FakeScalewayAPI) PostServer(definition ScalewayServerDefinition) (string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	server := ScalewayServer{
+		Identifier:        f.newID(),
+		Name:              definition.Name,
+		State:             "stopped",
+		Tags:              definition.Tags,
+		Organization:      f.Organization,
+		CommercialType:    definition.CommercialType,
+		DynamicIPRequired: definition.DynamicIPRequired,
+		EnableIPV6:        definition.EnableIPV6,
+		BootType:          definition.BootType,
+		Volumes:           make(map[string]ScalewayVolume),
+		CreationDate:      &ScalewayTime{time.Now()},
+	}
+	if definition.Image != nil {
+		for _, image := range f.Images {
+			if image.Identifier == *definition.Image {
+				server.Image = image
+				server.Arch = image.Arch
+			}
+		}
+		size := server.Image.RootVolume.Size
+		if resize, ok := definition.Volumes["0"].(*ScalewayServerVolumeDefinitionResize); ok {
+			size = resize.Size
+		}
+		server.Volumes["0"] = f.newVolume(server.Name, size, "l_ssd")
+	}
+	for index, definition := range definition.Volumes {
+		switch definition := definition.(type) {
+		case *ScalewayServerVolumeDefinitionNew:
+			server.Volumes[index] = f.newVolume(definition.Name, definition.Size, definition.VolumeType)
+		case *ScalewayServerVolumeDefinitionFromSnapshot:
+			var size uint64
+			for _, snapshot := range f.Snapshots {
+				if snapshot.Identifier == definition.BaseSnapshot {
+					size = snapshot.Size
+				}
+			}
+			server.Volumes[index] = f.newVolume(definition.Name, size, definition.VolumeType)
+		case ScalewayServerVolumeDefinitionFromId:
+			for _, volume := range f.Volumes {
+				if volume.Identifier == string(definition) {
+					server.Volumes[index] = volume
+				}
+			}
+		}
+	}
+	f.Servers = append(f.Servers, server)
+	f.attachVolumes(len(f.Servers) - 1)
+	return server.Identifier, nil
+}
+
+// attachVolumes sets the server of the volumes of the server at index i and unsets it on the volumes it no longer has,
+// the caller holds the lock
+func (f *FakeScalewayAPI) attachVolumes(i int) {
+	server := &f.Servers[i]
+	for j, volume := range f.Volumes {
+		if volume.Server != nil && volume.Server.Identifier == server.Identifier {
+			f.Volumes[j].Server = nil
+		}
+	}
+	for index, attached := range server.Volumes {
+		for j, volume := range f.Volumes {
+			if volume.Identifier != attached.Identifier {
+				continue
+			}
+			f.Volumes[j].Server = &struct {
+				Identifier string `json:"id,omitempty"`
+				Name       string `json:"name,omitempty"`
+			}{server.Identifier, server.Name}
+			server.Volumes[index] = f.Volumes[j]
+		}
+	}
+}
+
+// newVolume creates a volume, the caller holds the lock
+func (f *FakeScalewayAPI) newVolume(name string, size uint64, volumeType string) ScalewayVolume {
+	volume := ScalewayVolume{
+		Identifier:   f.newID(),
+		Name:         name,
+		Size:         size,
+		VolumeType:   volumeType,
+		Organization: f.Organization,
+		CreationDate: &ScalewayTime{time.Now()},
+	}
+	f.Volumes = append(f.Volumes, volume)
+	return volume
+}
+
+// PatchServer applies the name, the tags, the dynamic IP and the volumes of definition
+func (f *FakeScalewayAPI) PatchServer(serverID string, definition ScalewayServerPatchDefinition) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	i, err := f.serverIndex(serverID)
+	if err != nil {
+		return err
+	}
+	if definition.Name != nil {
+		f.Servers[i].Name = *definition.Name
+	}
+	if definition.Tags != nil {
+		f.Servers[i].Tags = *definition.Tags
+	}
+	if definition.DynamicIPRequired != nil {
+		f.Servers[i].DynamicIPRequired = definition.DynamicIPRequired
+	}
+	if definition.Volumes != nil {
+		volumes := make(map[string]ScalewayVolume)
+		for index, attached := range *definition.Volumes {
+			for _, volume := range f.Volumes {
+				if volume.Identifier == attached.Identifier {
+					volumes[index] = volume
+				}
+			}
+		}
+		f.Servers[i].Volumes = volumes
+		f.attachVolumes(i)
+	}
+	f.Servers[i].ModificationDate = &ScalewayTime{time.Now()}
+	return nil
+}
+
+// PostServerAction records action and moves the server to the state it reaches
+func (f *FakeScalewayAPI) PostServerAction(serverID, action string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	i, err := f.serverIndex(serverID)
+	if err != nil {
+		return err
+	}
+	f.Actions = append(f.Actions, serverID+" "+action)
+	switch action {
+	case "poweron", "reboot":
+		f.Servers[i].State = "running"
+	case "poweroff":
+		f.Servers[i].State = "stopped"
+	case "terminate":
+		f.removeServer(i, true)
+	default:
+		return fmt.Errorf("unknown action %s", action)
+	}
+	return nil
+}
+
+// PostServerActionTask runs PostServerAction and returns a successful task
+func (f *FakeScalewayAPI) PostServerActionTask(serverID, action string) (*ScalewayTask, error) {
+	if err := f.PostServerAction(serverID, action); err != nil {
+		return nil, err
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	task := ScalewayTask{
+		Identifier:  f.newID(),
+		HrefFrom:    fmt.Sprintf("/servers/%s/action", serverID),
+		Description: fmt.Sprintf("server_%s", action),
+		Status:      "success",
+		Progress:    100,
+	}
+	f.Tasks = append(f.Tasks, task)
+	return &task, nil
+}
+
+// GetServerPendingTask returns the last unfinished task of Tasks started by an action on the server serverID
+func (f *FakeScalewayAPI) GetServerPendingTask(serverID string) (*ScalewayTask, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return serverPendingTask(f.Tasks, serverID), nil
+}
+
+// GetSSHFingerprintFromServer returns the lines of the ssh-host-fingerprints user data
+func (f *FakeScalewayAPI) GetSSHFingerprintFromServer(serverID string) []string {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	ret := []string{}
+	if value, ok := f.Userdatas[serverID]["ssh-host-fingerprints"]; ok {
+		for _, line := range strings.Split(string(value), "\n") {
+			if line != "" {
+				ret = append(ret, line)
+			}
+		}
+	}
+	return ret
+}
+
+// removeServer deletes the server at index i and its volumes if withVolumes is set, the caller holds the lock
+func (f *FakeScalewayAPI) removeServer(i int, withVolumes bool) {
+	if withVolumes {
+		for _, attached := range f.Servers[i].Volumes {
+			for j, volume := range f.Volumes {
+				if volume.Identifier == attached.Identifier {
+					f.Volumes = append(f.Volumes[:j], f.Volumes[j+1:]...)
+					break
+				}
+			}
+		}
+	}
+	f.Servers = append(f.Servers[:i], f.Servers[i+1:]...)
+}
+
+// DeleteServer deletes a stopped server, its volumes are kept
+func (f *FakeScalewayAPI) DeleteServer(serverID string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	i, err := f.serverIndex(serverID)
+	if err != nil {
+		return err
+	}
+	if f.Servers[i].State != "stopped" {
+		return fmt.Errorf("server %s should be stopped", serverID)
+	}
+	f.removeServer(i, false)
+	return nil
+}
+
+// DeleteServerForce deletes a server with its volumes, whatever its state
+func (f *FakeScalewayAPI) DeleteServerForce(serverID string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	i, err := f.serverIndex(serverID)
+	if err != nil {
+		return err
+	}
+	f.removeServer(i, true)
+	return nil
+}
+
+// GetVolumes returns the volumes
+func (f *FakeScalewayAPI) GetVolumes() (*[]ScalewayVolume, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	volumes := append([]ScalewayVolume{}, f.Volumes...)
+	return &volumes, nil
+}
+
+// GetVolume returns the volume volumeID
+func (f *FakeScalewayAPI) GetVolume(volumeID string) (*ScalewayVolume, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for _, volume := range f.Volumes {
+		if volume.Identifier == volumeID {
+			return &volume, nil
+		}
+	}
+	return nil, newNotFoundError("No such volume: %s", volumeID)
+}
+
+// GetVolumeID resolves a volume by identifier prefix or name
+func (f *FakeScalewayAPI) GetVolumeID(needle string) (string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	identifiers, names := make([]string, len(f.Volumes)), make([]string, len(f.Volumes))
+	for i, volume := range f.Volumes {
+		identifiers[i], names[i] = volume.Identifier, volume.Name
+	}
+	return fakeResolve("volume", needle, identifiers, names)
+}
+
+// PostVolume creates a volume
+func (f *FakeScalewayAPI) PostVolume(definition ScalewayVolumeDefinition) (string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.newVolume(definition.Name, definition.Size, definition.Type).Identifier, nil
+}
+
+// PutVolume applies the name and the size of definition
+func (f *FakeScalewayAPI) PutVolume(volumeID string, definition ScalewayVolumePutDefinition) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for i, volume := range f.Volumes {
+		if volume.Identifier != volumeID {
+			continue
+		}
+		if definition.Name != nil {
+			f.Volumes[i].Name = *definition.Name
+		}
+		if definition.Size != nil {
+			f.Volumes[i].Size = *definition.Size
+		}
+		f.Volumes[i].ModificationDate = &ScalewayTime{time.Now()}
+		return nil
+	}
+	return newNotFoundError("No such volume: %s", volumeID)
+}
+
+// DeleteVolume deletes the volume volumeID
+func (f *FakeScalewayAPI) DeleteVolume(volumeID string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for i, volume := range f.Volumes {
+		if volume.Identifier == volumeID {
+			f.Volumes = append(f.Volumes[:i], f.Volumes[i+1:]...)
+			return nil
+		}
+	}
+	return newNotFoundError("No such volume: %s", volumeID)
+}
+
+// GetSnapshots returns the snapshots
+func (f *FakeScalewayAPI) GetSnapshots() (*[]ScalewaySnapshot, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	snapshots := append([]ScalewaySnapshot{}, f.Snapshots...)
+	return &snapshots, nil
+}
+
+// GetSnapshot returns the snapshot snapshotID
+func (f *FakeScalewayAPI) GetSnapshot(snapshotID string) (*ScalewaySnapshot, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for _, snapshot := range f.Snapshots {
+		if snapshot.Identifier == snapshotID {
+			return &snapshot, nil
+		}
+	}
+	return nil, newNotFoundError("No such snapshot: %s", snapshotID)
+}
+
+// GetSnapshotID resolves a snapshot by identifier prefix or name
+func (f *FakeScalewayAPI) GetSnapshotID(needle string) (string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	identifiers, names := make([]string, len(f.Snapshots)), make([]string, len(f.Snapshots))
+	for i, snapshot := range f.Snapshots {
+		identifiers[i], names[i] = snapshot.Identifier, snapshot.Name
+	}
+	return fakeResolve("snapshot", needle, identifiers, names)
+}
+
+// PostSnapshot creates a snapshot of the volume volumeID, it is immediately snapshotted
+func (f *FakeScalewayAPI) PostSnapshot(volumeID string, name string) (string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for _, volume := range f.Volumes {
+		if volume.Identifier != volumeID {
+			continue
+		}
+		snapshot := ScalewaySnapshot{
+			Identifier:   f.newID(),
+			Name:         name,
+			Size:         volume.Size,
+			Organization: f.Organization,
+			State:        "snapshotted",
+			VolumeType:   volume.VolumeType,
+			BaseVolume:   volume,
+			CreationDate: &ScalewayTime{time.Now()},
+		}
+		f.Snapshots = append(f.Snapshots, snapshot)
+		return snapshot.Identifier, nil
+	}
+	return "", newNotFoundError("No such volume: %s", volumeID)
+}
+
+// DeleteSnapshot deletes the snapshot snapshotID
+func (f *FakeScalewayAPI) DeleteSnapshot(snapshotID string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for i, snapshot := range f.Snapshots {
+		if snapshot.Identifier == snapshotID {
+			f.Snapshots = append(f.Snapshots[:i], f.Snapshots[i+1:]...)
+			return nil
+		}
+	}
+	return newNotFoundError("No such snapshot: %s", snapshotID)
+}
+
+// GetImages returns the marketplace images
+func (f *FakeScalewayAPI) GetImages() (*[]MarketImage, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	images := append([]MarketImage{}, f.MarketImages...)
+	return &images, nil
+}
+
+// GetOrganizationImages returns the images
+func (f *FakeScalewayAPI) GetOrganizationImages() (*[]ScalewayImage, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	images := append([]ScalewayImage{}, f.Images...)
+	return &images, nil
+}
+
+// GetImage returns the image imageID
+func (f *FakeScalewayAPI) GetImage(imageID string) (*ScalewayImage, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for _, image := range f.Images {
+		if image.Identifier == imageID {
+			return &image, nil
+		}
+	}
+	return nil, newNotFoundError("No such image: %s", imageID)
+}
+
+// GetImageID resolves an image of arch, any arch when empty, by identifier prefix or name
+func (f *FakeScalewayAPI) GetImageID(needle, arch string) (*ScalewayImageIdentifier, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	images := []ScalewayImage{}
+	for _, image := range f.Images {
+		if arch == "" || image.Arch == arch {
+			images = append(images, image)
+		}
+	}
+	identifiers, names := make([]string, len(images)), make([]string, len(images))
+	for i, image := range images {
+		identifiers[i], names[i] = image.Identifier, image.Name
+	}
+	identifier, err := fakeResolve("image", needle, identifiers, names)
+	if err != nil {
+		return nil, err
+	}
+	for _, image := range images {
+		if image.Identifier == identifier {
+			return &ScalewayImageIdentifier{
+				Identifier: image.Identifier,
+				Arch:       image.Arch,
+				Region:     "par1",
+				Owner:      image.Organization,
+			}, nil
+		}
+	}
+	return nil, newNotFoundError("No such image: %s", needle)
+}
+
+// ResolveImageAlias returns name, the fake has no image aliases
+func (f *FakeScalewayAPI) ResolveImageAlias(name, arch string) string {
+	return name
+}
+
+// PostImage creates an image from the snapshot volumeID
+func (f *FakeScalewayAPI) PostImage(volumeID string, name string, bootscript string, arch string) (string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for _, snapshot := range f.Snapshots {
+		if snapshot.Identifier != volumeID {
+			continue
+		}
+		image := ScalewayImage{
+			Identifier:   f.newID(),
+			Name:         name,
+			Arch:         arch,
+			Organization: f.Organization,
+			RootVolume:   ScalewayVolume{Identifier: snapshot.Identifier, Size: snapshot.Size},
+			CreationDate: &ScalewayTime{time.Now()},
+		}
+		if bootscript != "" {
+			image.DefaultBootscript = &ScalewayBootscript{Identifier: bootscript}
+		}
+		f.Images = append(f.Images, image)
+		return image.Identifier, nil
+	}
+	return "", newNotFoundError("No such snapshot: %s", volumeID)
+}
+
+// DeleteImage deletes the image imageID
+func (f *FakeScalewayAPI) DeleteImage(imageID string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for i, image := range f.Images {
+		if image.Identifier == imageID {
+			f.Images = append(f.Images[:i], f.Images[i+1:]...)
+			return nil
+		}
+	}
+	return newNotFoundError("No such image: %s", imageID)
+}
+
+// GetBootscripts returns the bootscripts
+func (f *FakeScalewayAPI) GetBootscripts() (*[]ScalewayBootscript, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	bootscripts := append([]ScalewayBootscript{}, f.Bootscripts...)
+	return &bootscripts, nil
+}
+
+// GetBootscript returns the bootscript bootscriptID
+func (f *FakeScalewayAPI) GetBootscript(bootscriptID string) (*ScalewayBootscript, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for _, bootscript := range f.Bootscripts {
+		if bootscript.Identifier == bootscriptID {
+			return &bootscript, nil
+		}
+	}
+	return nil, newNotFoundError("No such bootscript: %s", bootscriptID)
+}
+
+// GetBootscriptID resolves a bootscript of arch, any arch when empty, by identifier prefix or title
+func (f *FakeScalewayAPI) GetBootscriptID(needle, arch string) (string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	identifiers, titles := []string{}, []string{}
+	for _, bootscript := range f.Bootscripts {
+		if arch == "" || bootscript.Arch == arch {
+			identifiers = append(identifiers, bootscript.Identifier)
+			titles = append(titles, bootscript.Title)
+		}
+	}
+	return fakeResolve("bootscript", needle, identifiers, titles)
+}
+
+// GetIPS returns the flexible IPs
+func (f *FakeScalewayAPI) GetIPS() (*ScalewayGetIPS, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return &ScalewayGetIPS{IPS: append([]ScalewayIPDefinition{}, f.IPs...)}, nil
+}
+
+// GetIPID resolves a flexible IP by identifier prefix or address
+func (f *FakeScalewayAPI) GetIPID(needle string) (string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	identifiers, addresses := make([]string, len(f.IPs)), make([]string, len(f.IPs))
+	for i, ip := range f.IPs {
+		identifiers[i], addresses[i] = ip.ID, ip.Address
+	}
+	return fakeResolve("ip", needle, identifiers, addresses)
+}
+
+// GetIP returns the flexible IP ipID
+func (f *FakeScalewayAPI) GetIP(ipID string) (*ScalewayGetIP, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	i, err := f.ipIndex(ipID)
+	if err != nil {
+		return nil, err
+	}
+	return &ScalewayGetIP{IP: f.IPs[i]}, nil
+}
+
+func (f *FakeScalewayAPI) ipIndex(ipID string) (int, error) {
+	for i, ip := range f.IPs {
+		if ip.ID == ipID {
+			return i, nil
+		}
+	}
+	return -1, newNotFoundError("No such ip: %s", ipID)
+}
+
+// NewIP reserves a flexible IP, the addresses are taken in 198.51.100.0/24
+func (f *FakeScalewayAPI) NewIP() (*ScalewayGetIP, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	ip := ScalewayIPDefinition{
+		ID:           f.newID(),
+		Organization: f.Organization,
+		Address:      fmt.Sprintf("198.51.100.%d", len(f.IPs)+1),
+	}
+	f.IPs = append(f.IPs, ip)
+	return &ScalewayGetIP{IP: ip}, nil
+}
+
+// AttachIP attaches the flexible IP ipID to the server serverID
+func (f *FakeScalewayAPI) AttachIP(ipID, serverID string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	i, err := f.ipIndex(ipID)
+	if err != nil {
+		return err
+	}
+	j, err := f.serverIndex(serverID)
+	if err != nil {
+		return err
+	}
+	f.IPs[i].Server = &struct {
+		Identifier string `json:"id,omitempty"`
+		Name       string `json:"name,omitempty"`
+	}{serverID, f.Servers[j].Name}
+	f.Servers[j].PublicAddress = ScalewayIPAddress{Identifier: ipID, IP: f.IPs[i].Address}
+	return nil
+}
+
+// DetachIP detaches the flexible IP ipID from its server
+func (f *FakeScalewayAPI) DetachIP(ipID string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	i, err := f.ipIndex(ipID)
+	if err != nil {
+		return err
+	}
+	if f.IPs[i].Server != nil {
+		if j, err := f.serverIndex(f.IPs[i].Server.Identifier); err == nil {
+			f.Servers[j].PublicAddress = ScalewayIPAddress{}
+		}
+	}
+	f.IPs[i].Server = nil
+	return nil
+}
+
+// SetIPReverse sets the reverse of the flexible IP ipID
+func (f *FakeScalewayAPI) SetIPReverse(ipID, reverse string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	i, err := f.ipIndex(ipID)
+	if err != nil {
+		return err
+	}
+	f.IPs[i].Reverse = &reverse
+	return nil
+}
+
+// DeleteIP releases the flexible IP ipID
+func (f *FakeScalewayAPI) DeleteIP(ipID string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	i, err := f.ipIndex(ipID)
+	if err != nil {
+		return err
+	}
+	f.IPs = append(f.IPs[:i], f.IPs[i+1:]...)
+	return nil
+}
+
+// GetUserdatas returns the user data keys of the server serverID
+func (f *FakeScalewayAPI) GetUserdatas(serverID string, metadata bool) (*ScalewayUserdatas, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	userdatas := ScalewayUserdatas{UserData: []string{}}
+	for key := range f.Userdatas[serverID] {
+		userdatas.UserData = append(userdatas.UserData, key)
+	}
+	return &userdatas, nil
+}
+
+// GetUserdata returns the user data key of the server serverID
+func (f *FakeScalewayAPI) GetUserdata(serverID, key string, metadata bool) (*ScalewayUserdata, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	value, ok := f.Userdatas[serverID][key]
+	if !ok {
+		return nil, newNotFoundError("No such user data: %s", key)
+	}
+	userdata := ScalewayUserdata(value)
+	return &userdata, nil
+}
+
+// PatchUserdata sets the user data key of the server serverID
+func (f *FakeScalewayAPI) PatchUserdata(serverID, key string, value []byte, metadata bool) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.Userdatas[serverID] == nil {
+		f.Userdatas[serverID] = make(map[string][]byte)
+	}
+	f.Userdatas[serverID][key] = value
+	return nil
+}
+
+// DeleteUserdata deletes the user data key of the server serverID
+func (f *FakeScalewayAPI) DeleteUserdata(serverID, key string, metadata bool) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if _, ok := f.Userdatas[serverID][key]; !ok {
+		return newNotFoundError("No such user data: %s", key)
+	}
+	delete(f.Userdatas[serverID], key)
+	return nil
+}
+
+// GetTasks returns the tasks
+func (f *FakeScalewayAPI) GetTasks() (*[]ScalewayTask, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	tasks := append([]ScalewayTask{}, f.Tasks...)
+	return &tasks, nil
+}
+
+// GetTask returns the task taskID
+func (f *FakeScalewayAPI) GetTask(taskID string) (*ScalewayTask, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for _, task := range f.Tasks {
+		if task.Identifier == taskID {
+			return &task, nil
+		}
+	}
+	return nil, newNotFoundError("No such task: %s", taskID)
+}
+
+// DeleteTask deletes the task taskID
+func (f *FakeScalewayAPI) DeleteTask(taskID string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for i, task := range f.Tasks {
+		if task.Identifier == taskID {
+			f.Tasks = append(f.Tasks[:i], f.Tasks[i+1:]...)
+			return nil
+		}
+	}
+	return newNotFoundError("No such task: %s", taskID)
+}
+
+// GetProductsServers returns the commercial types of Products
+func (f *FakeScalewayAPI) GetProductsServers() (*ScalewayProductsServers, error) {
+	return &f.Products, nil
+}
+
+// GetToken fails, the fake has no account
+func (f *FakeScalewayAPI) GetToken() (*ScalewayTokenDefinition, error) {
+	return nil, errFakeUnsupported("GetToken")
+}
+
+// GetUser fails, the fake has no account
+func (f *FakeScalewayAPI) GetUser() (*ScalewayUserDefinition, error) {
+	return nil, errFakeUnsupported("GetUser")
+}
+
+// GetPermissions fails, the fake has no account
+func (f *FakeScalewayAPI) GetPermissions() (*ScalewayPermissionDefinition, error) {
+	return nil, errFakeUnsupported("GetPermissions")
+}
+
+// GetOrganization fails, the fake has no account
+func (f *FakeScalewayAPI) GetOrganization() (*ScalewayOrganizationsDefinition, error) {
+	return nil, errFakeUnsupported("GetOrganization")
+}
+
+// GetOrganizationMembers fails, the fake has no account
+func (f *FakeScalewayAPI) GetOrganizationMembers(organizationID string) ([]ScalewayOrganizationMember, error) {
+	return nil, errFakeUnsupported("GetOrganizationMembers")
+}
+
+// GetDashboard fails, the fake has no account
+func (f *FakeScalewayAPI) GetDashboard() (*ScalewayDashboard, error) {
+	return nil, errFakeUnsupported("GetDashboard")
+}
+
+// GetQuotas fails, the fake has no account
+func (f *FakeScalewayAPI) GetQuotas() (*ScalewayGetQuotas, error) {
+	return nil, errFakeUnsupported("GetQuotas")
+}
+
+// GetMarketPlaceImages returns MarketImages, or the one whose identifier is uuidImage
+func (f *FakeScalewayAPI) GetMarketPlaceImages(uuidImage string) (*MarketImages, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	images := MarketImages{Images: []MarketImage{}}
+	for _, image := range f.MarketImages {
+		if uuidImage == "" || image.ID == uuidImage {
+			images.Images = append(images.Images, image)
+		}
+	}
+	if uuidImage != "" && len(images.Images) == 0 {
+		return nil, newNotFoundError("No such image: %s", uuidImage)
+	}
+	return &images, nil
+}
+
+// GetMarketPlaceImageVersions fails, the fake has no marketplace versions
+func (f *FakeScalewayAPI) GetMarketPlaceImageVersions(uuidImage, uuidVersion string) (*MarketVersions, error) {
+	return nil, errFakeUnsupported("GetMarketPlaceImageVersions")
+}
+
+// GetMarketPlaceImageCurrentVersion fails, the fake has no marketplace versions
+func (f *FakeScalewayAPI) GetMarketPlaceImageCurrentVersion(uuidImage string) (*MarketVersion, error) {
+	return nil, errFakeUnsupported("GetMarketPlaceImageCurrentVersion")
+}
+
+// GetMarketPlaceLocalImages fails, the fake has no marketplace versions
+func (f *FakeScalewayAPI) GetMarketPlaceLocalImages(uuidImage, uuidVersion, uuidLocalImage string) (*MarketLocalImages, error) {
+	return nil, errFakeUnsupported("GetMarketPlaceLocalImages")
+}
+
+// PostMarketPlaceImage adds images to MarketImages
+func (f *FakeScalewayAPI) PostMarketPlaceImage(images MarketImage) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.MarketImages = append(f.MarketImages, images)
+	return nil
+}
+
+// PostMarketPlaceImageVersion fails, the fake has no marketplace versions
+func (f *FakeScalewayAPI) PostMarketPlaceImageVersion(uuidImage string, version MarketVersion) error {
+	return errFakeUnsupported("PostMarketPlaceImageVersion")
+}
+
+// PostMarketPlaceLocalImage fails, the fake has no marketplace versions
+func (f *FakeScalewayAPI) PostMarketPlaceLocalImage(uuidImage, uuidVersion, uuidLocalImage string, local MarketLocalImage) error {
+	return errFakeUnsupported("PostMarketPlaceLocalImage")
+}
+
+// DeleteMarketPlaceImage removes the image uudImage from MarketImages
+func (f *FakeScalewayAPI) DeleteMarketPlaceImage(uudImage string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for i, image := range f.MarketImages {
+		if image.ID == uudImage {
+			f.MarketImages = append(f.MarketImages[:i], f.MarketImages[i+1:]...)
+			return nil
+		}
+	}
+	return newNotFoundError("No such image: %s", uudImage)
+}
+
+// DeleteMarketPlaceImageVersion fails, the fake has no marketplace versions
+func (f *FakeScalewayAPI) DeleteMarketPlaceImageVersion(uuidImage, uuidVersion string) error {
+	return errFakeUnsupported("DeleteMarketPlaceImageVersion")
+}
+
+// DeleteMarketPlaceLocalImage fails, the fake has no marketplace versions
+func (f *FakeScalewayAPI) DeleteMarketPlaceLocalImage(uuidImage, uuidVersion, uuidLocalImage string) error {
+	return errFakeUnsupported("DeleteMarketPlaceLocalImage")
+}
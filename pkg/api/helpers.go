@@ -5,8 +5,10 @@
 package api
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"sort"
 	"strings"
@@ -14,7 +16,6 @@ import (
 	"time"
 
 	"github.com/docker/docker/pkg/namesgenerator"
-	"github.com/dustin/go-humanize"
 	"github.com/moul/anonuuid"
 	"github.com/scaleway/scaleway-cli/pkg/utils"
 	"github.com/sirupsen/logrus"
@@ -32,20 +33,21 @@ type ScalewayResolvedIdentifier struct {
 
 // ScalewayImageInterface is an interface to multiple Scaleway items
 type ScalewayImageInterface struct {
-	CreationDate time.Time
-	Identifier   string
-	Name         string
-	Tag          string
-	VirtualSize  uint64
-	Public       bool
-	Type         string
-	Organization string
-	Archs        []string
-	Region       []string
+	CreationDate     time.Time
+	ModificationDate time.Time
+	Identifier       string
+	Name             string
+	Tag              string
+	VirtualSize      uint64
+	Public           bool
+	Type             string
+	Organization     string
+	Archs            []string
+	Region           []string
 }
 
 // ResolveGateway tries to resolve a server public ip address, else returns the input string, i.e. IPv4, hostname
-func ResolveGateway(api *ScalewayAPI, gateway string) (string, error) {
+func ResolveGateway(api ScalewayAPIClient, gateway string) (string, error) {
 	if gateway == "" {
 		return "", nil
 	}
@@ -75,8 +77,8 @@ func ResolveGateway(api *ScalewayAPI, gateway string) (string, error) {
 }
 
 // CreateVolumeFromHumanSize creates a volume on the API with a human readable size
-func CreateVolumeFromHumanSize(api *ScalewayAPI, size string) (*string, error) {
-	bytes, err := humanize.ParseBytes(size)
+func CreateVolumeFromHumanSize(api ScalewayAPIClient, size string) (*string, error) {
+	bytes, err := utils.ParseSize(size)
 	if err != nil {
 		return nil, err
 	}
@@ -120,10 +122,10 @@ func VolumesFromSize(rootVolumeSize, targetSize, perVolumeMaxSize uint64) string
 }
 
 // fillIdentifierCache fills the cache by fetching from the API
-func fillIdentifierCache(api *ScalewayAPI, identifierType int) {
+func fillIdentifierCache(api ScalewayAPIClient, identifierType int) {
 	log.Debugf("Filling the cache")
 	var wg sync.WaitGroup
-	wg.Add(5)
+	wg.Add(6)
 	go func() {
 		if identifierType&(IdentifierUnknown|IdentifierServer) > 0 {
 			api.GetServers(true, 0)
@@ -154,11 +156,17 @@ func fillIdentifierCache(api *ScalewayAPI, identifierType int) {
 		}
 		wg.Done()
 	}()
+	go func() {
+		if identifierType&(IdentifierUnknown|IdentifierIP) > 0 {
+			api.GetIPS()
+		}
+		wg.Done()
+	}()
 	wg.Wait()
 }
 
 // GetIdentifier returns a an identifier if the resolved needles only match one element, else, it exists the program
-func GetIdentifier(api *ScalewayAPI, needle string) (*ScalewayResolverResult, error) {
+func GetIdentifier(api ScalewayAPIClient, needle string) (*ScalewayResolverResult, error) {
 	idents, err := ResolveIdentifier(api, needle)
 	if err != nil {
 		return nil, err
@@ -180,8 +188,8 @@ func GetIdentifier(api *ScalewayAPI, needle string) (*ScalewayResolverResult, er
 }
 
 // ResolveIdentifier resolves needle provided by the user
-func ResolveIdentifier(api *ScalewayAPI, needle string) (ScalewayResolverResults, error) {
-	idents, err := api.Cache.LookUpIdentifiers(needle)
+func ResolveIdentifier(api ScalewayAPIClient, needle string) (ScalewayResolverResults, error) {
+	idents, err := api.ResolverCache().LookUpIdentifiers(needle)
 	if err != nil {
 		return idents, err
 	}
@@ -192,17 +200,17 @@ func ResolveIdentifier(api *ScalewayAPI, needle string) (ScalewayResolverResults
 	identifierType, _ := parseNeedle(needle)
 	fillIdentifierCache(api, identifierType)
 
-	return api.Cache.LookUpIdentifiers(needle)
+	return api.ResolverCache().LookUpIdentifiers(needle)
 }
 
 // ResolveIdentifiers resolves needles provided by the user
-func ResolveIdentifiers(api *ScalewayAPI, needles []string, out chan ScalewayResolvedIdentifier) {
+func ResolveIdentifiers(api ScalewayAPIClient, needles []string, out chan ScalewayResolvedIdentifier) {
 	// first attempt, only lookup from the cache
 	var unresolved []string
 	for _, needle := range needles {
-		idents, err := api.Cache.LookUpIdentifiers(needle)
+		idents, err := api.ResolverCache().LookUpIdentifiers(needle)
 		if err != nil {
-			api.Logger.Fatalf("%s", err)
+			api.Fatalf("%s", err)
 		}
 		if len(idents) == 0 {
 			unresolved = append(unresolved, needle)
@@ -233,9 +241,9 @@ func ResolveIdentifiers(api *ScalewayAPI, needles []string, out chan ScalewayRes
 
 		// lookup again in the cache
 		for _, needle := range unresolved {
-			idents, err := api.Cache.LookUpIdentifiers(needle)
+			idents, err := api.ResolverCache().LookUpIdentifiers(needle)
 			if err != nil {
-				api.Logger.Fatalf("%s", err)
+				api.Fatalf("%s", err)
 			}
 			out <- ScalewayResolvedIdentifier{
 				Identifiers: idents,
@@ -254,7 +262,7 @@ type InspectIdentifierResult struct {
 }
 
 // InspectIdentifiers inspects identifiers concurrently
-func InspectIdentifiers(api *ScalewayAPI, ci chan ScalewayResolvedIdentifier, cj chan InspectIdentifierResult, arch string) {
+func InspectIdentifiers(api ScalewayAPIClient, ci chan ScalewayResolvedIdentifier, cj chan InspectIdentifierResult, arch string) {
 	var wg sync.WaitGroup
 	for {
 		idents, ok := <-ci
@@ -262,7 +270,7 @@ func InspectIdentifiers(api *ScalewayAPI, ci chan ScalewayResolvedIdentifier, cj
 			break
 		}
 		idents.Identifiers = FilterImagesByArch(idents.Identifiers, arch)
-		idents.Identifiers = FilterImagesByRegion(idents.Identifiers, api.Region)
+		idents.Identifiers = FilterImagesByRegion(idents.Identifiers, api.CurrentRegion())
 		if len(idents.Identifiers) != 1 {
 			if len(idents.Identifiers) == 0 {
 				log.Errorf("Unable to resolve identifier %s", idents.Needle)
@@ -287,6 +295,8 @@ func InspectIdentifiers(api *ScalewayAPI, ci chan ScalewayResolvedIdentifier, cj
 					obj, err = api.GetVolume(ident.Identifier)
 				case IdentifierBootscript:
 					obj, err = api.GetBootscript(ident.Identifier)
+				case IdentifierIP:
+					obj, err = api.GetIP(ident.Identifier)
 				}
 				if err == nil && obj != nil {
 					cj <- InspectIdentifierResult{
@@ -302,10 +312,30 @@ func InspectIdentifiers(api *ScalewayAPI, ci chan ScalewayResolvedIdentifier, cj
 	close(cj)
 }
 
+// GenerateServerName returns a memorable adjective-noun name, i.e: "prefix-agitated-turing",
+// avoiding the names of the servers known by the cache
+func GenerateServerName(api ScalewayAPIClient, prefix string) string {
+	var name string
+	for retry := 0; retry < 10; retry++ {
+		name = strings.Replace(namesgenerator.GetRandomName(retry), "_", "-", -1)
+		if prefix != "" {
+			name = strings.TrimSuffix(prefix, "-") + "-" + name
+		}
+		if !api.ResolverCache().HasServerName(name) {
+			break
+		}
+	}
+	return name
+}
+
+// DefaultCommercialType is the commercial type of the servers created without --commercial-type
+const DefaultCommercialType = "X64-2GB"
+
 // ConfigCreateServer represents the options sent to CreateServer and defining a server
 type ConfigCreateServer struct {
 	ImageName         string
 	Name              string
+	NamePrefix        string
 	Bootscript        string
 	Env               string
 	AdditionalVolumes string
@@ -314,6 +344,118 @@ type ConfigCreateServer struct {
 	DynamicIPRequired bool
 	EnableIPV6        bool
 	BootType          string
+	ForceBootscript   bool
+	PullPolicy        string
+
+	// FromSnapshot is the snapshot copied to the root volume instead of an image
+	FromSnapshot string
+
+	// Definition provides the fields left empty above and the fields without option
+	Definition *ServerDefinitionFile
+}
+
+// ServerDefinitionFile is a server definition read from a file, its volumes are always new volumes
+type ServerDefinitionFile struct {
+	ScalewayServerDefinition
+
+	Volumes map[string]ScalewayServerVolumeDefinitionNew `json:"volumes,omitempty"`
+}
+
+// LoadServerDefinitionFile reads and validates a server definition, $VAR and ${VAR} are replaced by environment variables
+func LoadServerDefinitionFile(path string) (*ServerDefinitionFile, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read definition file: %v", err)
+	}
+	content = []byte(os.ExpandEnv(string(content)))
+
+	var definition ServerDefinitionFile
+	if err = utils.ValidateJSON(content, &definition); err != nil {
+		return nil, fmt.Errorf("invalid definition file %s:\n%v", path, err)
+	}
+	if err = json.Unmarshal(content, &definition); err != nil {
+		return nil, fmt.Errorf("invalid definition file %s: %v", path, err)
+	}
+	return &definition, nil
+}
+
+// applyDefinition fills the options left empty with the values of the definition file
+func (c *ConfigCreateServer) applyDefinition() {
+	definition := c.Definition
+	if definition == nil {
+		return
+	}
+	if c.ImageName == "" && c.FromSnapshot == "" && definition.Image != nil {
+		c.ImageName = *definition.Image
+	}
+	if c.Name == "" {
+		c.Name = definition.Name
+	}
+	if c.Bootscript == "" && definition.Bootscript != nil {
+		c.Bootscript = *definition.Bootscript
+	}
+	if c.CommercialType == "" {
+		c.CommercialType = definition.CommercialType
+	}
+	if c.CommercialType == "" {
+		c.CommercialType = DefaultCommercialType
+	}
+	if c.BootType == "" {
+		c.BootType = definition.BootType
+	}
+	if c.BootType == "" {
+		c.BootType = "auto"
+	}
+	c.EnableIPV6 = c.EnableIPV6 || definition.EnableIPV6
+}
+
+// mergeDefinition adds the fields of the definition file which have no option to server
+func (c *ConfigCreateServer) mergeDefinition(api ScalewayAPIClient, server *ScalewayServerDefinition) {
+	definition := c.Definition
+	if definition == nil {
+		return
+	}
+	if server.SecurityGroup == "" {
+		server.SecurityGroup = definition.SecurityGroup
+	}
+	for slot, volume := range definition.Volumes {
+		if _, exists := server.Volumes[slot]; exists {
+			log.Warnf("Volume %s of the definition file is overridden by the command line", slot)
+			continue
+		}
+		if volume.OrganizationId == "" {
+			volume.OrganizationId = api.OrganizationID()
+		}
+		if volume.VolumeType == "" {
+			volume.VolumeType = "l_ssd"
+		}
+		if volume.Name == "" {
+			volume.Name = "Volume-" + slot
+		}
+		newVolume := volume
+		server.Volumes[slot] = &newVolume
+	}
+}
+
+const (
+	// PullPolicyMissing resolves image names using the local cache and only queries the marketplace on cache miss
+	PullPolicyMissing = "missing"
+	// PullPolicyAlways refreshes the marketplace before resolving image names, to use the latest published version
+	PullPolicyAlways = "always"
+)
+
+// BootscriptWarnings returns the reasons why a bootscript should not be pinned on a server of the given arch
+func BootscriptWarnings(bootscript *ScalewayBootscript, arch string) []string {
+	var warnings []string
+
+	title := strings.ToLower(bootscript.Title)
+	if bootscript.Deprecated || strings.Contains(title, "deprecated") || strings.Contains(title, "obsolete") {
+		warnings = append(warnings, fmt.Sprintf("bootscript %q is deprecated", bootscript.Title))
+	}
+	if arch != "" && bootscript.Arch != "" && bootscript.Arch != arch {
+		warnings = append(warnings, fmt.Sprintf("bootscript %q targets %s, the image targets %s", bootscript.Title, bootscript.Arch, arch))
+	}
+	return warnings
 }
 
 // Return offer from any of the product name or alternate names
@@ -361,7 +503,14 @@ var legacyInstantTypes = map[string]struct{}{
 }
 
 // CreateServer creates a server using API based on typical server fields
-func CreateServer(api *ScalewayAPI, c *ConfigCreateServer) (string, error) {
+func CreateServer(api ScalewayAPIClient, c *ConfigCreateServer) (string, error) {
+	c.applyDefinition()
+	if c.ImageName == "" && c.FromSnapshot == "" {
+		return "", errors.New("You need to specify an image")
+	}
+	if c.ImageName != "" && c.FromSnapshot != "" {
+		return "", errors.New("The root volume comes from an image or from a snapshot, not both")
+	}
 	commercialType := os.Getenv("SCW_COMMERCIAL_TYPE")
 	if commercialType == "" {
 		commercialType = c.CommercialType
@@ -384,7 +533,7 @@ func CreateServer(api *ScalewayAPI, c *ConfigCreateServer) (string, error) {
 	}
 
 	if c.Name == "" {
-		c.Name = strings.Replace(namesgenerator.GetRandomName(0), "_", "-", -1)
+		c.Name = GenerateServerName(api, c.NamePrefix)
 	}
 
 	var server ScalewayServerDefinition
@@ -423,11 +572,11 @@ func CreateServer(api *ScalewayAPI, c *ConfigCreateServer) (string, error) {
 
 	products, err := api.GetProductsServers()
 	if err != nil {
-		return "", fmt.Errorf("Unable to fetch products list from the Scaleway API: %v", err)
+		return "", Wrapf(err, "Unable to fetch products list from the Scaleway API")
 	}
 	offer, err := OfferNameFromName(server.CommercialType, products)
 	if err != nil {
-		return "", fmt.Errorf("Unknow commercial type %v: %v", server.CommercialType, err)
+		return "", Wrapf(err, "Unknow commercial type %v", server.CommercialType)
 	}
 	//
 	// Find the correct root size
@@ -436,9 +585,22 @@ func CreateServer(api *ScalewayAPI, c *ConfigCreateServer) (string, error) {
 	// 2- (default) use the largest possible size ==> min(categoryMaxSize,volumeMaxSize)
 	// 3- the user specify additional volumes ==> min(50G,volumeMaxSize)
 	//
+	var snapshot *ScalewaySnapshot
+	if c.FromSnapshot != "" {
+		snapshotID, err := api.GetSnapshotID(c.FromSnapshot)
+		if err != nil {
+			return "", err
+		}
+		if snapshot, err = api.GetSnapshot(snapshotID); err != nil {
+			return "", err
+		}
+	}
 	isUserDefinedRootSize := true
-	rootVolumeSize, err := humanize.ParseBytes(c.ImageName)
-	if err != nil {
+	rootVolumeSize, err := utils.ParseSize(c.ImageName)
+	if snapshot != nil {
+		isUserDefinedRootSize = false
+		rootVolumeSize = snapshot.Size
+	} else if err != nil {
 		isUserDefinedRootSize = false
 		rootVolumeSize = min(offer.PerVolumesConstraint.LSsdConstraint.MaxSize, offer.VolumesConstraint.MaxSize)
 		if c.AdditionalVolumes != "" {
@@ -446,10 +608,22 @@ func CreateServer(api *ScalewayAPI, c *ConfigCreateServer) (string, error) {
 		}
 	}
 
-	if isUserDefinedRootSize {
+	if snapshot != nil {
+		// copy the snapshot, the volume keeps its size
+		volumeType := snapshot.VolumeType
+		if volumeType == "" {
+			volumeType = "l_ssd"
+		}
+		server.Volumes["0"] = &ScalewayServerVolumeDefinitionFromSnapshot{
+			OrganizationId: api.OrganizationID(),
+			VolumeType:     volumeType,
+			Name:           fmt.Sprintf("%s-%s", c.Name, snapshot.Name),
+			BaseSnapshot:   snapshot.Identifier,
+		}
+	} else if isUserDefinedRootSize {
 		// create a new volume from scratch
 		server.Volumes["0"] = &ScalewayServerVolumeDefinitionNew{
-			OrganizationId: api.Organization,
+			OrganizationId: api.OrganizationID(),
 			VolumeType:     "l_ssd",
 			Name:           "Volume-0",
 			Size:           rootVolumeSize,
@@ -463,21 +637,21 @@ func CreateServer(api *ScalewayAPI, c *ConfigCreateServer) (string, error) {
 
 	if offer.VolumesConstraint.MinSize > 0 && c.AdditionalVolumes == "" {
 		c.AdditionalVolumes = VolumesFromSize(rootVolumeSize, offer.VolumesConstraint.MinSize, offer.PerVolumesConstraint.LSsdConstraint.MaxSize)
-		log.Debugf("%s needs at least %s. Automatically creates the following volumes: %dG %s",
-			server.CommercialType, humanize.Bytes(offer.VolumesConstraint.MinSize), rootVolumeSize/Giga, c.AdditionalVolumes)
+		log.Debugf("%s needs at least %s. Automatically creates the following volumes: %s %s",
+			server.CommercialType, utils.FormatSize(offer.VolumesConstraint.MinSize), utils.FormatSize(rootVolumeSize), c.AdditionalVolumes)
 	}
 
 	if c.AdditionalVolumes != "" {
-		volumes := strings.Split(c.AdditionalVolumes, " ")
+		volumes := strings.Fields(c.AdditionalVolumes)
 		for i := range volumes {
-			rootSize, err := humanize.ParseBytes(volumes[i])
+			rootSize, err := utils.ParseSize(volumes[i])
 			if err != nil {
-				return "", err
+				return "", Wrapf(err, "volume %d", i+1)
 			}
 
 			volumeIDx := fmt.Sprintf("%d", i+1)
 			server.Volumes[volumeIDx] = &ScalewayServerVolumeDefinitionNew{
-				OrganizationId: api.Organization,
+				OrganizationId: api.OrganizationID(),
 				VolumeType:     "l_ssd",
 				Name:           "Volume-" + volumeIDx,
 				Size:           rootSize,
@@ -495,12 +669,23 @@ func CreateServer(api *ScalewayAPI, c *ConfigCreateServer) (string, error) {
 	server.Name = c.Name
 	inheritingVolume := false
 
-	if !isUserDefinedRootSize {
+	if !isUserDefinedRootSize && snapshot == nil {
 		// Use an existing image
 		inheritingVolume = true
+		c.ImageName = api.ResolveImageAlias(c.ImageName, arch)
 		if anonuuid.IsUUID(c.ImageName) == nil {
 			server.Image = &c.ImageName
 		} else {
+			switch c.PullPolicy {
+			case "", PullPolicyMissing:
+			case PullPolicyAlways:
+				log.Debugf("Refreshing marketplace images")
+				if _, err = api.GetImages(); err != nil {
+					return "", err
+				}
+			default:
+				return "", fmt.Errorf("invalid pull policy %q, expected '%s' or '%s'", c.PullPolicy, PullPolicyMissing, PullPolicyAlways)
+			}
 			imageIdentifier, err = api.GetImageID(c.ImageName, arch)
 			if err != nil {
 				return "", err
@@ -537,8 +722,20 @@ func CreateServer(api *ScalewayAPI, c *ConfigCreateServer) (string, error) {
 				return "", errGetBootScript
 			}
 		}
+		metadata, err := api.GetBootscript(bootscript)
+		if err != nil {
+			return "", err
+		}
+		warnings := BootscriptWarnings(metadata, imageIdentifier.Arch)
+		for _, warning := range warnings {
+			log.Warn(warning)
+		}
+		if len(warnings) > 0 && !c.ForceBootscript {
+			return "", fmt.Errorf("refusing to pin bootscript %s, use --force-bootscript to use it anyway", metadata.Identifier)
+		}
 		server.Bootscript = &bootscript
 	}
+	c.mergeDefinition(api, &server)
 	serverID, err := api.PostServer(server)
 	if err != nil {
 		return "", err
@@ -555,13 +752,15 @@ func CreateServer(api *ScalewayAPI, c *ConfigCreateServer) (string, error) {
 		var volumePayload ScalewayVolumePutDefinition
 		newName := fmt.Sprintf("%s-%s", createdServer.Hostname, currentVolume.Name)
 		volumePayload.Name = &newName
-		volumePayload.CreationDate = &currentVolume.CreationDate
+		creationDate := currentVolume.CreationDate.APIString()
+		modificationDate := currentVolume.ModificationDate.APIString()
+		volumePayload.CreationDate = &creationDate
 		volumePayload.Organization = &currentVolume.Organization
 		volumePayload.Server.Identifier = &currentVolume.Server.Identifier
 		volumePayload.Server.Name = &currentVolume.Server.Name
 		volumePayload.Identifier = &currentVolume.Identifier
 		volumePayload.Size = &currentVolume.Size
-		volumePayload.ModificationDate = &currentVolume.ModificationDate
+		volumePayload.ModificationDate = &modificationDate
 		volumePayload.ExportURI = &currentVolume.ExportURI
 		volumePayload.VolumeType = &currentVolume.VolumeType
 
@@ -575,7 +774,7 @@ func CreateServer(api *ScalewayAPI, c *ConfigCreateServer) (string, error) {
 }
 
 // WaitForServerState asks API in a loop until a server matches a wanted state
-func WaitForServerState(api *ScalewayAPI, serverID string, targetState string) (*ScalewayServer, error) {
+func WaitForServerState(api ScalewayAPIClient, serverID string, targetState string) (*ScalewayServer, error) {
 	var server *ScalewayServer
 	var err error
 
@@ -593,14 +792,132 @@ func WaitForServerState(api *ScalewayAPI, serverID string, targetState string) (
 		if server.State == targetState {
 			break
 		}
-		time.Sleep(1 * time.Second)
+		time.Sleep(WaitPollInterval)
 	}
 
 	return server, nil
 }
 
+// WaitPollInterval is the delay between two requests of the loops waiting for a state
+var WaitPollInterval = time.Second
+
+// ConflictTimeout is the maximum time spent waiting for the tasks running on a server
+const ConflictTimeout = 10 * time.Minute
+
+// IsTransitionalState reports whether a server is starting, stopping or allocating, the API rejects its actions meanwhile
+func IsTransitionalState(server *ScalewayServer) bool {
+	return server.State == "starting" || server.State == "stopping" || strings.HasPrefix(server.StateDetail, "allocating")
+}
+
+// WaitForServerTransition asks API in a loop until a server is not starting, stopping or allocating anymore
+func WaitForServerTransition(api ScalewayAPIClient, serverID string, timeout time.Duration) (*ScalewayServer, error) {
+	deadline := time.Now().Add(timeout)
+	var currentState string
+	for {
+		server, err := api.GetServer(serverID)
+		if err != nil {
+			return nil, err
+		}
+		if !IsTransitionalState(server) {
+			return server, nil
+		}
+		if state := strings.TrimSpace(server.State + " " + server.StateDetail); state != currentState {
+			log.Infof("Server is %s", state)
+			currentState = state
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out after %v waiting for server %s to leave the %s state", timeout, serverID, server.State)
+		}
+		time.Sleep(WaitPollInterval)
+	}
+}
+
+// WaitForTask asks API in a loop until a task is terminated, it fails when the task fails or after timeout, 0 waits forever
+func WaitForTask(api ScalewayAPIClient, taskID string, timeout time.Duration) (*ScalewayTask, error) {
+	deadline := time.Now().Add(timeout)
+	var currentStatus string
+	for {
+		task, err := api.GetTask(taskID)
+		if err != nil {
+			return nil, err
+		}
+		if task.Status != currentStatus {
+			log.Debugf("Task %s (%s) is %s", task.Identifier, task.Description, task.Status)
+			currentStatus = task.Status
+		}
+		switch task.Status {
+		case "success":
+			return task, nil
+		case "failure":
+			return task, fmt.Errorf("task %s (%s) failed", task.Identifier, task.Description)
+		}
+		if timeout > 0 && time.Now().After(deadline) {
+			return task, fmt.Errorf("timed out after %v waiting for task %s (%s), %d%% done", timeout, task.Identifier, task.Description, task.Progress)
+		}
+		time.Sleep(WaitPollInterval)
+	}
+}
+
+// WaitForServerTasks asks API in a loop until no task is pending or started on a server
+func WaitForServerTasks(api ScalewayAPIClient, serverID string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		tasks, err := api.GetTasks()
+		if err != nil {
+			return err
+		}
+		running := 0
+		for _, task := range *tasks {
+			if (task.Status == "pending" || task.Status == "started") && strings.Contains(task.HrefFrom, serverID) {
+				log.Debugf("Task %s (%s) is %s on server %s", task.Identifier, task.Description, task.Status, serverID)
+				running++
+			}
+		}
+		if running == 0 {
+			// let the server settle in its new state
+			time.Sleep(2 * WaitPollInterval)
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %v waiting for %d task(s) on server %s", timeout, running, serverID)
+		}
+		time.Sleep(2 * WaitPollInterval)
+	}
+}
+
+// SnapshotTimeout is the time given to a snapshot to be taken
+const SnapshotTimeout = time.Hour
+
+// WaitForSnapshotState asks API in a loop until a snapshot matches a wanted state,
+// it fails when the snapshot fails or after timeout
+func WaitForSnapshotState(api ScalewayAPIClient, snapshotID string, targetState string, timeout time.Duration) (*ScalewaySnapshot, error) {
+	deadline := time.Now().Add(timeout)
+	var currentState string
+
+	for {
+		snapshot, err := api.GetSnapshot(snapshotID)
+		if err != nil {
+			return nil, err
+		}
+		if currentState != snapshot.State {
+			log.Infof("Snapshot changed state to '%s'", snapshot.State)
+			currentState = snapshot.State
+		}
+		if snapshot.State == targetState {
+			return snapshot, nil
+		}
+		if snapshot.State == "error" {
+			return nil, fmt.Errorf("snapshot %s failed", snapshotID)
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out after %v waiting for snapshot %s to be %s, it is %s", timeout, snapshotID, targetState, snapshot.State)
+		}
+		time.Sleep(WaitPollInterval)
+	}
+}
+
 // WaitForServerReady wait for a server state to be running, then wait for the SSH port to be available
-func WaitForServerReady(api *ScalewayAPI, serverID, gateway string) (*ScalewayServer, error) {
+func WaitForServerReady(api ScalewayAPIClient, serverID, gateway string) (*ScalewayServer, error) {
 	promise := make(chan bool)
 	var server *ScalewayServer
 	var err error
@@ -627,7 +944,7 @@ func WaitForServerReady(api *ScalewayAPI, serverID, gateway string) (*ScalewaySe
 				promise <- false
 				return
 			}
-			time.Sleep(1 * time.Second)
+			time.Sleep(WaitPollInterval)
 		}
 
 		if gateway == "" {
@@ -699,8 +1016,57 @@ func WaitForServerReady(api *ScalewayAPI, serverID, gateway string) (*ScalewaySe
 	}
 }
 
+// Boot stages reported while a server is starting
+const (
+	BootStageAllocating = "allocating"
+	BootStageBooting    = "booting kernel"
+	BootStageCloudInit  = "cloud-init"
+	BootStageSSHWait    = "waiting for ssh"
+	BootStageSSHReady   = "ssh ready"
+)
+
+// ServerBootStage guesses the boot stage of a server from its state and state detail
+func ServerBootStage(server *ScalewayServer) string {
+	switch server.State {
+	case "running":
+		return BootStageSSHWait
+	case "starting":
+		switch {
+		case strings.Contains(server.StateDetail, "kernel-started"), strings.Contains(server.StateDetail, "booted"):
+			return BootStageCloudInit
+		case strings.Contains(server.StateDetail, "kernel"):
+			return BootStageBooting
+		}
+	}
+	return BootStageAllocating
+}
+
+// GetServerPendingTask returns the last unfinished task started by an action on a server, if any
+func (s *ScalewayAPI) GetServerPendingTask(serverID string) (*ScalewayTask, error) {
+	tasks, err := s.GetTasks()
+	if err != nil {
+		return nil, err
+	}
+	return serverPendingTask(*tasks, serverID), nil
+}
+
+// serverPendingTask returns the last task of tasks started by an action on a server and not finished
+func serverPendingTask(tasks []ScalewayTask, serverID string) *ScalewayTask {
+	var pending *ScalewayTask
+	for i, task := range tasks {
+		if !strings.Contains(task.HrefFrom, serverID) {
+			continue
+		}
+		if task.Status == "success" || task.Status == "failure" {
+			continue
+		}
+		pending = &tasks[i]
+	}
+	return pending
+}
+
 // WaitForServerStopped wait for a server state to be stopped
-func WaitForServerStopped(api *ScalewayAPI, serverID string) (*ScalewayServer, error) {
+func WaitForServerStopped(api ScalewayAPIClient, serverID string) (*ScalewayServer, error) {
 	server, err := WaitForServerState(api, serverID, "stopped")
 	if err != nil {
 		return nil, err
@@ -716,7 +1082,7 @@ func (a ByCreationDate) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 func (a ByCreationDate) Less(i, j int) bool { return a[j].CreationDate.Before(a[i].CreationDate) }
 
 // StartServer start a server based on its needle, can optionaly block while server is booting
-func StartServer(api *ScalewayAPI, needle string, wait bool) error {
+func StartServer(api ScalewayAPIClient, needle string, wait bool) error {
 	server, err := api.GetServerID(needle)
 	if err != nil {
 		return err
@@ -736,7 +1102,7 @@ func StartServer(api *ScalewayAPI, needle string, wait bool) error {
 }
 
 // StartServerOnce wraps StartServer for golang channel
-func StartServerOnce(api *ScalewayAPI, needle string, wait bool, successChan chan string, errChan chan error) {
+func StartServerOnce(api ScalewayAPIClient, needle string, wait bool, successChan chan string, errChan chan error) {
 	err := StartServer(api, needle, wait)
 
 	if err != nil {
@@ -1,6 +1,9 @@
 package api
 
 import (
+	"encoding/json"
+	"time"
+
 	. "github.com/smartystreets/goconvey/convey"
 	"testing"
 )
@@ -59,3 +62,43 @@ func TestVolumesFromSize(t *testing.T) {
 	}
 
 }
+
+func TestServerBootStage(t *testing.T) {
+	Convey("Testing ServerBootStage", t, func() {
+		So(ServerBootStage(&ScalewayServer{State: "starting", StateDetail: "allocating node"}), ShouldEqual, BootStageAllocating)
+		So(ServerBootStage(&ScalewayServer{State: "starting", StateDetail: "booting kernel"}), ShouldEqual, BootStageBooting)
+		So(ServerBootStage(&ScalewayServer{State: "starting", StateDetail: "kernel-started"}), ShouldEqual, BootStageCloudInit)
+		So(ServerBootStage(&ScalewayServer{State: "running", StateDetail: "booted"}), ShouldEqual, BootStageSSHWait)
+	})
+}
+
+func TestWaitForSnapshotState(t *testing.T) {
+	Convey("Testing WaitForSnapshotState", t, func() {
+		defer func(interval time.Duration) { WaitPollInterval = interval }(WaitPollInterval)
+		WaitPollInterval = time.Millisecond
+
+		fake := NewFakeScalewayAPI("orga")
+		fake.Snapshots = []ScalewaySnapshot{
+			{Identifier: "1", State: "snapshotted"},
+			{Identifier: "2", State: "error"},
+			{Identifier: "3", State: "snapshotting"},
+		}
+		_, err := WaitForSnapshotState(fake, "1", "snapshotted", time.Second)
+		So(err, ShouldBeNil)
+		_, err = WaitForSnapshotState(fake, "2", "snapshotted", time.Second)
+		So(err, ShouldNotBeNil)
+		_, err = WaitForSnapshotState(fake, "3", "snapshotted", 10*time.Millisecond)
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestServerVolumeDefinitionFromSnapshot(t *testing.T) {
+	Convey("Testing the JSON of a root volume copied from a snapshot", t, func() {
+		server := ScalewayServerDefinition{Volumes: map[string]ScalewayServerVolumeDefinition{
+			"0": &ScalewayServerVolumeDefinitionFromSnapshot{Name: "web-backup", OrganizationId: "orga", VolumeType: "l_ssd", BaseSnapshot: "1234"},
+		}}
+		out, err := json.Marshal(server.Volumes)
+		So(err, ShouldBeNil)
+		So(string(out), ShouldEqual, `{"0":{"name":"web-backup","organization":"orga","volume_type":"l_ssd","base_snapshot":"1234"}}`)
+	})
+}
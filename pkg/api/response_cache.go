@@ -0,0 +1,125 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// TTLs of the cached responses
+const (
+	BootscriptsTTL  = 24 * time.Hour
+	MarketplaceTTL  = 24 * time.Hour
+	BootscriptTTL   = 7 * 24 * time.Hour
+	MarketImageTTL  = 7 * 24 * time.Hour
+	responseFileExt = ".json"
+	headersFileExt  = ".headers"
+)
+
+// ResponseCache stores on disk the JSON bodies of the resources which rarely change (bootscripts, marketplace images),
+// and of the GET responses carrying an ETag or a Last-Modified header, to send conditional requests.
+// A body is stored in a file named after the hash of its URL
+type ResponseCache struct {
+	Path string
+}
+
+// NewResponseCache returns a ResponseCache storing its files in path
+func NewResponseCache(path string) *ResponseCache {
+	return &ResponseCache{Path: path}
+}
+
+// cachedHeaders are the validators of a stored body
+type cachedHeaders struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+func (c *ResponseCache) file(uri string) string {
+	return c.fileExt(uri, responseFileExt)
+}
+
+func (c *ResponseCache) fileExt(uri, ext string) string {
+	hash := sha256.Sum256([]byte(uri))
+	return filepath.Join(c.Path, hex.EncodeToString(hash[:])+ext)
+}
+
+// Get returns the body stored for uri and its age, ok is false when nothing is stored
+func (c *ResponseCache) Get(uri string) (body []byte, age time.Duration, ok bool) {
+	file := c.file(uri)
+	stat, err := os.Stat(file)
+	if err != nil {
+		return nil, 0, false
+	}
+	if body, err = ioutil.ReadFile(file); err != nil {
+		return nil, 0, false
+	}
+	return body, time.Since(stat.ModTime()), true
+}
+
+// Put stores the body of uri
+func (c *ResponseCache) Put(uri string, body []byte) error {
+	os.Remove(c.fileExt(uri, headersFileExt))
+	return c.write(c.file(uri), body)
+}
+
+// GetValidators returns the ETag and Last-Modified headers stored with the body of uri
+func (c *ResponseCache) GetValidators(uri string) (etag, lastModified string, ok bool) {
+	data, err := ioutil.ReadFile(c.fileExt(uri, headersFileExt))
+	if err != nil {
+		return "", "", false
+	}
+	var headers cachedHeaders
+	if err = json.Unmarshal(data, &headers); err != nil {
+		return "", "", false
+	}
+	if _, err = os.Stat(c.file(uri)); err != nil {
+		return "", "", false
+	}
+	return headers.ETag, headers.LastModified, headers.ETag != "" || headers.LastModified != ""
+}
+
+// PutValidated stores the body of uri with its ETag and Last-Modified headers
+func (c *ResponseCache) PutValidated(uri string, body []byte, etag, lastModified string) error {
+	if err := c.Put(uri, body); err != nil {
+		return err
+	}
+	data, err := json.Marshal(cachedHeaders{ETag: etag, LastModified: lastModified})
+	if err != nil {
+		return err
+	}
+	return c.write(c.fileExt(uri, headersFileExt), data)
+}
+
+// write replaces the content of path atomically
+func (c *ResponseCache) write(path string, data []byte) error {
+	if err := os.MkdirAll(c.Path, 0700); err != nil {
+		return err
+	}
+	file, err := ioutil.TempFile(c.Path, "response")
+	if err != nil {
+		return err
+	}
+	if _, err = file.Write(data); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	if err = file.Close(); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+// Flush removes every stored body
+func (c *ResponseCache) Flush() error {
+	return os.RemoveAll(c.Path)
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestResponseCache(t *testing.T) {
+	Convey("Testing ResponseCache", t, func() {
+		dir, err := ioutil.TempDir("", "scw-responses")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		cache := NewResponseCache(filepath.Join(dir, "responses"))
+
+		_, _, ok := cache.Get("https://cp-par1.scaleway.com/bootscripts")
+		So(ok, ShouldBeFalse)
+
+		So(cache.Put("https://cp-par1.scaleway.com/bootscripts", []byte(`{"bootscripts":[]}`)), ShouldBeNil)
+		body, age, ok := cache.Get("https://cp-par1.scaleway.com/bootscripts")
+		So(ok, ShouldBeTrue)
+		So(string(body), ShouldEqual, `{"bootscripts":[]}`)
+		So(age, ShouldBeLessThan, time.Minute)
+		_, _, ok = cache.Get("https://cp-ams1.scaleway.com/bootscripts")
+		So(ok, ShouldBeFalse)
+
+		_, _, ok = cache.GetValidators("https://cp-par1.scaleway.com/bootscripts")
+		So(ok, ShouldBeFalse)
+		So(cache.PutValidated("https://cp-par1.scaleway.com/images", []byte(`{"images":[]}`), `"abc"`, ""), ShouldBeNil)
+		etag, lastModified, ok := cache.GetValidators("https://cp-par1.scaleway.com/images")
+		So(ok, ShouldBeTrue)
+		So(etag, ShouldEqual, `"abc"`)
+		So(lastModified, ShouldEqual, "")
+		So(cache.Put("https://cp-par1.scaleway.com/images", []byte(`{"images":[{}]}`)), ShouldBeNil)
+		_, _, ok = cache.GetValidators("https://cp-par1.scaleway.com/images")
+		So(ok, ShouldBeFalse)
+
+		So(cache.Flush(), ShouldBeNil)
+		_, _, ok = cache.Get("https://cp-par1.scaleway.com/bootscripts")
+		So(ok, ShouldBeFalse)
+	})
+}
+
+func TestResponseCacheAccountAPI(t *testing.T) {
+	Convey("Testing the ResponseCache of the API client", t, func() {
+		dir, err := ioutil.TempDir("", "scw-responses")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+
+		api, server := newTestAPI(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("ETag", `"abc"`)
+			fmt.Fprint(w, `{}`)
+		})
+		defer server.Close()
+		api.ResponseCache = NewResponseCache(filepath.Join(dir, "responses"))
+		api.accountAPI = server.URL + "/account/"
+		api.computeAPI = server.URL + "/compute/"
+
+		_, err = api.response("GET", server.URL+"/compute/images", nil)
+		So(err, ShouldBeNil)
+		_, _, ok := api.ResponseCache.GetValidators(server.URL + "/compute/images")
+		So(ok, ShouldBeTrue)
+
+		_, err = api.response("GET", server.URL+"/account/tokens/my-token", nil)
+		So(err, ShouldBeNil)
+		_, _, ok = api.ResponseCache.Get(server.URL + "/account/tokens/my-token")
+		So(ok, ShouldBeFalse)
+	})
+}
@@ -0,0 +1,93 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package api
+
+import (
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RetryMaxAttempts is the number of times a request is sent while the API fails transiently, 1 disables the retries
+var RetryMaxAttempts = 3
+
+// RetryBaseDelay is the delay before the first retry, doubled on each retry up to RetryMaxDelay
+var RetryBaseDelay = 500 * time.Millisecond
+
+// RetryMaxDelay caps the delay between two attempts
+var RetryMaxDelay = 10 * time.Second
+
+// DefaultMaxRateWait is the total time a request waits for the API to accept it again when rate limited (429)
+var DefaultMaxRateWait = time.Minute
+
+// jitter draws the random part of the retry delays, it is seeded so that concurrent scw processes
+// do not retry in step, and locked since a rand.Rand is not safe for concurrent use
+var (
+	jitter     = rand.New(rand.NewSource(time.Now().UnixNano()))
+	jitterLock sync.Mutex
+)
+
+// retryAfter returns the delay requested by the Retry-After header of resp, in seconds or as an HTTP date
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := date.Sub(now); delay > 0 {
+			return delay, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// isRetryable returns true if a request, answered with resp or failed with err, may be sent again.
+// POST and PATCH are not idempotent, they are only sent again when the API surely did not process them
+func isRetryable(method string, resp *http.Response, err error) bool {
+	idempotent := method != "POST" && method != "PATCH"
+	if err != nil {
+		urlErr, ok := err.(*url.Error)
+		if !ok {
+			return false
+		}
+		opErr, ok := urlErr.Err.(*net.OpError)
+		dialed := ok && opErr.Op == "dial"
+		if urlErr.Timeout() && !dialed {
+			// the API did not answer within the request timeout, retrying would defeat it
+			return false
+		}
+		return idempotent || dialed
+	}
+	switch resp.StatusCode {
+	case http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return idempotent
+	}
+	return false
+}
+
+// retryDelay returns the delay before the retry number attempt, with a random jitter
+// so concurrent requests do not hit the API at the same time again
+func retryDelay(attempt int) time.Duration {
+	delay := RetryBaseDelay
+	for i := 1; i < attempt && delay < RetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > RetryMaxDelay {
+		delay = RetryMaxDelay
+	}
+	jitterLock.Lock()
+	defer jitterLock.Unlock()
+	return delay/2 + time.Duration(jitter.Int63n(int64(delay/2)+1))
+}
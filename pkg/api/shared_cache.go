@@ -0,0 +1,83 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// SharedCacheTTL is the time a shared cache is kept in ResponseCache before being fetched again
+var SharedCacheTTL = 10 * time.Minute
+
+// sharedCacheURL returns the HTTP(S) URL of a shared cache, s3://BUCKET/KEY is read from the object storage of region
+func sharedCacheURL(rawURL, region string) (string, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid shared cache URL %q: %v", rawURL, err)
+	}
+	switch parsed.Scheme {
+	case "http", "https":
+		return rawURL, nil
+	case "s3":
+		endpoint := "https://s3.fr-par.scw.cloud"
+		if region == "ams1" {
+			endpoint = "https://s3.nl-ams.scw.cloud"
+		}
+		return fmt.Sprintf("%s/%s/%s", endpoint, parsed.Host, strings.TrimLeft(parsed.Path, "/")), nil
+	}
+	return "", fmt.Errorf("invalid shared cache URL %q: expected http, https or s3", rawURL)
+}
+
+// MergeSharedCache fetches a cache file published by the team at rawURL, read-only, and merges it
+// with the local cache, so everyone resolves the same names without listing everything from the API
+func (s *ScalewayAPI) MergeSharedCache(rawURL string) error {
+	uri, err := sharedCacheURL(rawURL, s.Region)
+	if err != nil {
+		return err
+	}
+
+	var body []byte
+	if s.ResponseCache != nil {
+		if cached, age, ok := s.ResponseCache.Get(uri); ok && age < SharedCacheTTL && !s.RefreshResponses {
+			body = cached
+		}
+	}
+	if body == nil {
+		// the shared cache is not on the API, the token must not be sent
+		req, err := http.NewRequest("GET", uri, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("User-Agent", s.userAgent)
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("cannot fetch shared cache %s: %s", uri, resp.Status)
+		}
+		if body, err = readResponseBody(resp); err != nil {
+			return err
+		}
+		if s.ResponseCache != nil {
+			if err := s.ResponseCache.Put(uri, body); err != nil {
+				s.Debugf("Cannot cache %s: %v", uri, err)
+			}
+		}
+	}
+
+	var shared ScalewayCache
+	if err := json.Unmarshal(body, &shared); err != nil {
+		return fmt.Errorf("invalid shared cache %s: %v", uri, err)
+	}
+	s.Cache.Merge(&shared)
+	return nil
+}
@@ -0,0 +1,90 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// RequestStats aggregates the API requests per method, its Record method is an AfterRequest hook
+type RequestStats struct {
+	lock    sync.Mutex
+	methods map[string]*methodStats
+}
+
+// methodStats holds the requests of an HTTP method
+type methodStats struct {
+	Count  int
+	Errors int
+	Total  time.Duration
+	Max    time.Duration
+}
+
+// NewRequestStats returns an empty RequestStats
+func NewRequestStats() *RequestStats {
+	return &RequestStats{methods: make(map[string]*methodStats)}
+}
+
+// Record adds a request to the statistics, a request without response or answered with a status >= 400 is an error
+func (r *RequestStats) Record(req *http.Request, resp *http.Response, err error, duration time.Duration) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	stats, ok := r.methods[req.Method]
+	if !ok {
+		stats = &methodStats{}
+		r.methods[req.Method] = stats
+	}
+	stats.Count++
+	stats.Total += duration
+	if duration > stats.Max {
+		stats.Max = duration
+	}
+	if err != nil || resp.StatusCode >= 400 {
+		stats.Errors++
+	}
+}
+
+// Fprint writes a line per HTTP method and a total line
+func (r *RequestStats) Fprint(out io.Writer) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	methods := make([]string, 0, len(r.methods))
+	for method := range r.methods {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	w := tabwriter.NewWriter(out, 10, 1, 3, ' ', 0)
+	defer w.Flush()
+	fmt.Fprintf(w, "METHOD\tREQUESTS\tERRORS\tTOTAL\tAVERAGE\tMAX\n")
+	var total methodStats
+	printLine := func(name string, stats *methodStats) {
+		average := time.Duration(0)
+		if stats.Count > 0 {
+			average = stats.Total / time.Duration(stats.Count)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%v\t%v\t%v\n", name, stats.Count, stats.Errors,
+			stats.Total.Round(time.Millisecond), average.Round(time.Millisecond), stats.Max.Round(time.Millisecond))
+	}
+	for _, method := range methods {
+		stats := r.methods[method]
+		printLine(method, stats)
+		total.Count += stats.Count
+		total.Errors += stats.Errors
+		total.Total += stats.Total
+		if stats.Max > total.Max {
+			total.Max = stats.Max
+		}
+	}
+	printLine("TOTAL", &total)
+}
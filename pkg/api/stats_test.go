@@ -0,0 +1,30 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRequestStats(t *testing.T) {
+	Convey("Testing RequestStats", t, func() {
+		stats := NewRequestStats()
+		get, _ := http.NewRequest("GET", "https://api.example.com/servers", nil)
+		post, _ := http.NewRequest("POST", "https://api.example.com/servers", nil)
+		stats.Record(get, &http.Response{StatusCode: http.StatusOK}, nil, 100*time.Millisecond)
+		stats.Record(get, &http.Response{StatusCode: http.StatusTooManyRequests}, nil, 300*time.Millisecond)
+		stats.Record(post, nil, fmt.Errorf("connection refused"), 50*time.Millisecond)
+
+		So(*stats.methods["GET"], ShouldResemble, methodStats{Count: 2, Errors: 1, Total: 400 * time.Millisecond, Max: 300 * time.Millisecond})
+		So(*stats.methods["POST"], ShouldResemble, methodStats{Count: 1, Errors: 1, Total: 50 * time.Millisecond, Max: 50 * time.Millisecond})
+
+		var output bytes.Buffer
+		stats.Fprint(&output)
+		So(output.String(), ShouldContainSubstring, "TOTAL")
+		So(output.String(), ShouldContainSubstring, "450ms")
+	})
+}
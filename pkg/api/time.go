@@ -0,0 +1,60 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package api
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ScalewayTimeLayout is the layout used by the Scaleway API for dates
+const ScalewayTimeLayout = "2006-01-02T15:04:05.000000+00:00"
+
+// ScalewayTime represents a date returned by the Scaleway API, an empty or null date is the zero time.
+// The resources which are sent back to the API hold a *ScalewayTime, so an unset date is left out of
+// the payload by omitempty instead of being encoded as null
+type ScalewayTime struct {
+	time.Time
+}
+
+// Value returns the date, or the zero time when t is nil
+func (t *ScalewayTime) Value() time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return t.Time
+}
+
+// UnmarshalJSON parses a date returned by the Scaleway API
+func (t *ScalewayTime) UnmarshalJSON(data []byte) error {
+	raw := strings.Trim(string(data), `"`)
+	if raw == "" || raw == "null" {
+		t.Time = time.Time{}
+		return nil
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, raw)
+	if err != nil {
+		return fmt.Errorf("unable to parse date %q from the Scaleway API: %v", raw, err)
+	}
+	t.Time = parsed
+	return nil
+}
+
+// MarshalJSON encodes the date the way the Scaleway API does, the zero time is encoded as null
+func (t ScalewayTime) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(`"` + t.APIString() + `"`), nil
+}
+
+// APIString returns the date formatted the way the Scaleway API does, or an empty string for the zero time or a nil t
+func (t *ScalewayTime) APIString() string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(ScalewayTimeLayout)
+}
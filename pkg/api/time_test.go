@@ -0,0 +1,29 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestScalewayTime(t *testing.T) {
+	Convey("Testing ScalewayTime JSON encoding", t, func() {
+		var task ScalewayTask
+		err := json.Unmarshal([]byte(`{"started_at": "2019-04-01T10:30:00.123456+00:00", "terminated_at": null}`), &task)
+		So(err, ShouldBeNil)
+		So(task.StartDate.Unix(), ShouldEqual, 1554114600)
+		So(task.TerminationDate, ShouldBeNil)
+		So(task.TerminationDate.Value().IsZero(), ShouldBeTrue)
+
+		So(task.StartDate.APIString(), ShouldEqual, "2019-04-01T10:30:00.123456+00:00")
+		encoded, err := json.Marshal(task.TerminationDate)
+		So(err, ShouldBeNil)
+		So(string(encoded), ShouldEqual, "null")
+
+		encoded, err = json.Marshal(ScalewayVolume{Identifier: "volume"})
+		So(err, ShouldBeNil)
+		So(string(encoded), ShouldNotContainSubstring, "creation_date")
+		So(string(encoded), ShouldNotContainSubstring, "modification_date")
+	})
+}
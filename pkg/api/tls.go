@@ -0,0 +1,62 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+)
+
+// TLSOptions configures how the API client verifies the server and authenticates itself
+type TLSOptions struct {
+	// CAFile is a PEM bundle of the authorities trusted in addition to the system ones
+	CAFile string
+
+	// CertFile and KeyFile are the PEM client certificate and its key
+	CertFile string
+	KeyFile  string
+
+	// InsecureSkipVerify accepts any certificate presented by the server
+	InsecureSkipVerify bool
+}
+
+// NewTLSConfig returns the TLS configuration described by options
+func NewTLSConfig(options TLSOptions) (*tls.Config, error) {
+	config := &tls.Config{InsecureSkipVerify: options.InsecureSkipVerify}
+	if options.CAFile != "" {
+		bundle, err := ioutil.ReadFile(options.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read CA bundle: %v", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("no PEM certificate found in %s", options.CAFile)
+		}
+		config.RootCAs = pool
+	}
+	if (options.CertFile == "") != (options.KeyFile == "") {
+		return nil, fmt.Errorf("a client certificate needs both a certificate and a key file")
+	}
+	if options.CertFile != "" {
+		certificate, err := tls.LoadX509KeyPair(options.CertFile, options.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load client certificate: %v", err)
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+	return config, nil
+}
+
+// WithTLSConfig makes the API client use config for its TLS connections
+func WithTLSConfig(config *tls.Config) func(*ScalewayAPI) {
+	return func(s *ScalewayAPI) {
+		s.tlsConfig = config
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tracer writes a JSON line per operation of a command: the API requests, the name resolutions
+// and the decisions, so a misbehaving command can be reported with its full context.
+// A nil Tracer traces nothing
+type Tracer struct {
+	lock sync.Mutex
+	out  io.Writer
+}
+
+// NewTracer returns a Tracer writing to out
+func NewTracer(out io.Writer) *Tracer {
+	return &Tracer{out: out}
+}
+
+// Event writes an event of kind with its fields
+func (t *Tracer) Event(kind string, fields map[string]interface{}) {
+	if t == nil {
+		return
+	}
+	event := map[string]interface{}{}
+	for key, value := range fields {
+		event[key] = value
+	}
+	event["time"] = time.Now().UTC().Format(time.RFC3339Nano)
+	event["kind"] = kind
+	line, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.out.Write(append(line, '\n'))
+}
+
+// traceTokenPath matches the token in the path of the account API requests, i.e: tokens/<token>/permissions
+var traceTokenPath = regexp.MustCompile(`(tokens/)[^/?#"\s]+`)
+
+// Request traces an API request, the headers and the bodies are left out and the token is redacted
+// from the URL and from the error
+func (t *Tracer) Request(req *http.Request, resp *http.Response, err error, duration time.Duration) {
+	if t == nil {
+		return
+	}
+	redact := func(text string) string {
+		if token := req.Header.Get("X-Auth-Token"); token != "" {
+			text = strings.Replace(text, token, "REDACTED", -1)
+		}
+		return traceTokenPath.ReplaceAllString(text, "${1}REDACTED")
+	}
+	fields := map[string]interface{}{
+		"method":      req.Method,
+		"url":         redact(req.URL.String()),
+		"duration_ms": int64(duration / time.Millisecond),
+	}
+	if err != nil {
+		fields["error"] = redact(err.Error())
+	} else {
+		fields["status"] = resp.StatusCode
+	}
+	t.Event("request", fields)
+}
+
+// traceResolve traces the resolution of needle into results
+func (s *ScalewayAPI) traceResolve(kind, needle string, results ScalewayResolverResults, err error) {
+	if s.Tracer == nil {
+		return
+	}
+	matches := make([]string, 0, len(results))
+	for _, result := range results {
+		matches = append(matches, result.Identifier)
+	}
+	fields := map[string]interface{}{
+		"type":    kind,
+		"needle":  needle,
+		"matches": matches,
+	}
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+	s.Tracer.Event("resolve", fields)
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTracer(t *testing.T) {
+	Convey("Testing Tracer", t, func() {
+		var tracer *Tracer
+		tracer.Event("command", nil)
+
+		var out bytes.Buffer
+		tracer = NewTracer(&out)
+		req, _ := http.NewRequest("GET", "https://cp-par1.scaleway.com/servers", nil)
+		tracer.Request(req, &http.Response{StatusCode: http.StatusOK}, nil, 1500*time.Millisecond)
+
+		var event map[string]interface{}
+		So(json.Unmarshal(out.Bytes(), &event), ShouldBeNil)
+		So(event["kind"], ShouldEqual, "request")
+		So(event["method"], ShouldEqual, "GET")
+		So(event["status"], ShouldEqual, 200.0)
+		So(event["duration_ms"], ShouldEqual, 1500.0)
+
+		out.Reset()
+		token := "a2f1b7c4-0000-4000-8000-000000000000"
+		req, _ = http.NewRequest("GET", "https://account.scaleway.com/tokens/"+token+"/permissions", nil)
+		req.Header.Set("X-Auth-Token", token)
+		tracer.Request(req, nil, fmt.Errorf("Get %s: connection refused", req.URL), time.Second)
+		So(out.String(), ShouldNotContainSubstring, token)
+		So(json.Unmarshal(out.Bytes(), &event), ShouldBeNil)
+		So(event["url"], ShouldEqual, "https://account.scaleway.com/tokens/REDACTED/permissions")
+	})
+}
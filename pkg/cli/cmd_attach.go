@@ -4,13 +4,16 @@
 
 package cli
 
-import "github.com/scaleway/scaleway-cli/pkg/commands"
+import (
+	"github.com/scaleway/scaleway-cli/pkg/commands"
+	"github.com/scaleway/scaleway-cli/pkg/utils"
+)
 
 var cmdAttach = &Command{
 	Exec:        runAttach,
 	UsageLine:   "attach [OPTIONS] SERVER",
 	Description: "Attach to a server serial console",
-	Help:        "Attach to a running server serial console.",
+	Help:        "Attach to a running server serial console, type '~.' at the beginning of a line or the detach keys to detach.",
 	Examples: `
     $ scw attach my-running-server
     $ scw attach $(scw start my-stopped-server)
@@ -21,11 +24,13 @@ var cmdAttach = &Command{
 func init() {
 	cmdAttach.Flag.BoolVar(&attachHelp, []string{"h", "-help"}, false, "Print usage")
 	cmdAttach.Flag.BoolVar(&attachNoStdin, []string{"-no-stdin"}, false, "Do not attach stdin")
+	cmdAttach.Flag.StringVar(&attachDetachKeys, []string{"-detach-keys"}, utils.DefaultDetachKeys, "Key sequence for detaching from the console")
 }
 
 // Flags
-var attachHelp bool    // -h, --help flag
-var attachNoStdin bool // --no-stdin flag
+var attachHelp bool         // -h, --help flag
+var attachNoStdin bool      // --no-stdin flag
+var attachDetachKeys string // --detach-keys flag
 
 func runAttach(cmd *Command, rawArgs []string) error {
 	if attachHelp {
@@ -36,8 +41,9 @@ func runAttach(cmd *Command, rawArgs []string) error {
 	}
 
 	args := commands.AttachArgs{
-		NoStdin: attachNoStdin,
-		Server:  rawArgs[0],
+		NoStdin:    attachNoStdin,
+		DetachKeys: attachDetachKeys,
+		Server:     rawArgs[0],
 	}
 	ctx := cmd.GetContext(rawArgs)
 	return commands.RunAttach(ctx, args)
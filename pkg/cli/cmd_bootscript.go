@@ -0,0 +1,86 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package cli
+
+import (
+	"fmt"
+
+	"github.com/scaleway/scaleway-cli/pkg/commands"
+)
+
+var cmdBootscript = &Command{
+	Exec:        runBootscript,
+	UsageLine:   "bootscript [OPTIONS] SUBCOMMAND [ARGS...]",
+	Description: "Browse bootscripts",
+	Help: `Browse bootscripts.
+
+Subcommands:
+    ls                       List bootscripts, defaults first
+    inspect BOOTSCRIPT...    Return low-level information on one or more bootscripts
+
+Deprecated bootscripts are only listed with --all.`,
+	Examples: `
+    $ scw bootscript ls
+    $ scw bootscript ls --arch=arm
+    $ scw bootscript ls -q --arch=x86_64
+    $ scw bootscript inspect --arch=x86_64 mainline
+`,
+}
+
+func init() {
+	cmdBootscript.Flag.BoolVar(&bootscriptHelp, []string{"h", "-help"}, false, "Print usage")
+	cmdBootscript.Flag.StringVar(&bootscriptArch, []string{"-arch"}, "", "Only match bootscripts of this architecture (arm, arm64, x86_64)")
+	cmdBootscript.Flag.BoolVar(&bootscriptAll, []string{"a", "-all"}, false, "Show deprecated bootscripts")
+	cmdBootscript.Flag.BoolVar(&bootscriptQuiet, []string{"q", "-quiet"}, false, "Only display numeric IDs")
+	cmdBootscript.Flag.BoolVar(&bootscriptNoTrunc, []string{"-no-trunc"}, false, "Don't truncate output")
+	subCmdBootscript = map[string]func(cmd *Command, args []string) error{
+		"ls":      bootscriptList,
+		"inspect": bootscriptInspect,
+	}
+}
+
+// Flags
+var bootscriptHelp bool    // -h, --help flag
+var bootscriptArch string  // --arch flag
+var bootscriptAll bool     // -a, --all flag
+var bootscriptQuiet bool   // -q, --quiet flag
+var bootscriptNoTrunc bool // --no-trunc flag
+
+var subCmdBootscript map[string]func(cmd *Command, args []string) error
+
+func bootscriptList(cmd *Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.PrintShortUsage()
+	}
+	ctx := cmd.GetContext(args)
+	return commands.RunBootscriptList(ctx, commands.BootscriptListArgs{
+		Arch:    bootscriptArch,
+		All:     bootscriptAll,
+		Quiet:   bootscriptQuiet,
+		NoTrunc: bootscriptNoTrunc,
+	})
+}
+
+func bootscriptInspect(cmd *Command, args []string) error {
+	if len(args) == 0 {
+		return cmd.PrintShortUsage()
+	}
+	ctx := cmd.GetContext(args)
+	return commands.RunBootscriptInspect(ctx, commands.BootscriptInspectArgs{
+		Arch:        bootscriptArch,
+		Bootscripts: args,
+	})
+}
+
+func runBootscript(cmd *Command, args []string) error {
+	if bootscriptHelp || len(args) == 0 {
+		return cmd.PrintUsage()
+	}
+	cmd.Flag.Parse(args[1:])
+	if function, ok := subCmdBootscript[args[0]]; ok {
+		return function(cmd, cmd.Flag.Args())
+	}
+	return fmt.Errorf("subcommand not found: %s", args[0])
+}
@@ -14,17 +14,20 @@ var cmdCommit = &Command{
 	Examples: `
     $ scw commit my-stopped-server
     $ scw commit -v 1 my-stopped-server
+    $ scw --notify-url=https://chat.example.com/hooks/ops commit -w my-stopped-server
 `,
 }
 
 func init() {
 	cmdCommit.Flag.IntVar(&commitVolume, []string{"v", "-volume"}, 0, "Volume slot")
 	cmdCommit.Flag.BoolVar(&commitHelp, []string{"h", "-help"}, false, "Print usage")
+	cmdCommit.Flag.BoolVar(&commitWait, []string{"w", "-wait"}, false, "Wait for the snapshot to be done")
 }
 
 // Flags
 var commitVolume int // -v, --volume flag
 var commitHelp bool  // -h, --help flag
+var commitWait bool  // -w, --wait flag
 
 func runCommit(cmd *Command, rawArgs []string) error {
 	if commitHelp {
@@ -38,6 +41,7 @@ func runCommit(cmd *Command, rawArgs []string) error {
 		Volume: commitVolume,
 		Server: rawArgs[0],
 		Name:   "",
+		Wait:   commitWait,
 	}
 	if len(rawArgs) > 1 {
 		args.Name = rawArgs[1]
@@ -10,7 +10,7 @@ var cmdCp = &Command{
 	Exec:        runCp,
 	UsageLine:   "cp [OPTIONS] SERVER:PATH|HOSTPATH|- SERVER:PATH|HOSTPATH|-",
 	Description: "Copy files/folders from a PATH on the server to a HOSTDIR on the host",
-	Help:        "Copy files/folders from a PATH on the server to a HOSTDIR on the host\nrunning the command. Use '-' to write the data as a tar file to STDOUT.",
+	Help:        "Copy files/folders from a PATH on the server to a HOSTDIR on the host\nrunning the command. Use '-' to write the data as a tar file to STDOUT.\nThe progress of the copy is displayed when running in a terminal.",
 	Examples: `
     $ scw cp path/to/my/local/file myserver:path
     $ scw cp --gateway=myotherserver path/to/my/local/file myserver:path
@@ -25,6 +25,8 @@ var cmdCp = &Command{
     $ scw cp myserver:path/to/dir  - | tar -tvf -
     $ cat archive.tar | scw cp - myserver:/path
     $ tar -cvf - . | scw cp - myserver:path
+    $ scw cp --compress myserver:/var/log path/to/my/local/dir
+    $ scw cp --resume path/to/my/local/dir myserver:path
 `,
 }
 
@@ -33,6 +35,8 @@ func init() {
 	cmdCp.Flag.StringVar(&cpGateway, []string{"g", "-gateway"}, "", "Use a SSH gateway")
 	cmdCp.Flag.StringVar(&cpSSHUser, []string{"-user"}, "root", "Specify SSH user")
 	cmdCp.Flag.IntVar(&cpSSHPort, []string{"p", "-port"}, 22, "Specify SSH port")
+	cmdCp.Flag.BoolVar(&cpCompress, []string{"z", "-compress"}, false, "Compress the tar stream with gzip")
+	cmdCp.Flag.BoolVar(&cpResume, []string{"-resume"}, false, "Skip the files already copied by an interrupted copy")
 }
 
 // Flags
@@ -40,6 +44,8 @@ var cpHelp bool      // -h, --help flag
 var cpGateway string // -g, --gateway flag
 var cpSSHUser string // --user flag
 var cpSSHPort int    // -p, --port flag
+var cpCompress bool  // -z, --compress flag
+var cpResume bool    // --resume flag
 
 func runCp(cmd *Command, rawArgs []string) error {
 	if cpHelp {
@@ -55,6 +61,8 @@ func runCp(cmd *Command, rawArgs []string) error {
 		Destination: rawArgs[1],
 		SSHUser:     cpSSHUser,
 		SSHPort:     cpSSHPort,
+		Compress:    cpCompress,
+		Resume:      cpResume,
 	}
 	ctx := cmd.GetContext(rawArgs)
 	return commands.RunCp(ctx, args)
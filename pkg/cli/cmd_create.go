@@ -7,6 +7,7 @@ package cli
 import (
 	"strings"
 
+	"github.com/scaleway/scaleway-cli/pkg/api"
 	"github.com/scaleway/scaleway-cli/pkg/commands"
 )
 
@@ -22,16 +23,20 @@ var cmdCreate = &Command{
     $ scw inspect $(scw create 1GB --bootscript=rescue --volume=50GB)
     $ scw create $(scw tag my-snapshot my-image)
     $ scw create --tmp-ssh-key 10GB
+    $ scw create --name-prefix=worker ubuntu-xenial
 `,
 }
 
 func init() {
 	cmdCreate.Flag.StringVar(&createName, []string{"-name"}, "", "Assign a name")
+	cmdCreate.Flag.StringVar(&createNamePrefix, []string{"-name-prefix"}, "", "Prefix the generated name when --name is not set")
 	cmdCreate.Flag.StringVar(&createBootscript, []string{"-bootscript"}, "", "Assign a bootscript")
+	cmdCreate.Flag.StringVar(&createPullPolicy, []string{"-pull-policy"}, "missing", "Image name resolution: 'missing' uses the cache, 'always' refreshes the marketplace to use the latest version")
+	cmdCreate.Flag.BoolVar(&createForceBootscript, []string{"-force-bootscript"}, false, "Assign the bootscript even if it is deprecated or doesn't match the image architecture")
 	cmdCreate.Flag.StringVar(&createEnv, []string{"e", "-env"}, "", "Provide metadata tags passed to initrd (i.e., boot=rescue INITRD_DEBUG=1)")
 	cmdCreate.Flag.StringVar(&createVolume, []string{"v", "-volume"}, "", "Attach additional volume (i.e., 50G)")
 	cmdCreate.Flag.StringVar(&createIPAddress, []string{"-ip-address"}, "dynamic", "Assign a reserved public IP, a 'dynamic' one or 'none'")
-	cmdCreate.Flag.StringVar(&createCommercialType, []string{"-commercial-type"}, "X64-2GB", "Create a server with specific commercial-type C1, C2[S|M|L], X64-[2|4|8|15|30|60|120]GB, ARM64-[2|4|8]GB")
+	cmdCreate.Flag.StringVar(&createCommercialType, []string{"-commercial-type"}, api.DefaultCommercialType, "Create a server with specific commercial-type C1, C2[S|M|L], X64-[2|4|8|15|30|60|120]GB, ARM64-[2|4|8]GB")
 	cmdCreate.Flag.StringVar(&createBootType, []string{"-boot-type"}, "auto", "Choose between 'local' and 'bootscript' boot")
 	cmdCreate.Flag.BoolVar(&createHelp, []string{"h", "-help"}, false, "Print usage")
 	cmdCreate.Flag.BoolVar(&createIPV6, []string{"-ipv6"}, false, "Enable IPV6")
@@ -40,6 +45,7 @@ func init() {
 
 // Flags
 var createName string           // --name flag
+var createNamePrefix string     // --name-prefix flag
 var createBootscript string     // --bootscript flag
 var createEnv string            // -e, --env flag
 var createVolume string         // -v, --volume flag
@@ -49,6 +55,8 @@ var createIPAddress string      // --ip-address flag
 var createCommercialType string // --commercial-type flag
 var createIPV6 bool             // --ipv6 flag
 var createBootType string       // --boot-type flag
+var createForceBootscript bool  // --force-bootscript flag
+var createPullPolicy string     // --pull-policy flag
 
 func runCreate(cmd *Command, rawArgs []string) error {
 	if createHelp {
@@ -59,14 +67,17 @@ func runCreate(cmd *Command, rawArgs []string) error {
 	}
 
 	args := commands.CreateArgs{
-		Name:           createName,
-		Bootscript:     createBootscript,
-		Image:          rawArgs[0],
-		TmpSSHKey:      createTmpSSHKey,
-		IP:             createIPAddress,
-		CommercialType: createCommercialType,
-		IPV6:           createIPV6,
-		BootType:       createBootType,
+		Name:            createName,
+		NamePrefix:      createNamePrefix,
+		Bootscript:      createBootscript,
+		Image:           rawArgs[0],
+		TmpSSHKey:       createTmpSSHKey,
+		IP:              createIPAddress,
+		CommercialType:  createCommercialType,
+		IPV6:            createIPV6,
+		BootType:        createBootType,
+		ForceBootscript: createForceBootscript,
+		PullPolicy:      createPullPolicy,
 	}
 
 	if len(createEnv) > 0 {
@@ -10,7 +10,7 @@ var cmdExec = &Command{
 	Exec:        runExec,
 	UsageLine:   "exec [OPTIONS] SERVER [COMMAND] [ARGS...]",
 	Description: "Run a command on a running server",
-	Help:        "Run a command on a running server.",
+	Help:        "Run a command on a running server.\nThe remote stdout and stderr are forwarded to the local ones and scw exits with\nthe exit code of the remote command, or 124 when it is killed by a timeout.",
 	Examples: `
     $ scw exec myserver
     $ scw exec myserver bash
@@ -21,28 +21,43 @@ var cmdExec = &Command{
     $ scw exec $(scw start -w $(scw create ubuntu-trusty)) bash
     $ scw exec myserver tmux new -d sleep 10
     $ scw exec myserver ls -la | grep password
+    $ scw exec myserver make test > test.log 2> errors.log || echo "tests failed"
+    $ scw exec --timeout=600 --idle-timeout=60 myserver ./provision.sh
     $ cat local-file | scw exec myserver 'cat > remote/path'
+    $ scw exec --script=deploy.sh myserver v1.2.3
+    $ scw exec --script=setup.sh --sudo --user=ubuntu myserver
+    $ scw exec --script=- --shell=bash myserver <<EOF
+    echo $HOSTNAME
+    EOF
 `,
 }
 
 func init() {
 	cmdExec.Flag.BoolVar(&execHelp, []string{"h", "-help"}, false, "Print usage")
-	cmdExec.Flag.Float64Var(&execTimeout, []string{"T", "-timeout"}, 0, "Set timeout values to seconds")
+	cmdExec.Flag.Float64Var(&execTimeout, []string{"T", "-timeout"}, 0, "Kill the command after this number of seconds")
+	cmdExec.Flag.Float64Var(&execIdleTimeout, []string{"-idle-timeout"}, 0, "Kill the command after this number of seconds without output")
 	cmdExec.Flag.BoolVar(&execW, []string{"w", "-wait"}, false, "Wait for SSH to be ready")
 	cmdExec.Flag.StringVar(&execGateway, []string{"g", "-gateway"}, "", "Use a SSH gateway")
 	cmdExec.Flag.StringVar(&execSSHUser, []string{"-user"}, "root", "Specify SSH user")
 	cmdExec.Flag.IntVar(&execSSHPort, []string{"p", "-port"}, 22, "Specify SSH port")
 	cmdExec.Flag.BoolVar(&execEnableSSHKeyForwarding, []string{"A"}, false, "Enable SSH keys forwarding")
+	cmdExec.Flag.StringVar(&execScript, []string{"-script"}, "", "Execute a local script ('-' for stdin), ARGS are passed to the script")
+	cmdExec.Flag.StringVar(&execShell, []string{"-shell"}, "sh", "Shell used to execute the script")
+	cmdExec.Flag.BoolVar(&execSudo, []string{"-sudo"}, false, "Run the command or the script with sudo")
 }
 
 // Flags
 var execW bool                      // -w, --wait flag
 var execTimeout float64             // -T flag
+var execIdleTimeout float64         // --idle-timeout flag
 var execHelp bool                   // -h, --help flag
 var execGateway string              // -g, --gateway flag
 var execSSHUser string              // --user flag
 var execSSHPort int                 // -p, --port flag
 var execEnableSSHKeyForwarding bool // -A flag
+var execScript string               // --script flag
+var execShell string                // --shell flag
+var execSudo bool                   // --sudo flag
 
 func runExec(cmd *Command, rawArgs []string) error {
 	if execHelp {
@@ -54,6 +69,7 @@ func runExec(cmd *Command, rawArgs []string) error {
 
 	args := commands.ExecArgs{
 		Timeout:                execTimeout,
+		IdleTimeout:            execIdleTimeout,
 		Wait:                   execW,
 		Gateway:                execGateway,
 		Server:                 rawArgs[0],
@@ -61,6 +77,9 @@ func runExec(cmd *Command, rawArgs []string) error {
 		SSHUser:                execSSHUser,
 		SSHPort:                execSSHPort,
 		EnableSSHKeyForwarding: execEnableSSHKeyForwarding,
+		Script:                 execScript,
+		Shell:                  execShell,
+		Sudo:                   execSudo,
 	}
 	ctx := cmd.GetContext(rawArgs)
 	return commands.RunExec(ctx, args)
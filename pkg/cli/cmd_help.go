@@ -45,6 +45,32 @@ Options:
  -v, --version=false          Print version information and quit
  --region=par1                Change the default region (e.g. ams1)
  -c, --config=<config path>   Option config file path (default to ~/.scwrc)
+ --time-format=relative       Display dates as relative, iso or unix
+ --report-format=""           Report the outcome of multi-target commands as a table or json
+ --wait-conflicts=false       Wait for the conflicting task and retry server actions rejected with 409
+ --wait-transition=false      Wait for a starting, stopping or allocating server to settle before acting on it
+ --check-token=false          Check the API token before running the command and report when it expires soon
+ --renew-token=0s             With --check-token, renew the API token when it expires within this duration
+ --notify-url=""              POST a JSON payload to this URL when a waited-on operation finishes
+ --stats=false                Print the number and duration of the API requests on exit
+ --api-prefer-ipv6=false      Connect to the API over IPv6 first, IPv4 is tried 300ms later
+ --no-interactive=false       Fail instead of prompting when a name matches several resources
+ --max-rate-wait=1m0s         Maximum time to wait and retry when the API rate limits a request (429)
+ --timeout=2m0s               Time limit of each API request, 0 disables it
+ --proxy=""                   Send the API requests through this proxy instead of HTTP_PROXY/HTTPS_PROXY
+ --tls-ca-cert=""             Trust the authorities of this PEM bundle too when connecting to the API
+ --tls-cert=""                Authenticate to the API with this PEM client certificate
+ --tls-key=""                 Private key of the --tls-cert client certificate
+ --insecure-skip-verify=false Do not verify the certificate of the API (same as SCW_TLSVERIFY=0)
+ --trace-file=""              Write a JSON line per API request, name resolution and outcome to this file
+ --dry-run=false              Print the API requests modifying resources instead of sending them
+ --ssh-proxy=""               Make the SSH connections through this SOCKS5 proxy, i.e: socks5://host:1080
+ --api-retries=3              Number of attempts of an API request failing transiently
+ --ssh-connect-timeout=0s     Time limit to open the SSH connections, 0 keeps the default of ssh
+ --wait-poll-interval=1s      Delay between two requests when waiting for a state
+ --unique-names=false         Fail instead of warning when creating a server or an image named like an existing one
+ --api-version=""             Version prefixed to the paths of the compute and account APIs, i.e: v2
+ --show-curl=false            Print the curl command equivalent to each API request on stderr
 
 Commands:
 {{range .}}{{if not .Hidden}}    {{.Name | printf "%-9s"}} {{.Description}}
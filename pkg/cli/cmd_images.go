@@ -5,10 +5,9 @@
 package cli
 
 import (
-	"strings"
+	"fmt"
 
 	"github.com/scaleway/scaleway-cli/pkg/commands"
-	"github.com/sirupsen/logrus"
 )
 
 var cmdImages = &Command{
@@ -33,6 +32,12 @@ var cmdImages = &Command{
     $ scw images -f public=true
     $ scw images -f public=false
     $ scw images -f "organization=me type=volume" -q
+    $ scw images -f "type=snapshot created-before=2w" -q
+    $ scw images -f modified-after=2019-04-01T00:00:00Z
+    $ scw images --check-updates
+    $ scw images --orphans
+    $ scw rmi $(scw images --orphans -q)
+    $ scw images --tree
 `,
 }
 
@@ -41,15 +46,21 @@ func init() {
 	cmdImages.Flag.BoolVar(&imagesNoTrunc, []string{"-no-trunc"}, false, "Don't truncate output")
 	cmdImages.Flag.BoolVar(&imagesQ, []string{"q", "-quiet"}, false, "Only show numeric IDs")
 	cmdImages.Flag.BoolVar(&imagesHelp, []string{"h", "-help"}, false, "Print usage")
+	cmdImages.Flag.BoolVar(&imagesCheckUpdates, []string{"-check-updates"}, false, "List servers built from an outdated image version")
+	cmdImages.Flag.BoolVar(&imagesOrphans, []string{"-orphans"}, false, "List images whose root snapshot and snapshots whose base volume no longer exist")
+	cmdImages.Flag.BoolVar(&imagesTree, []string{"-tree"}, false, "Show the images with their root snapshot and the servers using them")
 	cmdImages.Flag.StringVar(&imagesFilters, []string{"f", "-filter"}, "", "Filter output based on conditions provided")
 }
 
 // Flags
-var imagesA bool         // -a flag
-var imagesQ bool         // -q flag
-var imagesNoTrunc bool   // -no-trunc flag
-var imagesHelp bool      // -h, --help flag
-var imagesFilters string // -f, --filters
+var imagesA bool            // -a flag
+var imagesQ bool            // -q flag
+var imagesNoTrunc bool      // -no-trunc flag
+var imagesHelp bool         // -h, --help flag
+var imagesFilters string    // -f, --filters
+var imagesCheckUpdates bool // --check-updates flag
+var imagesOrphans bool      // --orphans flag
+var imagesTree bool         // --tree flag
 
 func runImages(cmd *Command, rawArgs []string) error {
 	if imagesHelp {
@@ -58,27 +69,26 @@ func runImages(cmd *Command, rawArgs []string) error {
 	if len(rawArgs) != 0 {
 		return cmd.PrintShortUsage()
 	}
+	if imagesOrphans && imagesCheckUpdates {
+		return fmt.Errorf("conflicting options: --orphans and --check-updates")
+	}
+	if imagesTree && (imagesOrphans || imagesCheckUpdates || imagesQ) {
+		return fmt.Errorf("conflicting options: --tree cannot be used with --orphans, --check-updates or --quiet")
+	}
 
 	args := commands.ImagesArgs{
-		All:     imagesA,
-		Quiet:   imagesQ,
-		NoTrunc: imagesNoTrunc,
-		Filters: make(map[string]string, 0),
+		All:          imagesA,
+		Quiet:        imagesQ,
+		NoTrunc:      imagesNoTrunc,
+		CheckUpdates: imagesCheckUpdates,
+		Orphans:      imagesOrphans,
+		Tree:         imagesTree,
 	}
-	if imagesFilters != "" {
-		for _, filter := range strings.Split(imagesFilters, " ") {
-			parts := strings.SplitN(filter, "=", 2)
-			if len(parts) != 2 {
-				logrus.Warnf("Invalid filter '%s', should be in the form 'key=value'", filter)
-				continue
-			}
-			if _, ok := args.Filters[parts[0]]; ok {
-				logrus.Warnf("Duplicated filter: %q", parts[0])
-			} else {
-				args.Filters[parts[0]] = parts[1]
-			}
-		}
+	filters, err := cmd.parseFilters(imagesFilters)
+	if err != nil {
+		return err
 	}
+	args.Filters = filters
 	ctx := cmd.GetContext(rawArgs)
 	return commands.RunImages(ctx, args)
 }
@@ -0,0 +1,133 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package cli
+
+import (
+	"fmt"
+
+	"github.com/scaleway/scaleway-cli/pkg/commands"
+)
+
+var cmdIP = &Command{
+	Exec:        runIP,
+	UsageLine:   "ip [OPTIONS] SUBCOMMAND [ARGS...]",
+	Description: "Manage reserved IPs",
+	Help: `Manage reserved IPs.
+
+Subcommands:
+    ls                        List reserved IPs, an IP attached to a deleted server is orphaned
+    create                    Reserve a new IP
+    rm IP...                  Release one or more IPs
+    attach IP SERVER          Attach an IP to a server
+    detach IP...              Detach one or more IPs from their server
+    set-reverse IP [REVERSE]  Set the reverse DNS of an IP, without REVERSE it is removed`,
+	Examples: `
+    $ scw ip ls
+    $ scw ip ls --orphans -q
+    $ scw ip ls --json
+    $ scw ip attach $(scw ip create) my-server
+    $ scw ip set-reverse 51.15.1.2 www.example.com
+    $ scw ip rm $(scw ip ls --orphans -q)
+`,
+}
+
+func init() {
+	cmdIP.Flag.BoolVar(&ipGroupHelp, []string{"h", "-help"}, false, "Print usage")
+	cmdIP.Flag.BoolVar(&ipGroupQuiet, []string{"q", "-quiet"}, false, "Only display numeric IDs")
+	cmdIP.Flag.BoolVar(&ipGroupJSON, []string{"-json"}, false, "Display the IPs as JSON")
+	cmdIP.Flag.BoolVar(&ipGroupOrphans, []string{"-orphans"}, false, "Only display the IPs attached to a deleted server")
+	subCmdIP = map[string]func(cmd *Command, args []string) error{
+		"ls":          ipList,
+		"create":      ipCreate,
+		"rm":          ipRemove,
+		"attach":      ipAttachServer,
+		"detach":      ipDetachServer,
+		"set-reverse": ipSetReverse,
+	}
+}
+
+// Flags
+var ipGroupHelp bool    // -h, --help flag
+var ipGroupQuiet bool   // -q, --quiet flag
+var ipGroupJSON bool    // --json flag
+var ipGroupOrphans bool // --orphans flag
+
+var subCmdIP map[string]func(cmd *Command, args []string) error
+
+func ipList(cmd *Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.PrintShortUsage()
+	}
+	if ipGroupQuiet && ipGroupJSON {
+		return fmt.Errorf("conflicting options: -q and --json")
+	}
+	ctx := cmd.GetContext(args)
+	return commands.RunIPList(ctx, commands.IPListArgs{
+		Quiet:   ipGroupQuiet,
+		JSON:    ipGroupJSON,
+		Orphans: ipGroupOrphans,
+	})
+}
+
+func ipCreate(cmd *Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.PrintShortUsage()
+	}
+	ctx := cmd.GetContext(args)
+	return commands.RunIPCreate(ctx)
+}
+
+func ipRemove(cmd *Command, args []string) error {
+	if len(args) == 0 {
+		return cmd.PrintShortUsage()
+	}
+	ctx := cmd.GetContext(args)
+	return commands.RunIPRemove(ctx, commands.IPsArgs{IPs: args})
+}
+
+func ipAttachServer(cmd *Command, args []string) error {
+	if len(args) != 2 {
+		return cmd.PrintShortUsage()
+	}
+	ctx := cmd.GetContext(args)
+	return commands.RunIPAttach(ctx, commands.IPAttachArgs{
+		IP:     args[0],
+		Server: args[1],
+	})
+}
+
+func ipDetachServer(cmd *Command, args []string) error {
+	if len(args) == 0 {
+		return cmd.PrintShortUsage()
+	}
+	ctx := cmd.GetContext(args)
+	return commands.RunIPDetach(ctx, commands.IPsArgs{IPs: args})
+}
+
+func ipSetReverse(cmd *Command, args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return cmd.PrintShortUsage()
+	}
+	reverse := ""
+	if len(args) == 2 {
+		reverse = args[1]
+	}
+	ctx := cmd.GetContext(args)
+	return commands.RunIPSetReverse(ctx, commands.IPSetReverseArgs{
+		IP:      args[0],
+		Reverse: reverse,
+	})
+}
+
+func runIP(cmd *Command, args []string) error {
+	if ipGroupHelp || len(args) == 0 {
+		return cmd.PrintUsage()
+	}
+	cmd.Flag.Parse(args[1:])
+	if function, ok := subCmdIP[args[0]]; ok {
+		return function(cmd, cmd.Flag.Args())
+	}
+	return fmt.Errorf("subcommand not found: %s", args[0])
+}
@@ -4,12 +4,7 @@
 
 package cli
 
-import (
-	"strings"
-
-	"github.com/scaleway/scaleway-cli/pkg/commands"
-	"github.com/sirupsen/logrus"
-)
+import "github.com/scaleway/scaleway-cli/pkg/commands"
 
 var cmdPs = &Command{
 	Exec:        runPs,
@@ -36,11 +31,18 @@ var cmdPs = &Command{
     $ scw ps -f server-type=COMMERCIALTYPE
     $ scw ps -f "state=booted image=docker tags=prod"
     $ scw ps -f zone=ams1
+    $ scw ps -a -f created-before=30d
+    $ scw ps -a -f "created-after=2019-04-01 created-before=72h"
+    $ scw ps -f "@prod arch=arm"
+    $ scw ps --all-profiles
+    $ scw ps -a --group-by=tag
 `,
 }
 
 func init() {
 	cmdPs.Flag.BoolVar(&psA, []string{"a", "-all"}, false, "Show all servers. Only running servers are shown by default")
+	cmdPs.Flag.BoolVar(&psAllProfiles, []string{"-all-profiles"}, false, "List the servers of every profile and region of the config file")
+	cmdPs.Flag.StringVar(&psGroupBy, []string{"-group-by"}, "", "Group servers by 'tag', 'image', 'type' or 'state' and print a summary")
 	cmdPs.Flag.BoolVar(&psL, []string{"l", "-latest"}, false, "Show only the latest created server, include non-running ones")
 	cmdPs.Flag.IntVar(&psN, []string{"n"}, 0, "Show n last created servers, include non-running ones")
 	cmdPs.Flag.BoolVar(&psNoTrunc, []string{"-no-trunc"}, false, "Don't truncate output")
@@ -50,13 +52,15 @@ func init() {
 }
 
 // Flags
-var psA bool         // -a flag
-var psL bool         // -l flag
-var psQ bool         // -q flag
-var psNoTrunc bool   // -no-trunc flag
-var psN int          // -n flag
-var psHelp bool      // -h, --help flag
-var psFilters string // -f, --filter flag
+var psA bool           // -a flag
+var psAllProfiles bool // --all-profiles flag
+var psGroupBy string   // --group-by flag
+var psL bool           // -l flag
+var psQ bool           // -q flag
+var psNoTrunc bool     // -no-trunc flag
+var psN int            // -n flag
+var psHelp bool        // -h, --help flag
+var psFilters string   // -f, --filter flag
 
 func runPs(cmd *Command, rawArgs []string) error {
 	if psHelp {
@@ -67,27 +71,19 @@ func runPs(cmd *Command, rawArgs []string) error {
 	}
 
 	args := commands.PsArgs{
-		All:     psA,
-		Latest:  psL,
-		Quiet:   psQ,
-		NoTrunc: psNoTrunc,
-		NLast:   psN,
-		Filters: make(map[string]string, 0),
+		All:         psA,
+		AllProfiles: psAllProfiles,
+		GroupBy:     psGroupBy,
+		Latest:      psL,
+		Quiet:       psQ,
+		NoTrunc:     psNoTrunc,
+		NLast:       psN,
 	}
-	if psFilters != "" {
-		for _, filter := range strings.Split(psFilters, " ") {
-			parts := strings.SplitN(filter, "=", 2)
-			if len(parts) != 2 {
-				logrus.Warnf("Invalid filter '%s', should be in the form 'key=value'", filter)
-				continue
-			}
-			if _, ok := args.Filters[parts[0]]; ok {
-				logrus.Warnf("Duplicated filter: %q", parts[0])
-			} else {
-				args.Filters[parts[0]] = parts[1]
-			}
-		}
+	filters, err := cmd.parseFilters(psFilters)
+	if err != nil {
+		return err
 	}
+	args.Filters = filters
 	ctx := cmd.GetContext(rawArgs)
 	return commands.RunPs(ctx, args)
 }
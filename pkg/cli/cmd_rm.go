@@ -10,10 +10,11 @@ var cmdRm = &Command{
 	Exec:        runRm,
 	UsageLine:   "rm [OPTIONS] SERVER [SERVER...]",
 	Description: "Remove one or more servers",
-	Help:        "Remove one or more servers.",
+	Help:        "Remove one or more servers, the servers tagged 'protected' are skipped unless --force-protected is passed.",
 	Examples: `
     $ scw rm myserver
     $ scw rm -f myserver
+    $ scw rm --with-volumes --with-ip myserver
     $ scw rm my-stopped-server my-second-stopped-server
     $ scw rm $(scw ps -q)
     $ scw rm $(scw ps | grep mysql | awk '{print $1}')
@@ -23,11 +24,17 @@ var cmdRm = &Command{
 func init() {
 	cmdRm.Flag.BoolVar(&rmHelp, []string{"h", "-help"}, false, "Print usage")
 	cmdRm.Flag.BoolVar(&rmForce, []string{"f", "-force"}, false, "Force the removal of a server")
+	cmdRm.Flag.BoolVar(&rmForceProtected, []string{"-force-protected"}, false, "Remove the servers tagged 'protected' too")
+	cmdRm.Flag.BoolVar(&rmWithVolumes, []string{"-with-volumes"}, false, "Delete the volumes attached to the servers too")
+	cmdRm.Flag.BoolVar(&rmWithIP, []string{"-with-ip"}, false, "Release the reserved IP of the servers too")
 }
 
 // Flags
-var rmHelp bool  // -h, --help flag
-var rmForce bool // -f, --force flag
+var rmHelp bool           // -h, --help flag
+var rmForce bool          // -f, --force flag
+var rmForceProtected bool // --force-protected flag
+var rmWithVolumes bool    // --with-volumes flag
+var rmWithIP bool         // --with-ip flag
 
 func runRm(cmd *Command, rawArgs []string) error {
 	if rmHelp {
@@ -38,8 +45,11 @@ func runRm(cmd *Command, rawArgs []string) error {
 	}
 
 	args := commands.RmArgs{
-		Servers: rawArgs,
-		Force:   rmForce,
+		Servers:        rawArgs,
+		Force:          rmForce,
+		ForceProtected: rmForceProtected,
+		WithVolumes:    rmWithVolumes,
+		WithIP:         rmWithIP,
 	}
 	ctx := cmd.GetContext(rawArgs)
 	return commands.RunRm(ctx, args)
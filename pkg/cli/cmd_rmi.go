@@ -10,7 +10,7 @@ var cmdRmi = &Command{
 	Exec:        runRmi,
 	UsageLine:   "rmi [OPTIONS] IDENTIFIER [IDENTIFIER...]",
 	Description: "Remove one or more image(s)/volume(s)/snapshot(s)",
-	Help:        "Remove one or more image(s)/volume(s)/snapshot(s)",
+	Help:        "Remove one or more image(s)/volume(s)/snapshot(s), the volumes of the servers tagged 'protected' are skipped unless --force-protected is passed.",
 	Examples: `
     $ scw rmi myimage
     $ scw rmi mysnapshot
@@ -21,10 +21,12 @@ var cmdRmi = &Command{
 
 func init() {
 	cmdRmi.Flag.BoolVar(&rmiHelp, []string{"h", "-help"}, false, "Print usage")
+	cmdRmi.Flag.BoolVar(&rmiForceProtected, []string{"-force-protected"}, false, "Remove the volumes of the servers tagged 'protected' too")
 }
 
 // Flags
-var rmiHelp bool // -h, --help flag
+var rmiHelp bool           // -h, --help flag
+var rmiForceProtected bool // --force-protected flag
 
 func runRmi(cmd *Command, rawArgs []string) error {
 	if rmiHelp {
@@ -35,7 +37,8 @@ func runRmi(cmd *Command, rawArgs []string) error {
 	}
 
 	args := commands.RmiArgs{
-		Identifier: rawArgs,
+		Identifier:     rawArgs,
+		ForceProtected: rmiForceProtected,
 	}
 	ctx := cmd.GetContext(rawArgs)
 	return commands.RunRmi(ctx, args)
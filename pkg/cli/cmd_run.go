@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/scaleway/scaleway-cli/pkg/api"
 	"github.com/scaleway/scaleway-cli/pkg/commands"
 )
 
@@ -15,7 +16,7 @@ var cmdRun = &Command{
 	Exec:        runRun,
 	UsageLine:   "run [OPTIONS] IMAGE [COMMAND] [ARG...]",
 	Description: "Run a command in a new server",
-	Help:        "Run a command in a new server.",
+	Help:        "Run a command in a new server, IMAGE is omitted when the root volume comes from --from-snapshot.",
 	Examples: `
     $ scw run ubuntu-trusty
     $ scw run --commercial-type=C2S ubuntu-trusty
@@ -25,28 +26,42 @@ var cmdRun = &Command{
     $ scw run --gateway=myotherserver ubuntu-trusty
     $ scw run ubuntu-trusty bash
     $ scw run --name=mydocker docker docker run moul/nyancat:armhf
+    $ scw run -d --name-prefix=web ubuntu-xenial
     $ scw run --bootscript=3.2.34 --env="boot=live rescue_image=http://j.mp/scaleway-ubuntu-trusty-tarball" 50GB bash
     $ scw run --attach alpine
     $ scw run --detach alpine
     $ scw run --tmp-ssh-key alpine
     $ scw run --userdata="FOO=BAR FILE=@/tmp/file" alpine
+    $ scw run --init-script=setup.sh ubuntu-xenial
+    $ cat site.tgz | scw run --attach-stdin nginx-image
+    $ cat setup.sh | scw run --attach-stdin ubuntu-xenial bash -s
+    $ scw run --definition-file=server.json
+    $ scw run --definition-file=server.json --name=other-name ubuntu-xenial bash
+    $ scw run --from-snapshot=backup-2018-01-01 --commercial-type=START1-S
 `,
 }
 
 func init() {
 	cmdRun.Flag.StringVar(&runCreateName, []string{"-name"}, "", "Assign a name")
+	cmdRun.Flag.StringVar(&runNamePrefix, []string{"-name-prefix"}, "", "Prefix the generated name when --name is not set")
 	cmdRun.Flag.StringVar(&runCreateBootscript, []string{"-bootscript"}, "", "Assign a bootscript")
+	cmdRun.Flag.StringVar(&runPullPolicy, []string{"-pull-policy"}, "missing", "Image name resolution: 'missing' uses the cache, 'always' refreshes the marketplace to use the latest version")
+	cmdRun.Flag.BoolVar(&runForceBootscript, []string{"-force-bootscript"}, false, "Assign the bootscript even if it is deprecated or doesn't match the image architecture")
 	cmdRun.Flag.StringVar(&runCreateEnv, []string{"e", "-env"}, "", "Provide metadata tags passed to initrd (i.e., boot=rescue INITRD_DEBUG=1)")
 	cmdRun.Flag.StringVar(&runCreateVolume, []string{"v", "-volume"}, "", "Attach additional volume (i.e., 50G)")
 	cmdRun.Flag.BoolVar(&runHelpFlag, []string{"h", "-help"}, false, "Print usage")
 	cmdRun.Flag.Int64Var(&runTimeout, []string{"T", "-timeout"}, 0, "Set timeout value to seconds")
 	cmdRun.Flag.StringVar(&runIPAddress, []string{"-ip-address"}, "", "Assign a reserved public IP, a 'dynamic' one or 'none' (default to 'none' if gateway specified, 'dynamic' otherwise)")
 	cmdRun.Flag.BoolVar(&runAttachFlag, []string{"a", "-attach"}, false, "Attach to serial console")
+	cmdRun.Flag.BoolVar(&runAttachStdin, []string{"-attach-stdin"}, false, "Pipe stdin into COMMAND once SSH is ready, tarballs are extracted in / and scripts executed by default")
 	cmdRun.Flag.BoolVar(&runDetachFlag, []string{"d", "-detach"}, false, "Run server in background and print server ID")
 	cmdRun.Flag.StringVar(&runGateway, []string{"g", "-gateway"}, "", "Use a SSH gateway")
 	cmdRun.Flag.StringVar(&runUserdatas, []string{"u", "-userdata"}, "", "Start a server with userdata predefined")
-	cmdRun.Flag.StringVar(&runCommercialType, []string{"-commercial-type"}, "X64-2GB", "Start a server with specific commercial-type C1, C2[S|M|L], X64-[2|4|8|15|30|60|120]GB, ARM64-[2|4|8]GB")
+	cmdRun.Flag.StringVar(&runInitScript, []string{"-init-script"}, "", "Upload a script via userdata and execute it once SSH is ready")
+	cmdRun.Flag.StringVar(&runCommercialType, []string{"-commercial-type"}, api.DefaultCommercialType, "Start a server with specific commercial-type C1, C2[S|M|L], X64-[2|4|8|15|30|60|120]GB, ARM64-[2|4|8]GB")
 	cmdRun.Flag.StringVar(&runBootType, []string{"-boot-type"}, "auto", "Choose between 'local' and 'bootscript' boot")
+	cmdRun.Flag.StringVar(&runDefinitionFile, []string{"-definition-file"}, "", "Read the server definition from a JSON file, options override its fields")
+	cmdRun.Flag.StringVar(&runFromSnapshot, []string{"-from-snapshot"}, "", "Create the root volume from this snapshot instead of an image")
 	cmdRun.Flag.StringVar(&runSSHUser, []string{"-user"}, "root", "Specify SSH User")
 	cmdRun.Flag.BoolVar(&runAutoRemove, []string{"-rm"}, false, "Automatically remove the server when it exits")
 	cmdRun.Flag.BoolVar(&runIPV6, []string{"-ipv6"}, false, "Enable IPV6")
@@ -58,6 +73,7 @@ func init() {
 
 // Flags
 var runCreateName string       // --name flag
+var runNamePrefix string       // --name-prefix flag
 var runAutoRemove bool         // --rm flag
 var runCreateBootscript string // --bootscript flag
 var runCreateEnv string        // -e, --env flag
@@ -65,6 +81,7 @@ var runCreateVolume string     // -v, --volume flag
 var runIPAddress string        // --ip-address flag
 var runHelpFlag bool           // -h, --help flag
 var runAttachFlag bool         // -a, --attach flag
+var runAttachStdin bool        // --attach-stdin flag
 var runDetachFlag bool         // -d, --detach flag
 var runGateway string          // -g, --gateway flag
 var runUserdatas string        // -u, --userdata flag
@@ -77,15 +94,25 @@ var runTimeout int64           // --timeout flag
 var runSetState string         // --set-state flag
 var runSSHUser string          // --user flag
 var runSSHPort int             // -p, --port flag
+var runForceBootscript bool    // --force-bootscript flag
+var runPullPolicy string       // --pull-policy flag
+var runInitScript string       // --init-script flag
+var runDefinitionFile string   // --definition-file flag
+var runFromSnapshot string     // --from-snapshot flag
 
 func runRun(cmd *Command, rawArgs []string) error {
 	if runHelpFlag {
 		return cmd.PrintUsage()
 	}
-	if len(rawArgs) < 1 {
+	if len(rawArgs) < 1 && runDefinitionFile == "" && runFromSnapshot == "" {
 		return cmd.PrintShortUsage()
 	}
-	if runAttachFlag && len(rawArgs) > 1 {
+	// without IMAGE, every argument belongs to COMMAND
+	command := rawArgs
+	if runFromSnapshot == "" && len(rawArgs) > 0 {
+		command = rawArgs[1:]
+	}
+	if runAttachFlag && len(command) > 0 {
 		return fmt.Errorf("conflicting options: -a and COMMAND")
 	}
 	if runAttachFlag && runDetachFlag {
@@ -94,41 +121,65 @@ func runRun(cmd *Command, rawArgs []string) error {
 	if runAttachFlag && runShowBoot {
 		return fmt.Errorf("conflicting options: -a and --show-boot")
 	}
-	if runShowBoot && len(rawArgs) > 1 {
+	if runShowBoot && len(command) > 0 {
 		return fmt.Errorf("conflicting options: --show-boot and COMMAND")
 	}
 	if runShowBoot && runDetachFlag {
 		return fmt.Errorf("conflicting options: --show-boot and -d")
 	}
-	if runDetachFlag && len(rawArgs) > 1 {
+	if runDetachFlag && len(command) > 0 {
 		return fmt.Errorf("conflicting options: -d and COMMAND")
 	}
 	if runAutoRemove && runDetachFlag {
 		return fmt.Errorf("conflicting options: --detach and --rm")
 	}
+	if runInitScript != "" && (runAttachFlag || runDetachFlag || runShowBoot) {
+		return fmt.Errorf("conflicting options: --init-script and -a, -d or --show-boot")
+	}
+	if runAttachStdin && (runAttachFlag || runDetachFlag || runShowBoot) {
+		return fmt.Errorf("conflicting options: --attach-stdin and -a, -d or --show-boot")
+	}
 
 	args := commands.RunArgs{
-		Attach:         runAttachFlag,
-		Bootscript:     runCreateBootscript,
-		Command:        rawArgs[1:],
-		Detach:         runDetachFlag,
-		Gateway:        runGateway,
-		Image:          rawArgs[0],
-		Name:           runCreateName,
-		AutoRemove:     runAutoRemove,
-		TmpSSHKey:      runTmpSSHKey,
-		ShowBoot:       runShowBoot,
-		IP:             runIPAddress,
-		Timeout:        runTimeout,
-		Userdata:       runUserdatas,
-		CommercialType: runCommercialType,
-		State:          runSetState,
-		IPV6:           runIPV6,
-		SSHUser:        runSSHUser,
-		SSHPort:        runSSHPort,
-		BootType:       runBootType,
+		Attach:          runAttachFlag,
+		AttachStdin:     runAttachStdin,
+		Bootscript:      runCreateBootscript,
+		Detach:          runDetachFlag,
+		Gateway:         runGateway,
+		Name:            runCreateName,
+		NamePrefix:      runNamePrefix,
+		AutoRemove:      runAutoRemove,
+		TmpSSHKey:       runTmpSSHKey,
+		ShowBoot:        runShowBoot,
+		IP:              runIPAddress,
+		Timeout:         runTimeout,
+		Userdata:        runUserdatas,
+		CommercialType:  runCommercialType,
+		State:           runSetState,
+		IPV6:            runIPV6,
+		SSHUser:         runSSHUser,
+		SSHPort:         runSSHPort,
+		BootType:        runBootType,
+		ForceBootscript: runForceBootscript,
+		PullPolicy:      runPullPolicy,
+		InitScript:      runInitScript,
+		DefinitionFile:  runDefinitionFile,
+		FromSnapshot:    runFromSnapshot,
+		Command:         command,
 		// FIXME: Timeout
 	}
+	if runFromSnapshot == "" && len(rawArgs) > 0 {
+		args.Image = rawArgs[0]
+	}
+	if runDefinitionFile != "" {
+		// options keep their default value only when the definition file doesn't set them
+		if !cmd.Flag.IsSet("-commercial-type") {
+			args.CommercialType = ""
+		}
+		if !cmd.Flag.IsSet("-boot-type") {
+			args.BootType = ""
+		}
+	}
 
 	if len(runCreateEnv) > 0 {
 		args.Tags = strings.Split(runCreateEnv, " ")
@@ -0,0 +1,115 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package cli
+
+import (
+	"fmt"
+
+	"github.com/scaleway/scaleway-cli/pkg/commands"
+)
+
+var cmdSnapshot = &Command{
+	Exec:        runSnapshot,
+	UsageLine:   "snapshot [OPTIONS] SUBCOMMAND [ARGS...]",
+	Description: "Manage snapshots",
+	Help: `Manage snapshots.
+
+Subcommands:
+    create SERVER          Snapshot a volume of SERVER, or all of them with --all-volumes
+    ls                     List snapshots
+    rm SNAPSHOT...         Remove one or more snapshots
+    inspect SNAPSHOT...    Return low-level information on one or more snapshots
+
+The --name of the snapshots may contain {server}, {volume} (the slot), {date} and {time}.`,
+	Examples: `
+    $ scw snapshot create my-server
+    $ scw snapshot create --all-volumes --name="{server}-{volume}-{date}" -w my-server
+    $ scw snapshot ls
+    $ scw snapshot rm $(scw snapshot ls -q)
+    $ scw snapshot inspect my-server-0-2017-03-01
+`,
+}
+
+func init() {
+	cmdSnapshot.Flag.BoolVar(&snapshotHelp, []string{"h", "-help"}, false, "Print usage")
+	cmdSnapshot.Flag.IntVar(&snapshotVolume, []string{"v", "-volume"}, 0, "Volume slot to snapshot")
+	cmdSnapshot.Flag.BoolVar(&snapshotAllVolumes, []string{"-all-volumes"}, false, "Snapshot all the volumes of the server")
+	cmdSnapshot.Flag.StringVar(&snapshotName, []string{"n", "-name"}, commands.DefaultSnapshotName, "Name of the snapshots")
+	cmdSnapshot.Flag.BoolVar(&snapshotWait, []string{"w", "-wait"}, false, "Wait for the snapshots to be done")
+	cmdSnapshot.Flag.BoolVar(&snapshotQuiet, []string{"q", "-quiet"}, false, "Only display numeric IDs")
+	cmdSnapshot.Flag.BoolVar(&snapshotNoTrunc, []string{"-no-trunc"}, false, "Don't truncate output")
+	subCmdSnapshot = map[string]func(cmd *Command, args []string) error{
+		"create":  snapshotCreate,
+		"ls":      snapshotList,
+		"rm":      snapshotRemove,
+		"inspect": snapshotInspect,
+	}
+}
+
+// Flags
+var snapshotHelp bool       // -h, --help flag
+var snapshotVolume int      // -v, --volume flag
+var snapshotAllVolumes bool // --all-volumes flag
+var snapshotName string     // -n, --name flag
+var snapshotWait bool       // -w, --wait flag
+var snapshotQuiet bool      // -q, --quiet flag
+var snapshotNoTrunc bool    // --no-trunc flag
+
+var subCmdSnapshot map[string]func(cmd *Command, args []string) error
+
+func snapshotCreate(cmd *Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.PrintShortUsage()
+	}
+	if snapshotAllVolumes && snapshotVolume != 0 {
+		return fmt.Errorf("conflicting options: --volume and --all-volumes")
+	}
+	ctx := cmd.GetContext(args)
+	return commands.RunSnapshotCreate(ctx, commands.SnapshotCreateArgs{
+		Server:     args[0],
+		Volume:     snapshotVolume,
+		AllVolumes: snapshotAllVolumes,
+		Name:       snapshotName,
+		Wait:       snapshotWait,
+	})
+}
+
+func snapshotList(cmd *Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.PrintShortUsage()
+	}
+	ctx := cmd.GetContext(args)
+	return commands.RunSnapshotList(ctx, commands.SnapshotListArgs{
+		Quiet:   snapshotQuiet,
+		NoTrunc: snapshotNoTrunc,
+	})
+}
+
+func snapshotRemove(cmd *Command, args []string) error {
+	if len(args) == 0 {
+		return cmd.PrintShortUsage()
+	}
+	ctx := cmd.GetContext(args)
+	return commands.RunSnapshotRemove(ctx, commands.SnapshotsArgs{Snapshots: args})
+}
+
+func snapshotInspect(cmd *Command, args []string) error {
+	if len(args) == 0 {
+		return cmd.PrintShortUsage()
+	}
+	ctx := cmd.GetContext(args)
+	return commands.RunSnapshotInspect(ctx, commands.SnapshotsArgs{Snapshots: args})
+}
+
+func runSnapshot(cmd *Command, args []string) error {
+	if snapshotHelp || len(args) == 0 {
+		return cmd.PrintUsage()
+	}
+	cmd.Flag.Parse(args[1:])
+	if function, ok := subCmdSnapshot[args[0]]; ok {
+		return function(cmd, cmd.Flag.Args())
+	}
+	return fmt.Errorf("subcommand not found: %s", args[0])
+}
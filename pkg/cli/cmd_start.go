@@ -4,27 +4,38 @@
 
 package cli
 
-import "github.com/scaleway/scaleway-cli/pkg/commands"
+import (
+	"time"
+
+	"github.com/scaleway/scaleway-cli/pkg/commands"
+)
 
 var cmdStart = &Command{
 	Exec:        runStart,
 	UsageLine:   "start [OPTIONS] SERVER [SERVER...]",
 	Description: "Start a stopped server",
 	Help:        "Start a stopped server.",
+	Examples: `
+    $ scw start myserver
+    $ scw start -w myserver
+    $ scw start --retry-capacity=30m myserver
+`,
 }
 
 func init() {
-	cmdStart.Flag.BoolVar(&startW, []string{"w", "-wait"}, false, "Synchronous start. Wait for SSH to be ready")
+	cmdStart.Flag.BoolVar(&startW, []string{"w", "-wait"}, false, "Synchronous start. Wait for SSH to be ready and display boot stages")
 	cmdStart.Flag.Float64Var(&startTimeout, []string{"T", "-timeout"}, 0, "Set timeout values to seconds")
 	cmdStart.Flag.BoolVar(&startHelp, []string{"h", "-help"}, false, "Print usage")
 	cmdStart.Flag.StringVar(&startSetState, []string{"-set-state"}, "", "Set a state after the boot")
+	cmdStart.Flag.DurationVar(&startRetryCapacity, []string{"-retry-capacity"}, 0, "Keep retrying for this long when there is no capacity for the server type (e.g. 30m)")
 }
 
 // Flags
-var startW bool          // -w flag
-var startTimeout float64 // -T flag
-var startHelp bool       // -h, --help flag
-var startSetState string // -set-state flag
+var startW bool                      // -w flag
+var startTimeout float64             // -T flag
+var startHelp bool                   // -h, --help flag
+var startSetState string             // -set-state flag
+var startRetryCapacity time.Duration // --retry-capacity flag
 
 func runStart(cmd *Command, rawArgs []string) error {
 	if startHelp {
@@ -35,10 +46,11 @@ func runStart(cmd *Command, rawArgs []string) error {
 	}
 
 	args := commands.StartArgs{
-		Servers:  rawArgs,
-		Timeout:  startTimeout,
-		Wait:     startW,
-		SetState: startSetState,
+		Servers:       rawArgs,
+		Timeout:       startTimeout,
+		Wait:          startW,
+		SetState:      startSetState,
+		RetryCapacity: startRetryCapacity,
 	}
 	ctx := cmd.GetContext(rawArgs)
 	return commands.RunStart(ctx, args)
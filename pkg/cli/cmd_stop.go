@@ -10,7 +10,7 @@ var cmdStop = &Command{
 	Exec:        runStop,
 	UsageLine:   "stop [OPTIONS] SERVER [SERVER...]",
 	Description: "Stop a running server",
-	Help:        "Stop a running server.",
+	Help:        "Stop a running server, -t skips the servers tagged 'protected' unless --force-protected is passed.",
 	Examples: `
     $ scw stop my-running-server my-second-running-server
     $ scw stop -t my-running-server my-second-running-server
@@ -24,13 +24,15 @@ var cmdStop = &Command{
 func init() {
 	cmdStop.Flag.BoolVar(&stopT, []string{"t", "-terminate"}, false, "Stop and trash a server with its volumes")
 	cmdStop.Flag.BoolVar(&stopHelp, []string{"h", "-help"}, false, "Print usage")
-	cmdStop.Flag.BoolVar(&stopW, []string{"w", "-wait"}, false, "Synchronous stop. Wait for SSH to be ready")
+	cmdStop.Flag.BoolVar(&stopW, []string{"w", "-wait"}, false, "Synchronous stop. Wait for the poweroff or terminate task to complete")
+	cmdStop.Flag.BoolVar(&stopForceProtected, []string{"-force-protected"}, false, "Terminate the servers tagged 'protected' too")
 }
 
 // Flags
-var stopT bool    // -t flag
-var stopHelp bool // -h, --help flag
-var stopW bool    // -w, --wait flat
+var stopT bool              // -t flag
+var stopHelp bool           // -h, --help flag
+var stopW bool              // -w, --wait flat
+var stopForceProtected bool // --force-protected flag
 
 func runStop(cmd *Command, rawArgs []string) error {
 	if stopHelp {
@@ -41,9 +43,10 @@ func runStop(cmd *Command, rawArgs []string) error {
 	}
 
 	args := commands.StopArgs{
-		Terminate: stopT,
-		Wait:      stopW,
-		Servers:   rawArgs,
+		Terminate:      stopT,
+		ForceProtected: stopForceProtected,
+		Wait:           stopW,
+		Servers:        rawArgs,
 	}
 	ctx := cmd.GetContext(rawArgs)
 	return commands.RunStop(ctx, args)
@@ -17,12 +17,14 @@ func init() {
 	cmdTag.Flag.BoolVar(&tagHelp, []string{"h", "-help"}, false, "Print usage")
 	cmdTag.Flag.StringVar(&tagBootscript, []string{"-bootscript"}, "", "Assign bootscript")
 	cmdTag.Flag.StringVar(&tagArch, []string{"-arch"}, "arm", "Image architecture arm, x86_64")
+	cmdTag.Flag.StringVar(&tagManifest, []string{"-manifest"}, "", "Append the image to a packer manifest file")
 }
 
 // Flags
 var tagHelp bool         // -h, --help flag
 var tagBootscript string // --bootscript flag
 var tagArch string       // --arch flag
+var tagManifest string   // --manifest flag
 
 func runTag(cmd *Command, rawArgs []string) error {
 	if tagHelp {
@@ -37,6 +39,7 @@ func runTag(cmd *Command, rawArgs []string) error {
 		Name:       rawArgs[1],
 		Bootscript: tagBootscript,
 		Arch:       tagArch,
+		Manifest:   tagManifest,
 	}
 	ctx := cmd.GetContext(rawArgs)
 	return commands.RunTag(ctx, args)
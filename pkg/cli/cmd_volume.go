@@ -0,0 +1,136 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package cli
+
+import (
+	"fmt"
+
+	"github.com/scaleway/scaleway-cli/pkg/commands"
+)
+
+var cmdVolume = &Command{
+	Exec:        runVolume,
+	UsageLine:   "volume [OPTIONS] SUBCOMMAND [ARGS...]",
+	Description: "Manage volumes",
+	Help: `Manage volumes.
+
+Subcommands:
+    create NAME             Create a volume of --size, or from a --snapshot
+    ls                      List volumes
+    rm VOLUME...            Remove one or more detached volumes
+    inspect VOLUME...       Return low-level information on one or more volumes
+    attach VOLUME SERVER    Attach a volume to a stopped server
+    detach VOLUME           Detach a volume from its stopped server`,
+	Examples: `
+    $ scw volume create --size=50G data
+    $ scw volume create --snapshot=my-snapshot restored
+    $ scw volume ls
+    $ scw volume attach data my-server
+    $ scw volume detach data
+    $ scw volume rm data
+`,
+}
+
+func init() {
+	cmdVolume.Flag.BoolVar(&volumeHelp, []string{"h", "-help"}, false, "Print usage")
+	cmdVolume.Flag.StringVar(&volumeSize, []string{"s", "-size"}, "", "Size of the volume (e.g. 50G), defaults to the size of the snapshot")
+	cmdVolume.Flag.StringVar(&volumeType, []string{"t", "-type"}, "", "Type of the volume (default l_ssd)")
+	cmdVolume.Flag.StringVar(&volumeSnapshot, []string{"-snapshot"}, "", "Initialize the volume from a snapshot")
+	cmdVolume.Flag.BoolVar(&volumeForce, []string{"f", "-force"}, false, "Detach the root volume of a server")
+	cmdVolume.Flag.BoolVar(&volumeQuiet, []string{"q", "-quiet"}, false, "Only display numeric IDs")
+	cmdVolume.Flag.BoolVar(&volumeNoTrunc, []string{"-no-trunc"}, false, "Don't truncate output")
+	subCmdVolume = map[string]func(cmd *Command, args []string) error{
+		"create":  volumeCreate,
+		"ls":      volumeList,
+		"rm":      volumeRemove,
+		"inspect": volumeInspect,
+		"attach":  volumeAttach,
+		"detach":  volumeDetach,
+	}
+}
+
+// Flags
+var volumeHelp bool       // -h, --help flag
+var volumeSize string     // -s, --size flag
+var volumeType string     // -t, --type flag
+var volumeSnapshot string // --snapshot flag
+var volumeForce bool      // -f, --force flag
+var volumeQuiet bool      // -q, --quiet flag
+var volumeNoTrunc bool    // --no-trunc flag
+
+var subCmdVolume map[string]func(cmd *Command, args []string) error
+
+func volumeCreate(cmd *Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.PrintShortUsage()
+	}
+	ctx := cmd.GetContext(args)
+	return commands.RunVolumeCreate(ctx, commands.VolumeCreateArgs{
+		Name:     args[0],
+		Size:     volumeSize,
+		Type:     volumeType,
+		Snapshot: volumeSnapshot,
+	})
+}
+
+func volumeList(cmd *Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.PrintShortUsage()
+	}
+	ctx := cmd.GetContext(args)
+	return commands.RunVolumeList(ctx, commands.VolumeListArgs{
+		Quiet:   volumeQuiet,
+		NoTrunc: volumeNoTrunc,
+	})
+}
+
+func volumeRemove(cmd *Command, args []string) error {
+	if len(args) == 0 {
+		return cmd.PrintShortUsage()
+	}
+	ctx := cmd.GetContext(args)
+	return commands.RunVolumeRemove(ctx, commands.VolumesArgs{Volumes: args})
+}
+
+func volumeInspect(cmd *Command, args []string) error {
+	if len(args) == 0 {
+		return cmd.PrintShortUsage()
+	}
+	ctx := cmd.GetContext(args)
+	return commands.RunVolumeInspect(ctx, commands.VolumesArgs{Volumes: args})
+}
+
+func volumeAttach(cmd *Command, args []string) error {
+	if len(args) != 2 {
+		return cmd.PrintShortUsage()
+	}
+	ctx := cmd.GetContext(args)
+	return commands.RunVolumeAttach(ctx, commands.VolumeAttachArgs{
+		Volume: args[0],
+		Server: args[1],
+	})
+}
+
+func volumeDetach(cmd *Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.PrintShortUsage()
+	}
+	ctx := cmd.GetContext(args)
+	return commands.RunVolumeDetach(ctx, commands.VolumeDetachArgs{
+		Volume: args[0],
+		Force:  volumeForce,
+	})
+}
+
+func runVolume(cmd *Command, args []string) error {
+	if volumeHelp || len(args) == 0 {
+		return cmd.PrintUsage()
+	}
+	cmd.Flag.Parse(args[1:])
+	if function, ok := subCmdVolume[args[0]]; ok {
+		return function(cmd, cmd.Flag.Args())
+	}
+	return fmt.Errorf("subcommand not found: %s", args[0])
+}
@@ -4,21 +4,37 @@
 
 package cli
 
-import "github.com/scaleway/scaleway-cli/pkg/commands"
+import (
+	"strings"
+
+	"github.com/scaleway/scaleway-cli/pkg/commands"
+)
 
 var cmdWait = &Command{
 	Exec:        runWait,
 	UsageLine:   "wait [OPTIONS] SERVER [SERVER...]",
 	Description: "Block until a server stops",
-	Help:        "Block until a server stops.",
+	Help:        "Block until a server stops, or until it satisfies the conditions of --for.",
+	Examples: `
+    $ scw wait my-server
+    $ scw wait --for=state=running,ssh my-server
+    $ scw wait --any --for=state=running,ssh web-1 web-2 web-3
+    $ scw wait --any --json --for=state=running web-1 web-2
+`,
 }
 
 func init() {
 	cmdWait.Flag.BoolVar(&waitHelp, []string{"h", "-help"}, false, "Print usage")
+	cmdWait.Flag.StringVar(&waitFor, []string{"-for"}, "", "Comma-separated conditions to satisfy, state=STATE or ssh (default: state=stopped)")
+	cmdWait.Flag.BoolVar(&waitAny, []string{"-any"}, false, "Return as soon as one of the servers satisfies the conditions")
+	cmdWait.Flag.BoolVar(&waitJSON, []string{"-json"}, false, "Print the server satisfying the conditions as JSON")
 }
 
 // Flags
-var waitHelp bool // -h, --help flag
+var waitHelp bool  // -h, --help flag
+var waitFor string // --for flag
+var waitAny bool   // --any flag
+var waitJSON bool  // --json flag
 
 func runWait(cmd *Command, rawArgs []string) error {
 	if waitHelp {
@@ -30,6 +46,11 @@ func runWait(cmd *Command, rawArgs []string) error {
 
 	args := commands.WaitArgs{
 		Servers: rawArgs,
+		Any:     waitAny,
+		JSON:    waitJSON,
+	}
+	if waitFor != "" {
+		args.Conditions = strings.Split(waitFor, ",")
 	}
 	ctx := cmd.GetContext(rawArgs)
 	return commands.RunWait(ctx, args)
@@ -54,16 +54,35 @@ type Command struct {
 	// ConfigPath for -c, --config parameter
 	ConfigPath string
 
+	// TimeFormat for --time-format parameter
+	TimeFormat string
+
+	// ReportFormat for --report-format parameter
+	ReportFormat string
+
+	// NotifyURL for --notify-url parameter
+	NotifyURL string
+
+	// NamedFilters are the filters of the config file, used as --filter @NAME
+	NamedFilters map[string]string
+
 	streams *commands.Streams
 }
 
 // GetContext returns a standard context, with real stdin, stdout, stderr, a configured API and raw arguments
 func (c *Command) GetContext(rawArgs []string) commands.CommandContext {
 	ctx := commands.CommandContext{
-		Env:        os.Environ(),
-		RawArgs:    rawArgs,
-		API:        c.API,
-		ConfigPath: c.ConfigPath,
+		Env:          os.Environ(),
+		RawArgs:      rawArgs,
+		ConfigPath:   c.ConfigPath,
+		TimeFormat:   c.TimeFormat,
+		ReportFormat: c.ReportFormat,
+		NotifyURL:    c.NotifyURL,
+	}
+
+	// a nil *api.ScalewayAPI would make a non-nil ctx.API
+	if c.API != nil {
+		ctx.API = c.API
 	}
 
 	if c.streams != nil {
@@ -19,3 +19,21 @@ func TestCommand_Name(t *testing.T) {
 		So(command.Name(), ShouldEqual, "top")
 	})
 }
+
+func TestCommand_parseFilters(t *testing.T) {
+	Convey("Testing Command.parseFilters()", t, func() {
+		command := Command{
+			NamedFilters: map[string]string{"prod": "tags=prod state=running"},
+		}
+		filters, err := command.parseFilters("@prod state=stopped arch=arm")
+		So(err, ShouldBeNil)
+		So(filters, ShouldResemble, map[string]string{"tags": "prod", "state": "stopped", "arch": "arm"})
+
+		filters, err = command.parseFilters("")
+		So(err, ShouldBeNil)
+		So(len(filters), ShouldEqual, 0)
+
+		_, err = command.parseFilters("@staging")
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -12,6 +12,7 @@ var Commands = []*Command{
 	CmdHelp,
 
 	cmdAttach,
+	cmdBootscript,
 	cmdCommit,
 	cmdCp,
 	cmdCreate,
@@ -21,6 +22,7 @@ var Commands = []*Command{
 	cmdImages,
 	cmdInfo,
 	cmdInspect,
+	cmdIP,
 	cmdKill,
 	cmdLogin,
 	cmdLogout,
@@ -35,20 +37,50 @@ var Commands = []*Command{
 	cmdRun,
 	cmdS3,
 	cmdSearch,
+	cmdSnapshot,
 	cmdStart,
 	cmdStop,
 	cmdTag,
 	cmdTop,
 	cmdUserdata,
 	cmdVersion,
+	cmdVolume,
 	cmdWait,
 
+	cmdApply,
+	cmdArchive,
 	cmdBilling,
+	cmdBuild,
+	cmdBulkEdit,
 	cmdCompletion,
+	cmdConsole,
+	cmdCostForecast,
+	cmdDeploy,
+	cmdDNS,
+	cmdDu,
+	cmdEnv,
+	cmdExport,
+	cmdFleetExec,
 	cmdFlushCache,
+	cmdImport,
 	cmdMarketplace,
+	cmdOrg,
 	cmdPatch,
+	cmdPing,
+	cmdRefreshCache,
+	cmdRescue,
+	cmdRestore,
+	cmdRolling,
+	cmdServe,
+	cmdSSHKeyscan,
+	cmdSSHProxy,
 	cmdSecurityGroups,
+	cmdStorageReport,
+	cmdTasks,
+	cmdTopAccount,
+	cmdVerifyImage,
+	cmdWatchTasks,
+	cmdWhoami,
 	cmdIPS,
 	cmdCS,
 }
@@ -0,0 +1,88 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package cli
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+)
+
+// Exit codes of the failures scripts may want to branch on, the other failures exit with 1
+const (
+	ExitNotFound         = 3
+	ExitAuthFailure      = 4
+	ExitPermissionDenied = 5
+	ExitQuotaExceeded    = 6
+	ExitConflict         = 7
+)
+
+// errorKinds are the kinds of failures with a dedicated exit code and hints
+var errorKinds = []struct {
+	is    func(error) bool
+	code  int
+	hints []string
+}{
+	{api.IsAuthFailure, ExitAuthFailure, []string{
+		"your API token is invalid or has expired",
+		"run 'scw login' to log in again",
+	}},
+	{api.IsQuotaExceeded, ExitQuotaExceeded, []string{
+		"a quota of your organization is reached",
+		"check 'scw info' for your quotas and remove the resources you don't use",
+	}},
+	{api.IsPermissionDenied, ExitPermissionDenied, []string{
+		"your API token is not allowed to do this",
+		"check the organization it belongs to with 'scw _whoami'",
+	}},
+	{api.IsNotFound, ExitNotFound, []string{
+		"the resource does not exist, it may have been deleted",
+		"check its name or identifier, i.e: with 'scw ps -a' for servers",
+	}},
+	{api.IsConflict, ExitConflict, []string{
+		"a task is already in progress on this resource",
+		"retry once it is done, or pass --wait-conflicts or --wait-transition to wait for it",
+	}},
+}
+
+// errorKind returns the index in errorKinds of the kind of err, or -1
+func errorKind(err error) int {
+	for i, kind := range errorKinds {
+		if kind.is(err) {
+			return i
+		}
+	}
+	return -1
+}
+
+// errorHints returns the lines advising what to do about the frequent failures, nil for other errors
+func errorHints(err error) []string {
+	if i := errorKind(err); i >= 0 {
+		return errorKinds[i].hints
+	}
+	return nil
+}
+
+// exitCode returns the exit code of a command which failed with err
+func exitCode(err error) int {
+	if i := errorKind(err); i >= 0 {
+		return errorKinds[i].code
+	}
+	return 1
+}
+
+// formatHints returns the hints about err on their own lines, to be appended to its message
+func formatHints(err error) string {
+	var b bytes.Buffer
+	for i, hint := range errorHints(err) {
+		prefix := "      "
+		if i == 0 {
+			prefix = "hint: "
+		}
+		fmt.Fprintf(&b, "\n%s%s", prefix, hint)
+	}
+	return b.String()
+}
@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestErrorHints(t *testing.T) {
+	Convey("Testing errorHints()", t, func() {
+		unauthorized := api.ScalewayAPIError{StatusCode: 401, Type: "invalid_auth"}
+		So(errorHints(unauthorized)[1], ShouldContainSubstring, "scw login")
+
+		wrapped := api.Wrapf(&api.ScalewayAPIError{StatusCode: 403, Type: "quotas_exceeded"}, "failed to create server")
+		So(errorHints(wrapped)[1], ShouldContainSubstring, "scw info")
+
+		conflict := &api.ScalewayAPIError{StatusCode: 409, Type: "conflict"}
+		So(errorHints(conflict)[1], ShouldContainSubstring, "--wait-conflicts")
+
+		So(exitCode(conflict), ShouldEqual, ExitConflict)
+		notFound := api.Wrapf(&api.ScalewayAPIError{StatusCode: 404, Type: "unknown_resource"}, "cannot start")
+		So(exitCode(notFound), ShouldEqual, ExitNotFound)
+
+		So(errorHints(errors.New("no such server")), ShouldBeNil)
+		So(exitCode(errors.New("no such server")), ShouldEqual, 1)
+		So(formatHints(errors.New("no such server")), ShouldEqual, "")
+	})
+}
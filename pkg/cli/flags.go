@@ -4,7 +4,12 @@
 
 package cli
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
 
 // CommandListOpts holds a list of parameters
 type CommandListOpts struct {
@@ -29,3 +34,39 @@ func (opts *CommandListOpts) Set(value string) error {
 	(*opts.Values) = append((*opts.Values), value)
 	return nil
 }
+
+// parseFilters parses a space separated list of key=value filters, as accepted by --filter.
+// @NAME is replaced by the filters named NAME in the config file, explicit filters override them
+func (c *Command) parseFilters(value string) (map[string]string, error) {
+	filters := make(map[string]string)
+	named := make(map[string]bool)
+	for _, filter := range strings.Fields(value) {
+		if strings.HasPrefix(filter, "@") {
+			expression, ok := c.NamedFilters[filter[1:]]
+			if !ok {
+				return nil, fmt.Errorf("unknown named filter %q, add it to the filters of the config file", filter)
+			}
+			for _, namedFilter := range strings.Fields(expression) {
+				parts := strings.SplitN(namedFilter, "=", 2)
+				if len(parts) != 2 {
+					return nil, fmt.Errorf("invalid named filter %s: '%s' should be in the form 'key=value'", filter, namedFilter)
+				}
+				filters[parts[0]] = parts[1]
+				named[parts[0]] = true
+			}
+			continue
+		}
+		parts := strings.SplitN(filter, "=", 2)
+		if len(parts) != 2 {
+			logrus.Warnf("Invalid filter '%s', should be in the form 'key=value'", filter)
+			continue
+		}
+		if _, ok := filters[parts[0]]; ok && !named[parts[0]] {
+			logrus.Warnf("Duplicated filter: %q", parts[0])
+			continue
+		}
+		filters[parts[0]] = parts[1]
+		named[parts[0]] = false
+	}
+	return filters, nil
+}
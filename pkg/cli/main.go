@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/hashicorp/go-version"
+	"github.com/mattn/go-isatty"
 	"github.com/scaleway/scaleway-cli/pkg/api"
 	"github.com/scaleway/scaleway-cli/pkg/clilogger"
 	"github.com/scaleway/scaleway-cli/pkg/commands"
@@ -31,9 +33,35 @@ var (
 	flVerbose   = flag.Bool([]string{"V", "-verbose"}, false, "Enable verbose mode")
 	flVersion   = flag.Bool([]string{"v", "-version"}, false, "Print version information and quit")
 	flQuiet     = flag.Bool([]string{"q", "-quiet"}, false, "Enable quiet mode")
+	flAPIIPv6   = flag.Bool([]string{"-api-prefer-ipv6"}, false, "Connect to the API over IPv6 first, IPv4 is tried 300ms later")
+	flStats     = flag.Bool([]string{"-stats"}, false, "Print the number and duration of the API requests on exit")
 	flSensitive = flag.Bool([]string{"-sensitive"}, false, "Show sensitive data in outputs, i.e. API Token/Organization")
 	flRegion    = flag.String([]string{"-region"}, "par1", "Change the default region (e.g. ams1)")
 	flConfig    = flag.String([]string{"c", "-config"}, "", "Optional config file path")
+	flWaitConfl = flag.Bool([]string{"-wait-conflicts"}, false, "Wait for the conflicting task and retry when a server action is rejected (409)")
+	flCheckTok  = flag.Bool([]string{"-check-token"}, false, "Check the API token before running the command and report when it expires soon")
+	flRenewTok  = flag.Duration([]string{"-renew-token"}, 0, "With --check-token, renew the API token when it expires within this duration")
+	flWaitTrans = flag.Bool([]string{"-wait-transition"}, false, "Wait for a starting, stopping or allocating server to settle before acting on it")
+	flReportFmt = flag.String([]string{"-report-format"}, "", "Report the outcome of multi-target commands as a table or json")
+	flTimeFmt   = flag.String([]string{"-time-format"}, "", "Display dates as relative (default), iso or unix")
+	flNotifyURL = flag.String([]string{"-notify-url"}, "", "POST a JSON payload to this URL when a waited-on operation finishes")
+	flNoInter   = flag.Bool([]string{"-no-interactive"}, false, "Fail instead of prompting when a name matches several resources")
+	flRateWait  = flag.Duration([]string{"-max-rate-wait"}, api.DefaultMaxRateWait, "Maximum time to wait and retry when the API rate limits a request (429)")
+	flTimeout   = flag.Duration([]string{"-timeout"}, api.DefaultTimeout, "Time limit of each API request, 0 disables it")
+	flProxy     = flag.String([]string{"-proxy"}, "", "Send the API requests through this proxy instead of HTTP_PROXY/HTTPS_PROXY")
+	flTLSCA     = flag.String([]string{"-tls-ca-cert"}, "", "Trust the authorities of this PEM bundle too when connecting to the API")
+	flTLSCert   = flag.String([]string{"-tls-cert"}, "", "Authenticate to the API with this PEM client certificate")
+	flTLSKey    = flag.String([]string{"-tls-key"}, "", "Private key of the --tls-cert client certificate")
+	flInsecure  = flag.Bool([]string{"-insecure-skip-verify"}, false, "Do not verify the certificate of the API (same as SCW_TLSVERIFY=0)")
+	flTraceFile = flag.String([]string{"-trace-file"}, "", "Write a JSON line per API request, name resolution and outcome to this file")
+	flDryRun    = flag.Bool([]string{"-dry-run"}, false, "Print the API requests modifying resources instead of sending them")
+	flSSHProxy  = flag.String([]string{"-ssh-proxy"}, "", "Make the SSH connections through this SOCKS5 proxy, i.e: socks5://host:1080")
+	flRetries   = flag.Int([]string{"-api-retries"}, api.RetryMaxAttempts, "Number of attempts of an API request failing transiently")
+	flSSHConnTO = flag.Duration([]string{"-ssh-connect-timeout"}, 0, "Time limit to open the SSH connections, 0 keeps the default of ssh")
+	flPollIntvl = flag.Duration([]string{"-wait-poll-interval"}, api.WaitPollInterval, "Delay between two requests when waiting for a state")
+	flAPIVers   = flag.String([]string{"-api-version"}, "", "Version prefixed to the paths of the compute and account APIs, i.e: v2")
+	flShowCurl  = flag.Bool([]string{"-show-curl"}, false, "Print the curl command equivalent to each API request on stderr")
+	flUniqNames = flag.Bool([]string{"-unique-names"}, false, "Fail instead of warning when creating a server or an image named like an existing one")
 )
 
 // Start is the entrypoint
@@ -53,6 +81,11 @@ func Start(rawArgs []string, streams *commands.Streams) (int, error) {
 		return 1, fmt.Errorf("unable to open .scwrc config file: %v", cfgErr)
 	}
 
+	*flRegion = selectedRegion(config)
+	if err := applyTimeouts(config); err != nil {
+		return 1, err
+	}
+
 	if *flVersion {
 		fmt.Fprintf(streams.Stderr, "scw version %s, build %s\n", scwversion.VERSION, scwversion.GITCOMMIT)
 		return 0, nil
@@ -70,6 +103,28 @@ func Start(rawArgs []string, streams *commands.Streams) (int, error) {
 		os.Setenv("SCW_VERBOSE_API", "1")
 	}
 
+	if *flAPIIPv6 {
+		os.Setenv("SCW_API_PREFER_IPV6", "1")
+	}
+
+	if err := utils.CheckTimeFormat(*flTimeFmt); err != nil {
+		return 1, err
+	}
+	if *flReportFmt != "" && *flReportFmt != commands.ReportFormatTable && *flReportFmt != commands.ReportFormatJSON {
+		return 1, fmt.Errorf("invalid report format %q, expected table or json", *flReportFmt)
+	}
+
+	if *flSSHProxy != "" {
+		utils.SSHProxy = *flSSHProxy
+	}
+	if utils.SSHProxy != "" {
+		if _, err := utils.ParseSOCKS5URL(utils.SSHProxy); err != nil {
+			return 1, err
+		}
+		// the ProxyCommand of ssh runs '_ssh-proxy', which reads the proxy from its environment
+		os.Setenv("SCW_SSH_PROXY", utils.SSHProxy)
+	}
+
 	utils.Quiet(*flQuiet)
 	initLogging(os.Getenv("DEBUG") != "", *flVerbose, streams)
 
@@ -95,8 +150,20 @@ func Start(rawArgs []string, streams *commands.Streams) (int, error) {
 				return 1, fmt.Errorf("usage: scw %s", cmd.UsageLine)
 			}
 			cmd.ConfigPath = *flConfig
+			cmd.TimeFormat = *flTimeFmt
+			cmd.ReportFormat = *flReportFmt
+			cmd.NotifyURL = *flNotifyURL
+			if cmd.NotifyURL == "" {
+				cmd.NotifyURL = os.Getenv("SCW_NOTIFY_URL")
+			}
+			if cmd.NotifyURL == "" && config != nil {
+				cmd.NotifyURL = config.NotifyURL
+			}
+			if config != nil {
+				cmd.NamedFilters = config.Filters
+			}
 			switch cmd.Name() {
-			case "login", "help", "version":
+			case "login", "help", "version", utils.SSHProxyCommand:
 				// commands that don't need API
 			case "_userdata":
 				// commands that may need API
@@ -117,12 +184,66 @@ func Start(rawArgs []string, streams *commands.Streams) (int, error) {
 				}
 				cmd.API = api
 			}
+			if cmd.API != nil {
+				cmd.API.MaxRateWait = *flRateWait
+				if *flDryRun {
+					cmd.API.DryRun = streams.Stdout
+				}
+				if *flShowCurl {
+					cmd.API.ShowCurl = streams.Stderr
+				}
+				cmd.API.WaitConflicts = *flWaitConfl || os.Getenv("SCW_WAIT_CONFLICTS") == "1"
+				cmd.API.WaitTransitions = *flWaitTrans || os.Getenv("SCW_WAIT_TRANSITION") == "1"
+				cmd.API.UniqueNames = *flUniqNames || os.Getenv("SCW_UNIQUE_NAMES") == "1" || (config != nil && config.UniqueNames)
+				cmd.API.Interactive = !*flNoInter && os.Getenv("SCW_NO_INTERACTIVE") != "1" &&
+					isatty.IsTerminal(os.Stdin.Fd()) && isatty.IsTerminal(os.Stderr.Fd())
+				if config != nil {
+					cmd.API.ImageAliases = config.ImageAliases
+				}
+				sharedCache := os.Getenv("SCW_SHARED_CACHE_URL")
+				if sharedCache == "" && config != nil {
+					sharedCache = config.SharedCacheURL
+				}
+				if sharedCache != "" {
+					if err := cmd.API.MergeSharedCache(sharedCache); err != nil {
+						logrus.Warnf("Cannot use the shared cache: %v", err)
+					}
+				}
+				if *flTraceFile != "" {
+					traceFile, err := os.OpenFile(*flTraceFile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+					if err != nil {
+						return 1, api.Wrapf(err, "cannot open trace file")
+					}
+					defer traceFile.Close()
+					cmd.API.Tracer = api.NewTracer(traceFile)
+					cmd.API.Tracer.Event("command", map[string]interface{}{"name": cmd.Name(), "args": cmd.Flag.Args()})
+				}
+				if *flStats {
+					stats := api.NewRequestStats()
+					cmd.API.AfterRequest = stats.Record
+					defer stats.Fprint(streams.Stderr)
+				}
+			}
+			if cmd.API != nil && (*flCheckTok || os.Getenv("SCW_CHECK_TOKEN") == "1") {
+				if err := checkToken(cmd.API, *flRenewTok, time.Now()); err != nil {
+					return exitCode(err), fmt.Errorf("cannot execute '%s': %v%s", cmd.Name(), err, formatHints(err))
+				}
+			}
 			// clean cache between versions
 			if cmd.API != nil && config.Version != scwversion.VERSION {
 				cmd.API.ClearCache()
 				config.Save(*flConfig)
 			}
 			err = cmd.Exec(cmd, cmd.Flag.Args())
+			if err != nil && cmd.API != nil {
+				cmd.API.Tracer.Event("error", map[string]interface{}{"error": err.Error()})
+			}
+			if exitCode, ok := err.(commands.ExitCodeError); ok {
+				return exitCode.Code, nil
+			}
+			if api.IsDryRun(err) {
+				return 0, nil
+			}
 			switch err {
 			case nil:
 			case ErrExitFailure:
@@ -130,7 +251,7 @@ func Start(rawArgs []string, streams *commands.Streams) (int, error) {
 			case ErrExitSuccess:
 				return 0, nil
 			default:
-				return 1, fmt.Errorf("cannot execute '%s': %v", cmd.Name(), err)
+				return exitCode(err), fmt.Errorf("cannot execute '%s': %v%s", cmd.Name(), err, formatHints(err))
 			}
 			if cmd.API != nil {
 				cmd.API.Sync()
@@ -141,6 +262,132 @@ func Start(rawArgs []string, streams *commands.Streams) (int, error) {
 	return 1, fmt.Errorf("scw: unknown subcommand %s\nRun 'scw help' for usage", name)
 }
 
+// tokenExpiryWarning is how long before its expiration --check-token warns about the API token
+const tokenExpiryWarning = time.Hour
+
+// checkToken fails when the API token is invalid, renews it when it expires within renewBefore
+// and warns when it expires soon
+func checkToken(scw *api.ScalewayAPI, renewBefore time.Duration, now time.Time) error {
+	token, err := scw.GetToken()
+	if err != nil {
+		return api.Wrapf(err, "invalid API token")
+	}
+	expires, ok := token.ExpiresAt()
+	if !ok {
+		logrus.Debugf("The API token never expires")
+		return nil
+	}
+	left := expires.Sub(now)
+	logrus.Debugf("The API token expires at %s", expires.UTC().Format(time.RFC3339))
+	if left <= 0 {
+		return &api.ScalewayAPIError{
+			StatusCode: http.StatusUnauthorized,
+			Type:       "token_expired",
+			APIMessage: fmt.Sprintf("the API token expired at %s", expires.UTC().Format(time.RFC3339)),
+		}
+	}
+	if left < renewBefore {
+		renewed, err := scw.RenewToken(token.ID)
+		if api.IsDryRun(err) {
+			return nil
+		}
+		if err != nil {
+			return api.Wrapf(err, "cannot renew the API token")
+		}
+		if expires, ok = renewed.ExpiresAt(); ok {
+			left = expires.Sub(now)
+			logrus.Infof("Renewed the API token until %s", expires.UTC().Format(time.RFC3339))
+		} else {
+			left = tokenExpiryWarning
+		}
+	}
+	if left < tokenExpiryWarning {
+		logrus.Warnf("The API token expires in %v, pass --renew-token=%v or run 'scw login'", left.Round(time.Second), tokenExpiryWarning)
+	}
+	return nil
+}
+
+// selectedRegion returns the region of --region, SCW_REGION or the config file, in this order, par1 by default
+func selectedRegion(cfg *config.Config) string {
+	if flag.IsSet("-region") {
+		return *flRegion
+	}
+	if region := os.Getenv("SCW_REGION"); region != "" {
+		return region
+	}
+	if cfg != nil && cfg.Region != "" {
+		return cfg.Region
+	}
+	return *flRegion
+}
+
+// selectedAPIVersion returns the API version of --api-version, SCW_API_VERSION or the config file, in this order
+func selectedAPIVersion(cfg *config.Config) string {
+	if *flAPIVers != "" {
+		return *flAPIVers
+	}
+	if version := os.Getenv("SCW_API_VERSION"); version != "" {
+		return version
+	}
+	if cfg != nil {
+		return cfg.APIVersion
+	}
+	return ""
+}
+
+// configuredDuration sets value to the duration of the config file unless the flag name was passed
+func configuredDuration(value *time.Duration, name, configured, key string) error {
+	if configured == "" || flag.IsSet(name) {
+		return nil
+	}
+	duration, err := time.ParseDuration(configured)
+	if err != nil {
+		return api.Wrapf(err, "invalid timeouts.%s %q in the config file", key, configured)
+	}
+	*value = duration
+	return nil
+}
+
+// applyTimeouts sets the limits and delays from the flags, SCW_API_MAX_ATTEMPTS and the timeouts of the config file
+func applyTimeouts(cfg *config.Config) error {
+	timeouts := config.Timeouts{}
+	if cfg != nil && cfg.Timeouts != nil {
+		timeouts = *cfg.Timeouts
+	}
+	for _, setting := range []struct {
+		value      *time.Duration
+		name       string
+		configured string
+		key        string
+	}{
+		{flTimeout, "-timeout", timeouts.API.Timeout, "api.timeout"},
+		{flRateWait, "-max-rate-wait", timeouts.API.MaxRateWait, "api.max_rate_wait"},
+		{flSSHConnTO, "-ssh-connect-timeout", timeouts.SSH.ConnectTimeout, "ssh.connect_timeout"},
+		{flPollIntvl, "-wait-poll-interval", timeouts.Wait.PollInterval, "wait.poll_interval"},
+	} {
+		if err := configuredDuration(setting.value, setting.name, setting.configured, setting.key); err != nil {
+			return err
+		}
+	}
+	utils.SSHConnectTimeout = *flSSHConnTO
+	if *flPollIntvl > 0 {
+		api.WaitPollInterval = *flPollIntvl
+	}
+
+	api.RetryMaxAttempts = *flRetries
+	if !flag.IsSet("-api-retries") {
+		if attempts, err := strconv.Atoi(os.Getenv("SCW_API_MAX_ATTEMPTS")); err == nil && attempts > 0 {
+			api.RetryMaxAttempts = attempts
+		} else if timeouts.API.Retries > 0 {
+			api.RetryMaxAttempts = timeouts.API.Retries
+		}
+	}
+	if api.RetryMaxAttempts < 1 {
+		return fmt.Errorf("invalid --api-retries %d, expected at least 1", api.RetryMaxAttempts)
+	}
+	return nil
+}
+
 // getScalewayAPI returns a ScalewayAPI using the user config file
 func getScalewayAPI(region string, configPath string) (*api.ScalewayAPI, error) {
 	// We already get config globally, but whis way we can get explicit error when trying to create a ScalewayAPI object
@@ -148,7 +395,30 @@ func getScalewayAPI(region string, configPath string) (*api.ScalewayAPI, error)
 	if err != nil {
 		return nil, err
 	}
-	return api.NewScalewayAPI(config.Organization, config.Token, scwversion.UserAgent(), region, clilogger.SetupLogger)
+	options := []func(*api.ScalewayAPI){clilogger.SetupLogger, api.WithTimeout(*flTimeout)}
+	if version := selectedAPIVersion(config); version != "" {
+		options = append(options, api.WithAPIVersion(version))
+	}
+	if *flProxy != "" {
+		proxy, err := api.ParseProxyURL(*flProxy)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, api.WithProxy(proxy))
+	}
+	if *flTLSCA != "" || *flTLSCert != "" || *flTLSKey != "" || *flInsecure {
+		tlsConfig, err := api.NewTLSConfig(api.TLSOptions{
+			CAFile:             *flTLSCA,
+			CertFile:           *flTLSCert,
+			KeyFile:            *flTLSKey,
+			InsecureSkipVerify: *flInsecure,
+		})
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, api.WithTLSConfig(tlsConfig))
+	}
+	return api.NewScalewayAPI(config.Organization, config.Token, scwversion.UserAgent(), region, options...)
 }
 
 func initLogging(debug bool, verbose bool, streams *commands.Streams) {
@@ -197,10 +467,15 @@ func checkVersion() {
 			return
 		}
 		scwupdate.Close()
-		req := http.Client{
+		client := http.Client{
 			Timeout: 1 * time.Second,
 		}
-		resp, err := req.Get("https://scw-devtools.s3.nl-ams.scw.cloud/scw-cli-version")
+		req, err := http.NewRequest("GET", "https://scw-devtools.s3.nl-ams.scw.cloud/scw-cli-version", nil)
+		if err != nil {
+			return
+		}
+		req.Header.Set("User-Agent", scwversion.UserAgent())
+		resp, err := client.Do(req)
 		if resp != nil {
 			defer resp.Body.Close()
 		}
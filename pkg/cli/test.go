@@ -9,12 +9,12 @@ import (
 var (
 	scwcli         = "../../scw"
 	publicCommands = []string{
-		"help", "attach", "commit", "cp", "create",
+		"help", "attach", "bootscript", "commit", "cp", "create",
 		"events", "exec", "history", "images", "info",
-		"inspect", "kill", "login", "logout", "logs",
+		"inspect", "ip", "kill", "login", "logout", "logs",
 		"port", "products", "ps", "rename", "restart",
-		"rm", "rmi", "run", "search", "start", "stop",
-		"tag", "top", "version", "wait",
+		"rm", "rmi", "run", "search", "snapshot", "start", "stop",
+		"tag", "top", "version", "volume", "wait",
 	}
 	secretCommands = []string{
 		"_patch", "_completion", "_flush-cache", "_userdata", "_billing",
@@ -0,0 +1,62 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package cli
+
+import "github.com/scaleway/scaleway-cli/pkg/commands"
+
+var cmdApply = &Command{
+	Exec:        runApply,
+	UsageLine:   "_apply [OPTIONS] [MANIFEST]",
+	Description: "",
+	Hidden:      true,
+	Help:        "Reconcile the servers carrying the tag of a JSON manifest with the servers it describes, '-' reads the manifest from stdin. JSON being valid YAML, manifests can be named '.yml'",
+	Examples: `
+    $ scw _apply --plan manifest.json
+    $ scw _apply manifest.json
+    $ scw _apply --auto-approve manifest.json
+    $ cat manifest.json | scw _apply --auto-approve -
+    $ scw _apply --import --tag=stack=web > manifest.yml
+    $ scw _apply --force-unlock manifest.json
+`,
+}
+
+func init() {
+	cmdApply.Flag.BoolVar(&applyAutoApprove, []string{"-auto-approve"}, false, "Apply the plan without asking for confirmation")
+	cmdApply.Flag.BoolVar(&applyForceUnlock, []string{"-force-unlock"}, false, "Remove the lock left by an interrupted run")
+	cmdApply.Flag.BoolVar(&applyHelp, []string{"h", "-help"}, false, "Print usage")
+	cmdApply.Flag.BoolVar(&applyImport, []string{"-import"}, false, "Print a manifest describing the servers carrying --tag")
+	cmdApply.Flag.BoolVar(&applyPlan, []string{"-plan"}, false, "Only print the changes the manifest would make")
+	cmdApply.Flag.StringVar(&applyTag, []string{"t", "-tag"}, "", "Tag of the servers to import")
+}
+
+// Flags
+var applyAutoApprove bool // --auto-approve flag
+var applyForceUnlock bool // --force-unlock flag
+var applyHelp bool        // -h, --help flag
+var applyImport bool      // --import flag
+var applyPlan bool        // --plan flag
+var applyTag string       // -t, --tag flag
+
+func runApply(cmd *Command, rawArgs []string) error {
+	if applyHelp {
+		return cmd.PrintUsage()
+	}
+	if (applyImport && len(rawArgs) != 0) || (!applyImport && len(rawArgs) != 1) {
+		return cmd.PrintShortUsage()
+	}
+
+	args := commands.ApplyArgs{
+		Plan:        applyPlan,
+		AutoApprove: applyAutoApprove,
+		Import:      applyImport,
+		Tag:         applyTag,
+		ForceUnlock: applyForceUnlock,
+	}
+	if !applyImport {
+		args.Manifest = rawArgs[0]
+	}
+	ctx := cmd.GetContext(rawArgs)
+	return commands.RunApply(ctx, args)
+}
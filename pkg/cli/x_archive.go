@@ -0,0 +1,45 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package cli
+
+import "github.com/scaleway/scaleway-cli/pkg/commands"
+
+var cmdArchive = &Command{
+	Exec:        runArchive,
+	UsageLine:   "_archive [OPTIONS] SERVER [SERVER...]",
+	Description: "",
+	Hidden:      true,
+	Help: `Archive a server: stop it, snapshot its volumes, create an image from its
+root volume, then delete the server and its volumes. The reserved IP and the
+snapshots are kept so the server can be recreated later with 'scw _restore',
+which deletes the image and the snapshots. When the archive fails, the server is
+kept and the snapshots and the image already created are deleted.`,
+	Examples: `
+    $ scw _archive my-server
+    $ scw _archive $(scw ps -q -f tags=staging)
+`,
+}
+
+func init() {
+	cmdArchive.Flag.BoolVar(&archiveHelp, []string{"h", "-help"}, false, "Print usage")
+}
+
+// Flags
+var archiveHelp bool // -h, --help flag
+
+func runArchive(cmd *Command, rawArgs []string) error {
+	if archiveHelp {
+		return cmd.PrintUsage()
+	}
+	if len(rawArgs) < 1 {
+		return cmd.PrintShortUsage()
+	}
+
+	args := commands.ArchiveArgs{
+		Servers: rawArgs,
+	}
+	ctx := cmd.GetContext(rawArgs)
+	return commands.RunArchive(ctx, args)
+}
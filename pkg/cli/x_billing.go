@@ -11,7 +11,6 @@ import (
 	"text/tabwriter"
 	"time"
 
-	"github.com/docker/go-units"
 	"github.com/scaleway/scaleway-cli/pkg/commands"
 	"github.com/scaleway/scaleway-cli/pkg/pricing"
 	"github.com/scaleway/scaleway-cli/pkg/utils"
@@ -79,9 +78,8 @@ func runBilling(cmd *Command, rawArgs []string) error {
 		commercialType := strings.ToLower(server.CommercialType)
 		shortID := utils.TruncIf(server.Identifier, 8, !args.NoTrunc)
 		shortName := utils.TruncIf(utils.Wordify(server.Name), 25, !args.NoTrunc)
-		modificationTime, _ := time.Parse("2006-01-02T15:04:05.000000+00:00", server.ModificationDate)
-		modificationAgo := time.Now().UTC().Sub(modificationTime)
-		shortModificationDate := units.HumanDuration(modificationAgo)
+		modificationTime := server.ModificationDate.Value()
+		shortModificationDate := ctx.FormatTime(modificationTime)
 		usage := pricing.NewUsageByPath(fmt.Sprintf("/compute/%s/run", commercialType))
 		usage.SetStartEnd(modificationTime, time.Now().UTC())
 
@@ -0,0 +1,70 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package cli
+
+import "github.com/scaleway/scaleway-cli/pkg/commands"
+
+var cmdBuild = &Command{
+	Exec:        runBuild,
+	UsageLine:   "_build [OPTIONS] [CONTEXT]",
+	Description: "",
+	Hidden:      true,
+	Help:        "Build an image from a recipe: boot a builder server from FROM, run the RUN commands over SSH, snapshot it as TAG, then destroy the builder",
+	Examples: `
+    $ cat Scwfile
+    FROM ubuntu-bionic
+    RUN apt-get update && apt-get install -y nginx
+    TAG my-nginx
+    $ scw _build .
+    $ scw _build -f scwfile --tag=my-nginx-v2 .
+    $ scw _build --manifest=manifest.json .
+`,
+}
+
+func init() {
+	cmdBuild.Flag.BoolVar(&buildHelp, []string{"h", "-help"}, false, "Print usage")
+	cmdBuild.Flag.StringVar(&buildFile, []string{"f", "-file"}, "", "Recipe file (default CONTEXT/"+commands.DefaultRecipeFile+")")
+	cmdBuild.Flag.StringVar(&buildTag, []string{"t", "-tag"}, "", "Name of the built image, overrides the TAG instruction")
+	cmdBuild.Flag.StringVar(&buildCommercialType, []string{"-commercial-type"}, "", "Commercial type of the builder server, defaults to the smallest one of the image arch")
+	cmdBuild.Flag.StringVar(&buildGateway, []string{"g", "-gateway"}, "", "Use a SSH gateway")
+	cmdBuild.Flag.StringVar(&buildSSHUser, []string{"-user"}, "root", "Specify SSH user")
+	cmdBuild.Flag.IntVar(&buildSSHPort, []string{"p", "-port"}, 22, "Specify SSH port")
+	cmdBuild.Flag.StringVar(&buildManifest, []string{"-manifest"}, "", "Append the image to a packer manifest file")
+}
+
+// Flags
+var buildHelp bool             // -h, --help flag
+var buildFile string           // -f, --file flag
+var buildTag string            // -t, --tag flag
+var buildCommercialType string // --commercial-type flag
+var buildGateway string        // -g, --gateway flag
+var buildSSHUser string        // --user flag
+var buildSSHPort int           // -p, --port flag
+var buildManifest string       // --manifest flag
+
+func runBuild(cmd *Command, rawArgs []string) error {
+	if buildHelp {
+		return cmd.PrintUsage()
+	}
+	if len(rawArgs) > 1 {
+		return cmd.PrintShortUsage()
+	}
+
+	args := commands.BuildArgs{
+		File:           buildFile,
+		Context:        ".",
+		Tag:            buildTag,
+		CommercialType: buildCommercialType,
+		Gateway:        buildGateway,
+		SSHUser:        buildSSHUser,
+		SSHPort:        buildSSHPort,
+		Manifest:       buildManifest,
+	}
+	if len(rawArgs) == 1 {
+		args.Context = rawArgs[0]
+	}
+	ctx := cmd.GetContext(rawArgs)
+	return commands.RunBuild(ctx, args)
+}
@@ -0,0 +1,50 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package cli
+
+import "github.com/scaleway/scaleway-cli/pkg/commands"
+
+var cmdBulkEdit = &Command{
+	Exec:        runBulkEdit,
+	UsageLine:   "_bulk-edit [OPTIONS] FILE",
+	Description: "",
+	Hidden:      true,
+	Help: `Rename and tag servers from a CSV file ('-' for stdin) of SERVER,NAME,TAGS rows.
+An empty NAME keeps the name, TAGS are space separated, prefixed with + to add
+them or - to remove them. Every row is resolved before anything is changed.`,
+	Examples: `
+    $ cat edits.csv
+    server,name,tags
+    web-1,front-1,+prod -staging
+    6c9fc5a2,,+backup
+    $ scw _bulk-edit --dry-run edits.csv
+    $ scw _bulk-edit edits.csv
+`,
+}
+
+func init() {
+	cmdBulkEdit.Flag.BoolVar(&bulkEditHelp, []string{"h", "-help"}, false, "Print usage")
+	cmdBulkEdit.Flag.BoolVar(&bulkEditDryRun, []string{"n", "-dry-run"}, false, "Show the changes without applying them")
+}
+
+// Flags
+var bulkEditHelp bool   // -h, --help flag
+var bulkEditDryRun bool // -n, --dry-run flag
+
+func runBulkEdit(cmd *Command, rawArgs []string) error {
+	if bulkEditHelp {
+		return cmd.PrintUsage()
+	}
+	if len(rawArgs) != 1 {
+		return cmd.PrintShortUsage()
+	}
+
+	args := commands.BulkEditArgs{
+		File:   rawArgs[0],
+		DryRun: bulkEditDryRun,
+	}
+	ctx := cmd.GetContext(rawArgs)
+	return commands.RunBulkEdit(ctx, args)
+}
@@ -0,0 +1,48 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package cli
+
+import "github.com/scaleway/scaleway-cli/pkg/commands"
+
+var cmdConsole = &Command{
+	Exec:        runConsole,
+	UsageLine:   "_console [OPTIONS] IDENTIFIER [IDENTIFIER...]",
+	Description: "",
+	Hidden:      true,
+	Help:        "Print the web console URL of servers, images, snapshots or volumes",
+	Examples: `
+    $ scw _console my-server
+    $ scw _console --open image:ubuntu-bionic
+    $ scw _console snapshot:my-backup volume:my-data
+`,
+}
+
+func init() {
+	cmdConsole.Flag.BoolVar(&consoleHelp, []string{"h", "-help"}, false, "Print usage")
+	cmdConsole.Flag.BoolVar(&consoleOpen, []string{"o", "-open"}, false, "Open the URLs in the browser")
+	cmdConsole.Flag.StringVar(&consoleArch, []string{"-arch"}, "*", "Specify architecture of the images")
+}
+
+// Flags
+var consoleHelp bool   // -h, --help flag
+var consoleOpen bool   // -o, --open flag
+var consoleArch string // --arch flag
+
+func runConsole(cmd *Command, rawArgs []string) error {
+	if consoleHelp {
+		return cmd.PrintUsage()
+	}
+	if len(rawArgs) < 1 {
+		return cmd.PrintShortUsage()
+	}
+
+	args := commands.ConsoleArgs{
+		Identifiers: rawArgs,
+		Open:        consoleOpen,
+		Arch:        consoleArch,
+	}
+	ctx := cmd.GetContext(rawArgs)
+	return commands.RunConsole(ctx, args)
+}
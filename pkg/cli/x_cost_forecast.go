@@ -0,0 +1,42 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package cli
+
+import (
+	"github.com/scaleway/scaleway-cli/pkg/commands"
+	"github.com/sirupsen/logrus"
+)
+
+var cmdCostForecast = &Command{
+	Exec:        runCostForecast,
+	UsageLine:   "_cost-forecast [OPTIONS]",
+	Description: "",
+	Hidden:      true,
+	Help:        "Forecast the compute and storage costs of the current month per server tag, from the power tasks and the current inventory",
+	Examples: `
+    $ scw _cost-forecast
+`,
+}
+
+func init() {
+	cmdCostForecast.Flag.BoolVar(&costForecastHelp, []string{"h", "-help"}, false, "Print usage")
+}
+
+// Flags
+var costForecastHelp bool // -h, --help flag
+
+func runCostForecast(cmd *Command, rawArgs []string) error {
+	if costForecastHelp {
+		return cmd.PrintUsage()
+	}
+	if len(rawArgs) > 0 {
+		return cmd.PrintShortUsage()
+	}
+
+	logrus.Warn("Running servers are expected to run until the end of the month, for real usage visit https://cloud.scaleway.com/#/billing")
+
+	ctx := cmd.GetContext(rawArgs)
+	return commands.RunCostForecast(ctx)
+}
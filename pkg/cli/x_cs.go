@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/dustin/go-humanize"
+	"github.com/scaleway/scaleway-cli/pkg/api"
 )
 
 var cmdCS = &Command{
@@ -42,7 +43,7 @@ func runCS(cmd *Command, args []string) error {
 	if len(args) == 0 {
 		containers, err := cmd.API.GetContainers()
 		if err != nil {
-			return fmt.Errorf("Unable to get your containers: %v", err)
+			return api.Wrapf(err, "Unable to get your containers")
 		}
 		for _, container := range containers.Containers {
 			fmt.Fprintf(cmd.Streams().Stdout, "s3://%s\n", container.Name)
@@ -52,7 +53,7 @@ func runCS(cmd *Command, args []string) error {
 	container := strings.Replace(args[0], "s3://", "", 1)
 	datas, err := cmd.API.GetContainerDatas(container)
 	if err != nil {
-		return fmt.Errorf("Unable to get your data from %s: %v", container, err)
+		return api.Wrapf(err, "Unable to get your data from %s", container)
 	}
 	for _, data := range datas.Container {
 		t, err := time.Parse(time.RFC3339, data.LastModified)
@@ -0,0 +1,75 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package cli
+
+import "github.com/scaleway/scaleway-cli/pkg/commands"
+
+var cmdDeploy = &Command{
+	Exec:        runDeploy,
+	UsageLine:   "_deploy [OPTIONS] [SERVER...] DIRECTORY",
+	Description: "",
+	Hidden:      true,
+	Help:        "Copy DIRECTORY to servers, run a post-deploy command and restart a service, then print the result of each server",
+	Examples: `
+    $ scw _deploy myserver ./build/
+    $ scw _deploy --destination=/var/www --restart=nginx web1 web2 ./build/
+    $ scw _deploy --parallel --filter tags=web --post-deploy="npm ci" --restart=myapp ./build/
+`,
+}
+
+func init() {
+	cmdDeploy.Flag.BoolVar(&deployHelp, []string{"h", "-help"}, false, "Print usage")
+	cmdDeploy.Flag.StringVar(&deployDestination, []string{"d", "-destination"}, "/srv/app", "Directory of the servers receiving the content of DIRECTORY")
+	cmdDeploy.Flag.StringVar(&deployFilters, []string{"f", "-filter"}, "", "Deploy to the running servers matching the filters instead of SERVER, see 'scw ps -h'")
+	cmdDeploy.Flag.StringVar(&deployPostDeploy, []string{"-post-deploy"}, "", "Command run in the destination directory after the copy")
+	cmdDeploy.Flag.StringVar(&deployRestart, []string{"-restart"}, "", "Systemd service restarted at the end of the deployment")
+	cmdDeploy.Flag.BoolVar(&deployParallel, []string{"-parallel"}, false, "Deploy to every server at the same time")
+	cmdDeploy.Flag.StringVar(&deployGateway, []string{"g", "-gateway"}, "", "Use a SSH gateway")
+	cmdDeploy.Flag.StringVar(&deploySSHUser, []string{"-user"}, "root", "Specify SSH user")
+	cmdDeploy.Flag.IntVar(&deploySSHPort, []string{"p", "-port"}, 22, "Specify SSH port")
+}
+
+// Flags
+var deployHelp bool          // -h, --help flag
+var deployDestination string // -d, --destination flag
+var deployFilters string     // -f, --filter flag
+var deployPostDeploy string  // --post-deploy flag
+var deployRestart string     // --restart flag
+var deployParallel bool      // --parallel flag
+var deployGateway string     // -g, --gateway flag
+var deploySSHUser string     // --user flag
+var deploySSHPort int        // -p, --port flag
+
+func runDeploy(cmd *Command, rawArgs []string) error {
+	if deployHelp {
+		return cmd.PrintUsage()
+	}
+	if len(rawArgs) < 1 {
+		return cmd.PrintShortUsage()
+	}
+	servers := rawArgs[:len(rawArgs)-1]
+	if (len(servers) == 0) == (deployFilters == "") {
+		return cmd.PrintShortUsage()
+	}
+
+	args := commands.DeployArgs{
+		Servers:     servers,
+		Source:      rawArgs[len(rawArgs)-1],
+		Destination: deployDestination,
+		PostDeploy:  deployPostDeploy,
+		Restart:     deployRestart,
+		Parallel:    deployParallel,
+		Gateway:     deployGateway,
+		SSHUser:     deploySSHUser,
+		SSHPort:     deploySSHPort,
+	}
+	filters, err := cmd.parseFilters(deployFilters)
+	if err != nil {
+		return err
+	}
+	args.Filters = filters
+	ctx := cmd.GetContext(rawArgs)
+	return commands.RunDeploy(ctx, args)
+}
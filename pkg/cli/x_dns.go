@@ -0,0 +1,64 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package cli
+
+import "github.com/scaleway/scaleway-cli/pkg/commands"
+
+var cmdDNS = &Command{
+	Exec:        runDNS,
+	UsageLine:   "_dns [OPTIONS] [SERVER...]",
+	Description: "",
+	Hidden:      true,
+	Help: `Generate A/AAAA records mapping server names to their current public IPs.
+With --output, the serial of the SOA record of the zone file is incremented when the records change.
+With --format=nsupdate --apply, the script is sent with nsupdate and the records published by the
+previous applied update of the zone, whose servers are not listed anymore, are deleted.`,
+	Examples: `
+    $ scw _dns
+    $ scw _dns -o /etc/bind/db.example.com
+    $ scw _dns --format=nsupdate --zone=example.com --nameserver=ns1.example.com --apply --key=Kexample.key
+    $ scw _dns my-server
+`,
+}
+
+func init() {
+	cmdDNS.Flag.BoolVar(&dnsHelp, []string{"h", "-help"}, false, "Print usage")
+	cmdDNS.Flag.StringVar(&dnsZone, []string{"z", "-zone"}, "", "DNS zone of the records (required with --format=nsupdate)")
+	cmdDNS.Flag.IntVar(&dnsTTL, []string{"-ttl"}, 300, "TTL of the records")
+	cmdDNS.Flag.StringVar(&dnsFormat, []string{"-format"}, "zone", "Output format (zone, nsupdate)")
+	cmdDNS.Flag.StringVar(&dnsOutput, []string{"o", "-output"}, "", "Update the records block of a zone file instead of printing it")
+	cmdDNS.Flag.StringVar(&dnsNameserver, []string{"-nameserver"}, "", "Nameserver to send updates to (with --format=nsupdate)")
+	cmdDNS.Flag.BoolVar(&dnsApply, []string{"-apply"}, false, "Send the update with nsupdate instead of printing it (with --format=nsupdate)")
+	cmdDNS.Flag.StringVar(&dnsKey, []string{"-key"}, "", "TSIG key file given to nsupdate -k (with --apply)")
+}
+
+// Flags
+var dnsHelp bool         // -h, --help flag
+var dnsZone string       // -z, --zone flag
+var dnsTTL int           // --ttl flag
+var dnsFormat string     // --format flag
+var dnsOutput string     // -o, --output flag
+var dnsNameserver string // --nameserver flag
+var dnsApply bool        // --apply flag
+var dnsKey string        // --key flag
+
+func runDNS(cmd *Command, rawArgs []string) error {
+	if dnsHelp {
+		return cmd.PrintUsage()
+	}
+
+	args := commands.DNSArgs{
+		Servers:    rawArgs,
+		Zone:       dnsZone,
+		TTL:        dnsTTL,
+		Format:     dnsFormat,
+		Output:     dnsOutput,
+		Nameserver: dnsNameserver,
+		Apply:      dnsApply,
+		Key:        dnsKey,
+	}
+	ctx := cmd.GetContext(rawArgs)
+	return commands.RunDNS(ctx, args)
+}
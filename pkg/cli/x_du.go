@@ -0,0 +1,58 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package cli
+
+import "github.com/scaleway/scaleway-cli/pkg/commands"
+
+var cmdDu = &Command{
+	Exec:        runDu,
+	UsageLine:   "_du [OPTIONS] SERVER [SERVER...]",
+	Description: "",
+	Hidden:      true,
+	Help:        "Show the disk usage of servers",
+	Examples: `
+    $ scw _du my-server
+    $ scw _du $(scw ps -q)
+    $ scw _du --threshold=80 $(scw ps -q)
+    $ scw _du --path=/var my-server
+`,
+}
+
+func init() {
+	cmdDu.Flag.BoolVar(&duHelp, []string{"h", "-help"}, false, "Print usage")
+	cmdDu.Flag.StringVar(&duGateway, []string{"g", "-gateway"}, "", "Use a SSH gateway")
+	cmdDu.Flag.StringVar(&duSSHUser, []string{"-user"}, "root", "Specify SSH user")
+	cmdDu.Flag.IntVar(&duSSHPort, []string{"p", "-port"}, 22, "Specify SSH port")
+	cmdDu.Flag.StringVar(&duPath, []string{"-path"}, "", "Show the size of the sub-directories of PATH instead of the filesystems")
+	cmdDu.Flag.IntVar(&duThreshold, []string{"-threshold"}, 90, "Warn about filesystems used above this percentage")
+}
+
+// Flags
+var duHelp bool      // -h, --help flag
+var duGateway string // -g, --gateway flag
+var duSSHUser string // --user flag
+var duSSHPort int    // -p, --port flag
+var duPath string    // --path flag
+var duThreshold int  // --threshold flag
+
+func runDu(cmd *Command, rawArgs []string) error {
+	if duHelp {
+		return cmd.PrintUsage()
+	}
+	if len(rawArgs) < 1 {
+		return cmd.PrintShortUsage()
+	}
+
+	args := commands.DuArgs{
+		Servers:   rawArgs,
+		Gateway:   duGateway,
+		SSHUser:   duSSHUser,
+		SSHPort:   duSSHPort,
+		Path:      duPath,
+		Threshold: duThreshold,
+	}
+	ctx := cmd.GetContext(rawArgs)
+	return commands.RunDu(ctx, args)
+}
@@ -0,0 +1,48 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package cli
+
+import "github.com/scaleway/scaleway-cli/pkg/commands"
+
+var cmdEnv = &Command{
+	Exec:        runEnv,
+	UsageLine:   "_env [OPTIONS]",
+	Description: "",
+	Hidden:      true,
+	Help:        "Print shell exports of the credentials, region and endpoint, for terraform, packer and the SDKs",
+	Examples: `
+    $ eval $(scw _env)
+    $ eval $(scw --region=ams1 _env)
+    $ eval $(scw _env --profile=staging)
+    $ scw _env --shell=fish | source
+`,
+}
+
+func init() {
+	cmdEnv.Flag.BoolVar(&envHelp, []string{"h", "-help"}, false, "Print usage")
+	cmdEnv.Flag.StringVar(&envProfile, []string{"-profile"}, "", "Export the credentials of a profile of the config file")
+	cmdEnv.Flag.StringVar(&envShell, []string{"-shell"}, "sh", "Syntax of the exports (sh, fish, powershell)")
+}
+
+// Flags
+var envHelp bool      // -h, --help flag
+var envProfile string // --profile flag
+var envShell string   // --shell flag
+
+func runEnv(cmd *Command, rawArgs []string) error {
+	if envHelp {
+		return cmd.PrintUsage()
+	}
+	if len(rawArgs) != 0 {
+		return cmd.PrintShortUsage()
+	}
+
+	args := commands.EnvArgs{
+		Profile: envProfile,
+		Shell:   envShell,
+	}
+	ctx := cmd.GetContext(rawArgs)
+	return commands.RunEnv(ctx, args)
+}
@@ -0,0 +1,55 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package cli
+
+import "github.com/scaleway/scaleway-cli/pkg/commands"
+
+var cmdExport = &Command{
+	Exec:        runExport,
+	UsageLine:   "_export [OPTIONS] SERVER[:PATH] FILE|-",
+	Description: "",
+	Hidden:      true,
+	Help:        "Export the root filesystem of a server (or PATH) as a tarball, its SHA256 is verified end-to-end and written to FILE.sha256",
+	Examples: `
+    $ scw _export my-server rootfs.tar
+    $ scw _export my-server:/srv srv.tar
+    $ scw _export --tag=false my-server - | gzip > rootfs.tar.gz
+`,
+}
+
+func init() {
+	cmdExport.Flag.BoolVar(&exportHelp, []string{"h", "-help"}, false, "Print usage")
+	cmdExport.Flag.BoolVar(&exportTag, []string{"-tag"}, true, "Record the SHA256 in the server tags")
+	cmdExport.Flag.StringVar(&exportGateway, []string{"g", "-gateway"}, "", "Use a SSH gateway")
+	cmdExport.Flag.StringVar(&exportSSHUser, []string{"-user"}, "root", "Specify SSH user")
+	cmdExport.Flag.IntVar(&exportSSHPort, []string{"p", "-port"}, 22, "Specify SSH port")
+}
+
+// Flags
+var exportHelp bool      // -h, --help flag
+var exportTag bool       // --tag flag
+var exportGateway string // -g, --gateway flag
+var exportSSHUser string // --user flag
+var exportSSHPort int    // -p, --port flag
+
+func runExport(cmd *Command, rawArgs []string) error {
+	if exportHelp {
+		return cmd.PrintUsage()
+	}
+	if len(rawArgs) != 2 {
+		return cmd.PrintShortUsage()
+	}
+
+	args := commands.ExportArgs{
+		Source:      rawArgs[0],
+		Destination: rawArgs[1],
+		Tag:         exportTag,
+		Gateway:     exportGateway,
+		SSHUser:     exportSSHUser,
+		SSHPort:     exportSSHPort,
+	}
+	ctx := cmd.GetContext(rawArgs)
+	return commands.RunExport(ctx, args)
+}
@@ -0,0 +1,79 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package cli
+
+import "github.com/scaleway/scaleway-cli/pkg/commands"
+
+var cmdFleetExec = &Command{
+	Exec:        runFleetExec,
+	UsageLine:   "_fleet-exec [OPTIONS] [SERVER...] -- COMMAND [ARGS...]",
+	Description: "",
+	Hidden:      true,
+	Help:        "Run a command on many servers in parallel, the output of each server is prefixed by its name or written to --output-dir",
+	Examples: `
+    $ scw _fleet-exec web1 web2 -- uptime
+    $ scw _fleet-exec --filter tags=web -- systemctl is-active nginx
+    $ scw _fleet-exec --jobs=50 --output-dir=./out --filter state=running -- 'df -h /'
+`,
+}
+
+func init() {
+	cmdFleetExec.Flag.BoolVar(&fleetExecHelp, []string{"h", "-help"}, false, "Print usage")
+	cmdFleetExec.Flag.StringVar(&fleetExecFilters, []string{"f", "-filter"}, "", "Run on the servers matching the filters instead of SERVER, see 'scw ps -h'")
+	cmdFleetExec.Flag.IntVar(&fleetExecJobs, []string{"j", "-jobs"}, 10, "Number of servers running the command at the same time")
+	cmdFleetExec.Flag.StringVar(&fleetExecOutputDir, []string{"o", "-output-dir"}, "", "Write the stdout and stderr of each server to DIR/NAME-ID.stdout and .stderr, and a summary to DIR/summary.json")
+	cmdFleetExec.Flag.StringVar(&fleetExecGateway, []string{"g", "-gateway"}, "", "Use a SSH gateway")
+	cmdFleetExec.Flag.StringVar(&fleetExecSSHUser, []string{"-user"}, "root", "Specify SSH user")
+	cmdFleetExec.Flag.IntVar(&fleetExecSSHPort, []string{"p", "-port"}, 22, "Specify SSH port")
+}
+
+// Flags
+var fleetExecHelp bool        // -h, --help flag
+var fleetExecFilters string   // -f, --filter flag
+var fleetExecJobs int         // -j, --jobs flag
+var fleetExecOutputDir string // -o, --output-dir flag
+var fleetExecGateway string   // -g, --gateway flag
+var fleetExecSSHUser string   // --user flag
+var fleetExecSSHPort int      // -p, --port flag
+
+func runFleetExec(cmd *Command, rawArgs []string) error {
+	if fleetExecHelp {
+		return cmd.PrintUsage()
+	}
+	servers, command := rawArgs, []string{}
+	for i, arg := range rawArgs {
+		if arg == "--" {
+			servers, command = rawArgs[:i], rawArgs[i+1:]
+			break
+		}
+	}
+	if len(command) == 0 && fleetExecFilters != "" {
+		// a leading -- is consumed by the flag parser
+		servers, command = nil, rawArgs
+	} else if len(command) == 0 && len(servers) > 0 {
+		// without --, the last argument is the command
+		servers, command = servers[:len(servers)-1], servers[len(servers)-1:]
+	}
+	if len(command) == 0 || (len(servers) == 0) == (fleetExecFilters == "") {
+		return cmd.PrintShortUsage()
+	}
+
+	args := commands.FleetExecArgs{
+		Servers:   servers,
+		Command:   command,
+		Jobs:      fleetExecJobs,
+		OutputDir: fleetExecOutputDir,
+		Gateway:   fleetExecGateway,
+		SSHUser:   fleetExecSSHUser,
+		SSHPort:   fleetExecSSHPort,
+	}
+	filters, err := cmd.parseFilters(fleetExecFilters)
+	if err != nil {
+		return err
+	}
+	args.Filters = filters
+	ctx := cmd.GetContext(rawArgs)
+	return commands.RunFleetExec(ctx, args)
+}
@@ -33,6 +33,9 @@ func runFlushCache(cmd *Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("Failed to flush the cache")
 	}
+	if err = cmd.API.ResponseCache.Flush(); err != nil {
+		return fmt.Errorf("Failed to flush the cached responses")
+	}
 	fmt.Println("Cache flushed")
 	return nil
 }
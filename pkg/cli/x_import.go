@@ -0,0 +1,58 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package cli
+
+import "github.com/scaleway/scaleway-cli/pkg/commands"
+
+var cmdImport = &Command{
+	Exec:        runImport,
+	UsageLine:   "_import [OPTIONS] FILE|- SERVER:PATH",
+	Description: "",
+	Hidden:      true,
+	Help:        "Extract a tarball on a server, its SHA256 is checked against FILE.sha256 or --sha256 before the upload and verified end-to-end, a tarball read from stdin is spooled to a temporary file to be checked against --sha256",
+	Examples: `
+    $ scw _import rootfs.tar my-server:/mnt
+    $ scw _import --sha256=9f86d08... rootfs.tar my-server:/mnt
+    $ scw _export old-server - | scw _import - new-server:/mnt
+`,
+}
+
+func init() {
+	cmdImport.Flag.BoolVar(&importHelp, []string{"h", "-help"}, false, "Print usage")
+	cmdImport.Flag.StringVar(&importSHA256, []string{"-sha256"}, "", "Expected SHA256 of the tarball")
+	cmdImport.Flag.BoolVar(&importTag, []string{"-tag"}, true, "Record the SHA256 in the server tags")
+	cmdImport.Flag.StringVar(&importGateway, []string{"g", "-gateway"}, "", "Use a SSH gateway")
+	cmdImport.Flag.StringVar(&importSSHUser, []string{"-user"}, "root", "Specify SSH user")
+	cmdImport.Flag.IntVar(&importSSHPort, []string{"p", "-port"}, 22, "Specify SSH port")
+}
+
+// Flags
+var importHelp bool      // -h, --help flag
+var importSHA256 string  // --sha256 flag
+var importTag bool       // --tag flag
+var importGateway string // -g, --gateway flag
+var importSSHUser string // --user flag
+var importSSHPort int    // -p, --port flag
+
+func runImport(cmd *Command, rawArgs []string) error {
+	if importHelp {
+		return cmd.PrintUsage()
+	}
+	if len(rawArgs) != 2 {
+		return cmd.PrintShortUsage()
+	}
+
+	args := commands.ImportArgs{
+		Source:      rawArgs[0],
+		Destination: rawArgs[1],
+		SHA256:      importSHA256,
+		Tag:         importTag,
+		Gateway:     importGateway,
+		SSHUser:     importSSHUser,
+		SSHPort:     importSSHPort,
+	}
+	ctx := cmd.GetContext(rawArgs)
+	return commands.RunImport(ctx, args)
+}
@@ -6,7 +6,7 @@ package cli
 
 var cmdIPS = &Command{
 	Exec:      runIPS,
-	UsageLine: "_ips [OPTIONS] [IP_ID [SERVER_ID]]",
+	UsageLine: "_ips [OPTIONS] [IP [SERVER]]",
 
 	Description: "Interacts with your IPs",
 	Hidden:      true,
@@ -16,7 +16,9 @@ var cmdIPS = &Command{
     $ scw _ips IP_ID
     $ scw _ips --new
     $ scw _ips --attach IP_ID SERVER_ID
+    $ scw _ips --attach 51.15.1.2 web-1
     $ scw _ips --delete IP_ID
+    $ scw _ips --delete 51.15.1.2
     $ scw _ips --detach IP_ID
 `,
 }
@@ -48,19 +50,42 @@ func runIPS(cmd *Command, args []string) error {
 		return nil
 	}
 	if ipDelete != "" {
-		return cmd.API.DeleteIP(ipDelete)
+		ipID, err := cmd.API.GetIPID(ipDelete)
+		if err != nil {
+			return err
+		}
+		return cmd.API.DeleteIP(ipID)
 	}
 	if ipAttach {
 		if len(args) != 2 {
 			return cmd.PrintShortUsage()
 		}
-		return cmd.API.AttachIP(args[0], args[1])
+		ipID, err := cmd.API.GetIPID(args[0])
+		if err != nil {
+			return err
+		}
+		serverID, err := cmd.API.GetServerID(args[1])
+		if err != nil {
+			return err
+		}
+		return cmd.API.AttachIP(ipID, serverID)
 	}
 	if ipDetach {
-		return cmd.API.DetachIP(args[0])
+		if len(args) != 1 {
+			return cmd.PrintShortUsage()
+		}
+		ipID, err := cmd.API.GetIPID(args[0])
+		if err != nil {
+			return err
+		}
+		return cmd.API.DetachIP(ipID)
 	}
 	if len(args) == 1 {
-		ip, err := cmd.API.GetIP(args[0])
+		ipID, err := cmd.API.GetIPID(args[0])
+		if err != nil {
+			return err
+		}
+		ip, err := cmd.API.GetIP(ipID)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,62 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package cli
+
+import (
+	"fmt"
+
+	"github.com/scaleway/scaleway-cli/pkg/commands"
+)
+
+var cmdOrg = &Command{
+	Exec:        runOrg,
+	UsageLine:   "_org [OPTIONS] SUBCOMMAND",
+	Description: "",
+	Hidden:      true,
+	Help:        "Interacts with the organization",
+	Examples: `
+    $ scw _org members
+    $ scw _org members --role=admin
+    $ scw _org members -q
+`,
+}
+
+func init() {
+	cmdOrg.Flag.BoolVar(&orgHelp, []string{"h", "-help"}, false, "Print usage")
+	cmdOrg.Flag.BoolVar(&orgQuiet, []string{"q", "-quiet"}, false, "Only display emails")
+	cmdOrg.Flag.StringVar(&orgRole, []string{"-role"}, "", "Only display the members having this role")
+	subCmdOrg = map[string]func(cmd *Command, args []string) error{
+		"members": orgMembers,
+	}
+}
+
+// Flags
+var orgHelp bool   // -h, --help flag
+var orgQuiet bool  // -q, --quiet flag
+var orgRole string // --role flag
+
+var subCmdOrg map[string]func(cmd *Command, args []string) error
+
+func orgMembers(cmd *Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.PrintShortUsage()
+	}
+	ctx := cmd.GetContext(args)
+	return commands.RunOrgMembers(ctx, commands.OrgMembersArgs{
+		Role:  orgRole,
+		Quiet: orgQuiet,
+	})
+}
+
+func runOrg(cmd *Command, args []string) error {
+	if orgHelp || len(args) == 0 {
+		return cmd.PrintUsage()
+	}
+	cmd.Flag.Parse(args[1:])
+	if function, ok := subCmdOrg[args[0]]; ok {
+		return function(cmd, cmd.Flag.Args())
+	}
+	return fmt.Errorf("subcommand not found: %s", args[0])
+}
@@ -6,6 +6,8 @@ package cli
 
 import (
 	"fmt"
+	"reflect"
+	"strconv"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
@@ -22,6 +24,8 @@ var cmdPatch = &Command{
 	Examples: `
     $ scw _patch myserver state_detail=booted
     $ scw _patch server:myserver state_detail=booted
+    $ scw _patch myserver dynamic_ip_required=false
+    $ scw _patch myserver tags="prod web"
 `,
 }
 
@@ -58,7 +62,7 @@ func runPatch(cmd *Command, args []string) error {
 	case api.IdentifierServer:
 		currentServer, err := cmd.API.GetServer(ident.Identifier)
 		if err != nil {
-			return fmt.Errorf("Cannot get server %s: %v", ident.Identifier, err)
+			return api.Wrapf(err, "Cannot get server %s", ident.Identifier)
 		}
 
 		var payload api.ScalewayServerPatchDefinition
@@ -96,11 +100,12 @@ func runPatch(cmd *Command, args []string) error {
 				}
 			}
 		case "tags":
-			newTags := strings.Split(newValue, " ")
+			newTags := strings.Fields(newValue)
 			log.Debugf("%s=%s  =>  %s=%s", fieldName, currentServer.Tags, fieldName, newTags)
-			// fixme test equality with reflect.DeepEqual ?
-			changes++
-			payload.Tags = &newTags
+			if len(currentServer.Tags)+len(newTags) > 0 && !reflect.DeepEqual(currentServer.Tags, newTags) {
+				changes++
+				payload.Tags = &newTags
+			}
 		case "ipv6":
 			log.Debugf("%s=%s  =>  %s=%s", fieldName, currentServer.Tags, fieldName, newValue)
 			switch strings.ToLower(newValue) {
@@ -113,10 +118,21 @@ func runPatch(cmd *Command, args []string) error {
 				payload.EnableIPV6 = &f
 				changes++
 			}
+		case "dynamic_ip_required":
+			required, err := strconv.ParseBool(newValue)
+			if err != nil {
+				return fmt.Errorf("invalid value for %s: %q", fieldName, newValue)
+			}
+			current := currentServer.DynamicIPRequired != nil && *currentServer.DynamicIPRequired
+			log.Debugf("%s=%v  =>  %s=%v", fieldName, current, fieldName, required)
+			if current != required {
+				changes++
+				payload.DynamicIPRequired = &required
+			}
 		default:
 			return fmt.Errorf("'_patch server %s=' not implemented", fieldName)
 		}
-		// FIXME: volumes, tags, dynamic_ip_required
+		// FIXME: volumes
 
 		if changes > 0 {
 			log.Debugf("updating server: %d change(s)", changes)
@@ -125,7 +141,7 @@ func runPatch(cmd *Command, args []string) error {
 			log.Debugf("no changes, not updating server")
 		}
 		if err != nil {
-			return fmt.Errorf("Cannot update server: %v", err)
+			return api.Wrapf(err, "Cannot update server")
 		}
 	default:
 		return fmt.Errorf("_patch not implemented for this kind of object")
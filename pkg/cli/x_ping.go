@@ -0,0 +1,42 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package cli
+
+import "github.com/scaleway/scaleway-cli/pkg/commands"
+
+var cmdPing = &Command{
+	Exec:        runPing,
+	UsageLine:   "_ping [OPTIONS] [SERVER...]",
+	Description: "",
+	Hidden:      true,
+	Help:        "Measure latency to the API endpoints and the TCP handshake time to the SSH port of servers",
+	Examples: `
+    $ scw _ping
+    $ scw _ping -n 10 my-server
+    $ scw _ping $(scw ps -q)
+`,
+}
+
+func init() {
+	cmdPing.Flag.BoolVar(&pingHelp, []string{"h", "-help"}, false, "Print usage")
+	cmdPing.Flag.IntVar(&pingCount, []string{"n", "-count"}, 3, "Number of measures per target")
+}
+
+// Flags
+var pingHelp bool // -h, --help flag
+var pingCount int // -n, --count flag
+
+func runPing(cmd *Command, rawArgs []string) error {
+	if pingHelp {
+		return cmd.PrintUsage()
+	}
+
+	args := commands.PingArgs{
+		Servers: rawArgs,
+		Count:   pingCount,
+	}
+	ctx := cmd.GetContext(rawArgs)
+	return commands.RunPing(ctx, args)
+}
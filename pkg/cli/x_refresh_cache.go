@@ -0,0 +1,45 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package cli
+
+import "github.com/scaleway/scaleway-cli/pkg/commands"
+
+var cmdRefreshCache = &Command{
+	Exec:        runRefreshCache,
+	UsageLine:   "_refresh-cache [OPTIONS] [TYPE...]",
+	Description: "",
+	Hidden:      true,
+	Help:        "Fetch resources and persist them in the cache, TYPE can be servers, images, snapshots, volumes, bootscripts or ips",
+	Examples: `
+    $ scw _refresh-cache
+    $ scw _refresh-cache servers ips
+    $ scw _refresh-cache --prune
+
+    # crontab entry keeping the cache warm on a bastion
+    */10 * * * * scw -q _refresh-cache --prune
+`,
+}
+
+func init() {
+	cmdRefreshCache.Flag.BoolVar(&refreshCacheHelp, []string{"h", "-help"}, false, "Print usage")
+	cmdRefreshCache.Flag.BoolVar(&refreshCachePrune, []string{"-prune"}, false, "Remove deleted resources from the cache")
+}
+
+// Flags
+var refreshCacheHelp bool  // -h, --help flag
+var refreshCachePrune bool // --prune flag
+
+func runRefreshCache(cmd *Command, rawArgs []string) error {
+	if refreshCacheHelp {
+		return cmd.PrintUsage()
+	}
+
+	args := commands.RefreshCacheArgs{
+		Types: rawArgs,
+		Prune: refreshCachePrune,
+	}
+	ctx := cmd.GetContext(rawArgs)
+	return commands.RunRefreshCache(ctx, args)
+}
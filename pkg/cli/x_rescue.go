@@ -0,0 +1,56 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package cli
+
+import "github.com/scaleway/scaleway-cli/pkg/commands"
+
+var cmdRescue = &Command{
+	Exec:        runRescue,
+	UsageLine:   "_rescue [OPTIONS] SERVER",
+	Description: "",
+	Hidden:      true,
+	Help:        "Reboot a server on the rescue bootscript and wait for SSH, --disable reverts to the normal boot",
+	Examples: `
+    $ scw _rescue my-server
+    $ scw _rescue --disable my-server
+`,
+}
+
+func init() {
+	cmdRescue.Flag.BoolVar(&rescueHelp, []string{"h", "-help"}, false, "Print usage")
+	cmdRescue.Flag.BoolVar(&rescueDisable, []string{"-disable"}, false, "Leave rescue mode and restore the previous boot configuration")
+	cmdRescue.Flag.StringVar(&rescueBootscript, []string{"-bootscript"}, "rescue", "Rescue bootscript to use")
+	cmdRescue.Flag.StringVar(&rescueGateway, []string{"g", "-gateway"}, "", "Use a SSH gateway")
+	cmdRescue.Flag.StringVar(&rescueSSHUser, []string{"-user"}, "root", "Specify SSH user")
+	cmdRescue.Flag.IntVar(&rescueSSHPort, []string{"p", "-port"}, 22, "Specify SSH port")
+}
+
+// Flags
+var rescueHelp bool         // -h, --help flag
+var rescueDisable bool      // --disable flag
+var rescueBootscript string // --bootscript flag
+var rescueGateway string    // -g, --gateway flag
+var rescueSSHUser string    // --user flag
+var rescueSSHPort int       // -p, --port flag
+
+func runRescue(cmd *Command, rawArgs []string) error {
+	if rescueHelp {
+		return cmd.PrintUsage()
+	}
+	if len(rawArgs) != 1 {
+		return cmd.PrintShortUsage()
+	}
+
+	args := commands.RescueArgs{
+		Server:     rawArgs[0],
+		Disable:    rescueDisable,
+		Bootscript: rescueBootscript,
+		Gateway:    rescueGateway,
+		SSHUser:    rescueSSHUser,
+		SSHPort:    rescueSSHPort,
+	}
+	ctx := cmd.GetContext(rawArgs)
+	return commands.RunRescue(ctx, args)
+}
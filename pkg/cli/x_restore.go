@@ -0,0 +1,43 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package cli
+
+import "github.com/scaleway/scaleway-cli/pkg/commands"
+
+var cmdRestore = &Command{
+	Exec:        runRestore,
+	UsageLine:   "_restore [OPTIONS] [ARCHIVE...]",
+	Description: "",
+	Hidden:      true,
+	Help:        "Recreate servers archived with 'scw _archive', or list archives when called without arguments. An archive is designated by the identifier of the archived server, or by its name when no other archive has it.",
+	Examples: `
+    $ scw _restore
+    $ scw _restore my-server
+    $ scw _restore --start my-server
+    $ scw _restore 5c4a8c3b
+`,
+}
+
+func init() {
+	cmdRestore.Flag.BoolVar(&restoreHelp, []string{"h", "-help"}, false, "Print usage")
+	cmdRestore.Flag.BoolVar(&restoreStart, []string{"s", "-start"}, false, "Start the server once restored")
+}
+
+// Flags
+var restoreHelp bool  // -h, --help flag
+var restoreStart bool // -s, --start flag
+
+func runRestore(cmd *Command, rawArgs []string) error {
+	if restoreHelp {
+		return cmd.PrintUsage()
+	}
+
+	args := commands.RestoreArgs{
+		Archives: rawArgs,
+		Start:    restoreStart,
+	}
+	ctx := cmd.GetContext(rawArgs)
+	return commands.RunRestore(ctx, args)
+}
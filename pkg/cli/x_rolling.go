@@ -0,0 +1,81 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package cli
+
+import (
+	"time"
+
+	"github.com/scaleway/scaleway-cli/pkg/commands"
+)
+
+var cmdRolling = &Command{
+	Exec:        runRolling,
+	UsageLine:   "_rolling [OPTIONS] [SERVER...] -- ACTION [ARGS...]",
+	Description: "",
+	Hidden:      true,
+	Help:        "Apply ACTION (restart, start, stop or exec COMMAND) to the servers, or to the ones matching --filter, batch by batch, pausing between batches and aborting when a server fails its health check",
+	Examples: `
+    $ scw _rolling --filter tags=web --batch 2 --pause 30s -- restart
+    $ scw _rolling --filter tags=web --health-check="curl -sf localhost" -- restart
+    $ scw _rolling web1 web2 web3 -- exec systemctl restart nginx
+    $ scw _rolling --filter "state=stopped tags=worker" --batch 5 -- start
+`,
+}
+
+func init() {
+	cmdRolling.Flag.BoolVar(&rollingHelp, []string{"h", "-help"}, false, "Print usage")
+	cmdRolling.Flag.StringVar(&rollingFilters, []string{"f", "-filter"}, "", "Apply ACTION to the servers matching the filters, see 'scw ps -h'")
+	cmdRolling.Flag.IntVar(&rollingBatch, []string{"b", "-batch"}, 1, "Number of servers handled at the same time")
+	cmdRolling.Flag.DurationVar(&rollingPause, []string{"-pause"}, 0, "Pause between two batches")
+	cmdRolling.Flag.StringVar(&rollingHealthCheck, []string{"-health-check"}, "", "Command run on each server after ACTION, a failure aborts the next batches")
+	cmdRolling.Flag.StringVar(&rollingGateway, []string{"g", "-gateway"}, "", "Use a SSH gateway")
+	cmdRolling.Flag.StringVar(&rollingSSHUser, []string{"-user"}, "root", "Specify SSH user")
+	cmdRolling.Flag.IntVar(&rollingSSHPort, []string{"p", "-port"}, 22, "Specify SSH port")
+}
+
+// Flags
+var rollingHelp bool           // -h, --help flag
+var rollingFilters string      // -f, --filter flag
+var rollingBatch int           // -b, --batch flag
+var rollingPause time.Duration // --pause flag
+var rollingHealthCheck string  // --health-check flag
+var rollingGateway string      // -g, --gateway flag
+var rollingSSHUser string      // --user flag
+var rollingSSHPort int         // -p, --port flag
+
+func runRolling(cmd *Command, rawArgs []string) error {
+	if rollingHelp {
+		return cmd.PrintUsage()
+	}
+	servers, action := []string{}, rawArgs
+	for i, arg := range rawArgs {
+		if arg == "--" {
+			servers, action = rawArgs[:i], rawArgs[i+1:]
+			break
+		}
+	}
+	// with --filter, a leading -- is consumed by the flag parser
+	if len(action) == 0 || (len(servers) == 0) == (rollingFilters == "") {
+		return cmd.PrintShortUsage()
+	}
+
+	args := commands.RollingArgs{
+		Servers:     servers,
+		Batch:       rollingBatch,
+		Pause:       rollingPause,
+		Action:      action,
+		HealthCheck: rollingHealthCheck,
+		Gateway:     rollingGateway,
+		SSHUser:     rollingSSHUser,
+		SSHPort:     rollingSSHPort,
+	}
+	filters, err := cmd.parseFilters(rollingFilters)
+	if err != nil {
+		return err
+	}
+	args.Filters = filters
+	ctx := cmd.GetContext(rawArgs)
+	return commands.RunRolling(ctx, args)
+}
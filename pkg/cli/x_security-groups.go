@@ -10,6 +10,7 @@ import (
 	"io"
 
 	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/scaleway/scaleway-cli/pkg/utils"
 )
 
 var cmdSecurityGroups = &Command{
@@ -81,7 +82,7 @@ type rulesDefinition struct {
 func printRawMode(out io.Writer, data interface{}) error {
 	js, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
-		return fmt.Errorf("Unable to parse the data: %v", err)
+		return api.Wrapf(err, "Unable to parse the data")
 	}
 	fmt.Fprintf(out, "%s\n", string(js))
 	return nil
@@ -174,6 +175,9 @@ func newSecurityGroupRule(cmd *Command, args []string) error {
 	if len(args) != 2 {
 		return cmd.PrintShortUsage()
 	}
+	if err := utils.ValidateJSON([]byte(args[1]), &rule); err != nil {
+		return fmt.Errorf("invalid rule definition:\n%v", err)
+	}
 	if err := json.Unmarshal([]byte(args[1]), &rule); err != nil {
 		return err
 	}
@@ -194,6 +198,9 @@ func updateSecurityGroupRule(cmd *Command, args []string) error {
 	if len(args) != 3 {
 		return cmd.PrintShortUsage()
 	}
+	if err := utils.ValidateJSON([]byte(args[2]), &rule); err != nil {
+		return fmt.Errorf("invalid rule definition:\n%v", err)
+	}
 	if err := json.Unmarshal([]byte(args[2]), &rule); err != nil {
 		return err
 	}
@@ -0,0 +1,52 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package cli
+
+import (
+	"time"
+
+	"github.com/scaleway/scaleway-cli/pkg/commands"
+)
+
+var cmdServe = &Command{
+	Exec:        runServe,
+	UsageLine:   "_serve [OPTIONS]",
+	Description: "",
+	Hidden:      true,
+	Help:        "Serve read-only endpoints (/servers, /servers/NAME, /servers/NAME/ip) without authentication, bind it to a trusted interface",
+	Examples: `
+    $ scw _serve
+    $ scw _serve --listen=10.1.2.3:8080 --ttl=60
+    $ curl -s localhost:8080/servers?state=running
+    $ ssh $(curl -s localhost:8080/servers/my-server/ip)
+`,
+}
+
+func init() {
+	cmdServe.Flag.BoolVar(&serveHelp, []string{"h", "-help"}, false, "Print usage")
+	cmdServe.Flag.StringVar(&serveListen, []string{"l", "-listen"}, "127.0.0.1:8080", "Address to listen on")
+	cmdServe.Flag.IntVar(&serveTTL, []string{"-ttl"}, 30, "Seconds before fetching the servers again")
+}
+
+// Flags
+var serveHelp bool     // -h, --help flag
+var serveListen string // -l, --listen flag
+var serveTTL int       // --ttl flag
+
+func runServe(cmd *Command, rawArgs []string) error {
+	if serveHelp {
+		return cmd.PrintUsage()
+	}
+	if len(rawArgs) != 0 {
+		return cmd.PrintShortUsage()
+	}
+
+	args := commands.ServeArgs{
+		Listen: serveListen,
+		TTL:    time.Duration(serveTTL) * time.Second,
+	}
+	ctx := cmd.GetContext(rawArgs)
+	return commands.RunServe(ctx, args)
+}
@@ -0,0 +1,56 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package cli
+
+import "github.com/scaleway/scaleway-cli/pkg/commands"
+
+var cmdSSHKeyscan = &Command{
+	Exec:        runSSHKeyscan,
+	UsageLine:   "_ssh-keyscan [OPTIONS] [SERVER...]",
+	Description: "",
+	Hidden:      true,
+	Help:        "Print the SSH host keys of the running servers as known_hosts lines, or merge them into a known_hosts file, replacing the keys of recreated servers",
+	Examples: `
+    $ scw _ssh-keyscan
+    $ scw _ssh-keyscan myserver
+    $ scw _ssh-keyscan --filter tags=web -o ~/.ssh/known_hosts
+    $ scw _ssh-keyscan --scan -o ~/.ssh/scaleway_known_hosts
+`,
+}
+
+func init() {
+	cmdSSHKeyscan.Flag.BoolVar(&sshKeyscanHelp, []string{"h", "-help"}, false, "Print usage")
+	cmdSSHKeyscan.Flag.StringVar(&sshKeyscanFilters, []string{"f", "-filter"}, "", "Only the servers matching the filters, see 'scw ps -h'")
+	cmdSSHKeyscan.Flag.StringVar(&sshKeyscanOutput, []string{"o", "-output"}, "", "Merge the host keys into this known_hosts file instead of printing them")
+	cmdSSHKeyscan.Flag.IntVar(&sshKeyscanPort, []string{"p", "-port"}, 22, "Specify SSH port")
+	cmdSSHKeyscan.Flag.BoolVar(&sshKeyscanScan, []string{"-scan"}, false, "Connect to the servers which do not publish their host keys")
+}
+
+// Flags
+var sshKeyscanHelp bool      // -h, --help flag
+var sshKeyscanFilters string // -f, --filter flag
+var sshKeyscanOutput string  // -o, --output flag
+var sshKeyscanPort int       // -p, --port flag
+var sshKeyscanScan bool      // --scan flag
+
+func runSSHKeyscan(cmd *Command, rawArgs []string) error {
+	if sshKeyscanHelp {
+		return cmd.PrintUsage()
+	}
+
+	args := commands.SSHKeyscanArgs{
+		Servers: rawArgs,
+		Output:  sshKeyscanOutput,
+		Port:    sshKeyscanPort,
+		Scan:    sshKeyscanScan,
+	}
+	filters, err := cmd.parseFilters(sshKeyscanFilters)
+	if err != nil {
+		return err
+	}
+	args.Filters = filters
+	ctx := cmd.GetContext(rawArgs)
+	return commands.RunSSHKeyscan(ctx, args)
+}
@@ -0,0 +1,52 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package cli
+
+import (
+	"github.com/scaleway/scaleway-cli/pkg/commands"
+	"github.com/sirupsen/logrus"
+)
+
+var cmdStorageReport = &Command{
+	Exec:        runStorageReport,
+	UsageLine:   "_storage-report [OPTIONS]",
+	Description: "",
+	Hidden:      true,
+	Help:        "Aggregate volume, snapshot and image storage per name prefix or tag, with an estimated monthly price",
+	Examples: `
+    $ scw _storage-report
+    $ scw _storage-report --group-by=tag
+    $ scw _storage-report --separator=-
+`,
+}
+
+func init() {
+	cmdStorageReport.Flag.BoolVar(&storageReportHelp, []string{"h", "-help"}, false, "Print usage")
+	cmdStorageReport.Flag.StringVar(&storageReportGroupBy, []string{"-group-by"}, "prefix", "Group resources by name 'prefix' or by server 'tag'")
+	cmdStorageReport.Flag.StringVar(&storageReportSeparator, []string{"-separator"}, "-_.", "Characters ending the name prefix")
+}
+
+// Flags
+var storageReportHelp bool        // -h, --help flag
+var storageReportGroupBy string   // --group-by flag
+var storageReportSeparator string // --separator flag
+
+func runStorageReport(cmd *Command, rawArgs []string) error {
+	if storageReportHelp {
+		return cmd.PrintUsage()
+	}
+	if len(rawArgs) > 0 {
+		return cmd.PrintShortUsage()
+	}
+
+	logrus.Warn("Prices are estimated with the local SSD storage rate, for real usage visit https://cloud.scaleway.com/#/billing")
+
+	args := commands.StorageReportArgs{
+		GroupBy:   storageReportGroupBy,
+		Separator: storageReportSeparator,
+	}
+	ctx := cmd.GetContext(rawArgs)
+	return commands.RunStorageReport(ctx, args)
+}
@@ -0,0 +1,51 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package cli
+
+import "github.com/scaleway/scaleway-cli/pkg/commands"
+
+var cmdTasks = &Command{
+	Exec:        runTasks,
+	UsageLine:   "_tasks [OPTIONS] [ls | cancel TASK...]",
+	Description: "",
+	Hidden:      true,
+	Help:        "List the pending tasks of the account or cancel them",
+	Examples: `
+    $ scw _tasks
+    $ scw _tasks ls -a
+    $ scw _tasks cancel 5a8b34c2
+`,
+}
+
+func init() {
+	cmdTasks.Flag.BoolVar(&tasksHelp, []string{"h", "-help"}, false, "Print usage")
+	cmdTasks.Flag.BoolVar(&tasksAll, []string{"a", "-all"}, false, "Show all tasks, not only pending and started ones")
+	cmdTasks.Flag.BoolVar(&tasksNoTrunc, []string{"-no-trunc"}, false, "Don't truncate output")
+}
+
+// Flags
+var tasksHelp bool    // -h, --help flag
+var tasksAll bool     // -a, --all flag
+var tasksNoTrunc bool // --no-trunc flag
+
+func runTasks(cmd *Command, rawArgs []string) error {
+	if tasksHelp {
+		return cmd.PrintUsage()
+	}
+
+	args := commands.TasksArgs{
+		All:     tasksAll,
+		NoTrunc: tasksNoTrunc,
+	}
+	if len(rawArgs) > 0 {
+		args.Action = rawArgs[0]
+		args.Tasks = rawArgs[1:]
+	}
+	if args.Action == "cancel" && len(args.Tasks) == 0 {
+		return cmd.PrintShortUsage()
+	}
+	ctx := cmd.GetContext(rawArgs)
+	return commands.RunTasks(ctx, args)
+}
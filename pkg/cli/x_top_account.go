@@ -0,0 +1,54 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package cli
+
+import (
+	"time"
+
+	"github.com/scaleway/scaleway-cli/pkg/commands"
+)
+
+var cmdTopAccount = &Command{
+	Exec:        runTopAccount,
+	UsageLine:   "_top-account [OPTIONS]",
+	Description: "",
+	Hidden:      true,
+	Help:        "Live dashboard of the servers, quotas, recent tasks and API latency of the account",
+	Examples: `
+    $ scw _top-account
+    $ scw _top-account -n 10
+    $ scw _top-account --iterations=1
+`,
+}
+
+func init() {
+	cmdTopAccount.Flag.BoolVar(&topAccountHelp, []string{"h", "-help"}, false, "Print usage")
+	cmdTopAccount.Flag.IntVar(&topAccountInterval, []string{"n", "-interval"}, 5, "Seconds to wait between refreshes")
+	cmdTopAccount.Flag.IntVar(&topAccountIterations, []string{"-iterations"}, 0, "Exit after this number of refreshes, 0 runs until interrupted")
+	cmdTopAccount.Flag.IntVar(&topAccountTasks, []string{"-tasks"}, 10, "Number of recent tasks to display")
+}
+
+// Flags
+var topAccountHelp bool      // -h, --help flag
+var topAccountInterval int   // -n, --interval flag
+var topAccountIterations int // --iterations flag
+var topAccountTasks int      // --tasks flag
+
+func runTopAccount(cmd *Command, rawArgs []string) error {
+	if topAccountHelp {
+		return cmd.PrintUsage()
+	}
+	if len(rawArgs) > 0 {
+		return cmd.PrintShortUsage()
+	}
+
+	args := commands.TopAccountArgs{
+		Interval:   time.Duration(topAccountInterval) * time.Second,
+		Iterations: topAccountIterations,
+		Tasks:      topAccountTasks,
+	}
+	ctx := cmd.GetContext(rawArgs)
+	return commands.RunTopAccount(ctx, args)
+}
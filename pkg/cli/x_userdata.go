@@ -44,11 +44,11 @@ func runUserdata(cmd *Command, args []string) error {
 	}
 	metadata := false
 	ctx := cmd.GetContext(args)
-	var API *api.ScalewayAPI
+	var API api.ScalewayAPIClient
 	var err error
 	var serverID string
 	if args[0] == "local" {
-		API, err = api.NewScalewayAPI("", "", scwversion.UserAgent(), *flRegion)
+		API, err = api.NewScalewayAPI("", "", scwversion.UserAgent(), *flRegion, api.WithTimeout(*flTimeout))
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,62 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package cli
+
+import (
+	"time"
+
+	"github.com/scaleway/scaleway-cli/pkg/commands"
+)
+
+var cmdVerifyImage = &Command{
+	Exec:        runVerifyImage,
+	UsageLine:   "_verify-image [OPTIONS] IMAGE [COMMAND] [ARGS...]",
+	Description: "",
+	Hidden:      true,
+	Help:        "Boot a temporary server from IMAGE, wait for SSH, run COMMAND as a health check, then destroy the server and its volumes and report PASS or FAIL",
+	Examples: `
+    $ scw _verify-image my-custom-image
+    $ scw _verify-image my-custom-image systemctl is-active nginx
+    $ scw _verify-image --commercial-type=DEV1-S --timeout=10m my-custom-image curl -sf localhost
+`,
+}
+
+func init() {
+	cmdVerifyImage.Flag.BoolVar(&verifyImageHelp, []string{"h", "-help"}, false, "Print usage")
+	cmdVerifyImage.Flag.StringVar(&verifyImageCommercialType, []string{"-commercial-type"}, "", "Commercial type of the temporary server, defaults to the smallest one of the image arch")
+	cmdVerifyImage.Flag.StringVar(&verifyImageGateway, []string{"g", "-gateway"}, "", "Use a SSH gateway")
+	cmdVerifyImage.Flag.StringVar(&verifyImageSSHUser, []string{"-user"}, "root", "Specify SSH user")
+	cmdVerifyImage.Flag.IntVar(&verifyImageSSHPort, []string{"p", "-port"}, 22, "Specify SSH port")
+	cmdVerifyImage.Flag.DurationVar(&verifyImageTimeout, []string{"-timeout"}, 15*time.Minute, "Fail if SSH is not reachable within this duration")
+}
+
+// Flags
+var verifyImageHelp bool             // -h, --help flag
+var verifyImageCommercialType string // --commercial-type flag
+var verifyImageGateway string        // -g, --gateway flag
+var verifyImageSSHUser string        // --user flag
+var verifyImageSSHPort int           // -p, --port flag
+var verifyImageTimeout time.Duration // --timeout flag
+
+func runVerifyImage(cmd *Command, rawArgs []string) error {
+	if verifyImageHelp {
+		return cmd.PrintUsage()
+	}
+	if len(rawArgs) < 1 {
+		return cmd.PrintShortUsage()
+	}
+
+	args := commands.VerifyImageArgs{
+		Image:          rawArgs[0],
+		Command:        rawArgs[1:],
+		CommercialType: verifyImageCommercialType,
+		Gateway:        verifyImageGateway,
+		SSHUser:        verifyImageSSHUser,
+		SSHPort:        verifyImageSSHPort,
+		Timeout:        verifyImageTimeout,
+	}
+	ctx := cmd.GetContext(rawArgs)
+	return commands.RunVerifyImage(ctx, args)
+}
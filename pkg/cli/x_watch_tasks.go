@@ -0,0 +1,63 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package cli
+
+import (
+	"time"
+
+	"github.com/scaleway/scaleway-cli/pkg/commands"
+)
+
+var cmdWatchTasks = &Command{
+	Exec:        runWatchTasks,
+	UsageLine:   "_watch-tasks [OPTIONS]",
+	Description: "",
+	Hidden:      true,
+	Help:        "Poll the tasks of the account and stream the tasks as they appear or change of status",
+	Examples: `
+    $ scw _watch-tasks
+    $ scw _watch-tasks --server=my-server --status=failure
+    $ scw _watch-tasks --type=poweron --format=json | jq .
+`,
+}
+
+func init() {
+	cmdWatchTasks.Flag.BoolVar(&watchTasksHelp, []string{"h", "-help"}, false, "Print usage")
+	cmdWatchTasks.Flag.IntVar(&watchTasksInterval, []string{"n", "-interval"}, 2, "Seconds to wait between polls")
+	cmdWatchTasks.Flag.StringVar(&watchTasksServer, []string{"-server"}, "", "Only show the tasks of a server")
+	cmdWatchTasks.Flag.StringVar(&watchTasksType, []string{"-type"}, "", "Only show the tasks whose description contains TYPE (i.e: poweron)")
+	cmdWatchTasks.Flag.StringVar(&watchTasksStatus, []string{"-status"}, "", "Only show the tasks with a status (pending, started, success, failure)")
+	cmdWatchTasks.Flag.StringVar(&watchTasksFormat, []string{"-format"}, "table", "Output format, table or json (one object per line)")
+	cmdWatchTasks.Flag.BoolVar(&watchTasksExisting, []string{"a", "-all"}, false, "Also show the tasks existing when starting")
+}
+
+// Flags
+var watchTasksHelp bool     // -h, --help flag
+var watchTasksInterval int  // -n, --interval flag
+var watchTasksServer string // --server flag
+var watchTasksType string   // --type flag
+var watchTasksStatus string // --status flag
+var watchTasksFormat string // --format flag
+var watchTasksExisting bool // -a, --all flag
+
+func runWatchTasks(cmd *Command, rawArgs []string) error {
+	if watchTasksHelp {
+		return cmd.PrintUsage()
+	}
+	if len(rawArgs) != 0 {
+		return cmd.PrintShortUsage()
+	}
+
+	args := commands.WatchTasksArgs{
+		Interval: time.Duration(watchTasksInterval) * time.Second,
+		Server:   watchTasksServer,
+		Type:     watchTasksType,
+		Status:   watchTasksStatus,
+		Format:   watchTasksFormat,
+		Existing: watchTasksExisting,
+	}
+	ctx := cmd.GetContext(rawArgs)
+	return commands.RunWatchTasks(ctx, args)
+}
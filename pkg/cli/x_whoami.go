@@ -0,0 +1,39 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package cli
+
+import "github.com/scaleway/scaleway-cli/pkg/commands"
+
+var cmdWhoami = &Command{
+	Exec:        runWhoami,
+	UsageLine:   "_whoami [OPTIONS]",
+	Description: "",
+	Hidden:      true,
+	Help:        "Print the authenticated user, organization, token and API endpoint in use",
+	Examples: `
+    $ scw _whoami
+    $ scw --region=ams1 _whoami
+`,
+}
+
+func init() {
+	cmdWhoami.Flag.BoolVar(&whoamiHelp, []string{"h", "-help"}, false, "Print usage")
+}
+
+// Flags
+var whoamiHelp bool // -h, --help flag
+
+func runWhoami(cmd *Command, rawArgs []string) error {
+	if whoamiHelp {
+		return cmd.PrintUsage()
+	}
+	if len(rawArgs) != 0 {
+		return cmd.PrintShortUsage()
+	}
+
+	args := commands.WhoamiArgs{}
+	ctx := cmd.GetContext(rawArgs)
+	return commands.RunWhoami(ctx, args)
+}
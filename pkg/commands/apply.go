@@ -0,0 +1,471 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/sirupsen/logrus"
+)
+
+// ApplyArgs are flags for the `RunApply` function
+type ApplyArgs struct {
+	Manifest    string
+	Plan        bool
+	AutoApprove bool
+	Import      bool
+	Tag         string
+	ForceUnlock bool
+}
+
+// Manifest is the desired state of the servers reconciled by `scw _apply`
+type Manifest struct {
+	// Tag is set on every server of the manifest, the servers carrying it and missing from the manifest are deleted
+	Tag string `json:"tag"`
+	// Lock is the name or ID of a server, not managed by the manifest, whose user_data locks concurrent runs
+	Lock    string           `json:"lock,omitempty"`
+	Servers []ManifestServer `json:"servers"`
+}
+
+// ManifestServer is a server of a Manifest, identified by its name
+type ManifestServer struct {
+	Name           string   `json:"name"`
+	Image          string   `json:"image"`
+	CommercialType string   `json:"commercial_type"`
+	Tags           []string `json:"tags,omitempty"`
+	State          string   `json:"state,omitempty"`
+	IPV6           bool     `json:"ipv6,omitempty"`
+}
+
+// Actions of an apply plan
+const (
+	applyCreate  = "create"
+	applyUpdate  = "update"
+	applyReplace = "replace"
+	applyDelete  = "delete"
+)
+
+// applyDiff is a field of a server changed by an apply plan
+type applyDiff struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// applyChange is a server created, updated, replaced or deleted by an apply plan
+type applyChange struct {
+	Action  string
+	Name    string
+	Current *api.ScalewayServer
+	Desired *ManifestServer
+	Diffs   []applyDiff
+}
+
+// LoadManifest reads a JSON manifest, "-" reads the standard input
+func LoadManifest(path string, stdin io.Reader) (*Manifest, error) {
+	var content []byte
+	var err error
+	if path == "-" {
+		content, err = ioutil.ReadAll(stdin)
+	} else {
+		content, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var manifest Manifest
+	if err = json.Unmarshal(content, &manifest); err != nil {
+		return nil, api.Wrapf(err, "invalid manifest %s", path)
+	}
+	if manifest.Tag == "" {
+		return nil, fmt.Errorf("invalid manifest %s: missing \"tag\"", path)
+	}
+	names := make(map[string]bool)
+	for i := range manifest.Servers {
+		server := &manifest.Servers[i]
+		if server.Name == "" || server.Image == "" || server.CommercialType == "" {
+			return nil, fmt.Errorf("invalid manifest %s: server #%d needs a name, an image and a commercial_type", path, i+1)
+		}
+		if names[server.Name] {
+			return nil, fmt.Errorf("invalid manifest %s: duplicated server %q", path, server.Name)
+		}
+		names[server.Name] = true
+		if server.Name == manifest.Lock {
+			return nil, fmt.Errorf("invalid manifest %s: the lock server %q cannot be managed by the manifest", path, server.Name)
+		}
+		switch server.State {
+		case "":
+			server.State = "running"
+		case "running", "stopped":
+		default:
+			return nil, fmt.Errorf("invalid manifest %s: state of %q must be 'running' or 'stopped'", path, server.Name)
+		}
+	}
+	return &manifest, nil
+}
+
+// ImportManifest returns a manifest describing the servers carrying tag
+func ImportManifest(servers []api.ScalewayServer, tag string) *Manifest {
+	manifest := &Manifest{Tag: tag, Servers: []ManifestServer{}}
+	for _, server := range servers {
+		if !hasTag(server.Tags, tag) {
+			continue
+		}
+		image := server.Image.Name
+		if image == "" {
+			image = server.Image.Identifier
+		}
+		var tags []string
+		for _, serverTag := range server.Tags {
+			if serverTag != tag {
+				tags = append(tags, serverTag)
+			}
+		}
+		manifest.Servers = append(manifest.Servers, ManifestServer{
+			Name:           server.Name,
+			Image:          image,
+			CommercialType: server.CommercialType,
+			Tags:           tags,
+			State:          applyServerState(server.State),
+			IPV6:           server.EnableIPV6,
+		})
+	}
+	sort.SliceStable(manifest.Servers, func(i, j int) bool { return manifest.Servers[i].Name < manifest.Servers[j].Name })
+	return manifest
+}
+
+// manifestTags returns the sorted tags a server of manifest must carry
+func manifestTags(manifest *Manifest, server *ManifestServer) []string {
+	tags := []string{manifest.Tag}
+	for _, tag := range server.Tags {
+		if tag != manifest.Tag {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// applyServerState returns "running" or "stopped" for the state of an existing server
+func applyServerState(state string) string {
+	switch state {
+	case "running", "starting":
+		return "running"
+	}
+	return "stopped"
+}
+
+// planApply compares the servers of manifest with the servers carrying its tag,
+// resolveImage returns the identifier of an image for an architecture
+func planApply(manifest *Manifest, servers []api.ScalewayServer, resolveImage func(name, arch string) (string, error)) ([]applyChange, error) {
+	existing := make(map[string]*api.ScalewayServer)
+	for i := range servers {
+		server := &servers[i]
+		if !hasTag(server.Tags, manifest.Tag) {
+			continue
+		}
+		if _, ok := existing[server.Name]; ok {
+			return nil, fmt.Errorf("several servers named %q carry the tag %q, remove one of them", server.Name, manifest.Tag)
+		}
+		existing[server.Name] = server
+	}
+
+	var changes []applyChange
+	for i := range manifest.Servers {
+		desired := &manifest.Servers[i]
+		current, ok := existing[desired.Name]
+		if !ok {
+			changes = append(changes, applyChange{Action: applyCreate, Name: desired.Name, Desired: desired, Diffs: []applyDiff{
+				{Field: "commercial_type", New: desired.CommercialType},
+				{Field: "image", New: desired.Image},
+				{Field: "state", New: desired.State},
+				{Field: "tags", New: strings.Join(manifestTags(manifest, desired), " ")},
+			}})
+			continue
+		}
+		delete(existing, desired.Name)
+
+		change := applyChange{Action: applyUpdate, Name: desired.Name, Current: current, Desired: desired}
+		if !strings.EqualFold(current.CommercialType, desired.CommercialType) {
+			change.Action = applyReplace
+			change.Diffs = append(change.Diffs, applyDiff{Field: "commercial_type", Old: current.CommercialType, New: desired.CommercialType})
+		}
+		if desired.Image != current.Image.Name && desired.Image != current.Image.Identifier {
+			imageID, err := resolveImage(desired.Image, current.Arch)
+			if err != nil {
+				return nil, api.Wrapf(err, "cannot resolve the image of %q", desired.Name)
+			}
+			if imageID != current.Image.Identifier {
+				change.Action = applyReplace
+				change.Diffs = append(change.Diffs, applyDiff{Field: "image", Old: current.Image.Name, New: desired.Image})
+			}
+		}
+		if current.EnableIPV6 != desired.IPV6 {
+			change.Diffs = append(change.Diffs, applyDiff{Field: "ipv6", Old: strconv.FormatBool(current.EnableIPV6), New: strconv.FormatBool(desired.IPV6)})
+		}
+		if state := applyServerState(current.State); state != desired.State {
+			change.Diffs = append(change.Diffs, applyDiff{Field: "state", Old: state, New: desired.State})
+		}
+		currentTags := append([]string{}, current.Tags...)
+		sort.Strings(currentTags)
+		if old, new := strings.Join(currentTags, " "), strings.Join(manifestTags(manifest, desired), " "); old != new {
+			change.Diffs = append(change.Diffs, applyDiff{Field: "tags", Old: old, New: new})
+		}
+		if len(change.Diffs) > 0 {
+			changes = append(changes, change)
+		}
+	}
+
+	names := make([]string, 0, len(existing))
+	for name := range existing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		changes = append(changes, applyChange{Action: applyDelete, Name: name, Current: existing[name]})
+	}
+	return changes, nil
+}
+
+// applyColors are the ANSI colors of the plan actions
+var applyColors = map[string]string{
+	applyCreate:  "\033[32m",
+	applyUpdate:  "\033[33m",
+	applyReplace: "\033[35m",
+	applyDelete:  "\033[31m",
+}
+
+// printPlan writes a plan similar to `terraform plan`, colored when color is true
+func printPlan(w io.Writer, changes []applyChange, color bool) {
+	symbols := map[string]string{applyCreate: "+", applyUpdate: "~", applyReplace: "-/+", applyDelete: "-"}
+	paint := func(action, text string) string {
+		if !color {
+			return text
+		}
+		return applyColors[action] + text + "\033[0m"
+	}
+
+	created, modified, deleted := 0, 0, 0
+	for _, change := range changes {
+		switch change.Action {
+		case applyCreate:
+			created++
+		case applyUpdate:
+			modified++
+		case applyReplace:
+			created++
+			deleted++
+		case applyDelete:
+			deleted++
+		}
+
+		fmt.Fprintf(w, "%s %s (%s)\n", paint(change.Action, symbols[change.Action]), change.Name, change.Action)
+		for _, diff := range change.Diffs {
+			switch {
+			case change.Action == applyCreate:
+				fmt.Fprintf(w, "    %s: %q\n", diff.Field, diff.New)
+			case change.Action == applyReplace && diff.Field != "state" && diff.Field != "tags" && diff.Field != "ipv6":
+				fmt.Fprintf(w, "    %s: %q => %q %s\n", diff.Field, diff.Old, diff.New, paint(applyReplace, "(forces replacement)"))
+			default:
+				fmt.Fprintf(w, "    %s: %q => %q\n", diff.Field, diff.Old, diff.New)
+			}
+		}
+	}
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "No changes, the servers match the manifest.")
+		return
+	}
+	fmt.Fprintf(w, "\nPlan: %d to create, %d to modify, %d to delete.\n", created, modified, deleted)
+}
+
+// createManifestServer creates a server of manifest named name, stopped
+func createManifestServer(ctx CommandContext, manifest *Manifest, desired *ManifestServer, name string) (string, error) {
+	return api.CreateServer(ctx.API, &api.ConfigCreateServer{
+		ImageName:         desired.Image,
+		Name:              name,
+		CommercialType:    desired.CommercialType,
+		Env:               strings.Join(manifestTags(manifest, desired), " "),
+		DynamicIPRequired: true,
+		EnableIPV6:        desired.IPV6,
+		BootType:          "auto",
+	})
+}
+
+// replaceManifestServer replaces the server of change by a new one, the server is only deleted
+// once its replacement is created, under a temporary name so both never share a name
+func replaceManifestServer(ctx CommandContext, manifest *Manifest, change applyChange) (string, error) {
+	serverID, err := createManifestServer(ctx, manifest, change.Desired, change.Name+"-replacement")
+	if err != nil {
+		return "", api.Wrapf(err, "cannot create the replacement, %s is kept", change.Name)
+	}
+	if err = ctx.API.DeleteServerForce(change.Current.Identifier); err != nil {
+		if deleteErr := ctx.API.DeleteServer(serverID); deleteErr != nil {
+			logrus.Warnf("failed to delete the replacement %s: %v", serverID, deleteErr)
+		}
+		return "", err
+	}
+	name := change.Name
+	if err = ctx.API.PatchServer(serverID, api.ScalewayServerPatchDefinition{Name: &name}); err != nil {
+		return "", api.Wrapf(err, "cannot rename the replacement %s", serverID)
+	}
+	return serverID, nil
+}
+
+// executeChange applies a change of a plan
+func executeChange(ctx CommandContext, manifest *Manifest, change applyChange) error {
+	var serverID string
+	var err error
+	switch change.Action {
+	case applyCreate:
+		serverID, err = createManifestServer(ctx, manifest, change.Desired, change.Name)
+	case applyReplace:
+		serverID, err = replaceManifestServer(ctx, manifest, change)
+	case applyDelete:
+		return ctx.API.DeleteServerForce(change.Current.Identifier)
+	}
+	if err != nil {
+		return err
+	}
+	if serverID != "" {
+		if change.Desired.State == "running" {
+			return ctx.API.PostServerAction(serverID, "poweron")
+		}
+		return nil
+	}
+
+	for _, diff := range change.Diffs {
+		switch diff.Field {
+		case "tags":
+			tags := manifestTags(manifest, change.Desired)
+			if err := ctx.API.PatchServer(change.Current.Identifier, api.ScalewayServerPatchDefinition{Tags: &tags}); err != nil {
+				return err
+			}
+		case "ipv6":
+			enable := change.Desired.IPV6
+			if err := ctx.API.PatchServer(change.Current.Identifier, api.ScalewayServerPatchDefinition{EnableIPV6: &enable}); err != nil {
+				return err
+			}
+		case "state":
+			action := "poweroff"
+			if diff.New == "running" {
+				action = "poweron"
+			}
+			if err := ctx.API.PostServerAction(change.Current.Identifier, action); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// confirmApply asks to type "yes" before applying a plan
+func confirmApply(ctx CommandContext) bool {
+	fmt.Fprint(ctx.Stdout, "\nDo you want to apply these changes? Only 'yes' will be accepted: ")
+	answer, _ := bufio.NewReader(ctx.Stdin).ReadString('\n')
+	return strings.TrimSpace(answer) == "yes"
+}
+
+// RunApply is the handler for 'scw _apply'
+func RunApply(ctx CommandContext, args ApplyArgs) error {
+	if args.Import {
+		if args.Tag == "" {
+			return fmt.Errorf("--import needs the --tag of the servers to import")
+		}
+		servers, err := ctx.API.GetServers(true, 0)
+		if err != nil {
+			return api.Wrapf(err, "unable to fetch servers from the Scaleway API")
+		}
+		manifest := ImportManifest(*servers, args.Tag)
+		if len(manifest.Servers) == 0 {
+			logrus.Warnf("No server carries the tag %q", args.Tag)
+		}
+		content, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(ctx.Stdout, string(content))
+		return nil
+	}
+
+	manifest, err := LoadManifest(args.Manifest, ctx.Stdin)
+	if err != nil {
+		return err
+	}
+	if manifest.Lock == "" {
+		if args.ForceUnlock {
+			return fmt.Errorf("the manifest has no \"lock\" server")
+		}
+		logrus.Warnf("The manifest has no \"lock\" server, nothing prevents concurrent runs")
+	} else {
+		lockServer, err := ctx.API.GetServerID(manifest.Lock)
+		if err != nil {
+			return err
+		}
+		if args.ForceUnlock {
+			return forceApplyUnlock(ctx, lockServer)
+		}
+		release, err := acquireApplyLock(ctx, lockServer)
+		if err != nil {
+			return err
+		}
+		defer release()
+	}
+
+	servers, err := ctx.API.GetServers(true, 0)
+	if err != nil {
+		return api.Wrapf(err, "unable to fetch servers from the Scaleway API")
+	}
+	changes, err := planApply(manifest, *servers, func(name, arch string) (string, error) {
+		image, err := ctx.API.GetImageID(name, arch)
+		if err != nil {
+			return "", err
+		}
+		return image.Identifier, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	file, ok := ctx.Stdout.(*os.File)
+	printPlan(ctx.Stdout, changes, ok && isatty.IsTerminal(file.Fd()))
+	if args.Plan || len(changes) == 0 {
+		return nil
+	}
+	if !args.AutoApprove {
+		if args.Manifest == "-" {
+			return fmt.Errorf("cannot ask for confirmation when the manifest is read from stdin, use --auto-approve")
+		}
+		if !confirmApply(ctx) {
+			return fmt.Errorf("apply cancelled")
+		}
+	}
+
+	targets := make([]string, len(changes))
+	for i, change := range changes {
+		targets[i] = change.Action + " " + change.Name
+	}
+	result := NewBulkResult(ctx, "apply manifest", targets)
+	for i, change := range changes {
+		done := result.Start(targets[i])
+		done(executeChange(ctx, manifest, change))
+	}
+	if err = result.Report(); err != nil {
+		return err
+	}
+	if result.Failed() > 0 {
+		return fmt.Errorf("at least 1 change failed to be applied")
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/sirupsen/logrus"
+)
+
+// applyLockKey is the user_data key holding the lock of `scw _apply` on the lock server of a manifest
+const applyLockKey = "scw-apply-lock"
+
+// applyLockSettle is the time given to a concurrent run to overwrite the lock before checking it is still ours
+var applyLockSettle = 2 * time.Second
+
+// ApplyLock is the JSON value of the lock preventing concurrent `scw _apply` runs of a manifest
+type ApplyLock struct {
+	ID    string    `json:"id"`
+	Owner string    `json:"owner"`
+	Since time.Time `json:"since"`
+}
+
+func (l *ApplyLock) String() string {
+	return fmt.Sprintf("locked by %s since %s", l.Owner, l.Since.Format(time.RFC3339))
+}
+
+// readApplyLock returns the lock held on serverID, nil when the manifest is not locked
+func readApplyLock(ctx CommandContext, serverID string) (*ApplyLock, error) {
+	keys, err := ctx.API.GetUserdatas(serverID, false)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys.UserData {
+		if key != applyLockKey {
+			continue
+		}
+		value, err := ctx.API.GetUserdata(serverID, applyLockKey, false)
+		if err != nil {
+			return nil, err
+		}
+		var lock ApplyLock
+		if err = json.Unmarshal(*value, &lock); err != nil {
+			return nil, fmt.Errorf("invalid lock %q: %v", string(*value), err)
+		}
+		return &lock, nil
+	}
+	return nil, nil
+}
+
+// acquireApplyLock stores a lock in the user_data of the lock server, the returned function releases it
+func acquireApplyLock(ctx CommandContext, serverID string) (func(), error) {
+	current, err := readApplyLock(ctx, serverID)
+	if err != nil {
+		return nil, api.Wrapf(err, "cannot read the lock")
+	}
+	if current != nil {
+		return nil, fmt.Errorf("manifest %s, use --force-unlock if that run was interrupted", current)
+	}
+
+	random := make([]byte, 8)
+	if _, err = rand.Read(random); err != nil {
+		return nil, err
+	}
+	owner := os.Getenv("USER")
+	if owner == "" {
+		owner = "unknown"
+	}
+	hostname, _ := os.Hostname()
+	lock := ApplyLock{ID: hex.EncodeToString(random), Owner: owner + "@" + hostname, Since: time.Now().UTC()}
+	value, err := json.Marshal(lock)
+	if err != nil {
+		return nil, err
+	}
+	if err = ctx.API.PatchUserdata(serverID, applyLockKey, value, false); err != nil {
+		return nil, api.Wrapf(err, "cannot write the lock")
+	}
+
+	// user_data has no compare-and-swap, the last writer wins a race
+	time.Sleep(applyLockSettle)
+	if current, err = readApplyLock(ctx, serverID); err != nil {
+		return nil, api.Wrapf(err, "cannot read the lock")
+	}
+	if current == nil || current.ID != lock.ID {
+		if current == nil {
+			return nil, fmt.Errorf("the lock was removed while acquiring it")
+		}
+		return nil, fmt.Errorf("manifest %s", current)
+	}
+	logrus.Debugf("Lock %s acquired", lock.ID)
+
+	return func() {
+		if err := ctx.API.DeleteUserdata(serverID, applyLockKey, false); err != nil {
+			logrus.Errorf("Cannot release the lock, run 'scw _apply --force-unlock': %v", err)
+		}
+	}, nil
+}
+
+// forceApplyUnlock removes the lock left by an interrupted run
+func forceApplyUnlock(ctx CommandContext, serverID string) error {
+	current, err := readApplyLock(ctx, serverID)
+	if err != nil {
+		return api.Wrapf(err, "cannot read the lock")
+	}
+	if current == nil {
+		logrus.Warnf("The manifest is not locked")
+		return nil
+	}
+	if err = ctx.API.DeleteUserdata(serverID, applyLockKey, false); err != nil {
+		return err
+	}
+	fmt.Fprintf(ctx.Stdout, "Removed the lock of %s\n", current.Owner)
+	return nil
+}
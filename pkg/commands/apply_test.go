@@ -0,0 +1,120 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPlanApply(t *testing.T) {
+	Convey("Testing planApply", t, func() {
+		manifest := &Manifest{Tag: "stack=web", Servers: []ManifestServer{
+			{Name: "web-1", Image: "ubuntu-xenial", CommercialType: "VC1S", State: "running"},
+			{Name: "web-2", Image: "ubuntu-xenial", CommercialType: "VC1M", State: "running", Tags: []string{"prod"}},
+			{Name: "web-3", Image: "ubuntu-xenial", CommercialType: "VC1S", State: "running"},
+		}}
+		web1 := api.ScalewayServer{Identifier: "1", Name: "web-1", State: "running", CommercialType: "VC1S", Tags: []string{"stack=web"}}
+		web1.Image.Name = "Ubuntu Xenial"
+		web1.Image.Identifier = "xenial-id"
+		web2 := web1
+		web2.Identifier, web2.Name, web2.State = "2", "web-2", "stopped"
+		old := web1
+		old.Identifier, old.Name = "4", "old"
+		other := web1
+		other.Identifier, other.Name, other.Tags = "5", "other", nil
+
+		changes, err := planApply(manifest, []api.ScalewayServer{web1, web2, old, other}, func(name, arch string) (string, error) {
+			return "xenial-id", nil
+		})
+		So(err, ShouldBeNil)
+		So(len(changes), ShouldEqual, 3)
+		So(changes[0].Action, ShouldEqual, applyReplace)
+		So(changes[0].Name, ShouldEqual, "web-2")
+		So(changes[0].Diffs, ShouldResemble, []applyDiff{
+			{Field: "commercial_type", Old: "VC1S", New: "VC1M"},
+			{Field: "state", Old: "stopped", New: "running"},
+			{Field: "tags", Old: "stack=web", New: "prod stack=web"},
+		})
+		So(changes[1].Action, ShouldEqual, applyCreate)
+		So(changes[1].Name, ShouldEqual, "web-3")
+		So(changes[2].Action, ShouldEqual, applyDelete)
+		So(changes[2].Name, ShouldEqual, "old")
+
+		var output bytes.Buffer
+		printPlan(&output, changes, false)
+		So(output.String(), ShouldContainSubstring, "Plan: 2 to create, 0 to modify, 2 to delete.")
+
+		manifest.Servers[0].IPV6 = true
+		changes, err = planApply(manifest, []api.ScalewayServer{web1}, func(name, arch string) (string, error) {
+			return "xenial-id", nil
+		})
+		So(err, ShouldBeNil)
+		So(changes[0].Action, ShouldEqual, applyUpdate)
+		So(changes[0].Diffs, ShouldResemble, []applyDiff{{Field: "ipv6", Old: "false", New: "true"}})
+	})
+}
+
+func TestExecuteChangeReplace(t *testing.T) {
+	Convey("Testing executeChange() replacing a server", t, func() {
+		fake := api.NewFakeScalewayAPI("orga")
+		fake.Products = api.ScalewayProductsServers{Servers: map[string]api.ProductServer{
+			"VC1M": {
+				Arch:                 "x86_64",
+				VolumesConstraint:    api.ProductVolumeConstraint{MinSize: 50 * api.Giga, MaxSize: 200 * api.Giga},
+				PerVolumesConstraint: api.ProductPerVolumeConstraint{LSsdConstraint: api.ProductVolumeConstraint{MaxSize: 50 * api.Giga}},
+			},
+		}}
+		fake.Images = []api.ScalewayImage{{
+			Identifier: "11111111-1111-1111-1111-111111111111",
+			Name:       "ubuntu-xenial",
+			Arch:       "x86_64",
+			RootVolume: api.ScalewayVolume{Size: 50 * api.Giga},
+		}}
+		fake.Servers = []api.ScalewayServer{{Identifier: "22222222-2222-2222-2222-222222222222", Name: "web", State: "running", CommercialType: "VC1S", Tags: []string{"stack=web"}}}
+		ctx := CommandContext{
+			Streams: Streams{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}},
+			API:     fake,
+		}
+		manifest := &Manifest{Tag: "stack=web", Servers: []ManifestServer{
+			{Name: "web", Image: "ubuntu-xenial", CommercialType: "VC1M", State: "running"},
+		}}
+		current := fake.Servers[0]
+		change := applyChange{Action: applyReplace, Name: "web", Current: &current, Desired: &manifest.Servers[0]}
+
+		Convey("replacement created", func() {
+			So(executeChange(ctx, manifest, change), ShouldBeNil)
+			So(len(fake.Servers), ShouldEqual, 1)
+			So(fake.Servers[0].Identifier, ShouldNotEqual, current.Identifier)
+			So(fake.Servers[0].Name, ShouldEqual, "web")
+			So(fake.Servers[0].CommercialType, ShouldEqual, "VC1M")
+			So(fake.Servers[0].State, ShouldEqual, "running")
+		})
+
+		Convey("replacement failing", func() {
+			manifest.Servers[0].Image = "unknown-image"
+			So(executeChange(ctx, manifest, change), ShouldNotBeNil)
+			So(len(fake.Servers), ShouldEqual, 1)
+			So(fake.Servers[0].Identifier, ShouldEqual, current.Identifier)
+		})
+	})
+}
+
+func TestImportManifest(t *testing.T) {
+	Convey("Testing ImportManifest", t, func() {
+		web := api.ScalewayServer{Name: "web", State: "starting", CommercialType: "VC1S", Tags: []string{"prod", "stack=web"}}
+		web.Image.Name = "Ubuntu Xenial"
+		db := api.ScalewayServer{Name: "db", State: "running", CommercialType: "VC1M"}
+
+		manifest := ImportManifest([]api.ScalewayServer{web, db}, "stack=web")
+		So(manifest.Tag, ShouldEqual, "stack=web")
+		So(manifest.Servers, ShouldResemble, []ManifestServer{
+			{Name: "web", Image: "Ubuntu Xenial", CommercialType: "VC1S", Tags: []string{"prod"}, State: "running"},
+		})
+	})
+}
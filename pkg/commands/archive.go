@@ -0,0 +1,391 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/scaleway/scaleway-cli/pkg/config"
+	"github.com/scaleway/scaleway-cli/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// ArchiveManifest holds everything needed to recreate an archived server
+type ArchiveManifest struct {
+	ServerID       string            `json:"server_id"`
+	Name           string            `json:"name"`
+	CommercialType string            `json:"commercial_type"`
+	Arch           string            `json:"arch"`
+	BootType       string            `json:"boot_type"`
+	Bootscript     string            `json:"bootscript,omitempty"`
+	Tags           []string          `json:"tags,omitempty"`
+	EnableIPV6     bool              `json:"enable_ipv6,omitempty"`
+	IPID           string            `json:"ip_id,omitempty"`
+	ImageID        string            `json:"image_id"`
+	Snapshots      map[string]string `json:"snapshots"`
+	ArchiveDate    string            `json:"archive_date"`
+}
+
+// ArchiveArgs are flags for the `RunArchive` function
+type ArchiveArgs struct {
+	Servers []string
+}
+
+// RestoreArgs are flags for the `RunRestore` function
+type RestoreArgs struct {
+	// Archives are the identifiers of the archived servers, or their names
+	Archives []string
+	Start    bool
+}
+
+// getArchivesPath returns the path of the local archives database
+func getArchivesPath() (string, error) {
+	homeDir, err := config.GetHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".scw-archives.json"), nil
+}
+
+func loadArchives() (map[string]ArchiveManifest, error) {
+	archives := make(map[string]ArchiveManifest)
+
+	path, err := getArchivesPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return archives, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &archives); err != nil {
+		return nil, api.Wrapf(err, "unable to parse %s", path)
+	}
+	return archives, nil
+}
+
+func saveArchives(archives map[string]ArchiveManifest) error {
+	path, err := getArchivesPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(archives, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+// RunArchive is the handler for 'scw _archive'
+func RunArchive(ctx CommandContext, args ArchiveArgs) error {
+	archives, err := loadArchives()
+	if err != nil {
+		return err
+	}
+	hasError := false
+	for _, needle := range args.Servers {
+		manifest, err := archiveServer(ctx, needle)
+		if err != nil {
+			logrus.Errorf("failed to archive server %s: %v", needle, err)
+			hasError = true
+			continue
+		}
+		// servers may share a name, the archives are kept by server identifier
+		archives[manifest.ServerID] = *manifest
+		if err = saveArchives(archives); err != nil {
+			return api.Wrapf(err, "unable to save archive of %s", manifest.Name)
+		}
+		fmt.Fprintln(ctx.Stdout, manifest.Name)
+	}
+	if hasError {
+		return fmt.Errorf("at least 1 server failed to be archived")
+	}
+	return nil
+}
+
+func archiveServer(ctx CommandContext, needle string) (_ *ArchiveManifest, err error) {
+	serverID, err := ctx.API.GetServerID(needle)
+	if err != nil {
+		return nil, err
+	}
+	server, err := ctx.API.GetServer(serverID)
+	if err != nil {
+		return nil, err
+	}
+
+	if server.State != "stopped" {
+		logrus.Infof("Stopping server %s ...", server.Name)
+		if err = ctx.API.PostServerAction(serverID, "poweroff"); err != nil {
+			return nil, err
+		}
+		if server, err = api.WaitForServerStopped(ctx.API, serverID); err != nil {
+			return nil, err
+		}
+	}
+
+	manifest := ArchiveManifest{
+		ServerID:       server.Identifier,
+		Name:           server.Name,
+		CommercialType: server.CommercialType,
+		Arch:           server.Arch,
+		BootType:       server.BootType,
+		Tags:           server.Tags,
+		EnableIPV6:     server.EnableIPV6,
+		Snapshots:      make(map[string]string),
+		ArchiveDate:    time.Now().UTC().Format(time.RFC3339),
+	}
+	// the server is kept when the archive fails, the snapshots and the image already created are deleted
+	defer func() {
+		if err != nil {
+			deleteArchiveImages(ctx, manifest)
+		}
+	}()
+	if server.Bootscript != nil {
+		manifest.Bootscript = server.Bootscript.Identifier
+	}
+	if server.PublicAddress.Dynamic != nil && !*server.PublicAddress.Dynamic {
+		manifest.IPID = server.PublicAddress.Identifier
+	}
+
+	for index, volume := range server.Volumes {
+		logrus.Infof("Creating snapshot of volume %s ...", volume.Name)
+		snapshotID, err := ctx.API.PostSnapshot(volume.Identifier, fmt.Sprintf("%s-archive-%s", server.Name, index))
+		if err != nil {
+			return nil, api.Wrapf(err, "cannot create snapshot of volume %s", volume.Name)
+		}
+		if _, err = api.WaitForSnapshotState(ctx.API, snapshotID, "snapshotted", api.SnapshotTimeout); err != nil {
+			return nil, err
+		}
+		manifest.Snapshots[index] = snapshotID
+	}
+	rootSnapshot, ok := manifest.Snapshots["0"]
+	if !ok {
+		return nil, fmt.Errorf("server %s has no root volume", server.Name)
+	}
+	manifest.ImageID, err = ctx.API.PostImage(rootSnapshot, server.Name+"-archive", manifest.Bootscript, server.Arch)
+	if err != nil {
+		return nil, api.Wrapf(err, "cannot create image")
+	}
+
+	// the server is stopped, so deleting it keeps snapshots, images and reserved IP
+	logrus.Infof("Deleting server %s ...", server.Name)
+	if err = ctx.API.DeleteServer(serverID); err != nil {
+		return nil, err
+	}
+	for _, volume := range server.Volumes {
+		if err = ctx.API.DeleteVolume(volume.Identifier); err != nil {
+			logrus.Warnf("failed to delete volume %s: %v", volume.Identifier, err)
+		}
+	}
+	return &manifest, nil
+}
+
+// RunRestore is the handler for 'scw _restore'
+func RunRestore(ctx CommandContext, args RestoreArgs) error {
+	archives, err := loadArchives()
+	if err != nil {
+		return err
+	}
+	if len(args.Archives) == 0 {
+		return printArchives(ctx, archives)
+	}
+
+	hasError := false
+	for _, needle := range args.Archives {
+		key, err := findArchive(archives, needle)
+		if err != nil {
+			logrus.Errorf("%v", err)
+			hasError = true
+			continue
+		}
+		manifest := archives[key]
+		serverID, err := restoreServer(ctx, manifest, args.Start)
+		if err != nil {
+			logrus.Errorf("failed to restore server %s: %v", needle, err)
+			hasError = true
+			continue
+		}
+		delete(archives, key)
+		if err = saveArchives(archives); err != nil {
+			return err
+		}
+		// the restored volumes do not depend on the snapshots and the image anymore
+		deleteArchiveImages(ctx, manifest)
+		fmt.Fprintln(ctx.Stdout, serverID)
+	}
+	if hasError {
+		return fmt.Errorf("at least 1 server failed to be restored")
+	}
+	return nil
+}
+
+// findArchive returns the key of the archive of the server whose identifier starts with needle,
+// or of the only archived server named needle
+func findArchive(archives map[string]ArchiveManifest, needle string) (string, error) {
+	if _, ok := archives[needle]; ok {
+		return needle, nil
+	}
+	matches := []string{}
+	for key, manifest := range archives {
+		if manifest.Name == needle || strings.HasPrefix(key, needle) {
+			matches = append(matches, key)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no such archive: %s", needle)
+	case 1:
+		return matches[0], nil
+	}
+	return "", fmt.Errorf("%s matches %d archives, use the server identifier", needle, len(matches))
+}
+
+// deleteArchiveImages deletes the image and the snapshots of an archive, the ones which cannot be deleted are logged
+func deleteArchiveImages(ctx CommandContext, manifest ArchiveManifest) {
+	if manifest.ImageID != "" {
+		if err := ctx.API.DeleteImage(manifest.ImageID); err != nil {
+			logrus.Warnf("failed to delete image %s: %v", manifest.ImageID, err)
+		}
+	}
+	for _, snapshotID := range manifest.Snapshots {
+		if err := ctx.API.DeleteSnapshot(snapshotID); err != nil {
+			logrus.Warnf("failed to delete snapshot %s: %v", snapshotID, err)
+		}
+	}
+}
+
+func restoreServer(ctx CommandContext, manifest ArchiveManifest, start bool) (_ string, err error) {
+	serverID, err := api.CreateServer(ctx.API, &api.ConfigCreateServer{
+		ImageName:         manifest.ImageID,
+		Name:              manifest.Name,
+		Bootscript:        manifest.Bootscript,
+		Env:               strings.Join(manifest.Tags, " "),
+		IP:                manifest.IPID,
+		CommercialType:    manifest.CommercialType,
+		DynamicIPRequired: manifest.IPID == "",
+		EnableIPV6:        manifest.EnableIPV6,
+		BootType:          manifest.BootType,
+	})
+	if err != nil {
+		return "", err
+	}
+	// the archive is kept when the restore fails, the server and the volumes already created are deleted
+	var volumeIDs []string
+	defer func() {
+		if err != nil {
+			deleteRestoredServer(ctx, serverID, volumeIDs)
+		}
+	}()
+
+	if len(manifest.Snapshots) > 1 {
+		server, err := ctx.API.GetServer(serverID)
+		if err != nil {
+			return "", err
+		}
+		volumes := make(map[string]api.ScalewayVolume)
+		for index, volume := range server.Volumes {
+			volumes[index] = api.ScalewayVolume{Identifier: volume.Identifier}
+		}
+		for index, snapshotID := range manifest.Snapshots {
+			if index == "0" {
+				continue
+			}
+			snapshot, err := ctx.API.GetSnapshot(snapshotID)
+			if err != nil {
+				return "", err
+			}
+			volumeID, err := ctx.API.PostVolume(api.ScalewayVolumeDefinition{
+				Name:         fmt.Sprintf("%s-%s", manifest.Name, index),
+				Size:         snapshot.Size,
+				Type:         snapshot.VolumeType,
+				BaseSnapshot: snapshotID,
+			})
+			if err != nil {
+				return "", api.Wrapf(err, "cannot create volume from snapshot %s", snapshotID)
+			}
+			volumeIDs = append(volumeIDs, volumeID)
+			volumes[index] = api.ScalewayVolume{Identifier: volumeID}
+		}
+		if err = ctx.API.PatchServer(serverID, api.ScalewayServerPatchDefinition{Volumes: &volumes}); err != nil {
+			return "", api.Wrapf(err, "cannot attach restored volumes")
+		}
+		// the volumes created with the server are detached, not deleted, when they are replaced
+		for index, volume := range server.Volumes {
+			if volumes[index].Identifier == volume.Identifier {
+				continue
+			}
+			if err = ctx.API.DeleteVolume(volume.Identifier); err != nil {
+				logrus.Warnf("failed to delete volume %s: %v", volume.Identifier, err)
+			}
+		}
+	}
+
+	if start {
+		if err = api.StartServer(ctx.API, serverID, false); err != nil {
+			return "", err
+		}
+	}
+	return serverID, nil
+}
+
+// deleteRestoredServer deletes a server whose restore failed, with its volumes and the volumes created for it
+func deleteRestoredServer(ctx CommandContext, serverID string, volumeIDs []string) {
+	if server, err := ctx.API.GetServer(serverID); err == nil {
+		for _, volume := range server.Volumes {
+			volumeIDs = append(volumeIDs, volume.Identifier)
+		}
+	}
+	if err := ctx.API.DeleteServerForce(serverID); err != nil {
+		logrus.Warnf("failed to delete server %s: %v", serverID, err)
+		return
+	}
+	deleted := make(map[string]bool)
+	for _, volumeID := range volumeIDs {
+		if deleted[volumeID] {
+			continue
+		}
+		deleted[volumeID] = true
+		if err := ctx.API.DeleteVolume(volumeID); err != nil && !api.IsNotFound(err) {
+			logrus.Warnf("failed to delete volume %s: %v", volumeID, err)
+		}
+	}
+}
+
+func printArchives(ctx CommandContext, archives map[string]ArchiveManifest) error {
+	keys := make([]string, 0, len(archives))
+	for key := range archives {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if archives[keys[i]].Name != archives[keys[j]].Name {
+			return archives[keys[i]].Name < archives[keys[j]].Name
+		}
+		return keys[i] < keys[j]
+	})
+
+	w := tabwriter.NewWriter(ctx.Stdout, 20, 1, 3, ' ', 0)
+	defer w.Flush()
+	fmt.Fprintf(w, "SERVER ID\tNAME\tCOMMERCIAL TYPE\tVOLUMES\tRESERVED IP\tARCHIVED\n")
+	for _, key := range keys {
+		manifest := archives[key]
+		ip := "-"
+		if manifest.IPID != "" {
+			ip = utils.TruncIf(manifest.IPID, 8, true)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n", utils.TruncIf(key, 8, true), manifest.Name, manifest.CommercialType, len(manifest.Snapshots), ip, manifest.ArchiveDate)
+	}
+	return nil
+}
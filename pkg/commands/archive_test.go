@@ -0,0 +1,125 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRunRestore(t *testing.T) {
+	Convey("Testing RunRestore() against a FakeScalewayAPI", t, func() {
+		home, err := ioutil.TempDir("", "scw-archives")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(home)
+		defer os.Setenv("HOME", os.Getenv("HOME"))
+		os.Setenv("HOME", home)
+
+		fake := api.NewFakeScalewayAPI("orga")
+		fake.Products = api.ScalewayProductsServers{Servers: map[string]api.ProductServer{
+			"VC1M": {
+				Arch:                 "x86_64",
+				VolumesConstraint:    api.ProductVolumeConstraint{MinSize: 100 * api.Giga, MaxSize: 200 * api.Giga},
+				PerVolumesConstraint: api.ProductPerVolumeConstraint{LSsdConstraint: api.ProductVolumeConstraint{MaxSize: 50 * api.Giga}},
+			},
+		}}
+		fake.Images = []api.ScalewayImage{{
+			Identifier: "11111111-1111-1111-1111-111111111111",
+			Name:       "web-archive",
+			Arch:       "x86_64",
+			RootVolume: api.ScalewayVolume{Size: 50 * api.Giga},
+		}}
+		fake.Snapshots = []api.ScalewaySnapshot{
+			{Identifier: "22222222-2222-2222-2222-222222222222", Name: "web-archive-0", Size: 50 * api.Giga, VolumeType: "l_ssd"},
+			{Identifier: "33333333-3333-3333-3333-333333333333", Name: "web-archive-1", Size: 50 * api.Giga, VolumeType: "l_ssd"},
+		}
+		// two archived servers were named web
+		So(saveArchives(map[string]ArchiveManifest{
+			"44444444-4444-4444-4444-444444444444": {
+				ServerID:       "44444444-4444-4444-4444-444444444444",
+				Name:           "web",
+				CommercialType: "VC1M",
+				BootType:       "local",
+				ImageID:        "11111111-1111-1111-1111-111111111111",
+				Snapshots: map[string]string{
+					"0": "22222222-2222-2222-2222-222222222222",
+					"1": "33333333-3333-3333-3333-333333333333",
+				},
+			},
+			"55555555-5555-5555-5555-555555555555": {
+				ServerID: "55555555-5555-5555-5555-555555555555",
+				Name:     "web",
+			},
+		}), ShouldBeNil)
+		ctx := CommandContext{
+			Streams: Streams{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}},
+			API:     fake,
+		}
+
+		err = RunRestore(ctx, RestoreArgs{Archives: []string{"web"}})
+		So(err, ShouldNotBeNil)
+		So(len(fake.Servers), ShouldEqual, 0)
+
+		err = RunRestore(ctx, RestoreArgs{Archives: []string{"4444"}})
+		So(err, ShouldBeNil)
+		So(len(fake.Servers), ShouldEqual, 1)
+		server := fake.Servers[0]
+		So(server.Name, ShouldEqual, "web")
+		So(len(server.Volumes), ShouldEqual, 2)
+		So(server.Volumes["1"].Name, ShouldEqual, "web-1")
+
+		// the volume 1 created with the server was replaced by the restored one and deleted
+		So(len(fake.Volumes), ShouldEqual, 2)
+		for _, volume := range fake.Volumes {
+			So(volume.Identifier, ShouldBeIn, server.Volumes["0"].Identifier, server.Volumes["1"].Identifier)
+		}
+
+		// the image and the snapshots of the restored archive are deleted
+		So(len(fake.Images), ShouldEqual, 0)
+		So(len(fake.Snapshots), ShouldEqual, 0)
+
+		archives, err := loadArchives()
+		So(err, ShouldBeNil)
+		So(len(archives), ShouldEqual, 1)
+		_, ok := archives["55555555-5555-5555-5555-555555555555"]
+		So(ok, ShouldBeTrue)
+
+		// a restore failing once the server is created deletes it with its volumes, and keeps the archive
+		So(saveArchives(map[string]ArchiveManifest{
+			"66666666-6666-6666-6666-666666666666": {
+				ServerID:       "66666666-6666-6666-6666-666666666666",
+				Name:           "db",
+				CommercialType: "VC1M",
+				BootType:       "local",
+				ImageID:        "11111111-1111-1111-1111-111111111111",
+				Snapshots: map[string]string{
+					"0": "22222222-2222-2222-2222-222222222222",
+					"1": "77777777-7777-7777-7777-777777777777",
+				},
+			},
+		}), ShouldBeNil)
+		fake.Images = []api.ScalewayImage{{
+			Identifier: "11111111-1111-1111-1111-111111111111",
+			Name:       "db-archive",
+			Arch:       "x86_64",
+			RootVolume: api.ScalewayVolume{Size: 50 * api.Giga},
+		}}
+		volumes := len(fake.Volumes)
+		err = RunRestore(ctx, RestoreArgs{Archives: []string{"db"}})
+		So(err, ShouldNotBeNil)
+		So(len(fake.Servers), ShouldEqual, 1)
+		So(len(fake.Volumes), ShouldEqual, volumes)
+		So(len(fake.Images), ShouldEqual, 1)
+		archives, err = loadArchives()
+		So(err, ShouldBeNil)
+		_, ok = archives["66666666-6666-6666-6666-666666666666"]
+		So(ok, ShouldBeTrue)
+	})
+}
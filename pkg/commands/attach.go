@@ -8,8 +8,9 @@ import "github.com/scaleway/scaleway-cli/pkg/utils"
 
 // AttachArgs are flags for the `RunAttach` function
 type AttachArgs struct {
-	NoStdin bool
-	Server  string
+	NoStdin    bool
+	DetachKeys string
+	Server     string
 }
 
 // RunAttach is the handler for 'scw attach'
@@ -18,7 +19,10 @@ func RunAttach(ctx CommandContext, args AttachArgs) error {
 	if err != nil {
 		return err
 	}
-	_, done, err := utils.AttachToSerial(serverID, ctx.API.Token, ctx.API.ResolveTTYUrl())
+	_, done, err := utils.AttachToSerial(serverID, ctx.API.AuthToken(), ctx.API.ResolveTTYUrl(), utils.SerialOptions{
+		DetachKeys: args.DetachKeys,
+		NoStdin:    args.NoStdin,
+	})
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,127 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/scaleway/scaleway-cli/pkg/utils"
+)
+
+// BootscriptListArgs are flags for the `RunBootscriptList` function
+type BootscriptListArgs struct {
+	Arch    string
+	All     bool
+	Quiet   bool
+	NoTrunc bool
+}
+
+// BootscriptInspectArgs are flags for the `RunBootscriptInspect` function
+type BootscriptInspectArgs struct {
+	Arch        string
+	Bootscripts []string
+}
+
+var kernelVersionRegexp = regexp.MustCompile(`\d+\.\d+(\.\d+)?[\w.+-]*`)
+
+// kernelVersion extracts the kernel version from the kernel URL of a bootscript,
+// falling back on its title
+func kernelVersion(bootscript api.ScalewayBootscript) string {
+	for _, source := range []string{path.Base(bootscript.Kernel), bootscript.Title} {
+		if version := kernelVersionRegexp.FindString(source); version != "" {
+			return version
+		}
+	}
+	return "-"
+}
+
+// filterBootscripts keeps the bootscripts of arch (any if empty), deprecated ones
+// are dropped unless all is set. Defaults come first, then titles in order
+func filterBootscripts(bootscripts []api.ScalewayBootscript, arch string, all bool) []api.ScalewayBootscript {
+	filtered := []api.ScalewayBootscript{}
+	for _, bootscript := range bootscripts {
+		if arch != "" && bootscript.Arch != arch {
+			continue
+		}
+		if bootscript.Deprecated && !all {
+			continue
+		}
+		filtered = append(filtered, bootscript)
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		if filtered[i].Default != filtered[j].Default {
+			return filtered[i].Default
+		}
+		return filtered[i].Title < filtered[j].Title
+	})
+	return filtered
+}
+
+// RunBootscriptList is the handler for 'scw bootscript ls'
+func RunBootscriptList(ctx CommandContext, args BootscriptListArgs) error {
+	bootscripts, err := ctx.API.GetBootscripts()
+	if err != nil {
+		return api.Wrapf(err, "unable to fetch bootscripts from the Scaleway API")
+	}
+
+	w := tabwriter.NewWriter(ctx.Stdout, 20, 1, 3, ' ', 0)
+	defer w.Flush()
+	if !args.Quiet {
+		fmt.Fprintf(w, "BOOTSCRIPT ID\tTITLE\tARCH\tKERNEL\tDEFAULT\n")
+	}
+	for _, bootscript := range filterBootscripts(*bootscripts, args.Arch, args.All) {
+		if args.Quiet {
+			fmt.Fprintln(w, bootscript.Identifier)
+			continue
+		}
+		flags := []string{}
+		if bootscript.Default {
+			flags = append(flags, "default")
+		}
+		if bootscript.Deprecated {
+			flags = append(flags, "deprecated")
+		}
+		mark := "-"
+		if len(flags) > 0 {
+			mark = strings.Join(flags, ",")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			utils.TruncIf(bootscript.Identifier, 8, !args.NoTrunc),
+			utils.TruncIf(bootscript.Title, 40, !args.NoTrunc),
+			bootscript.Arch,
+			kernelVersion(bootscript),
+			mark,
+		)
+	}
+	return nil
+}
+
+// RunBootscriptInspect is the handler for 'scw bootscript inspect'
+func RunBootscriptInspect(ctx CommandContext, args BootscriptInspectArgs) error {
+	bootscripts := []api.ScalewayBootscript{}
+	for _, needle := range args.Bootscripts {
+		bootscriptID, err := ctx.API.GetBootscriptID(needle, args.Arch)
+		if err != nil {
+			return err
+		}
+		bootscript, err := ctx.API.GetBootscript(bootscriptID)
+		if err != nil {
+			return api.Wrapf(err, "cannot fetch bootscript %s", needle)
+		}
+		bootscripts = append(bootscripts, *bootscript)
+	}
+	res, err := marshalInspected(bootscripts, ctx.TimeFormat)
+	if err != nil {
+		return api.Wrapf(err, "cannot marshal bootscripts")
+	}
+	fmt.Fprintln(ctx.Stdout, string(res))
+	return nil
+}
@@ -0,0 +1,41 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestKernelVersion(t *testing.T) {
+	Convey("Testing kernelVersion()", t, func() {
+		So(kernelVersion(api.ScalewayBootscript{Kernel: "http://169.254.42.24/kernel/x86_64-mainline-lts-4.4-4.4.127-rev1/vmlinuz-4.4.127"}), ShouldEqual, "4.4.127")
+		So(kernelVersion(api.ScalewayBootscript{Title: "armv7l mainline 4.9.93 rev1"}), ShouldEqual, "4.9.93")
+		So(kernelVersion(api.ScalewayBootscript{Title: "rescue"}), ShouldEqual, "-")
+	})
+}
+
+func TestFilterBootscripts(t *testing.T) {
+	Convey("Testing filterBootscripts()", t, func() {
+		bootscripts := []api.ScalewayBootscript{
+			{Identifier: "1", Title: "b", Arch: "x86_64"},
+			{Identifier: "2", Title: "c", Arch: "x86_64", Default: true},
+			{Identifier: "3", Title: "a", Arch: "arm"},
+			{Identifier: "4", Title: "a", Arch: "x86_64", Deprecated: true},
+		}
+		ids := func(bootscripts []api.ScalewayBootscript) []string {
+			res := []string{}
+			for _, bootscript := range bootscripts {
+				res = append(res, bootscript.Identifier)
+			}
+			return res
+		}
+		So(ids(filterBootscripts(bootscripts, "", false)), ShouldResemble, []string{"2", "3", "1"})
+		So(ids(filterBootscripts(bootscripts, "x86_64", false)), ShouldResemble, []string{"2", "1"})
+		So(ids(filterBootscripts(bootscripts, "x86_64", true)), ShouldResemble, []string{"2", "4", "1"})
+	})
+}
@@ -0,0 +1,192 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/scaleway/scaleway-cli/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultRecipeFile is the recipe read from the build context when --file is not given
+const DefaultRecipeFile = "Scwfile"
+
+// BuildArgs are arguments passed to `RunBuild`
+type BuildArgs struct {
+	File           string
+	Context        string
+	Tag            string
+	CommercialType string
+	Gateway        string
+	SSHUser        string
+	SSHPort        int
+	Manifest       string
+}
+
+// Recipe is an image build recipe: the base image, the commands run on the builder and the name of the resulting image
+type Recipe struct {
+	From string
+	Run  []string
+	Tag  string
+}
+
+// ParseRecipe reads a recipe made of FROM, RUN and TAG instructions, lines starting with # are comments
+// and a line ending with a backslash continues on the next one
+func ParseRecipe(r io.Reader) (*Recipe, error) {
+	recipe := &Recipe{}
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		start := lineNumber
+		line := strings.TrimSpace(scanner.Text())
+		for strings.HasSuffix(line, "\\") && scanner.Scan() {
+			lineNumber++
+			line = strings.TrimSpace(strings.TrimSuffix(line, "\\")) + " " + strings.TrimSpace(scanner.Text())
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, " ", 2)
+		instruction := strings.ToUpper(parts[0])
+		value := ""
+		if len(parts) == 2 {
+			value = strings.TrimSpace(parts[1])
+		}
+		if value == "" {
+			return nil, fmt.Errorf("line %d: %s expects an argument", start, instruction)
+		}
+		switch instruction {
+		case "FROM":
+			if recipe.From != "" {
+				return nil, fmt.Errorf("line %d: only one FROM is allowed", start)
+			}
+			recipe.From = value
+		case "RUN":
+			if recipe.From == "" {
+				return nil, fmt.Errorf("line %d: RUN before FROM", start)
+			}
+			recipe.Run = append(recipe.Run, value)
+		case "TAG":
+			recipe.Tag = value
+		default:
+			return nil, fmt.Errorf("line %d: unknown instruction %q, expected FROM, RUN or TAG", start, parts[0])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if recipe.From == "" {
+		return nil, fmt.Errorf("missing FROM instruction")
+	}
+	return recipe, nil
+}
+
+// LoadRecipe parses the recipe file, relative to the build context when file is empty
+func LoadRecipe(file, context string) (*Recipe, error) {
+	if file == "" {
+		file = filepath.Join(context, DefaultRecipeFile)
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, api.Wrapf(err, "cannot read recipe")
+	}
+	defer f.Close()
+	recipe, err := ParseRecipe(f)
+	if err != nil {
+		return nil, api.Wrapf(err, "%s", file)
+	}
+	return recipe, nil
+}
+
+// RunBuild is the handler for 'scw _build'
+func RunBuild(ctx CommandContext, args BuildArgs) error {
+	recipe, err := LoadRecipe(args.File, args.Context)
+	if err != nil {
+		return err
+	}
+	if args.Tag != "" {
+		recipe.Tag = args.Tag
+	}
+	if recipe.Tag == "" {
+		return fmt.Errorf("missing image name, add a TAG instruction or use --tag")
+	}
+
+	commercialType, err := resolveCommercialType(ctx, recipe.From, args.CommercialType)
+	if err != nil {
+		return err
+	}
+	gateway, err := api.ResolveGateway(ctx.API, args.Gateway)
+	if err != nil {
+		return api.Wrapf(err, "cannot resolve Gateway '%s'", args.Gateway)
+	}
+
+	steps := len(recipe.Run) + 2
+	logrus.Infof("Step 1/%d : FROM %s", steps, recipe.From)
+	serverID, err := api.CreateServer(ctx.API, &api.ConfigCreateServer{
+		ImageName:         recipe.From,
+		Name:              "scw-build-" + utils.Wordify(recipe.Tag),
+		CommercialType:    commercialType,
+		DynamicIPRequired: gateway == "",
+		BootType:          "auto",
+	})
+	if err != nil {
+		return api.Wrapf(err, "failed to create builder server")
+	}
+	defer destroyServerAndVolumes(ctx, serverID)
+
+	if err = api.StartServer(ctx.API, serverID, false); err != nil {
+		return api.Wrapf(err, "failed to start builder server %s", serverID)
+	}
+	server, err := api.WaitForServerReady(ctx.API, serverID, gateway)
+	if err != nil {
+		return api.Wrapf(err, "builder server did not boot")
+	}
+
+	for i, command := range recipe.Run {
+		logrus.Infof("Step %d/%d : RUN %s", i+2, steps, command)
+		if err = utils.SSHExec(server.PublicAddress.IP, server.PrivateIP, args.SSHUser, args.SSHPort, []string{command}, false, gateway, false); err != nil {
+			return api.Wrapf(err, "step %d/%d failed", i+2, steps)
+		}
+	}
+
+	logrus.Infof("Step %d/%d : TAG %s", steps, steps, recipe.Tag)
+	if err = ctx.API.PostServerAction(serverID, "poweroff"); err != nil {
+		return api.Wrapf(err, "failed to stop builder server")
+	}
+	if server, err = api.WaitForServerStopped(ctx.API, serverID); err != nil {
+		return api.Wrapf(err, "failed to stop builder server")
+	}
+	snapshotID, err := ctx.API.PostSnapshot(server.Volumes["0"].Identifier, recipe.Tag+"-snapshot")
+	if err != nil {
+		return api.Wrapf(err, "cannot create snapshot")
+	}
+	start := time.Now()
+	_, err = api.WaitForSnapshotState(ctx.API, snapshotID, "snapshotted", api.SnapshotTimeout)
+	ctx.Notify(NotifySnapshotDone, recipe.Tag, start, err)
+	if err != nil {
+		return api.Wrapf(err, "cannot wait for snapshot %s", snapshotID)
+	}
+	imageID, err := ctx.API.PostImage(snapshotID, recipe.Tag, "", server.Arch)
+	if err != nil {
+		return api.Wrapf(err, "cannot create image")
+	}
+	if args.Manifest != "" {
+		build := newPackerBuild(recipe.Tag, imageID, snapshotID, ctx.API.CurrentRegion(), server.Arch, time.Now())
+		if err = writePackerManifest(args.Manifest, build); err != nil {
+			return api.Wrapf(err, "cannot write manifest")
+		}
+	}
+	fmt.Fprintln(ctx.Stdout, imageID)
+	return nil
+}
@@ -0,0 +1,36 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseRecipe(t *testing.T) {
+	Convey("Testing ParseRecipe()", t, func() {
+		recipe, err := ParseRecipe(strings.NewReader(`# nginx image
+FROM ubuntu-bionic
+RUN apt-get update && \
+    apt-get install -y nginx
+
+run systemctl enable nginx
+TAG my-nginx
+`))
+		So(err, ShouldBeNil)
+		So(recipe.From, ShouldEqual, "ubuntu-bionic")
+		So(recipe.Run, ShouldResemble, []string{"apt-get update && apt-get install -y nginx", "systemctl enable nginx"})
+		So(recipe.Tag, ShouldEqual, "my-nginx")
+
+		_, err = ParseRecipe(strings.NewReader("RUN true\nFROM ubuntu-bionic\n"))
+		So(err, ShouldNotBeNil)
+		_, err = ParseRecipe(strings.NewReader("FROM ubuntu-bionic\nCOPY . /srv\n"))
+		So(err.Error(), ShouldEqual, `line 2: unknown instruction "COPY", expected FROM, RUN or TAG`)
+		_, err = ParseRecipe(strings.NewReader("TAG my-nginx\n"))
+		So(err, ShouldNotBeNil)
+	})
+}
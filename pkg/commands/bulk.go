@@ -0,0 +1,150 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/sirupsen/logrus"
+)
+
+// Report formats accepted by BulkResult.Report
+const (
+	ReportFormatTable = "table"
+	ReportFormatJSON  = "json"
+)
+
+// BulkItem is the outcome of a multi-target command on one of its targets
+type BulkItem struct {
+	Target     string        `json:"target"`
+	Status     string        `json:"status"`
+	Error      string        `json:"error,omitempty"`
+	Duration   time.Duration `json:"-"`
+	DurationMS int64         `json:"duration_ms"`
+}
+
+// BulkResult collects the outcome of a multi-target command, target by target
+type BulkResult struct {
+	Operation string     `json:"operation"`
+	Items     []BulkItem `json:"items"`
+
+	// Quiet disables printing the targets on success without --report-format
+	Quiet bool `json:"-"`
+
+	// Event is sent to --notify-url when an item finishes, nothing is sent when empty
+	Event string `json:"-"`
+
+	ctx  CommandContext
+	lock sync.Mutex
+}
+
+// NewBulkResult returns a BulkResult with one pending item per target, in the order of the command line
+func NewBulkResult(ctx CommandContext, operation string, targets []string) *BulkResult {
+	result := &BulkResult{
+		Operation: operation,
+		Items:     make([]BulkItem, len(targets)),
+		ctx:       ctx,
+	}
+	for i, target := range targets {
+		result.Items[i] = BulkItem{Target: target, Status: "pending"}
+	}
+	return result
+}
+
+// Start starts measuring the operation on target, the returned function records its outcome.
+// Without --report-format, the outcome is printed immediately: the target on success, an error log otherwise
+func (r *BulkResult) Start(target string) func(err error) {
+	start := time.Now()
+	return func(err error) {
+		r.record(target, "ok", start, err)
+		if r.Event != "" {
+			r.ctx.Notify(r.Event, target, start, err)
+		}
+	}
+}
+
+// Skip records that nothing had to be done on target, i.e: stopping a stopped server
+func (r *BulkResult) Skip(target, reason string) {
+	r.record(target, "skipped", time.Now(), fmt.Errorf("%s", reason))
+}
+
+func (r *BulkResult) record(target, status string, start time.Time, err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if api.IsDryRun(err) {
+		status = "skipped"
+	}
+	item := BulkItem{Target: target, Status: status, Duration: time.Since(start)}
+	item.DurationMS = int64(item.Duration / time.Millisecond)
+	if err != nil {
+		item.Error = err.Error()
+		if status == "ok" {
+			item.Status = "failed"
+		}
+	}
+	if r.ctx.API != nil {
+		r.ctx.API.RequestTracer().Event("outcome", map[string]interface{}{
+			"operation": r.Operation, "target": target, "status": item.Status, "error": item.Error,
+		})
+	}
+	for i := range r.Items {
+		if r.Items[i].Target == target && r.Items[i].Status == "pending" {
+			r.Items[i] = item
+			break
+		}
+	}
+
+	if r.ctx.ReportFormat != "" || item.Status == "skipped" {
+		return
+	}
+	if item.Status == "failed" {
+		logrus.Errorf("failed to %s %s: %v", r.Operation, target, err)
+	} else if !r.Quiet {
+		fmt.Fprintln(r.ctx.Stdout, target)
+	}
+}
+
+// Failed returns the number of failed items
+func (r *BulkResult) Failed() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	failed := 0
+	for _, item := range r.Items {
+		if item.Status == "failed" {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Report renders the items as a table or as JSON, according to --report-format
+func (r *BulkResult) Report() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	switch r.ctx.ReportFormat {
+	case ReportFormatJSON:
+		out, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(r.ctx.Stdout, "%s\n", out)
+	case ReportFormatTable:
+		w := tabwriter.NewWriter(r.ctx.Stdout, 20, 1, 3, ' ', 0)
+		defer w.Flush()
+		fmt.Fprintf(w, "TARGET\tSTATUS\tDURATION\tERROR\n")
+		for _, item := range r.Items {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", item.Target, item.Status, item.Duration/time.Millisecond*time.Millisecond, item.Error)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+)
+
+// BulkEditArgs are arguments passed to `RunBulkEdit`
+type BulkEditArgs struct {
+	File   string
+	DryRun bool
+}
+
+// bulkEdit is a row of the edits file: the server, its new name and the tags to add or remove
+type bulkEdit struct {
+	Line       int
+	Server     string
+	Name       string
+	AddTags    []string
+	RemoveTags []string
+}
+
+// parseBulkEdits reads CSV rows of SERVER,NAME,TAGS where an empty NAME keeps the name and TAGS
+// are space separated, prefixed with + to add them (the default) or - to remove them.
+// An optional header starting with "server" and lines starting with # are ignored
+func parseBulkEdits(r io.Reader) ([]bulkEdit, error) {
+	edits := []bulkEdit{}
+	scanner := bufio.NewScanner(r)
+	// each row is parsed on its own to report its line number, fields cannot span lines
+	for line := 1; scanner.Scan(); line++ {
+		reader := csv.NewReader(strings.NewReader(scanner.Text()))
+		reader.Comment = '#'
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+
+		record, err := reader.Read()
+		if err == io.EOF {
+			continue
+		}
+		if err != nil {
+			return nil, api.Wrapf(err, "line %d", line)
+		}
+		if len(edits) == 0 && strings.EqualFold(strings.TrimSpace(record[0]), "server") {
+			continue
+		}
+		if len(record) > 3 {
+			return nil, fmt.Errorf("line %d: expected SERVER,NAME,TAGS, got %d fields", line, len(record))
+		}
+		edit := bulkEdit{Line: line, Server: strings.TrimSpace(record[0])}
+		if edit.Server == "" {
+			return nil, fmt.Errorf("line %d: missing server", line)
+		}
+		if len(record) > 1 {
+			edit.Name = strings.TrimSpace(record[1])
+		}
+		if len(record) > 2 {
+			for _, tag := range strings.Fields(record[2]) {
+				switch {
+				case strings.HasPrefix(tag, "-"):
+					edit.RemoveTags = append(edit.RemoveTags, tag[1:])
+				default:
+					edit.AddTags = append(edit.AddTags, strings.TrimPrefix(tag, "+"))
+				}
+			}
+		}
+		if edit.Name == "" && len(edit.AddTags) == 0 && len(edit.RemoveTags) == 0 {
+			return nil, fmt.Errorf("line %d: nothing to change on %s", line, edit.Server)
+		}
+		edits = append(edits, edit)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return edits, nil
+}
+
+// editTags returns tags without the removed tags and with the added ones, keeping their order
+func (e bulkEdit) editTags(tags []string) []string {
+	removed := make(map[string]bool, len(e.RemoveTags))
+	for _, tag := range e.RemoveTags {
+		removed[tag] = true
+	}
+	edited := []string{}
+	seen := map[string]bool{}
+	for _, tag := range append(append([]string{}, tags...), e.AddTags...) {
+		if removed[tag] || seen[tag] || tag == "" {
+			continue
+		}
+		seen[tag] = true
+		edited = append(edited, tag)
+	}
+	return edited
+}
+
+// sameTags returns true if a and b are the same tags in the same order
+func sameTags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// bulkChange is an edit resolved against the current state of its server
+type bulkChange struct {
+	edit    bulkEdit
+	server  *api.ScalewayServer
+	name    string
+	tags    []string
+	changed bool
+}
+
+// RunBulkEdit is the handler for 'scw _bulk-edit'
+func RunBulkEdit(ctx CommandContext, args BulkEditArgs) error {
+	var input io.Reader = ctx.Stdin
+	if args.File != "-" {
+		file, err := os.Open(args.File)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	}
+	edits, err := parseBulkEdits(input)
+	if err != nil {
+		return api.Wrapf(err, "%s", args.File)
+	}
+
+	// resolve every row before changing anything, so a typo does not leave the inventory half edited
+	changes := make([]bulkChange, len(edits))
+	for i, edit := range edits {
+		serverID, err := ctx.API.GetServerID(edit.Server)
+		if err != nil {
+			return api.Wrapf(err, "line %d", edit.Line)
+		}
+		server, err := ctx.API.GetServer(serverID)
+		if err != nil {
+			return api.Wrapf(err, "line %d", edit.Line)
+		}
+		change := bulkChange{edit: edit, server: server, name: server.Name, tags: edit.editTags(server.Tags)}
+		if edit.Name != "" {
+			change.name = edit.Name
+		}
+		change.changed = change.name != server.Name || !sameTags(change.tags, server.Tags)
+		changes[i] = change
+	}
+
+	if args.DryRun {
+		w := tabwriter.NewWriter(ctx.Stdout, 20, 1, 3, ' ', 0)
+		defer w.Flush()
+		fmt.Fprintf(w, "SERVER\tNAME\tTAGS\n")
+		for _, change := range changes {
+			name := change.server.Name
+			if change.name != change.server.Name {
+				name += " -> " + change.name
+			}
+			tags := strings.Join(change.server.Tags, " ")
+			if !sameTags(change.tags, change.server.Tags) {
+				tags += " -> " + strings.Join(change.tags, " ")
+			}
+			if !change.changed {
+				name += " (unchanged)"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", change.server.Identifier, name, tags)
+		}
+		return nil
+	}
+
+	targets := make([]string, len(changes))
+	for i, change := range changes {
+		targets[i] = change.edit.Server
+	}
+	if ctx.ReportFormat == "" {
+		ctx.ReportFormat = ReportFormatTable
+	}
+	result := NewBulkResult(ctx, "bulk-edit", targets)
+	for _, change := range changes {
+		if !change.changed {
+			result.Skip(change.edit.Server, "unchanged")
+			continue
+		}
+		done := result.Start(change.edit.Server)
+		name, tags := change.name, change.tags
+		err := ctx.API.PatchServer(change.server.Identifier, api.ScalewayServerPatchDefinition{
+			Name: &name,
+			Tags: &tags,
+		})
+		if err == nil {
+			ctx.API.ResolverCache().InsertServer(change.server.Identifier, change.server.Location.ZoneID, change.server.Arch, change.server.Organization, name)
+		}
+		done(err)
+	}
+	if err := result.Report(); err != nil {
+		return err
+	}
+	if failed := result.Failed(); failed > 0 {
+		return fmt.Errorf("bulk edit failed on %d of %d servers", failed, len(changes))
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseBulkEdits(t *testing.T) {
+	Convey("Testing parseBulkEdits()", t, func() {
+		edits, err := parseBulkEdits(strings.NewReader("server,name,tags\n# cleanup\nweb-1,front-1,+prod -staging\n6c9fc5a2,,backup\n"))
+		So(err, ShouldBeNil)
+		So(len(edits), ShouldEqual, 2)
+		So(edits[0].Line, ShouldEqual, 3)
+		So(edits[0].Name, ShouldEqual, "front-1")
+		So(edits[0].AddTags, ShouldResemble, []string{"prod"})
+		So(edits[0].RemoveTags, ShouldResemble, []string{"staging"})
+		So(edits[1].Name, ShouldEqual, "")
+		So(edits[1].AddTags, ShouldResemble, []string{"backup"})
+
+		edits, err = parseBulkEdits(strings.NewReader("\n# cleanup\n\nweb-1,front-1\n"))
+		So(err, ShouldBeNil)
+		So(len(edits), ShouldEqual, 1)
+		So(edits[0].Line, ShouldEqual, 4)
+
+		_, err = parseBulkEdits(strings.NewReader("web-1,front-1\nweb-2\n"))
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldStartWith, "line 2:")
+		_, err = parseBulkEdits(strings.NewReader("web-1,\"front-1\n"))
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldStartWith, "line 1:")
+		_, err = parseBulkEdits(strings.NewReader(",front-1\n"))
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestBulkEditTags(t *testing.T) {
+	Convey("Testing bulkEdit.editTags()", t, func() {
+		edit := bulkEdit{AddTags: []string{"prod", "web"}, RemoveTags: []string{"staging"}}
+		So(edit.editTags([]string{"web", "staging"}), ShouldResemble, []string{"web", "prod"})
+		So(edit.editTags(nil), ShouldResemble, []string{"prod", "web"})
+	})
+}
@@ -0,0 +1,37 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBulkResult(t *testing.T) {
+	Convey("Testing BulkResult", t, func() {
+		var stdout bytes.Buffer
+		ctx := CommandContext{Streams: Streams{Stdout: &stdout}, ReportFormat: ReportFormatJSON}
+
+		result := NewBulkResult(ctx, "stop server", []string{"web-1", "web-2", "web-3"})
+		result.Start("web-2")(errors.New("server is locked"))
+		result.Start("web-1")(nil)
+		result.Skip("web-3", "server should be running")
+		So(result.Failed(), ShouldEqual, 1)
+		So(stdout.Len(), ShouldEqual, 0)
+
+		So(result.Report(), ShouldBeNil)
+		var report BulkResult
+		So(json.Unmarshal(stdout.Bytes(), &report), ShouldBeNil)
+		So(report.Items[0].Target, ShouldEqual, "web-1")
+		So(report.Items[0].Status, ShouldEqual, "ok")
+		So(report.Items[1].Status, ShouldEqual, "failed")
+		So(report.Items[1].Error, ShouldEqual, "server is locked")
+		So(report.Items[2].Status, ShouldEqual, "skipped")
+	})
+}
@@ -6,10 +6,13 @@
 package commands
 
 import (
+	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/scaleway/scaleway-cli/pkg/utils"
 )
 
 // Streams is used to redirects the streams
@@ -23,10 +26,18 @@ type Streams struct {
 type CommandContext struct {
 	Streams
 
-	Env        []string
-	RawArgs    []string
-	API        *api.ScalewayAPI
-	ConfigPath string
+	Env          []string
+	RawArgs      []string
+	API          api.ScalewayAPIClient
+	ConfigPath   string
+	TimeFormat   string
+	ReportFormat string
+	NotifyURL    string
+}
+
+// FormatTime displays a date using the --time-format option
+func (c *CommandContext) FormatTime(t time.Time) string {
+	return utils.FormatTime(t, c.TimeFormat)
 }
 
 // Getenv returns the equivalent of os.Getenv for the CommandContext.Env
@@ -34,3 +45,12 @@ func (c *CommandContext) Getenv(key string) string {
 	// FIXME: parse c.Env instead
 	return os.Getenv(key)
 }
+
+// ExitCodeError is returned by commands which want the CLI to exit with a specific code
+type ExitCodeError struct {
+	Code int
+}
+
+func (e ExitCodeError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
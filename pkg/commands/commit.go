@@ -6,6 +6,10 @@ package commands
 
 import (
 	"fmt"
+	"time"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/sirupsen/logrus"
 )
 
 // CommitArgs are flags for the `RunCommit` function
@@ -13,6 +17,7 @@ type CommitArgs struct {
 	Volume int
 	Server string
 	Name   string
+	Wait   bool
 }
 
 // RunCommit is the handler for 'scw commit'
@@ -23,7 +28,7 @@ func RunCommit(ctx CommandContext, args CommitArgs) error {
 	}
 	server, err := ctx.API.GetServer(serverID)
 	if err != nil {
-		return fmt.Errorf("Cannot fetch server: %v", err)
+		return api.Wrapf(err, "Cannot fetch server")
 	}
 	var volume = server.Volumes[fmt.Sprintf("%d", args.Volume)]
 	var name string
@@ -34,7 +39,16 @@ func RunCommit(ctx CommandContext, args CommitArgs) error {
 	}
 	snapshot, err := ctx.API.PostSnapshot(volume.Identifier, name)
 	if err != nil {
-		return fmt.Errorf("Cannot create snapshot: %v", err)
+		return api.Wrapf(err, "Cannot create snapshot")
+	}
+	if args.Wait {
+		logrus.Infof("Waiting for snapshot %s to be done", snapshot)
+		start := time.Now()
+		_, err = api.WaitForSnapshotState(ctx.API, snapshot, "snapshotted", api.SnapshotTimeout)
+		ctx.Notify(NotifySnapshotDone, name, start, err)
+		if err != nil {
+			return api.Wrapf(err, "Cannot wait for snapshot %s", snapshot)
+		}
 	}
 	fmt.Fprintln(ctx.Stdout, snapshot)
 	return nil
@@ -0,0 +1,93 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/skratchdot/open-golang/open"
+)
+
+// ConsoleURL is the web console of Scaleway
+var ConsoleURL = "https://cloud.scaleway.com"
+
+// ConsoleArgs are flags for the `RunConsole` function
+type ConsoleArgs struct {
+	Identifiers []string
+	Open        bool
+	Arch        string
+}
+
+// consoleURL returns the page of the web console showing the resource identifier of type kind
+func consoleURL(kind int, identifier string) (string, error) {
+	var page string
+	switch kind {
+	case api.IdentifierServer:
+		page = "servers"
+	case api.IdentifierImage:
+		page = "images"
+	case api.IdentifierSnapshot:
+		page = "snapshots"
+	case api.IdentifierVolume:
+		page = "volumes"
+	default:
+		return "", fmt.Errorf("%s has no page in the web console", identifier)
+	}
+	return fmt.Sprintf("%s/#/%s/%s", ConsoleURL, page, identifier), nil
+}
+
+// uniqueResolved returns the only result matching needle, the exact identifier or name wins over the prefixes
+func uniqueResolved(needle string, results api.ScalewayResolverResults) (api.ScalewayResolverResult, error) {
+	if len(results) == 0 {
+		return api.ScalewayResolverResult{}, fmt.Errorf("no such resource: %s", needle)
+	}
+	if len(results) > 1 {
+		exact := api.ScalewayResolverResults{}
+		for _, result := range results {
+			if result.Identifier == needle || result.Name == needle {
+				exact = append(exact, result)
+			}
+		}
+		if len(exact) != 1 {
+			names := make([]string, 0, len(results))
+			for _, result := range results {
+				names = append(names, fmt.Sprintf("%s (%s)", result.Name, result.Identifier))
+			}
+			return api.ScalewayResolverResult{}, fmt.Errorf("too many candidates for %s: %s", needle, strings.Join(names, ", "))
+		}
+		results = exact
+	}
+	return results[0], nil
+}
+
+// RunConsole is the handler for 'scw _console'
+func RunConsole(ctx CommandContext, args ConsoleArgs) error {
+	ci := make(chan api.ScalewayResolvedIdentifier)
+	go api.ResolveIdentifiers(ctx.API, args.Identifiers, ci)
+
+	var firstErr error
+	for idents := range ci {
+		results := api.FilterImagesByArch(idents.Identifiers, args.Arch)
+		results = api.FilterImagesByRegion(results, ctx.API.CurrentRegion())
+		result, err := uniqueResolved(idents.Needle, results)
+		if err == nil {
+			var url string
+			if url, err = consoleURL(result.Type, result.Identifier); err == nil {
+				fmt.Fprintln(ctx.Stdout, url)
+				if args.Open {
+					if err = open.Start(url); err != nil {
+						err = api.Wrapf(err, "cannot open browser")
+					}
+				}
+			}
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,41 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestConsoleURL(t *testing.T) {
+	Convey("Testing consoleURL()", t, func() {
+		url, err := consoleURL(api.IdentifierSnapshot, "2a8c3a6e-7c1e-4bdb-a1b6-5e6d2b9f1a10")
+		So(err, ShouldBeNil)
+		So(url, ShouldEqual, "https://cloud.scaleway.com/#/snapshots/2a8c3a6e-7c1e-4bdb-a1b6-5e6d2b9f1a10")
+
+		_, err = consoleURL(api.IdentifierBootscript, "2a8c3a6e-7c1e-4bdb-a1b6-5e6d2b9f1a10")
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestUniqueResolved(t *testing.T) {
+	Convey("Testing uniqueResolved()", t, func() {
+		results := api.ScalewayResolverResults{
+			{Identifier: "aaaa", Name: "web", Type: api.IdentifierServer},
+			{Identifier: "bbbb", Name: "web-2", Type: api.IdentifierServer},
+		}
+		result, err := uniqueResolved("web", results)
+		So(err, ShouldBeNil)
+		So(result.Identifier, ShouldEqual, "aaaa")
+
+		_, err = uniqueResolved("we", results)
+		So(err, ShouldNotBeNil)
+
+		_, err = uniqueResolved("db", nil)
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,196 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/scaleway/scaleway-cli/pkg/pricing"
+	"github.com/sirupsen/logrus"
+)
+
+// costForecast holds the forecasted costs of a group of resources
+type costForecast struct {
+	Servers int
+	Hours   float64
+	Compute *big.Rat
+	Storage uint64
+}
+
+func newCostForecast() *costForecast {
+	return &costForecast{Compute: new(big.Rat)}
+}
+
+func (f *costForecast) total(storage *pricing.Object) *big.Rat {
+	price := storage.MonthPrice(big.NewRat(int64(f.Storage/api.Giga), 1))
+	return price.Add(price, f.Compute)
+}
+
+// monthBounds returns the first instant of the month of now and of the next one, in UTC
+func monthBounds(now time.Time) (time.Time, time.Time) {
+	now = now.UTC()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 1, 0)
+}
+
+// powerTask returns 1 for a task powering a server on, -1 for a task stopping it and 0 otherwise
+func powerTask(task api.ScalewayTask) int {
+	if task.Status == "failure" {
+		return 0
+	}
+	switch {
+	case strings.Contains(task.Description, "poweron"):
+		return 1
+	case strings.Contains(task.Description, "poweroff"), strings.Contains(task.Description, "terminate"):
+		return -1
+	}
+	return 0
+}
+
+// runningDuration returns how long a server ran between from and now according to its power tasks,
+// the state before the first task is deduced from it, runningNow is used when there is no task
+func runningDuration(tasks []api.ScalewayTask, runningNow bool, from, now time.Time) time.Duration {
+	events := []api.ScalewayTask{}
+	for _, task := range tasks {
+		if powerTask(task) != 0 && !task.StartDate.Value().Before(from) && task.StartDate.Value().Before(now) {
+			events = append(events, task)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].StartDate.Value().Before(events[j].StartDate.Value())
+	})
+
+	running := runningNow
+	if len(events) > 0 {
+		running = powerTask(events[0]) < 0
+	}
+	var duration time.Duration
+	since := from
+	for _, event := range events {
+		on := powerTask(event) > 0
+		if running && !on {
+			duration += event.StartDate.Value().Sub(since)
+		}
+		if !running && on {
+			since = event.StartDate.Value()
+		}
+		running = on
+	}
+	if running {
+		duration += now.Sub(since)
+	}
+	return duration
+}
+
+// RunCostForecast is the handler for 'scw _cost-forecast'
+func RunCostForecast(ctx CommandContext) error {
+	servers, err := ctx.API.GetServers(true, 0)
+	if err != nil {
+		return api.Wrapf(err, "unable to fetch servers from the Scaleway API")
+	}
+	volumes, err := ctx.API.GetVolumes()
+	if err != nil {
+		return api.Wrapf(err, "unable to fetch volumes from the Scaleway API")
+	}
+	tasks, err := ctx.API.GetTasks()
+	if err != nil {
+		return api.Wrapf(err, "unable to fetch tasks from the Scaleway API")
+	}
+
+	groups := make(map[string]*costForecast)
+	groupsOf := func(tags []string) []*costForecast {
+		if len(tags) == 0 {
+			tags = []string{storageReportUntagged}
+		}
+		forecasts := make([]*costForecast, 0, len(tags))
+		for _, tag := range tags {
+			if _, ok := groups[tag]; !ok {
+				groups[tag] = newCostForecast()
+			}
+			forecasts = append(forecasts, groups[tag])
+		}
+		return forecasts
+	}
+
+	now := time.Now().UTC()
+	start, end := monthBounds(now)
+	total := newCostForecast()
+	volumeTags := make(map[string][]string)
+	for _, server := range *servers {
+		for _, volume := range server.Volumes {
+			volumeTags[volume.Identifier] = server.Tags
+		}
+
+		serverTasks := []api.ScalewayTask{}
+		for _, task := range *tasks {
+			if strings.Contains(task.HrefFrom, server.Identifier) {
+				serverTasks = append(serverTasks, task)
+			}
+		}
+		from := start
+		if server.CreationDate.Value().After(from) {
+			from = server.CreationDate.Value()
+		}
+		running := server.State == "running" || server.State == "starting"
+		duration := runningDuration(serverTasks, running, from, now)
+		if running {
+			duration += end.Sub(now)
+		}
+
+		price := new(big.Rat)
+		commercialType := strings.ToLower(server.CommercialType)
+		if object := pricing.CurrentPricing.GetByPath(fmt.Sprintf("/compute/%s/run", commercialType)); object != nil {
+			usage := pricing.NewUsage(object)
+			usage.SetDuration(duration)
+			price = usage.Total()
+		} else if duration > 0 {
+			logrus.Warnf("No price known for the %s commercial type of server %s", server.CommercialType, server.Name)
+		}
+
+		for _, forecast := range append(groupsOf(server.Tags), total) {
+			forecast.Servers++
+			forecast.Hours += duration.Hours()
+			forecast.Compute.Add(forecast.Compute, price)
+		}
+	}
+	for _, volume := range *volumes {
+		for _, forecast := range append(groupsOf(volumeTags[volume.Identifier]), total) {
+			forecast.Storage += volume.Size
+		}
+	}
+
+	storage := pricing.CurrentPricing.GetByPath("/storage/local/ssd/storage")
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	// most expensive first
+	sort.Slice(keys, func(i, j int) bool {
+		if cmp := groups[keys[i]].total(storage).Cmp(groups[keys[j]].total(storage)); cmp != 0 {
+			return cmp > 0
+		}
+		return keys[i] < keys[j]
+	})
+
+	w := tabwriter.NewWriter(ctx.Stdout, 20, 1, 3, ' ', 0)
+	defer w.Flush()
+	fmt.Fprintf(w, "TAG\tSERVERS\tCOMPUTE HOURS\tCOMPUTE\tSTORAGE\tMONTH PRICE\n")
+	printRow := func(name string, forecast *costForecast) {
+		fmt.Fprintf(w, "%s\t%d\t%.0f\t%s\t%s\t%s\n", name, forecast.Servers, forecast.Hours,
+			pricing.PriceString(forecast.Compute, storage.Currency), storageReportGB(forecast.Storage),
+			pricing.PriceString(forecast.total(storage), storage.Currency))
+	}
+	for _, key := range keys {
+		printRow(key, groups[key])
+	}
+	printRow("TOTAL", total)
+	return nil
+}
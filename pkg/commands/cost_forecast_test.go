@@ -0,0 +1,40 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRunningDuration(t *testing.T) {
+	Convey("Testing runningDuration()", t, func() {
+		from := time.Date(2017, 3, 1, 0, 0, 0, 0, time.UTC)
+		now := from.Add(100 * time.Hour)
+		task := func(description string, hours int) api.ScalewayTask {
+			return api.ScalewayTask{Description: description, StartDate: &api.ScalewayTime{Time: from.Add(time.Duration(hours) * time.Hour)}}
+		}
+
+		So(runningDuration(nil, true, from, now), ShouldEqual, 100*time.Hour)
+		So(runningDuration(nil, false, from, now), ShouldEqual, 0)
+
+		tasks := []api.ScalewayTask{task("server_batch_poweron", 50), task("server_poweroff", 10)}
+		So(runningDuration(tasks, true, from, now), ShouldEqual, 60*time.Hour)
+
+		tasks = []api.ScalewayTask{task("server_batch_poweron", 20), task("server_reboot", 30), task("server_terminate", 40)}
+		So(runningDuration(tasks, false, from, now), ShouldEqual, 20*time.Hour)
+	})
+}
+
+func TestMonthBounds(t *testing.T) {
+	Convey("Testing monthBounds()", t, func() {
+		start, end := monthBounds(time.Date(2017, 12, 15, 10, 0, 0, 0, time.UTC))
+		So(start.Equal(time.Date(2017, 12, 1, 0, 0, 0, 0, time.UTC)), ShouldBeTrue)
+		So(end.Equal(time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)), ShouldBeTrue)
+	})
+}
@@ -5,14 +5,19 @@
 package commands
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/docker/docker/pkg/archive"
+	"github.com/mattn/go-isatty"
 	"github.com/scaleway/scaleway-cli/pkg/api"
 	"github.com/scaleway/scaleway-cli/pkg/utils"
 	"github.com/sirupsen/logrus"
@@ -25,207 +30,341 @@ type CpArgs struct {
 	Destination string
 	SSHUser     string
 	SSHPort     int
+	Compress    bool
+	Resume      bool
 }
 
-// RunCp is the handler for 'scw cp'
-func RunCp(ctx CommandContext, args CpArgs) error {
-	if strings.Count(args.Source, ":") > 1 || strings.Count(args.Destination, ":") > 1 {
-		return fmt.Errorf("bad usage, see 'scw help cp'")
+// cpEndpoint is the source or the destination of a copy
+type cpEndpoint struct {
+	// path is a local path, a path on server or "-" for stdin/stdout
+	path string
+
+	// the server is nil for local endpoints
+	remoteServer
+}
+
+// newCpEndpoint resolves a SERVER:PATH, HOSTPATH or - uri
+func newCpEndpoint(ctx CommandContext, uri string, args CpArgs) (*cpEndpoint, error) {
+	endpoint := &cpEndpoint{
+		path:         uri,
+		remoteServer: remoteServer{user: args.SSHUser, port: args.SSHPort},
+	}
+	if !strings.Contains(uri, ":") {
+		return endpoint, nil
 	}
 
-	sourceStream, err := TarFromSource(ctx, args.Source, args.Gateway, args.SSHUser, args.SSHPort)
-	if err != nil {
-		return fmt.Errorf("cannot tar from source '%s': %v", args.Source, err)
+	serverParts := strings.Split(uri, ":")
+	if len(serverParts) != 2 {
+		return nil, fmt.Errorf("invalid uri %q, see 'scw cp -h' for usage", uri)
 	}
+	endpoint.path = serverParts[1]
 
-	err = UntarToDest(ctx, sourceStream, args.Destination, args.Gateway, args.SSHUser, args.SSHPort)
+	serverID, err := ctx.API.GetServerID(serverParts[0])
 	if err != nil {
-		return fmt.Errorf("cannot untar to destination '%s': %v", args.Destination, err)
+		return nil, err
+	}
+	endpoint.server, err = ctx.API.GetServer(serverID)
+	if err != nil {
+		return nil, err
 	}
-	return nil
-}
-
-// TarFromSource creates a stream buffer with the tarballed content of the user source
-func TarFromSource(ctx CommandContext, source, gateway, user string, port int) (*io.ReadCloser, error) {
-	var tarOutputStream io.ReadCloser
 
-	// source is a server address + path (scp-like uri)
-	if strings.Contains(source, ":") {
-		logrus.Debugf("Creating a tarball remotely and streaming it using SSH")
-		serverParts := strings.Split(source, ":")
-		if len(serverParts) != 2 {
-			return nil, fmt.Errorf("invalid source uri, see 'scw cp -h' for usage")
+	// Resolve gateway
+	gateway := args.Gateway
+	if gateway == "" {
+		gateway = ctx.Getenv("SCW_GATEWAY")
+	}
+	if gateway != serverID && gateway != serverParts[0] {
+		endpoint.gateway, err = api.ResolveGateway(ctx.API, gateway)
+		if err != nil {
+			return nil, api.Wrapf(err, "cannot resolve Gateway '%s'", gateway)
 		}
+	}
+	return endpoint, nil
+}
+
+func (e *cpEndpoint) isStdio() bool {
+	return e.server == nil && e.path == "-"
+}
 
-		serverID, err := ctx.API.GetServerID(serverParts[0])
+// listFiles returns the size of the regular files below base, indexed by their path relative to dir
+func (e *cpEndpoint) listFiles(dir, base string) (map[string]int64, error) {
+	files := make(map[string]int64)
+	if e.server != nil {
+		remoteCommand := fmt.Sprintf("cd %s 2>/dev/null && find %s -type f -printf '%%p\\t%%s\\n' 2>/dev/null || true", utils.ShellQuote(dir), utils.ShellQuote(base))
+		out, err := e.command(remoteCommand).Output()
 		if err != nil {
 			return nil, err
 		}
+		scanner := bufio.NewScanner(bytes.NewReader(out))
+		for scanner.Scan() {
+			fields := strings.Split(scanner.Text(), "\t")
+			if len(fields) != 2 {
+				continue
+			}
+			size, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				continue
+			}
+			files[filepath.Clean(fields[0])] = size
+		}
+		return files, scanner.Err()
+	}
 
-		server, err := ctx.API.GetServer(serverID)
+	root := filepath.Join(dir, base)
+	if _, err := os.Lstat(root); os.IsNotExist(err) {
+		return files, nil
+	}
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
-			return nil, err
+			return err
 		}
+		if info.Mode().IsRegular() {
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			files[rel] = info.Size()
+		}
+		return nil
+	})
+	return files, err
+}
 
-		dir, base := utils.PathToTARPathparts(serverParts[1])
-		logrus.Debugf("Equivalent to 'scp root@%s:%s/%s ...'", server.PublicAddress.IP, dir, base)
+// sourceParts returns the directory and the base name of a source path, the tarball is rooted in the directory
+func (e *cpEndpoint) sourceParts() (string, string, error) {
+	if e.server != nil {
+		dir, base := utils.PathToTARPathparts(e.path)
+		return dir, base, nil
+	}
+	path, err := filepath.Abs(e.path)
+	if err != nil {
+		return "", "", err
+	}
+	path, err = filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", "", err
+	}
+	logrus.Debugf("Real local path is %s", path)
+	dir, base := utils.PathToTARPathparts(path)
+	return dir, base, nil
+}
+
+// remoteStream is the output of a command, closing it waits for the command
+type remoteStream struct {
+	io.ReadCloser
+	cmd *exec.Cmd
+}
+
+func (s *remoteStream) Close() error {
+	s.ReadCloser.Close()
+	return s.cmd.Wait()
+}
+
+// tar streams a tarball of the source, compressed if requested, skipping the excluded files
+func (e *cpEndpoint) tar(ctx CommandContext, compress bool, excludes []string) (io.ReadCloser, error) {
+	// source is stdin
+	if e.isStdio() {
+		logrus.Debugf("Streaming tarball from stdin")
+		// FIXME: should be ctx.Stdin
+		return os.Stdin, nil
+	}
+
+	dir, base, err := e.sourceParts()
+	if err != nil {
+		return nil, err
+	}
+
+	// source is a server address + path (scp-like uri)
+	if e.server != nil {
+		logrus.Debugf("Creating a tarball remotely and streaming it using SSH")
+		logrus.Debugf("Equivalent to 'scp root@%s:%s/%s ...'", e.server.PublicAddress.IP, dir, base)
 
 		// remoteCommand is executed on the remote server
 		// it streams a tarball raw content
-		remoteCommand := []string{"tar"}
-		remoteCommand = append(remoteCommand, "-C", dir)
+		remoteCommand := []string{"tar", "-C", utils.ShellQuote(dir)}
 		if ctx.Getenv("DEBUG") == "1" {
 			remoteCommand = append(remoteCommand, "-v")
 		}
-		remoteCommand = append(remoteCommand, "-cf", "-")
-		remoteCommand = append(remoteCommand, base)
-
-		// Resolve gateway
-		if gateway == "" {
-			gateway = ctx.Getenv("SCW_GATEWAY")
+		if len(excludes) > 0 {
+			remoteCommand = append(remoteCommand, "--anchored", "--no-wildcards", "-X", "/dev/stdin")
 		}
-
-		if gateway == serverID || gateway == serverParts[0] {
-			gateway = ""
-		} else {
-			gateway, err = api.ResolveGateway(ctx.API, gateway)
-			if err != nil {
-				return nil, fmt.Errorf("cannot resolve Gateway '%s': %v", gateway, err)
-			}
+		if compress {
+			remoteCommand = append(remoteCommand, "-z")
 		}
+		remoteCommand = append(remoteCommand, "-cf", "-", utils.ShellQuote(base))
 
-		// execCmd contains the ssh connection + the remoteCommand
-		sshCommand := utils.NewSSHExecCmd(server.PublicAddress.IP, server.PrivateIP, user, port, false, remoteCommand, gateway, false)
-		logrus.Debugf("Executing: %s", sshCommand)
-		spawnSrc := exec.Command("ssh", sshCommand.Slice()[1:]...)
-
-		tarOutputStream, err = spawnSrc.StdoutPipe()
-		if err != nil {
-			return nil, err
+		spawnSrc := e.command(strings.Join(remoteCommand, " "))
+		spawnSrc.Stderr = ctx.Stderr
+		if len(excludes) > 0 {
+			spawnSrc.Stdin = strings.NewReader(strings.Join(excludes, "\n") + "\n")
 		}
-
-		tarErrorStream, err := spawnSrc.StderrPipe()
+		stdout, err := spawnSrc.StdoutPipe()
 		if err != nil {
 			return nil, err
 		}
-		defer tarErrorStream.Close()
-		io.Copy(ctx.Stderr, tarErrorStream)
-
-		err = spawnSrc.Start()
-		if err != nil {
+		if err = spawnSrc.Start(); err != nil {
 			return nil, err
 		}
-		defer spawnSrc.Wait()
-
-		return &tarOutputStream, nil
-	}
-
-	// source is stdin
-	if source == "-" {
-		logrus.Debugf("Streaming tarball from stdin")
-		// FIXME: should be ctx.Stdin
-		tarOutputStream = os.Stdin
-		return &tarOutputStream, nil
+		return &remoteStream{ReadCloser: stdout, cmd: spawnSrc}, nil
 	}
 
 	// source is a path on localhost
-	logrus.Debugf("Taring local path %s", source)
-	path, err := filepath.Abs(source)
-	if err != nil {
-		return nil, err
+	logrus.Debugf("Taring local path %s", filepath.Join(dir, base))
+	patterns := make([]string, len(excludes))
+	for i, exclude := range excludes {
+		patterns[i] = cpEscapePattern(exclude)
 	}
-	path, err = filepath.EvalSymlinks(path)
-	if err != nil {
-		return nil, err
-	}
-	logrus.Debugf("Real local path is %s", path)
-
-	dir, base := utils.PathToTARPathparts(path)
-
-	tarOutputStream, err = archive.TarWithOptions(dir, &archive.TarOptions{
-		Compression:  archive.Uncompressed,
-		IncludeFiles: []string{base},
+	return archive.TarWithOptions(dir, &archive.TarOptions{
+		Compression:     archive.Uncompressed,
+		IncludeFiles:    []string{base},
+		ExcludePatterns: patterns,
 	})
-	if err != nil {
-		return nil, err
-	}
-	return &tarOutputStream, nil
 }
 
-// UntarToDest writes to user destination the streamed tarball in input
-func UntarToDest(ctx CommandContext, sourceStream *io.ReadCloser, destination, gateway, user string, port int) error {
+// untar extracts the tarball at destination, or writes it to stdout
+func (e *cpEndpoint) untar(ctx CommandContext, stream io.Reader, compress bool) error {
 	// destination is a server address + path (scp-like uri)
-	if strings.Contains(destination, ":") {
+	if e.server != nil {
 		logrus.Debugf("Streaming using ssh and untaring remotely")
-		serverParts := strings.Split(destination, ":")
-		if len(serverParts) != 2 {
-			return fmt.Errorf("invalid destination uri, see 'scw cp -h' for usage")
-		}
-
-		serverID, err := ctx.API.GetServerID(serverParts[0])
-		if err != nil {
-			return err
-		}
-
-		server, err := ctx.API.GetServer(serverID)
-		if err != nil {
-			return err
-		}
 
 		// remoteCommand is executed on the remote server
-		// it streams a tarball raw content
-		remoteCommand := []string{"tar"}
-		remoteCommand = append(remoteCommand, "-C", serverParts[1])
+		// it extracts the streamed tarball
+		remoteCommand := []string{"tar", "-C", utils.ShellQuote(e.path)}
 		if ctx.Getenv("DEBUG") == "1" {
 			remoteCommand = append(remoteCommand, "-v")
 		}
+		if compress {
+			remoteCommand = append(remoteCommand, "-z")
+		}
 		remoteCommand = append(remoteCommand, "-xf", "-")
 
-		// Resolve gateway
-		if gateway == "" {
-			gateway = ctx.Getenv("SCW_GATEWAY")
+		spawnDst := e.command(strings.Join(remoteCommand, " "))
+		spawnDst.Stdin = stream
+		spawnDst.Stderr = ctx.Stderr
+		return spawnDst.Run()
+	}
+
+	// destination is stdout
+	if e.isStdio() {
+		logrus.Debugf("Writing the tarball to ctx.Stdout(%v)", ctx.Stdout)
+		_, err := io.Copy(ctx.Stdout, stream)
+		return err
+	}
+
+	// destination is a path on localhost, archive.Untar detects the compression
+	logrus.Debugf("Untaring to local path: %s", e.path)
+	return archive.Untar(stream, e.path, &archive.TarOptions{NoLchown: true})
+}
+
+// cpEscapePattern escapes the wildcards of a path used as an exclude pattern
+func cpEscapePattern(path string) string {
+	var escaped bytes.Buffer
+	for _, c := range path {
+		if strings.ContainsRune(`*?[\`, c) {
+			escaped.WriteRune('\\')
 		}
-		if gateway == serverID || gateway == serverParts[0] {
-			gateway = ""
-		} else {
-			gateway, err = api.ResolveGateway(ctx.API, gateway)
-			if err != nil {
-				return fmt.Errorf("cannot resolve Gateway '%s': %v", gateway, err)
-			}
+		escaped.WriteRune(c)
+	}
+	return escaped.String()
+}
+
+// gzipStream compresses stream on the fly
+func gzipStream(stream io.Reader) io.Reader {
+	reader, writer := io.Pipe()
+	go func() {
+		gz := gzip.NewWriter(writer)
+		_, err := io.Copy(gz, stream)
+		if err == nil {
+			err = gz.Close()
 		}
+		writer.CloseWithError(err)
+	}()
+	return reader
+}
 
-		// execCmd contains the ssh connection + the remoteCommand
-		sshCommand := utils.NewSSHExecCmd(server.PublicAddress.IP, server.PrivateIP, user, port, false, remoteCommand, gateway, false)
-		logrus.Debugf("Executing: %s", sshCommand)
-		spawnDst := exec.Command("ssh", sshCommand.Slice()[1:]...)
+// RunCp is the handler for 'scw cp'
+func RunCp(ctx CommandContext, args CpArgs) error {
+	if strings.Count(args.Source, ":") > 1 || strings.Count(args.Destination, ":") > 1 {
+		return fmt.Errorf("bad usage, see 'scw help cp'")
+	}
 
-		untarInputStream, err := spawnDst.StdinPipe()
-		if err != nil {
-			return err
-		}
-		defer untarInputStream.Close()
+	source, err := newCpEndpoint(ctx, args.Source, args)
+	if err != nil {
+		return api.Wrapf(err, "cannot tar from source '%s'", args.Source)
+	}
+	destination, err := newCpEndpoint(ctx, args.Destination, args)
+	if err != nil {
+		return api.Wrapf(err, "cannot untar to destination '%s'", args.Destination)
+	}
+	if args.Resume && (source.isStdio() || destination.isStdio()) {
+		return fmt.Errorf("--resume needs a path as source and destination")
+	}
 
-		// spawnDst.Stderr = ctx.Stderr
-		// spawnDst.Stdout = ctx.Stdout
+	progress := !utils.IsQuiet()
+	if file, ok := ctx.Stderr.(*os.File); !ok || !isatty.IsTerminal(file.Fd()) {
+		progress = false
+	}
 
-		err = spawnDst.Start()
+	// files of the source are listed to skip those already copied and to compute the progress
+	var excludes []string
+	total := int64(-1)
+	if args.Resume || (progress && !source.isStdio()) {
+		dir, base, err := source.sourceParts()
 		if err != nil {
-			return err
+			return api.Wrapf(err, "cannot tar from source '%s'", args.Source)
 		}
+		sourceFiles, err := source.listFiles(dir, base)
+		if err != nil {
+			return api.Wrapf(err, "cannot list files of source '%s'", args.Source)
+		}
+		var destinationFiles map[string]int64
+		if args.Resume {
+			destinationFiles, err = destination.listFiles(destination.path, base)
+			if err != nil {
+				return api.Wrapf(err, "cannot list files of destination '%s'", args.Destination)
+			}
+		}
+		total = 0
+		for path, size := range sourceFiles {
+			// a file with the same size was completely copied
+			if copied, ok := destinationFiles[path]; ok && copied == size {
+				excludes = append(excludes, path)
+				continue
+			}
+			total += size
+		}
+		if args.Resume {
+			logrus.Infof("Resuming, %d of %d files already copied", len(excludes), len(sourceFiles))
+		}
+	}
 
-		_, err = io.Copy(untarInputStream, *sourceStream)
-		return err
+	// remote sources are compressed on the server
+	stream, err := source.tar(ctx, args.Compress && source.server != nil, excludes)
+	if err != nil {
+		return api.Wrapf(err, "cannot tar from source '%s'", args.Source)
 	}
 
-	// destination is stdout
-	if destination == "-" { // stdout
-		logrus.Debugf("Writing sourceStream(%v) to ctx.Stdout(%v)", sourceStream, ctx.Stdout)
-		_, err := io.Copy(ctx.Stdout, *sourceStream)
-		return err
+	reader := io.Reader(stream)
+	if progress {
+		if args.Compress && source.server != nil {
+			// the size of the compressed stream is unknown
+			total = -1
+		}
+		progressReader := utils.NewProgressReader(stream, total, ctx.Stderr)
+		defer progressReader.Done()
+		reader = progressReader
+	}
+	if args.Compress && source.server == nil {
+		reader = gzipStream(reader)
 	}
 
-	// destination is a path on localhost
-	logrus.Debugf("Untaring to local path: %s", destination)
-	err := archive.Untar(*sourceStream, destination, &archive.TarOptions{NoLchown: true})
-	return err
+	err = destination.untar(ctx, reader, args.Compress)
+	closeErr := stream.Close()
+	if err != nil {
+		return api.Wrapf(err, "cannot untar to destination '%s'", args.Destination)
+	}
+	if err = closeErr; err != nil {
+		return api.Wrapf(err, "cannot tar from source '%s'", args.Source)
+	}
+	return nil
 }
@@ -14,16 +14,19 @@ import (
 
 // CreateArgs are arguments passed to `RunCreate`
 type CreateArgs struct {
-	Volumes        []string
-	Tags           []string
-	Name           string
-	Bootscript     string
-	Image          string
-	IP             string
-	CommercialType string
-	TmpSSHKey      bool
-	IPV6           bool
-	BootType       string
+	Volumes         []string
+	Tags            []string
+	Name            string
+	NamePrefix      string
+	Bootscript      string
+	Image           string
+	IP              string
+	CommercialType  string
+	TmpSSHKey       bool
+	IPV6            bool
+	BootType        string
+	ForceBootscript bool
+	PullPolicy      string
 }
 
 // RunCreate is the handler for 'scw create'
@@ -40,6 +43,7 @@ func RunCreate(ctx CommandContext, args CreateArgs) error {
 	config := api.ConfigCreateServer{
 		ImageName:         args.Image,
 		Name:              args.Name,
+		NamePrefix:        args.NamePrefix,
 		Bootscript:        args.Bootscript,
 		Env:               env,
 		AdditionalVolumes: volume,
@@ -48,6 +52,8 @@ func RunCreate(ctx CommandContext, args CreateArgs) error {
 		CommercialType:    args.CommercialType,
 		EnableIPV6:        args.IPV6,
 		BootType:          args.BootType,
+		ForceBootscript:   args.ForceBootscript,
+		PullPolicy:        args.PullPolicy,
 	}
 	if args.IP == "dynamic" || args.IP == "" {
 		config.DynamicIPRequired = true
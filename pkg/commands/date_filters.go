@@ -0,0 +1,63 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/scaleway/scaleway-cli/pkg/utils"
+)
+
+// dateFilterKeys are the `--filter` keys comparing a creation or modification date
+var dateFilterKeys = []string{"created-before", "created-after", "modified-before", "modified-after"}
+
+// dateFilters holds the parsed dates of the date `--filter` keys
+type dateFilters map[string]time.Time
+
+// parseDateFilters parses the date keys of filters, durations are relative to now
+func parseDateFilters(filters map[string]string) (dateFilters, error) {
+	now := time.Now().UTC()
+	dates := dateFilters{}
+	for _, key := range dateFilterKeys {
+		value, ok := filters[key]
+		if !ok {
+			continue
+		}
+		date, err := utils.ParseDate(value, now)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter '%s': %v", key, err)
+		}
+		dates[key] = date
+	}
+	return dates, nil
+}
+
+// match returns true if created and modified satisfy every date filter, an unknown (zero) date never matches
+func (f dateFilters) match(created, modified time.Time) bool {
+	for key, date := range f {
+		var value time.Time
+		switch key {
+		case "created-before", "created-after":
+			value = created
+		default:
+			value = modified
+		}
+		if value.IsZero() {
+			return false
+		}
+		switch key {
+		case "created-before", "modified-before":
+			if !value.Before(date) {
+				return false
+			}
+		default:
+			if !value.After(date) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,117 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/docker/docker/pkg/archive"
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/scaleway/scaleway-cli/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// DeployArgs are arguments passed to `RunDeploy`
+type DeployArgs struct {
+	Servers     []string
+	Filters     map[string]string
+	Source      string
+	Destination string
+	PostDeploy  string
+	Restart     string
+	Parallel    bool
+	Gateway     string
+	SSHUser     string
+	SSHPort     int
+}
+
+// deployServer copies the source directory to the server, then runs the post-deploy command and restarts the service
+func deployServer(ctx CommandContext, args DeployArgs, server *api.ScalewayServer, gateway string) error {
+	endpoint := &cpEndpoint{
+		path:         args.Destination,
+		remoteServer: remoteServer{server: server, gateway: gateway, user: args.SSHUser, port: args.SSHPort},
+	}
+	if err := endpoint.run("mkdir", "mkdir -p "+utils.ShellQuote(args.Destination)); err != nil {
+		return err
+	}
+	stream, err := archive.TarWithOptions(args.Source, &archive.TarOptions{Compression: archive.Gzip})
+	if err != nil {
+		return api.Wrapf(err, "cannot tar %s", args.Source)
+	}
+	defer stream.Close()
+	if err = endpoint.untar(ctx, stream, true); err != nil {
+		return api.Wrapf(err, "copy failed")
+	}
+	if args.PostDeploy != "" {
+		if err = endpoint.run("post-deploy", "cd "+utils.ShellQuote(args.Destination)+" && "+args.PostDeploy); err != nil {
+			return err
+		}
+	}
+	if args.Restart != "" {
+		if err = endpoint.run("restart", "systemctl restart "+utils.ShellQuote(args.Restart)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunDeploy is the handler for 'scw _deploy'
+func RunDeploy(ctx CommandContext, args DeployArgs) error {
+	if info, err := os.Stat(args.Source); err != nil {
+		return err
+	} else if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", args.Source)
+	}
+	servers, err := selectServers(ctx, args.Servers, args.Filters)
+	if err != nil {
+		return err
+	}
+	if len(servers) == 0 {
+		return fmt.Errorf("no server matches the filters")
+	}
+	if args.Gateway == "" {
+		args.Gateway = ctx.Getenv("SCW_GATEWAY")
+	}
+	gateway, err := api.ResolveGateway(ctx.API, args.Gateway)
+	if err != nil {
+		return api.Wrapf(err, "cannot resolve Gateway '%s'", args.Gateway)
+	}
+
+	targets := make([]string, len(servers))
+	for i, server := range servers {
+		targets[i] = server.Name
+	}
+	// the per-host table is the output of the command
+	if ctx.ReportFormat == "" {
+		ctx.ReportFormat = ReportFormatTable
+	}
+	result := NewBulkResult(ctx, "deploy", targets)
+	wg := sync.WaitGroup{}
+	for i := range servers {
+		server := &servers[i]
+		done := result.Start(server.Name)
+		if !args.Parallel {
+			logrus.Infof("Deploying %s to %s:%s ...", args.Source, server.Name, args.Destination)
+			done(deployServer(ctx, args, server, gateway))
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			done(deployServer(ctx, args, server, gateway))
+		}()
+	}
+	wg.Wait()
+
+	if err := result.Report(); err != nil {
+		return err
+	}
+	if failed := result.Failed(); failed > 0 {
+		return fmt.Errorf("deploy failed on %d of %d servers", failed, len(servers))
+	}
+	return nil
+}
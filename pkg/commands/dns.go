@@ -0,0 +1,324 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/scaleway/scaleway-cli/pkg/config"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	dnsBlockBegin = "; BEGIN scw _dns"
+	dnsBlockEnd   = "; END scw _dns"
+)
+
+// nsupdateCommand is the program the updates are sent with, overridden by tests
+var nsupdateCommand = "nsupdate"
+
+// DNSArgs are flags for the `RunDNS` function
+type DNSArgs struct {
+	Servers    []string
+	Zone       string
+	TTL        int
+	Format     string
+	Output     string
+	Nameserver string
+	Apply      bool
+	Key        string
+}
+
+// DNSRecord is an A or AAAA record pointing a server name to one of its IPs
+type DNSRecord struct {
+	Name    string
+	Type    string
+	Address string
+}
+
+var dnsInvalidLabel = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// DNSLabel converts a server name into a valid DNS label
+func DNSLabel(name string) string {
+	label := dnsInvalidLabel.ReplaceAllString(strings.ToLower(name), "-")
+	label = strings.Trim(label, "-")
+	if len(label) > 63 {
+		label = strings.TrimRight(label[:63], "-")
+	}
+	return label
+}
+
+// RunDNS is the handler for 'scw _dns'
+func RunDNS(ctx CommandContext, args DNSArgs) error {
+	if args.TTL <= 0 {
+		args.TTL = 300
+	}
+	servers, err := dnsServers(ctx, args.Servers)
+	if err != nil {
+		return err
+	}
+
+	records := []DNSRecord{}
+	for _, server := range servers {
+		label := DNSLabel(server.Name)
+		if label == "" {
+			logrus.Warnf("server %s has no valid DNS name, skipping", server.Identifier)
+			continue
+		}
+		if server.PublicAddress.IP != "" {
+			records = append(records, DNSRecord{Name: label, Type: "A", Address: server.PublicAddress.IP})
+		}
+		if server.IPV6 != nil && server.IPV6.Address != "" {
+			records = append(records, DNSRecord{Name: label, Type: "AAAA", Address: server.IPV6.Address})
+		}
+		if server.PublicAddress.IP == "" && server.IPV6 == nil {
+			logrus.Warnf("server %s has no public IP address, skipping", server.Name)
+		}
+	}
+	sort.Sort(dnsRecords(records))
+
+	var buf bytes.Buffer
+	switch args.Format {
+	case "", "zone":
+		writeZoneRecords(&buf, records, args.TTL)
+	case "nsupdate":
+		if args.Zone == "" {
+			return fmt.Errorf("--zone is required with --format=nsupdate")
+		}
+		if args.Apply && args.Output != "" {
+			return fmt.Errorf("--apply cannot be used with --output")
+		}
+		return runNSUpdate(ctx, records, args)
+	default:
+		return fmt.Errorf("unknown format %q, expected 'zone' or 'nsupdate'", args.Format)
+	}
+
+	if args.Output == "" {
+		_, err = io.Copy(ctx.Stdout, &buf)
+		return err
+	}
+	return updateZoneFile(args.Output, buf.String())
+}
+
+// runNSUpdate writes the nsupdate script of records, deleting the records of the servers
+// published in the zone by the previous applied update and missing from records
+func runNSUpdate(ctx CommandContext, records []DNSRecord, args DNSArgs) error {
+	zone := strings.TrimSuffix(args.Zone, ".")
+	published, err := loadDNSNames()
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	writeNSUpdate(&buf, records, published[zone], args)
+	if !args.Apply {
+		// nothing tells us the script will be sent, the published names are left as they are
+		if args.Output == "" {
+			_, err = io.Copy(ctx.Stdout, &buf)
+			return err
+		}
+		return ioutil.WriteFile(args.Output, buf.Bytes(), 0644)
+	}
+
+	var nsupdateArgs []string
+	if args.Key != "" {
+		nsupdateArgs = append(nsupdateArgs, "-k", args.Key)
+	}
+	nsupdate := exec.Command(nsupdateCommand, nsupdateArgs...)
+	nsupdate.Stdin = &buf
+	nsupdate.Stdout = ctx.Stdout
+	nsupdate.Stderr = ctx.Stderr
+	if err = nsupdate.Run(); err != nil {
+		return api.Wrapf(err, "%s failed", nsupdateCommand)
+	}
+
+	names := []string{}
+	for _, record := range records {
+		if len(names) == 0 || names[len(names)-1] != record.Name {
+			names = append(names, record.Name)
+		}
+	}
+	published[zone] = names
+	return saveDNSNames(published)
+}
+
+// getDNSNamesPath returns the path of the names published by 'scw _dns --format=nsupdate --apply', by zone
+func getDNSNamesPath() (string, error) {
+	homeDir, err := config.GetHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".scw-dns.json"), nil
+}
+
+func loadDNSNames() (map[string][]string, error) {
+	published := make(map[string][]string)
+
+	path, err := getDNSNamesPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return published, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &published); err != nil {
+		return nil, api.Wrapf(err, "unable to parse %s", path)
+	}
+	return published, nil
+}
+
+func saveDNSNames(published map[string][]string) error {
+	path, err := getDNSNamesPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(published, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+func dnsServers(ctx CommandContext, needles []string) ([]api.ScalewayServer, error) {
+	if len(needles) == 0 {
+		servers, err := ctx.API.GetServers(true, 0)
+		if err != nil {
+			return nil, api.Wrapf(err, "unable to fetch servers from the Scaleway API")
+		}
+		return *servers, nil
+	}
+
+	servers := []api.ScalewayServer{}
+	for _, needle := range needles {
+		serverID, err := ctx.API.GetServerID(needle)
+		if err != nil {
+			return nil, err
+		}
+		server, err := ctx.API.GetServer(serverID)
+		if err != nil {
+			return nil, err
+		}
+		servers = append(servers, *server)
+	}
+	return servers, nil
+}
+
+func writeZoneRecords(w io.Writer, records []DNSRecord, ttl int) {
+	fmt.Fprintln(w, dnsBlockBegin)
+	for _, record := range records {
+		fmt.Fprintf(w, "%s\t%d\tIN\t%s\t%s\n", record.Name, ttl, record.Type, record.Address)
+	}
+	fmt.Fprintln(w, dnsBlockEnd)
+}
+
+// writeNSUpdate writes the nsupdate script of records, the A and AAAA records of the stale names are deleted
+func writeNSUpdate(w io.Writer, records []DNSRecord, stale []string, args DNSArgs) {
+	zone := strings.TrimSuffix(args.Zone, ".")
+	if args.Nameserver != "" {
+		fmt.Fprintf(w, "server %s\n", args.Nameserver)
+	}
+	fmt.Fprintf(w, "zone %s.\n", zone)
+	current := make(map[string]bool)
+	for _, record := range records {
+		current[record.Name] = true
+	}
+	for _, name := range stale {
+		if current[name] {
+			continue
+		}
+		// the server was removed or renamed since the previous update
+		fqdn := fmt.Sprintf("%s.%s.", name, zone)
+		fmt.Fprintf(w, "update delete %s A\n", fqdn)
+		fmt.Fprintf(w, "update delete %s AAAA\n", fqdn)
+	}
+	deleted := make(map[string]bool)
+	for _, record := range records {
+		fqdn := fmt.Sprintf("%s.%s.", record.Name, zone)
+		// drop stale records of the same type before adding the current address
+		key := fqdn + " " + record.Type
+		if !deleted[key] {
+			fmt.Fprintf(w, "update delete %s %s\n", fqdn, record.Type)
+			deleted[key] = true
+		}
+		fmt.Fprintf(w, "update add %s %d %s %s\n", fqdn, args.TTL, record.Type, record.Address)
+	}
+	fmt.Fprintln(w, "send")
+}
+
+// updateZoneFile replaces the block managed by 'scw _dns' in a zone file,
+// or appends it if the file doesn't have one yet, and increments the serial of its SOA record when the block changed
+func updateZoneFile(path, block string) error {
+	content, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	zone := string(content)
+
+	begin := strings.Index(zone, dnsBlockBegin)
+	end := strings.Index(zone, dnsBlockEnd)
+	switch {
+	case begin >= 0 && end > begin:
+		zone = zone[:begin] + block + strings.TrimPrefix(zone[end+len(dnsBlockEnd):], "\n")
+	case begin >= 0 || end >= 0:
+		return fmt.Errorf("%s: unbalanced '%s' / '%s' markers", path, dnsBlockBegin, dnsBlockEnd)
+	default:
+		if zone != "" && !strings.HasSuffix(zone, "\n") {
+			zone += "\n"
+		}
+		zone += block
+	}
+	if zone == string(content) {
+		return nil
+	}
+	zone, ok := bumpSOASerial(zone)
+	if !ok {
+		logrus.Warnf("%s has no SOA record, increment the serial of the zone to publish the records", path)
+	}
+	return ioutil.WriteFile(path, []byte(zone), 0644)
+}
+
+// dnsSOASerial matches a SOA record up to its serial, after the primary nameserver and the mailbox
+var dnsSOASerial = regexp.MustCompile(`(?i)(\sSOA\s+\S+\s+\S+\s*(?:\(\s*)?(?:;[^\n]*\n\s*)*)(\d+)`)
+
+// bumpSOASerial increments the serial of the SOA record of a zone file, it reports false when there is none
+func bumpSOASerial(zone string) (string, bool) {
+	match := dnsSOASerial.FindStringSubmatchIndex(zone)
+	if match == nil {
+		return zone, false
+	}
+	serial, err := strconv.ParseUint(zone[match[4]:match[5]], 10, 32)
+	if err != nil {
+		return zone, false
+	}
+	// serials are compared with the RFC 1982 arithmetic, they wrap around at 2^32
+	serial = (serial + 1) % (1 << 32)
+	return zone[:match[4]] + strconv.FormatUint(serial, 10) + zone[match[5]:], true
+}
+
+type dnsRecords []DNSRecord
+
+func (r dnsRecords) Len() int      { return len(r) }
+func (r dnsRecords) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r dnsRecords) Less(i, j int) bool {
+	if r[i].Name != r[j].Name {
+		return r[i].Name < r[j].Name
+	}
+	return r[i].Type < r[j].Type
+}
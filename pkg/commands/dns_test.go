@@ -0,0 +1,108 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBumpSOASerial(t *testing.T) {
+	Convey("Testing bumpSOASerial()", t, func() {
+		zone, ok := bumpSOASerial("@ IN SOA ns1.example.com. admin.example.com. 2017030101 3600 600 86400 300\n")
+		So(ok, ShouldBeTrue)
+		So(zone, ShouldEqual, "@ IN SOA ns1.example.com. admin.example.com. 2017030102 3600 600 86400 300\n")
+
+		zone, ok = bumpSOASerial("@\tIN\tSOA\tns1.example.com. admin.example.com. (\n\t; serial\n\t41 ; serial\n\t3600 )\n")
+		So(ok, ShouldBeTrue)
+		So(zone, ShouldEqual, "@\tIN\tSOA\tns1.example.com. admin.example.com. (\n\t; serial\n\t42 ; serial\n\t3600 )\n")
+
+		zone, ok = bumpSOASerial("@ SOA ns1. admin. 4294967295 3600 600 86400 300\n")
+		So(ok, ShouldBeTrue)
+		So(zone, ShouldEqual, "@ SOA ns1. admin. 0 3600 600 86400 300\n")
+
+		_, ok = bumpSOASerial("www IN A 1.2.3.4\n")
+		So(ok, ShouldBeFalse)
+	})
+}
+
+func TestUpdateZoneFile(t *testing.T) {
+	Convey("Testing updateZoneFile()", t, func() {
+		dir, err := ioutil.TempDir("", "scw-dns")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "db.example.com")
+		So(ioutil.WriteFile(path, []byte("@ IN SOA ns1. admin. 1 3600 600 86400 300\n"), 0644), ShouldBeNil)
+
+		block := dnsBlockBegin + "\nweb\t300\tIN\tA\t1.2.3.4\n" + dnsBlockEnd + "\n"
+		So(updateZoneFile(path, block), ShouldBeNil)
+		So(updateZoneFile(path, block), ShouldBeNil)
+		content, err := ioutil.ReadFile(path)
+		So(err, ShouldBeNil)
+		// the serial is only incremented when the records change
+		So(string(content), ShouldEqual, "@ IN SOA ns1. admin. 2 3600 600 86400 300\n"+block)
+	})
+}
+
+func TestWriteNSUpdate(t *testing.T) {
+	Convey("Testing writeNSUpdate()", t, func() {
+		records := []DNSRecord{
+			{Name: "web", Type: "A", Address: "1.2.3.4"},
+			{Name: "web", Type: "AAAA", Address: "2001:db8::1"},
+		}
+		var buf bytes.Buffer
+		writeNSUpdate(&buf, records, []string{"old", "web"}, DNSArgs{Zone: "example.com.", TTL: 300})
+		So(buf.String(), ShouldEqual, `zone example.com.
+update delete old.example.com. A
+update delete old.example.com. AAAA
+update delete web.example.com. A
+update add web.example.com. 300 A 1.2.3.4
+update delete web.example.com. AAAA
+update add web.example.com. 300 AAAA 2001:db8::1
+send
+`)
+	})
+}
+
+func TestRunNSUpdate(t *testing.T) {
+	Convey("Testing runNSUpdate()", t, func() {
+		home, err := ioutil.TempDir("", "scw-dns")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(home)
+		defer os.Setenv("HOME", os.Getenv("HOME"))
+		os.Setenv("HOME", home)
+		defer func(command string) { nsupdateCommand = command }(nsupdateCommand)
+
+		ctx := CommandContext{Streams: Streams{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}}}
+		records := []DNSRecord{{Name: "web", Type: "A", Address: "1.2.3.4"}}
+		args := DNSArgs{Zone: "example.com", TTL: 300}
+
+		// printing the script does not record the names as published
+		So(runNSUpdate(ctx, records, args), ShouldBeNil)
+		published, err := loadDNSNames()
+		So(err, ShouldBeNil)
+		So(published["example.com"], ShouldBeEmpty)
+
+		// neither does a failed nsupdate
+		args.Apply = true
+		nsupdateCommand = "false"
+		So(runNSUpdate(ctx, records, args), ShouldNotBeNil)
+		published, err = loadDNSNames()
+		So(err, ShouldBeNil)
+		So(published["example.com"], ShouldBeEmpty)
+
+		nsupdateCommand = "cat"
+		So(runNSUpdate(ctx, records, args), ShouldBeNil)
+		So(ctx.Stdout.(*bytes.Buffer).String(), ShouldContainSubstring, "update add web.example.com. 300 A 1.2.3.4\n")
+		published, err = loadDNSNames()
+		So(err, ShouldBeNil)
+		So(published["example.com"], ShouldResemble, []string{"web"})
+	})
+}
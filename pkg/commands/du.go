@@ -0,0 +1,213 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/docker/go-units"
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/scaleway/scaleway-cli/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// DuArgs are flags for the `RunDu` function
+type DuArgs struct {
+	Servers   []string
+	Gateway   string
+	SSHUser   string
+	SSHPort   int
+	Path      string
+	Threshold int
+}
+
+// DiskUsage is a filesystem usage line of `df`
+type DiskUsage struct {
+	Filesystem string
+	Size       int64
+	Used       int64
+	Available  int64
+	Percent    int
+	MountPoint string
+}
+
+// DirectoryUsage is a directory usage line of `du`
+type DirectoryUsage struct {
+	Size int64
+	Path string
+}
+
+type duResult struct {
+	server      *api.ScalewayServer
+	disks       []DiskUsage
+	directories []DirectoryUsage
+}
+
+// ParseDf parses the output of `df -P -k`
+func ParseDf(output []byte) ([]DiskUsage, error) {
+	var disks []DiskUsage
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for first := true; scanner.Scan(); first = false {
+		fields := strings.Fields(scanner.Text())
+		if first || len(fields) < 6 {
+			continue
+		}
+		var disk DiskUsage
+		var err error
+		disk.Filesystem = fields[0]
+		disk.MountPoint = strings.Join(fields[5:], " ")
+		if disk.Size, err = strconv.ParseInt(fields[1], 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid df line %q", scanner.Text())
+		}
+		if disk.Used, err = strconv.ParseInt(fields[2], 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid df line %q", scanner.Text())
+		}
+		if disk.Available, err = strconv.ParseInt(fields[3], 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid df line %q", scanner.Text())
+		}
+		if disk.Percent, err = strconv.Atoi(strings.TrimSuffix(fields[4], "%")); err != nil {
+			return nil, fmt.Errorf("invalid df line %q", scanner.Text())
+		}
+		disk.Size *= 1024
+		disk.Used *= 1024
+		disk.Available *= 1024
+		disks = append(disks, disk)
+	}
+	return disks, scanner.Err()
+}
+
+// ParseDu parses the output of `du -k`
+func ParseDu(output []byte) ([]DirectoryUsage, error) {
+	var directories []DirectoryUsage
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), "\t", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		size, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid du line %q", scanner.Text())
+		}
+		directories = append(directories, DirectoryUsage{Size: size * 1024, Path: fields[1]})
+	}
+	return directories, scanner.Err()
+}
+
+// RunDu is the handler for 'scw _du'
+func RunDu(ctx CommandContext, args DuArgs) error {
+	if args.Gateway == "" {
+		args.Gateway = ctx.Getenv("SCW_GATEWAY")
+	}
+
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	results := []duResult{}
+	hasError := false
+	for _, needle := range args.Servers {
+		wg.Add(1)
+		go func(needle string) {
+			defer wg.Done()
+			result, err := duServer(ctx, args, needle)
+			lock.Lock()
+			defer lock.Unlock()
+			if err != nil {
+				logrus.Errorf("failed to get disk usage of %s: %v", needle, err)
+				hasError = true
+				return
+			}
+			results = append(results, *result)
+		}(needle)
+	}
+	wg.Wait()
+	sort.Slice(results, func(i, j int) bool { return results[i].server.Name < results[j].server.Name })
+
+	w := tabwriter.NewWriter(ctx.Stdout, 20, 1, 3, ' ', 0)
+	if args.Path == "" {
+		fmt.Fprintf(w, "SERVER\tFILESYSTEM\tSIZE\tUSED\tAVAIL\tUSE%%\tMOUNTED ON\n")
+		for _, result := range results {
+			for _, disk := range result.disks {
+				percent := fmt.Sprintf("%d%%", disk.Percent)
+				if args.Threshold > 0 && disk.Percent >= args.Threshold {
+					percent += " !"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", result.server.Name, disk.Filesystem,
+					units.HumanSize(float64(disk.Size)), units.HumanSize(float64(disk.Used)), units.HumanSize(float64(disk.Available)),
+					percent, disk.MountPoint)
+			}
+		}
+	} else {
+		fmt.Fprintf(w, "SERVER\tSIZE\tPATH\n")
+		for _, result := range results {
+			sort.Slice(result.directories, func(i, j int) bool { return result.directories[i].Size > result.directories[j].Size })
+			for _, directory := range result.directories {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", result.server.Name, units.HumanSize(float64(directory.Size)), directory.Path)
+			}
+		}
+	}
+	w.Flush()
+
+	if hasError {
+		return fmt.Errorf("at least 1 server failed to report its disk usage")
+	}
+	for _, result := range results {
+		for _, disk := range result.disks {
+			if args.Threshold > 0 && disk.Percent >= args.Threshold {
+				logrus.Warnf("%s: %s is %d%% full", result.server.Name, disk.MountPoint, disk.Percent)
+			}
+		}
+	}
+	return nil
+}
+
+func duServer(ctx CommandContext, args DuArgs, needle string) (*duResult, error) {
+	serverID, err := ctx.API.GetServerID(needle)
+	if err != nil {
+		return nil, err
+	}
+	server, err := ctx.API.GetServer(serverID)
+	if err != nil {
+		return nil, api.Wrapf(err, "failed to get server information for %s", serverID)
+	}
+
+	var gateway string
+	if args.Gateway != serverID && args.Gateway != needle {
+		gateway, err = api.ResolveGateway(ctx.API, args.Gateway)
+		if err != nil {
+			return nil, api.Wrapf(err, "cannot resolve Gateway '%s'", args.Gateway)
+		}
+	}
+
+	command := "df -P -k -x tmpfs -x devtmpfs"
+	if args.Path != "" {
+		command = fmt.Sprintf("du -x -k --max-depth=1 %s 2>/dev/null", utils.ShellQuote(args.Path))
+	}
+	sshCommand := utils.NewSSHExecCmd(server.PublicAddress.IP, server.PrivateIP, args.SSHUser, args.SSHPort, false, []string{command}, gateway, false)
+	logrus.Debugf("Executing: %s", sshCommand)
+	out, err := exec.Command("ssh", sshCommand.Slice()[1:]...).Output()
+	// du exits with an error on unreadable directories, keep what it managed to read
+	if err != nil && (args.Path == "" || len(out) == 0) {
+		return nil, err
+	}
+
+	result := &duResult{server: server}
+	if args.Path == "" {
+		result.disks, err = ParseDf(out)
+	} else {
+		result.directories, err = ParseDu(out)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,41 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseDf(t *testing.T) {
+	Convey("Testing ParseDf()", t, func() {
+		output := []byte(`Filesystem     1024-blocks     Used Available Capacity Mounted on
+/dev/vda        48750348 44128336   2113860      96% /
+/dev/vdb        48750348   102400  46147948       1% /mnt/my data
+`)
+		disks, err := ParseDf(output)
+		So(err, ShouldBeNil)
+		So(len(disks), ShouldEqual, 2)
+		So(disks[0].Filesystem, ShouldEqual, "/dev/vda")
+		So(disks[0].Size, ShouldEqual, 48750348*1024)
+		So(disks[0].Percent, ShouldEqual, 96)
+		So(disks[0].MountPoint, ShouldEqual, "/")
+		So(disks[1].MountPoint, ShouldEqual, "/mnt/my data")
+
+		_, err = ParseDf([]byte("Filesystem 1024-blocks Used Available Capacity Mounted on\n/dev/vda x y z 1% /\n"))
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestParseDu(t *testing.T) {
+	Convey("Testing ParseDu()", t, func() {
+		directories, err := ParseDu([]byte("1024\t/var/log\n2048\t/var\n"))
+		So(err, ShouldBeNil)
+		So(len(directories), ShouldEqual, 2)
+		So(directories[0].Path, ShouldEqual, "/var/log")
+		So(directories[1].Size, ShouldEqual, 2048*1024)
+	})
+}
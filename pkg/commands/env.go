@@ -0,0 +1,85 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/scaleway/scaleway-cli/pkg/config"
+)
+
+// EnvArgs are flags for the `RunEnv` function
+type EnvArgs struct {
+	Profile string
+	Shell   string
+}
+
+// envVariable is an exported variable, in the order they are printed
+type envVariable struct {
+	Name  string
+	Value string
+}
+
+// credentialsEnv returns the variables read by terraform, packer and the Scaleway SDKs
+func credentialsEnv(organization, token, region string) []envVariable {
+	endpoint := api.ComputeAPIPar1
+	if region == "ams1" {
+		endpoint = api.ComputeAPIAms1
+	}
+	return []envVariable{
+		{"SCALEWAY_ORGANIZATION", organization},
+		{"SCALEWAY_TOKEN", token},
+		// packer names the token differently
+		{"SCALEWAY_API_TOKEN", token},
+		{"SCALEWAY_REGION", region},
+		{"SCALEWAY_API_URL", endpoint},
+		{"SCALEWAY_ACCOUNT_URL", api.AccountAPI},
+	}
+}
+
+// shellExport returns the statement exporting variable in shell, sh, fish or powershell
+func shellExport(shell string, variable envVariable) (string, error) {
+	switch shell {
+	case "", "sh", "bash", "zsh":
+		return fmt.Sprintf("export %s='%s'", variable.Name, strings.Replace(variable.Value, "'", `'\''`, -1)), nil
+	case "fish":
+		value := strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(variable.Value)
+		return fmt.Sprintf("set -gx %s '%s';", variable.Name, value), nil
+	case "powershell":
+		return fmt.Sprintf("$Env:%s = '%s'", variable.Name, strings.Replace(variable.Value, "'", "''", -1)), nil
+	}
+	return "", fmt.Errorf("unsupported shell %q, expected sh, fish or powershell", shell)
+}
+
+// RunEnv is the handler for 'scw _env'
+func RunEnv(ctx CommandContext, args EnvArgs) error {
+	organization, token, region := ctx.API.OrganizationID(), ctx.API.AuthToken(), ctx.API.CurrentRegion()
+	if args.Profile != "" {
+		cfg, err := config.GetConfig(ctx.ConfigPath)
+		if err != nil {
+			return api.Wrapf(err, "unable to open .scwrc config file")
+		}
+		_, profiles := cfg.AllProfiles()
+		profile, ok := profiles[args.Profile]
+		if !ok {
+			return fmt.Errorf("no such profile %q in %s", args.Profile, ctx.ConfigPath)
+		}
+		organization, token = profile.Organization, profile.Token
+		if len(profile.Regions) > 0 {
+			region = profile.Regions[0]
+		}
+	}
+
+	for _, variable := range credentialsEnv(organization, token, region) {
+		line, err := shellExport(args.Shell, variable)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(ctx.Stdout, line)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestShellExport(t *testing.T) {
+	Convey("Testing shellExport()", t, func() {
+		variable := envVariable{"SCALEWAY_TOKEN", "it's"}
+
+		line, err := shellExport("sh", variable)
+		So(err, ShouldBeNil)
+		So(line, ShouldEqual, `export SCALEWAY_TOKEN='it'\''s'`)
+
+		line, err = shellExport("fish", variable)
+		So(err, ShouldBeNil)
+		So(line, ShouldEqual, `set -gx SCALEWAY_TOKEN 'it\'s';`)
+
+		line, err = shellExport("powershell", variable)
+		So(err, ShouldBeNil)
+		So(line, ShouldEqual, `$Env:SCALEWAY_TOKEN = 'it''s'`)
+
+		_, err = shellExport("csh", variable)
+		So(err, ShouldNotBeNil)
+	})
+}
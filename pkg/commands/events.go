@@ -6,9 +6,8 @@ package commands
 
 import (
 	"fmt"
-	"time"
 
-	"github.com/docker/go-units"
+	"github.com/scaleway/scaleway-cli/pkg/api"
 )
 
 // EventsArgs are arguments passed to `RunEvents`
@@ -18,25 +17,16 @@ type EventsArgs struct{}
 func RunEvents(ctx CommandContext, args EventsArgs) error {
 	events, err := ctx.API.GetTasks()
 	if err != nil {
-		return fmt.Errorf("unable to fetch tasks from the Scaleway API: %v", err)
+		return api.Wrapf(err, "unable to fetch tasks from the Scaleway API")
 	}
 
 	for _, event := range *events {
-		startedAt, err := time.Parse("2006-01-02T15:04:05.000000+00:00", event.StartDate)
-		if err != nil {
-			return fmt.Errorf("unable to parse started date from the Scaleway API: %v", err)
-		}
-
 		terminatedAt := ""
-		if event.TerminationDate != "" {
-			terminatedAtTime, err := time.Parse("2006-01-02T15:04:05.000000+00:00", event.TerminationDate)
-			if err != nil {
-				return fmt.Errorf("unable to parse terminated date from the Scaleway API: %v", err)
-			}
-			terminatedAt = units.HumanDuration(time.Now().UTC().Sub(terminatedAtTime))
+		if !event.TerminationDate.Value().IsZero() {
+			terminatedAt = ctx.FormatTime(event.TerminationDate.Value())
 		}
 
-		fmt.Fprintf(ctx.Stdout, "%s %s: %s (%s %d) %s\n", startedAt, event.HrefFrom, event.Description, event.Status, event.Progress, terminatedAt)
+		fmt.Fprintf(ctx.Stdout, "%s %s: %s (%s %d) %s\n", ctx.FormatTime(event.StartDate.Value()), event.HrefFrom, event.Description, event.Status, event.Progress, terminatedAt)
 	}
 	return nil
 }
@@ -5,7 +5,12 @@
 package commands
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"os/exec"
+	"syscall"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -17,6 +22,7 @@ import (
 // ExecArgs are flags for the `RunExec` function
 type ExecArgs struct {
 	Timeout                float64
+	IdleTimeout            float64
 	Wait                   bool
 	Gateway                string
 	Server                 string
@@ -24,11 +30,67 @@ type ExecArgs struct {
 	SSHUser                string
 	SSHPort                int
 	EnableSSHKeyForwarding bool
+	Script                 string
+	Shell                  string
+	Sudo                   bool
+}
+
+// scriptCommand returns the remote command executing a script read on stdin, COMMAND being its arguments
+func scriptCommand(args ExecArgs) []string {
+	shell := args.Shell
+	if shell == "" {
+		shell = "sh"
+	}
+	command := []string{shell, "-s", "--"}
+	if args.Sudo {
+		command = append([]string{"sudo", "-n"}, command...)
+	}
+	for _, arg := range args.Command {
+		command = append(command, utils.ShellQuote(arg))
+	}
+	return command
+}
+
+// ExecTimeoutExitCode is the exit code of 'scw exec' when the command is killed by --timeout or --idle-timeout
+const ExecTimeoutExitCode = 124
+
+// remoteExitCode returns the exit status of the remote command when err is its failure,
+// 255 is not one as ssh uses it for its own errors
+func remoteExitCode(err error) (ExitCodeError, bool) {
+	if err == utils.ErrSSHTimeout || err == utils.ErrSSHIdleTimeout {
+		logrus.Errorf("Remote command killed: %v", err)
+		return ExitCodeError{Code: ExecTimeoutExitCode}, true
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.ExitStatus() != 255 {
+			logrus.Debugf("Remote command exited with status %d", status.ExitStatus())
+			return ExitCodeError{Code: status.ExitStatus()}, true
+		}
+	}
+	return ExitCodeError{}, false
 }
 
 // RunExec is the handler for 'scw exec'
 func RunExec(ctx CommandContext, args ExecArgs) error {
 	var fingerprints []string
+	var script io.Reader
+
+	if args.Script != "" {
+		// read the script before anything else, "-" may be a heredoc
+		var data []byte
+		var err error
+		if args.Script == "-" {
+			data, err = ioutil.ReadAll(ctx.Stdin)
+		} else {
+			data, err = ioutil.ReadFile(args.Script)
+		}
+		if err != nil {
+			return api.Wrapf(err, "cannot read script")
+		}
+		script = bytes.NewReader(data)
+	} else if args.Sudo && len(args.Command) > 0 {
+		args.Command = append([]string{"sudo"}, args.Command...)
+	}
 
 	done := make(chan struct{})
 
@@ -53,7 +115,7 @@ func RunExec(ctx CommandContext, args ExecArgs) error {
 	} else {
 		gateway, err = api.ResolveGateway(ctx.API, args.Gateway)
 		if err != nil {
-			return fmt.Errorf("Cannot resolve Gateway '%s': %v", args.Gateway, err)
+			return api.Wrapf(err, "Cannot resolve Gateway '%s'", args.Gateway)
 		}
 		if gateway != "" {
 			logrus.Debugf("The server will be accessed using the gateway '%s' as a SSH relay", gateway)
@@ -73,7 +135,7 @@ func RunExec(ctx CommandContext, args ExecArgs) error {
 		logrus.Debugf("scw won't wait for the server to be ready, if it is not, the command will fail")
 		server, err = ctx.API.GetServer(serverID)
 		if err != nil {
-			rerr := fmt.Errorf("Failed to get server information for %s: %v", serverID, err)
+			rerr := api.Wrapf(err, "Failed to get server information for %s", serverID)
 			if err.Error() == `"`+serverID+`" not found` {
 				return fmt.Errorf("%v\nmaybe try to flush the cache with : scw _flush-cache", rerr)
 			}
@@ -85,26 +147,36 @@ func RunExec(ctx CommandContext, args ExecArgs) error {
 		logrus.Warn(`Your host has no public IP address, you should use '--gateway', see 'scw help exec'`)
 	}
 
-	// --timeout
-	if args.Timeout > 0 {
-		logrus.Debugf("Setting up a global timeout of %d seconds", args.Timeout)
-		// FIXME: avoid use of log.Fatalf here
-		go func() {
-			time.Sleep(time.Duration(args.Timeout*1000) * time.Millisecond)
-			logrus.Fatalf("Operation timed out")
-		}()
+	// --timeout, --idle-timeout
+	timeouts := utils.SSHTimeouts{
+		Timeout: time.Duration(args.Timeout*1000) * time.Millisecond,
+		Idle:    time.Duration(args.IdleTimeout*1000) * time.Millisecond,
 	}
 
 	<-done
 	if len(fingerprints) > 0 {
 		for i := range fingerprints {
-			fmt.Fprintf(ctx.Stdout, "%s\n", fingerprints[i])
+			fmt.Fprintf(ctx.Stderr, "%s\n", fingerprints[i])
 		}
 	}
 	logrus.Debugf("PublicDNS %s", serverID+api.URLPublicDNS)
 	logrus.Debugf("PrivateDNS %s", serverID+api.URLPrivateDNS)
-	if err = utils.SSHExec(server.PublicAddress.IP, server.PrivateIP, args.SSHUser, args.SSHPort, args.Command, !args.Wait, gateway, args.EnableSSHKeyForwarding); err != nil {
-		return fmt.Errorf("Failed to run the command: %v", err)
+	if script != nil {
+		err = utils.SSHExecStdinWithTimeouts(server.PublicAddress.IP, server.PrivateIP, args.SSHUser, args.SSHPort, scriptCommand(args), !args.Wait, gateway, script, timeouts)
+		if exitCode, ok := remoteExitCode(err); ok {
+			return exitCode
+		}
+		if err != nil {
+			return api.Wrapf(err, "Failed to run the script")
+		}
+	} else {
+		err = utils.SSHExecWithTimeouts(server.PublicAddress.IP, server.PrivateIP, args.SSHUser, args.SSHPort, args.Command, !args.Wait, gateway, args.EnableSSHKeyForwarding, timeouts)
+		if exitCode, ok := remoteExitCode(err); ok {
+			return exitCode
+		}
+		if err != nil {
+			return api.Wrapf(err, "Failed to run the command")
+		}
 	}
 
 	logrus.Debugf("Command successfully executed")
@@ -0,0 +1,28 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"fmt"
+	"os/exec"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRemoteExitCode(t *testing.T) {
+	Convey("Testing remoteExitCode()", t, func() {
+		exitCode, ok := remoteExitCode(exec.Command("sh", "-c", "exit 3").Run())
+		So(ok, ShouldBeTrue)
+		So(exitCode.Code, ShouldEqual, 3)
+
+		_, ok = remoteExitCode(exec.Command("sh", "-c", "exit 255").Run())
+		So(ok, ShouldBeFalse)
+		_, ok = remoteExitCode(fmt.Errorf("server is not ready, try again later"))
+		So(ok, ShouldBeFalse)
+		_, ok = remoteExitCode(nil)
+		So(ok, ShouldBeFalse)
+	})
+}
@@ -0,0 +1,289 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/scaleway/scaleway-cli/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// checksumTagPrefix prefixes the server tag recording the SHA256 of the last exported or imported tarball
+const checksumTagPrefix = "scw-sha256:"
+
+// sha256sumLine matches the output of `sha256sum` reading stdin
+var sha256sumLine = regexp.MustCompile(`^([0-9a-f]{64})  -$`)
+
+// ExportArgs are flags for the `RunExport` function
+type ExportArgs struct {
+	Source      string
+	Destination string
+	Tag         bool
+	Gateway     string
+	SSHUser     string
+	SSHPort     int
+}
+
+// ImportArgs are flags for the `RunImport` function
+type ImportArgs struct {
+	Source      string
+	Destination string
+	SHA256      string
+	Tag         bool
+	Gateway     string
+	SSHUser     string
+	SSHPort     int
+}
+
+// checksumCommand wraps a remote pipeline so the SHA256 of the tarball it reads or writes is printed on stderr,
+// the tarball goes through `tee` on a fifo read by `sha256sum`.
+// The pipeline fails when the producer fails, without relying on pipefail which /bin/sh may lack:
+// a failed producer leaves a marker file next to the fifo, both are removed on exit
+func checksumCommand(producer, consumer string) (string, error) {
+	random := make([]byte, 8)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	fifo := fmt.Sprintf("/tmp/scw-sha256-%x.fifo", random)
+	failed := fifo + ".failed"
+	return fmt.Sprintf("trap 'rm -f %s %s' EXIT; mkfifo %s && { sha256sum < %s >&2 & } && { %s || touch %s; } | tee %s | %s && wait && test ! -e %s",
+		fifo, failed, fifo, fifo, producer, failed, fifo, consumer, failed), nil
+}
+
+// remoteChecksum forwards the stderr of a remote command and extracts the SHA256 printed by `checksumCommand`
+type remoteChecksum struct {
+	writer *io.PipeWriter
+	digest string
+	done   sync.WaitGroup
+}
+
+func newRemoteChecksum(stderr io.Writer) *remoteChecksum {
+	reader, writer := io.Pipe()
+	checksum := &remoteChecksum{writer: writer}
+	checksum.done.Add(1)
+	go func() {
+		defer checksum.done.Done()
+		scanner := bufio.NewScanner(reader)
+		for scanner.Scan() {
+			if match := sha256sumLine.FindStringSubmatch(scanner.Text()); match != nil {
+				checksum.digest = match[1]
+				continue
+			}
+			fmt.Fprintln(stderr, scanner.Text())
+		}
+		io.Copy(ioutil.Discard, reader)
+	}()
+	return checksum
+}
+
+func (c *remoteChecksum) Write(p []byte) (int, error) {
+	return c.writer.Write(p)
+}
+
+// Digest waits for the end of the remote command and returns the SHA256 it computed
+func (c *remoteChecksum) Digest() (string, error) {
+	c.writer.Close()
+	c.done.Wait()
+	if c.digest == "" {
+		return "", fmt.Errorf("the server did not report any checksum, is sha256sum installed?")
+	}
+	return c.digest, nil
+}
+
+// tagChecksum records digest in the tags of server, replacing the previous one
+func tagChecksum(ctx CommandContext, server *api.ScalewayServer, digest string) error {
+	tags := []string{}
+	for _, tag := range server.Tags {
+		if !strings.HasPrefix(tag, checksumTagPrefix) {
+			tags = append(tags, tag)
+		}
+	}
+	tags = append(tags, checksumTagPrefix+digest)
+	return ctx.API.PatchServer(server.Identifier, api.ScalewayServerPatchDefinition{Tags: &tags})
+}
+
+// readChecksumFile returns the digest of a `sha256sum` formatted file
+func readChecksumFile(path string) (string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(string(content))
+	if len(fields) == 0 || len(fields[0]) != sha256.Size*2 {
+		return "", fmt.Errorf("invalid checksum file %s", path)
+	}
+	return strings.ToLower(fields[0]), nil
+}
+
+// RunExport is the handler for 'scw _export'
+func RunExport(ctx CommandContext, args ExportArgs) error {
+	uri := args.Source
+	if !strings.Contains(uri, ":") {
+		uri += ":/"
+	}
+	source, err := newCpEndpoint(ctx, uri, CpArgs{Gateway: args.Gateway, SSHUser: args.SSHUser, SSHPort: args.SSHPort})
+	if err != nil {
+		return err
+	}
+
+	// pseudo filesystems and mounts are skipped when exporting a whole rootfs
+	tarCommand := fmt.Sprintf("tar -C %s --one-file-system --numeric-owner -cf - .", utils.ShellQuote(source.path))
+	remoteCommand, err := checksumCommand(tarCommand, "cat")
+	if err != nil {
+		return err
+	}
+
+	var output io.Writer = ctx.Stdout
+	if args.Destination != "-" {
+		file, err := os.Create(args.Destination)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		output = file
+	}
+
+	hash := sha256.New()
+	checksum := newRemoteChecksum(ctx.Stderr)
+	spawn := source.command(remoteCommand)
+	spawn.Stdout = io.MultiWriter(output, hash)
+	spawn.Stderr = checksum
+	logrus.Infof("Exporting %s:%s", source.server.Name, source.path)
+	err = spawn.Run()
+	remoteDigest, digestErr := checksum.Digest()
+	if err == nil {
+		err = digestErr
+	}
+	digest := hex.EncodeToString(hash.Sum(nil))
+	if err == nil && digest != remoteDigest {
+		err = fmt.Errorf("checksum mismatch, the server sent %s but %s was received", remoteDigest, digest)
+	}
+	if err != nil {
+		if args.Destination != "-" {
+			os.Remove(args.Destination)
+		}
+		return api.Wrapf(err, "cannot export %s", args.Source)
+	}
+
+	if args.Destination != "-" {
+		sidecar := fmt.Sprintf("%s  %s\n", digest, filepath.Base(args.Destination))
+		if err = ioutil.WriteFile(args.Destination+".sha256", []byte(sidecar), 0644); err != nil {
+			return err
+		}
+	}
+	logrus.Infof("SHA256: %s", digest)
+	if args.Tag {
+		if err = tagChecksum(ctx, source.server, digest); err != nil {
+			return api.Wrapf(err, "cannot record the checksum in the tags of %s", source.server.Name)
+		}
+	}
+	if args.Destination != "-" {
+		fmt.Fprintln(ctx.Stdout, digest)
+	}
+	return nil
+}
+
+// RunImport is the handler for 'scw _import'
+func RunImport(ctx CommandContext, args ImportArgs) error {
+	destination, err := newCpEndpoint(ctx, args.Destination, CpArgs{Gateway: args.Gateway, SSHUser: args.SSHUser, SSHPort: args.SSHPort})
+	if err != nil {
+		return err
+	}
+	if destination.server == nil {
+		return fmt.Errorf("invalid destination %q, must be SERVER:PATH", args.Destination)
+	}
+
+	expected := strings.ToLower(args.SHA256)
+	var input io.Reader = ctx.Stdin
+	var file *os.File
+	if args.Source != "-" {
+		if expected == "" {
+			if expected, err = readChecksumFile(args.Source + ".sha256"); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		if file, err = os.Open(args.Source); err != nil {
+			return err
+		}
+		defer file.Close()
+	} else if expected != "" {
+		// stdin is spooled to a temporary file so it is verified before anything is extracted
+		if file, err = ioutil.TempFile("", "scw-import-"); err != nil {
+			return err
+		}
+		defer os.Remove(file.Name())
+		defer file.Close()
+		if _, err = io.Copy(file, ctx.Stdin); err != nil {
+			return err
+		}
+		if _, err = file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+	}
+
+	// the tarball is verified before anything is sent to the server
+	if file != nil {
+		if expected != "" {
+			hash := sha256.New()
+			if _, err = io.Copy(hash, file); err != nil {
+				return err
+			}
+			if digest := hex.EncodeToString(hash.Sum(nil)); digest != expected {
+				return fmt.Errorf("checksum mismatch for %s, expected %s but got %s", args.Source, expected, digest)
+			}
+			if _, err = file.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+		}
+		input = file
+	}
+
+	tarCommand := fmt.Sprintf("tar -C %s --numeric-owner -xf -", utils.ShellQuote(destination.path))
+	remoteCommand, err := checksumCommand("cat", tarCommand)
+	if err != nil {
+		return err
+	}
+
+	hash := sha256.New()
+	checksum := newRemoteChecksum(ctx.Stderr)
+	spawn := destination.command(remoteCommand)
+	spawn.Stdin = io.TeeReader(input, hash)
+	spawn.Stderr = checksum
+	logrus.Infof("Importing %s to %s:%s", args.Source, destination.server.Name, destination.path)
+	err = spawn.Run()
+	remoteDigest, digestErr := checksum.Digest()
+	if err == nil {
+		err = digestErr
+	}
+	if err != nil {
+		return api.Wrapf(err, "cannot import %s", args.Source)
+	}
+	digest := hex.EncodeToString(hash.Sum(nil))
+	if remoteDigest != digest || (expected != "" && digest != expected) {
+		return fmt.Errorf("checksum mismatch, %s was sent but the server received %s", digest, remoteDigest)
+	}
+
+	logrus.Infof("SHA256: %s", digest)
+	if args.Tag {
+		if err = tagChecksum(ctx, destination.server, digest); err != nil {
+			return api.Wrapf(err, "cannot record the checksum in the tags of %s", destination.server.Name)
+		}
+	}
+	fmt.Fprintln(ctx.Stdout, digest)
+	return nil
+}
@@ -0,0 +1,61 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestChecksumCommand(t *testing.T) {
+	Convey("Testing checksumCommand() run by a local shell", t, func() {
+		run := func(producer string) (string, string, error, string) {
+			command, err := checksumCommand(producer, "cat")
+			So(err, ShouldBeNil)
+			var stdout, stderr bytes.Buffer
+			spawn := exec.Command("/bin/sh", "-e", "-c", command)
+			spawn.Stdout, spawn.Stderr = &stdout, &stderr
+			err = spawn.Run()
+			return stdout.String(), stderr.String(), err, regexp.MustCompile(`/tmp/scw-sha256-[0-9a-f]+\.fifo`).FindString(command)
+		}
+
+		stdout, stderr, err, fifo := run("printf abc")
+		So(err, ShouldBeNil)
+		So(stdout, ShouldEqual, "abc")
+		So(strings.TrimSpace(stderr), ShouldEqual, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad  -")
+		_, err = os.Stat(fifo)
+		So(os.IsNotExist(err), ShouldBeTrue)
+
+		// a producer failing halfway fails the pipeline
+		_, _, err, fifo = run("printf abc; false")
+		So(err, ShouldNotBeNil)
+		_, err = os.Stat(fifo)
+		So(os.IsNotExist(err), ShouldBeTrue)
+		_, err = os.Stat(fifo + ".failed")
+		So(os.IsNotExist(err), ShouldBeTrue)
+	})
+}
+
+func TestRunImport_stdinMismatch(t *testing.T) {
+	Convey("Testing RunImport() verifying stdin before extracting it", t, func() {
+		fake := api.NewFakeScalewayAPI("orga")
+		fake.Servers = []api.ScalewayServer{{Identifier: "11111111-1111-1111-1111-111111111111", Name: "web", State: "running"}}
+		ctx := CommandContext{
+			Streams: Streams{Stdin: strings.NewReader("not the expected tarball"), Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}},
+			API:     fake,
+		}
+
+		err := RunImport(ctx, ImportArgs{Source: "-", Destination: "web:/mnt", SHA256: strings.Repeat("0", 64)})
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldStartWith, "checksum mismatch for -")
+	})
+}
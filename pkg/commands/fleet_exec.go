@@ -0,0 +1,241 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/scaleway/scaleway-cli/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// FleetExecArgs are arguments passed to `RunFleetExec`
+type FleetExecArgs struct {
+	Servers   []string
+	Filters   map[string]string
+	Command   []string
+	Jobs      int
+	OutputDir string
+	Gateway   string
+	SSHUser   string
+	SSHPort   int
+}
+
+// FleetExecHost is the outcome of the command on one server
+type FleetExecHost struct {
+	Server     string `json:"server"`
+	ID         string `json:"id"`
+	ExitCode   int    `json:"exit_code"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+	Stdout     string `json:"stdout,omitempty"`
+	Stderr     string `json:"stderr,omitempty"`
+}
+
+// FleetExecSummary is written to summary.json in the --output-dir directory
+type FleetExecSummary struct {
+	Command   string          `json:"command"`
+	StartDate time.Time       `json:"start_date"`
+	Succeeded int             `json:"succeeded"`
+	Failed    int             `json:"failed"`
+	Hosts     []FleetExecHost `json:"hosts"`
+}
+
+// fleetExecFileName returns a file name for the outputs of a server, unique even when names are shared
+func fleetExecFileName(server api.ScalewayServer) string {
+	name := regexp.MustCompile(`[^A-Za-z0-9._-]+`).ReplaceAllString(server.Name, "_")
+	return fmt.Sprintf("%s-%s", strings.Trim(name, "."), utils.TruncIf(server.Identifier, 8, true))
+}
+
+// prefixWriter prefixes each line written to w, the lines of concurrent writers sharing lock are not interleaved
+type prefixWriter struct {
+	w      io.Writer
+	prefix string
+	lock   *sync.Mutex
+	buffer []byte
+}
+
+func (p *prefixWriter) Write(data []byte) (int, error) {
+	p.buffer = append(p.buffer, data...)
+	for {
+		i := bytes.IndexByte(p.buffer, '\n')
+		if i < 0 {
+			return len(data), nil
+		}
+		if err := p.writeLine(p.buffer[:i+1]); err != nil {
+			return 0, err
+		}
+		p.buffer = p.buffer[i+1:]
+	}
+}
+
+// Flush writes the last line when it does not end with a newline
+func (p *prefixWriter) Flush() error {
+	if len(p.buffer) == 0 {
+		return nil
+	}
+	err := p.writeLine(append(p.buffer, '\n'))
+	p.buffer = nil
+	return err
+}
+
+func (p *prefixWriter) writeLine(line []byte) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	_, err := fmt.Fprintf(p.w, "%s: %s", p.prefix, line)
+	return err
+}
+
+// fleetExecHost runs the command on server, its output goes to stdout and stderr
+func fleetExecHost(args FleetExecArgs, server api.ScalewayServer, gateway string, stdout, stderr io.Writer) (int, error) {
+	if server.PublicAddress.IP == "" && gateway == "" {
+		return -1, errors.New("server does not have a public IP, use --gateway")
+	}
+	sshCommand := utils.NewSSHExecCmd(server.PublicAddress.IP, server.PrivateIP, args.SSHUser, args.SSHPort, false, args.Command, gateway, false)
+	logrus.Debugf("Executing: %s", sshCommand)
+	spawn := exec.Command("ssh", sshCommand.Slice()[1:]...)
+	spawn.Stdout = stdout
+	spawn.Stderr = stderr
+	err := spawn.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+			return status.ExitStatus(), fmt.Errorf("exit status %d", status.ExitStatus())
+		}
+		return -1, err
+	}
+	if err != nil {
+		return -1, err
+	}
+	return 0, nil
+}
+
+// RunFleetExec is the handler for 'scw _fleet-exec'
+func RunFleetExec(ctx CommandContext, args FleetExecArgs) error {
+	servers, err := selectServers(ctx, args.Servers, args.Filters)
+	if err != nil {
+		return err
+	}
+	if len(servers) == 0 {
+		return fmt.Errorf("no server matches the filters")
+	}
+	if args.Gateway == "" {
+		args.Gateway = ctx.Getenv("SCW_GATEWAY")
+	}
+	gateway, err := api.ResolveGateway(ctx.API, args.Gateway)
+	if err != nil {
+		return api.Wrapf(err, "cannot resolve Gateway '%s'", args.Gateway)
+	}
+	if args.OutputDir != "" {
+		if err = os.MkdirAll(args.OutputDir, 0755); err != nil {
+			return err
+		}
+	}
+	if args.Jobs < 1 {
+		args.Jobs = 1
+	}
+
+	summary := FleetExecSummary{
+		Command:   strings.Join(args.Command, " "),
+		StartDate: time.Now().UTC(),
+		Hosts:     make([]FleetExecHost, len(servers)),
+	}
+	lock := sync.Mutex{}
+	jobs := make(chan struct{}, args.Jobs)
+	wg := sync.WaitGroup{}
+	for i := range servers {
+		server := servers[i]
+		host := &summary.Hosts[i]
+		host.Server = server.Name
+		host.ID = server.Identifier
+
+		wg.Add(1)
+		jobs <- struct{}{}
+		go func() {
+			defer func() {
+				<-jobs
+				wg.Done()
+			}()
+			start := time.Now()
+			var stdout, stderr io.Writer
+			var files []*os.File
+			var writers []*prefixWriter
+			if args.OutputDir != "" {
+				name := fleetExecFileName(server)
+				host.Stdout, host.Stderr = name+".stdout", name+".stderr"
+				for _, path := range []string{host.Stdout, host.Stderr} {
+					file, err := os.Create(filepath.Join(args.OutputDir, path))
+					if err != nil {
+						for _, file := range files {
+							file.Close()
+						}
+						host.ExitCode, host.Error = -1, err.Error()
+						logrus.Errorf("%s: %v", server.Name, err)
+						return
+					}
+					files = append(files, file)
+				}
+				stdout, stderr = files[0], files[1]
+			} else {
+				writers = []*prefixWriter{
+					{w: ctx.Stdout, prefix: server.Name, lock: &lock},
+					{w: ctx.Stderr, prefix: server.Name, lock: &lock},
+				}
+				stdout, stderr = writers[0], writers[1]
+			}
+
+			exitCode, err := fleetExecHost(args, server, gateway, stdout, stderr)
+			for _, writer := range writers {
+				writer.Flush()
+			}
+			for _, file := range files {
+				file.Close()
+			}
+			host.ExitCode = exitCode
+			host.DurationMS = int64(time.Since(start) / time.Millisecond)
+			if err != nil {
+				host.Error = err.Error()
+				logrus.Errorf("%s: %v", server.Name, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, host := range summary.Hosts {
+		if host.Error != "" {
+			summary.Failed++
+		} else {
+			summary.Succeeded++
+		}
+	}
+	if args.OutputDir != "" {
+		out, err := json.MarshalIndent(summary, "", "  ")
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(args.OutputDir, "summary.json")
+		if err = ioutil.WriteFile(path, append(out, '\n'), 0644); err != nil {
+			return err
+		}
+		fmt.Fprintf(ctx.Stdout, "%d succeeded, %d failed, outputs written to %s\n", summary.Succeeded, summary.Failed, args.OutputDir)
+	}
+	if summary.Failed > 0 {
+		return fmt.Errorf("command failed on %d of %d servers", summary.Failed, len(servers))
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFleetExecFileName(t *testing.T) {
+	Convey("Testing fleetExecFileName()", t, func() {
+		server := api.ScalewayServer{Name: "web 1/prod", Identifier: "8a9c4a43-1f5e-4c2b-9f44-3b2a41d7e1c0"}
+		So(fleetExecFileName(server), ShouldEqual, "web_1_prod-8a9c4a43")
+	})
+}
+
+func TestPrefixWriter(t *testing.T) {
+	Convey("Testing prefixWriter", t, func() {
+		var out bytes.Buffer
+		writer := &prefixWriter{w: &out, prefix: "web1", lock: &sync.Mutex{}}
+		writer.Write([]byte("first\nsec"))
+		So(out.String(), ShouldEqual, "web1: first\n")
+		writer.Write([]byte("ond\nlast"))
+		writer.Flush()
+		So(out.String(), ShouldEqual, "web1: first\nweb1: second\nweb1: last\n")
+	})
+}
@@ -7,9 +7,9 @@ package commands
 import (
 	"fmt"
 	"text/tabwriter"
-	"time"
 
 	"github.com/docker/go-units"
+	"github.com/scaleway/scaleway-cli/pkg/api"
 	"github.com/scaleway/scaleway-cli/pkg/utils"
 )
 
@@ -29,7 +29,7 @@ func RunHistory(ctx CommandContext, args HistoryArgs) error {
 	}
 	image, err := ctx.API.GetImage(imageID.Identifier)
 	if err != nil {
-		return fmt.Errorf("cannot get image %s: %v", imageID.Identifier, err)
+		return api.Wrapf(err, "cannot get image %s", imageID.Identifier)
 	}
 
 	if args.Quiet {
@@ -43,11 +43,7 @@ func RunHistory(ctx CommandContext, args HistoryArgs) error {
 
 	identifier := utils.TruncIf(image.Identifier, 8, !args.NoTrunc)
 
-	creationDate, err := time.Parse("2006-01-02T15:04:05.000000+00:00", image.CreationDate)
-	if err != nil {
-		return fmt.Errorf("unable to parse creation date from the Scaleway API: %v", err)
-	}
-	creationDateStr := units.HumanDuration(time.Now().UTC().Sub(creationDate))
+	creationDateStr := ctx.FormatTime(image.CreationDate.Value())
 
 	volumeName := utils.TruncIf(image.RootVolume.Name, 25, !args.NoTrunc)
 	size := units.HumanSize(float64(image.RootVolume.Size))
@@ -6,13 +6,13 @@ package commands
 
 import (
 	"fmt"
+	"io"
 	"sort"
 	"strings"
 	"sync"
 	"text/tabwriter"
 	"time"
 
-	"github.com/docker/go-units"
 	"github.com/renstrom/fuzzysearch/fuzzy"
 	"github.com/scaleway/scaleway-cli/pkg/api"
 	"github.com/scaleway/scaleway-cli/pkg/utils"
@@ -21,14 +21,32 @@ import (
 
 // ImagesArgs are flags for the `RunImages` function
 type ImagesArgs struct {
-	All     bool
-	NoTrunc bool
-	Quiet   bool
-	Filters map[string]string
+	All          bool
+	NoTrunc      bool
+	Quiet        bool
+	Filters      map[string]string
+	CheckUpdates bool
+	Orphans      bool
+	Tree         bool
 }
 
 // RunImages is the handler for 'scw images'
 func RunImages(ctx CommandContext, args ImagesArgs) error {
+	if args.CheckUpdates {
+		return runImagesCheckUpdates(ctx, args)
+	}
+	if args.Orphans {
+		return runImagesOrphans(ctx, args)
+	}
+	if args.Tree {
+		return runImagesTree(ctx, args)
+	}
+
+	dates, err := parseDateFilters(args.Filters)
+	if err != nil {
+		return err
+	}
+
 	wg := sync.WaitGroup{}
 	chEntries := make(chan api.ScalewayImageInterface)
 	errChan := make(chan error, 10)
@@ -42,15 +60,12 @@ func RunImages(ctx CommandContext, args ImagesArgs) error {
 			defer wg.Done()
 			images, err := ctx.API.GetImages()
 			if err != nil {
-				errChan <- fmt.Errorf("unable to fetch images from the Scaleway API: %v", err)
+				errChan <- api.Wrapf(err, "unable to fetch images from the Scaleway API")
 				return
 			}
 			for _, val := range *images {
-				creationDate, err := time.Parse("2006-01-02T15:04:05.000000+00:00", val.CreationDate)
-				if err != nil {
-					errChan <- fmt.Errorf("unable to parse creation date from the Scaleway API: %v", err)
-					return
-				}
+				creationDate := val.CreationDate.Value()
+				modificationDate := val.ModificationDate.Value()
 				archAvailable := make(map[string]struct{})
 				zoneAvailable := make(map[string]struct{})
 
@@ -72,15 +87,16 @@ func RunImages(ctx CommandContext, args ImagesArgs) error {
 					archs = append(archs, k)
 				}
 				chEntries <- api.ScalewayImageInterface{
-					Type:         "image",
-					CreationDate: creationDate,
-					Identifier:   val.CurrentPublicVersion,
-					Name:         val.Name,
-					Tag:          "latest",
-					Organization: val.Organization.ID,
-					Public:       val.Public,
-					Region:       regions,
-					Archs:        archs,
+					Type:             "image",
+					CreationDate:     creationDate,
+					ModificationDate: modificationDate,
+					Identifier:       val.CurrentPublicVersion,
+					Name:             val.Name,
+					Tag:              "latest",
+					Organization:     val.Organization.ID,
+					Public:           val.Public,
+					Region:           regions,
+					Archs:            archs,
 				}
 			}
 		}()
@@ -93,24 +109,21 @@ func RunImages(ctx CommandContext, args ImagesArgs) error {
 				defer wg.Done()
 				snapshots, err := ctx.API.GetSnapshots()
 				if err != nil {
-					errChan <- fmt.Errorf("unable to fetch snapshots from the Scaleway API: %v", err)
+					errChan <- api.Wrapf(err, "unable to fetch snapshots from the Scaleway API")
 					return
 				}
 				for _, val := range *snapshots {
-					creationDate, err := time.Parse("2006-01-02T15:04:05.000000+00:00", val.CreationDate)
-					if err != nil {
-						errChan <- fmt.Errorf("unable to parse creation date from the Scaleway API: %v", err)
-						return
-					}
+					creationDate := val.CreationDate.Value()
 					chEntries <- api.ScalewayImageInterface{
-						Type:         "snapshot",
-						CreationDate: creationDate,
-						Identifier:   val.Identifier,
-						Name:         val.Name,
-						Tag:          "<snapshot>",
-						VirtualSize:  val.Size,
-						Public:       false,
-						Organization: val.Organization,
+						Type:             "snapshot",
+						CreationDate:     creationDate,
+						ModificationDate: val.ModificationDate.Value(),
+						Identifier:       val.Identifier,
+						Name:             val.Name,
+						Tag:              "<snapshot>",
+						VirtualSize:      val.Size,
+						Public:           false,
+						Organization:     val.Organization,
 						// FIXME the region should not be hardcoded
 						Region: []string{"par1"},
 					}
@@ -124,7 +137,7 @@ func RunImages(ctx CommandContext, args ImagesArgs) error {
 				defer wg.Done()
 				bootscripts, err := ctx.API.GetBootscripts()
 				if err != nil {
-					errChan <- fmt.Errorf("unable to fetch bootscripts from the Scaleway API: %v", err)
+					errChan <- api.Wrapf(err, "unable to fetch bootscripts from the Scaleway API")
 					return
 				}
 				for _, val := range *bootscripts {
@@ -147,24 +160,21 @@ func RunImages(ctx CommandContext, args ImagesArgs) error {
 				defer wg.Done()
 				volumes, err := ctx.API.GetVolumes()
 				if err != nil {
-					errChan <- fmt.Errorf("unable to fetch volumes from the Scaleway API: %v", err)
+					errChan <- api.Wrapf(err, "unable to fetch volumes from the Scaleway API")
 					return
 				}
 				for _, val := range *volumes {
-					creationDate, err := time.Parse("2006-01-02T15:04:05.000000+00:00", val.CreationDate)
-					if err != nil {
-						errChan <- fmt.Errorf("unable to parse creation date from the Scaleway API: %v", err)
-						return
-					}
+					creationDate := val.CreationDate.Value()
 					chEntries <- api.ScalewayImageInterface{
-						Type:         "volume",
-						CreationDate: creationDate,
-						Identifier:   val.Identifier,
-						Name:         val.Name,
-						Tag:          "<volume>",
-						VirtualSize:  val.Size,
-						Public:       false,
-						Organization: val.Organization,
+						Type:             "volume",
+						CreationDate:     creationDate,
+						ModificationDate: val.ModificationDate.Value(),
+						Identifier:       val.Identifier,
+						Name:             val.Name,
+						Tag:              "<volume>",
+						VirtualSize:      val.Size,
+						Public:           false,
+						Organization:     val.Organization,
 						// FIXME the region should not be hardcoded
 						Region: []string{"par1"},
 					}
@@ -193,7 +203,8 @@ func RunImages(ctx CommandContext, args ImagesArgs) error {
 	}
 	for key, value := range args.Filters {
 		switch key {
-		case "organization", "type", "name", "public":
+		case "organization", "type", "name", "public",
+			"created-before", "created-after", "modified-before", "modified-after":
 			continue
 		default:
 			logrus.Warnf("Unknown filter: '%s=%s'", key, value)
@@ -210,6 +221,9 @@ func RunImages(ctx CommandContext, args ImagesArgs) error {
 		if image.Identifier == "" {
 			continue
 		}
+		if !dates.match(image.CreationDate, image.ModificationDate) {
+			continue
+		}
 		for key, value := range args.Filters {
 			switch key {
 			case "type":
@@ -219,7 +233,7 @@ func RunImages(ctx CommandContext, args ImagesArgs) error {
 			case "organization":
 				switch value {
 				case "me":
-					value = ctx.API.Organization
+					value = ctx.API.OrganizationID()
 				case "official-distribs":
 					value = "a283af0b-d13e-42e1-a43f-855ffbf281ab"
 				case "official-apps":
@@ -249,12 +263,7 @@ func RunImages(ctx CommandContext, args ImagesArgs) error {
 				name = "user/" + name
 			}
 			shortName := utils.TruncIf(name, 25, !args.NoTrunc)
-			var creationDate string
-			if image.CreationDate.IsZero() {
-				creationDate = "n/a"
-			} else {
-				creationDate = units.HumanDuration(time.Now().UTC().Sub(image.CreationDate))
-			}
+			creationDate := ctx.FormatTime(image.CreationDate)
 			if len(image.Archs) == 0 {
 				image.Archs = []string{"n/a"}
 			}
@@ -271,3 +280,222 @@ func RunImages(ctx CommandContext, args ImagesArgs) error {
 	}
 	return nil
 }
+
+// runImagesCheckUpdates lists servers built from an outdated marketplace image version
+func runImagesCheckUpdates(ctx CommandContext, args ImagesArgs) error {
+	images, err := ctx.API.GetMarketPlaceImages("")
+	if err != nil {
+		return api.Wrapf(err, "unable to fetch images from the marketplace")
+	}
+	servers, err := ctx.API.GetServers(true, 0)
+	if err != nil {
+		return api.Wrapf(err, "unable to fetch servers from the Scaleway API")
+	}
+	index := api.IndexMarketLocalImages(images.Images)
+
+	sort.Sort(api.ScalewaySortServers(*servers))
+	w := tabwriter.NewWriter(ctx.Stdout, 20, 1, 3, ' ', 0)
+	defer w.Flush()
+	if !args.Quiet {
+		fmt.Fprintf(w, "SERVER ID\tNAME\tIMAGE\tVERSION\tLATEST VERSION\tLATEST IMAGE ID\n")
+	}
+	for _, server := range *servers {
+		entry, ok := index[server.Image.Identifier]
+		if !ok || !entry.Outdated() {
+			continue
+		}
+		if args.Quiet {
+			fmt.Fprintf(w, "%s\n", server.Identifier)
+			continue
+		}
+		latest := index[entry.Latest.ID]
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			utils.TruncIf(server.Identifier, 8, !args.NoTrunc),
+			utils.TruncIf(utils.Wordify(server.Name), 25, !args.NoTrunc),
+			utils.TruncIf(utils.Wordify(entry.Image.Name), 25, !args.NoTrunc),
+			entry.Version.Name,
+			latest.Version.Name,
+			utils.TruncIf(entry.Latest.ID, 8, !args.NoTrunc))
+	}
+	return nil
+}
+
+// imageOrphan is an image or a snapshot whose backing resource no longer exists
+type imageOrphan struct {
+	Type         string
+	Identifier   string
+	Name         string
+	CreationDate time.Time
+	Reason       string
+}
+
+// findOrphans returns the images whose root snapshot no longer exists and the snapshots whose base volume is gone
+func findOrphans(images []api.ScalewayImage, snapshots []api.ScalewaySnapshot, volumes []api.ScalewayVolume) []imageOrphan {
+	snapshotIDs := make(map[string]struct{}, len(snapshots))
+	for _, snapshot := range snapshots {
+		snapshotIDs[snapshot.Identifier] = struct{}{}
+	}
+	volumeIDs := make(map[string]struct{}, len(volumes))
+	for _, volume := range volumes {
+		volumeIDs[volume.Identifier] = struct{}{}
+	}
+
+	orphans := []imageOrphan{}
+	for _, image := range images {
+		if _, ok := snapshotIDs[image.RootVolume.Identifier]; ok {
+			continue
+		}
+		reason := fmt.Sprintf("root snapshot %s not found", image.RootVolume.Identifier)
+		if image.RootVolume.Identifier == "" {
+			reason = "no root snapshot"
+		}
+		orphans = append(orphans, imageOrphan{
+			Type:         "image",
+			Identifier:   image.Identifier,
+			Name:         image.Name,
+			CreationDate: image.CreationDate.Value(),
+			Reason:       reason,
+		})
+	}
+	for _, snapshot := range snapshots {
+		if _, ok := volumeIDs[snapshot.BaseVolume.Identifier]; ok {
+			continue
+		}
+		reason := fmt.Sprintf("base volume %s not found", snapshot.BaseVolume.Identifier)
+		if snapshot.BaseVolume.Identifier == "" {
+			reason = "no base volume"
+		}
+		orphans = append(orphans, imageOrphan{
+			Type:         "snapshot",
+			Identifier:   snapshot.Identifier,
+			Name:         snapshot.Name,
+			CreationDate: snapshot.CreationDate.Value(),
+			Reason:       reason,
+		})
+	}
+	return orphans
+}
+
+func runImagesOrphans(ctx CommandContext, args ImagesArgs) error {
+	images, err := ctx.API.GetOrganizationImages()
+	if err != nil {
+		return api.Wrapf(err, "unable to fetch images from the Scaleway API")
+	}
+	snapshots, err := ctx.API.GetSnapshots()
+	if err != nil {
+		return api.Wrapf(err, "unable to fetch snapshots from the Scaleway API")
+	}
+	volumes, err := ctx.API.GetVolumes()
+	if err != nil {
+		return api.Wrapf(err, "unable to fetch volumes from the Scaleway API")
+	}
+	owned := make([]api.ScalewaySnapshot, 0, len(*snapshots))
+	for _, snapshot := range *snapshots {
+		if snapshot.Organization == ctx.API.OrganizationID() {
+			owned = append(owned, snapshot)
+		}
+	}
+
+	w := tabwriter.NewWriter(ctx.Stdout, 20, 1, 3, ' ', 0)
+	defer w.Flush()
+	if !args.Quiet {
+		fmt.Fprintf(w, "TYPE\tID\tNAME\tCREATED\tREASON\n")
+	}
+	for _, orphan := range findOrphans(*images, owned, *volumes) {
+		if args.Quiet {
+			fmt.Fprintf(w, "%s\n", orphan.Identifier)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			orphan.Type,
+			utils.TruncIf(orphan.Identifier, 8, !args.NoTrunc),
+			utils.TruncIf(utils.Wordify(orphan.Name), 25, !args.NoTrunc),
+			ctx.FormatTime(orphan.CreationDate),
+			orphan.Reason)
+	}
+	return nil
+}
+
+// imageTreeNode is a line of `scw images --tree` and the lines nested below it
+type imageTreeNode struct {
+	Label    string
+	Children []imageTreeNode
+}
+
+// buildImageTree returns the images with their root snapshot, itself with the servers built from the image
+func buildImageTree(images []api.ScalewayImage, snapshots []api.ScalewaySnapshot, servers []api.ScalewayServer, noTrunc bool) []imageTreeNode {
+	snapshotNames := make(map[string]string, len(snapshots))
+	for _, snapshot := range snapshots {
+		snapshotNames[snapshot.Identifier] = snapshot.Name
+	}
+	serversByImage := make(map[string][]api.ScalewayServer)
+	for _, server := range servers {
+		serversByImage[server.Image.Identifier] = append(serversByImage[server.Image.Identifier], server)
+	}
+
+	nodes := make([]imageTreeNode, 0, len(images))
+	for _, image := range images {
+		users := serversByImage[image.Identifier]
+		node := imageTreeNode{
+			Label: fmt.Sprintf("%s (%s)", image.Name, utils.TruncIf(image.Identifier, 8, !noTrunc)),
+		}
+		if len(users) == 0 {
+			node.Label += ", no server, safe to delete"
+		}
+		servers := make([]imageTreeNode, 0, len(users))
+		for _, server := range users {
+			servers = append(servers, imageTreeNode{
+				Label: fmt.Sprintf("server %s (%s, %s)", server.Name, utils.TruncIf(server.Identifier, 8, !noTrunc), server.State),
+			})
+		}
+		switch name, ok := snapshotNames[image.RootVolume.Identifier]; {
+		case ok:
+			node.Children = []imageTreeNode{{
+				Label:    fmt.Sprintf("snapshot %s (%s)", name, utils.TruncIf(image.RootVolume.Identifier, 8, !noTrunc)),
+				Children: servers,
+			}}
+		case image.RootVolume.Identifier != "":
+			node.Children = append([]imageTreeNode{{
+				Label: fmt.Sprintf("snapshot %s not found", utils.TruncIf(image.RootVolume.Identifier, 8, !noTrunc)),
+			}}, servers...)
+		default:
+			node.Children = servers
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// writeImageTree draws nodes with box-drawing characters, prefix is prepended to the lines of the children
+func writeImageTree(w io.Writer, nodes []imageTreeNode, prefix string) {
+	for i, node := range nodes {
+		branch, indent := "├── ", "│   "
+		if i == len(nodes)-1 {
+			branch, indent = "└── ", "    "
+		}
+		fmt.Fprintf(w, "%s%s%s\n", prefix, branch, node.Label)
+		writeImageTree(w, node.Children, prefix+indent)
+	}
+}
+
+func runImagesTree(ctx CommandContext, args ImagesArgs) error {
+	images, err := ctx.API.GetOrganizationImages()
+	if err != nil {
+		return api.Wrapf(err, "unable to fetch images from the Scaleway API")
+	}
+	snapshots, err := ctx.API.GetSnapshots()
+	if err != nil {
+		return api.Wrapf(err, "unable to fetch snapshots from the Scaleway API")
+	}
+	servers, err := ctx.API.GetServers(true, 0)
+	if err != nil {
+		return api.Wrapf(err, "unable to fetch servers from the Scaleway API")
+	}
+	sort.Sort(api.ScalewaySortServers(*servers))
+
+	for _, node := range buildImageTree(*images, *snapshots, *servers, args.NoTrunc) {
+		fmt.Fprintln(ctx.Stdout, node.Label)
+		writeImageTree(ctx.Stdout, node.Children, "")
+	}
+	return nil
+}
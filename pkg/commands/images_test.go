@@ -5,9 +5,12 @@
 package commands
 
 import (
+	"bytes"
+	"fmt"
 	"strings"
 	"testing"
 
+	"github.com/scaleway/scaleway-cli/pkg/api"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -134,3 +137,48 @@ func TestRunImages_realAPI(t *testing.T) {
 		})
 	})
 }
+
+func TestFindOrphans(t *testing.T) {
+	Convey("Testing findOrphans()", t, func() {
+		images := []api.ScalewayImage{
+			{Identifier: "image-ok", RootVolume: api.ScalewayVolume{Identifier: "snap-ok"}},
+			{Identifier: "image-orphan", RootVolume: api.ScalewayVolume{Identifier: "snap-gone"}},
+		}
+		snapshots := []api.ScalewaySnapshot{
+			{Identifier: "snap-ok", BaseVolume: api.ScalewayVolume{Identifier: "vol-ok"}},
+			{Identifier: "snap-orphan"},
+		}
+		volumes := []api.ScalewayVolume{{Identifier: "vol-ok"}}
+
+		orphans := findOrphans(images, snapshots, volumes)
+		So(len(orphans), ShouldEqual, 2)
+		So(orphans[0].Identifier, ShouldEqual, "image-orphan")
+		So(orphans[0].Reason, ShouldEqual, "root snapshot snap-gone not found")
+		So(orphans[1].Identifier, ShouldEqual, "snap-orphan")
+		So(orphans[1].Reason, ShouldEqual, "no base volume")
+	})
+}
+
+func TestBuildImageTree(t *testing.T) {
+	Convey("Testing buildImageTree()", t, func() {
+		images := []api.ScalewayImage{
+			{Identifier: "image-used", Name: "used", RootVolume: api.ScalewayVolume{Identifier: "snap-used"}},
+			{Identifier: "image-free", Name: "free", RootVolume: api.ScalewayVolume{Identifier: "snap-gone"}},
+		}
+		snapshots := []api.ScalewaySnapshot{{Identifier: "snap-used", Name: "used-snap"}}
+		servers := []api.ScalewayServer{{Identifier: "server-1", Name: "web", State: "running"}}
+		servers[0].Image.Identifier = "image-used"
+
+		var out bytes.Buffer
+		for _, node := range buildImageTree(images, snapshots, servers, true) {
+			fmt.Fprintln(&out, node.Label)
+			writeImageTree(&out, node.Children, "")
+		}
+		So(out.String(), ShouldEqual, `used (image-used)
+└── snapshot used-snap (snap-used)
+    └── server web (server-1, running)
+free (image-free), no server, safe to delete
+└── snapshot snap-gone not found
+`)
+	})
+}
@@ -24,7 +24,7 @@ func RunInfo(ctx CommandContext, args InfoArgs) error {
 	// FIXME: fmt.Fprintf(ctx.Stdout, "Images: %s\n", "quantity")
 	fmt.Fprintf(ctx.Stdout, "Debug mode (client):\t%v\n", ctx.Getenv("DEBUG") != "")
 
-	fmt.Fprintf(ctx.Stdout, "Organization:\t\t%s\n", ctx.API.Organization)
+	fmt.Fprintf(ctx.Stdout, "Organization:\t\t%s\n", ctx.API.OrganizationID())
 	// FIXME: add partially-masked token
 	configPath, _ := config.GetConfigFilePath()
 	fmt.Fprintf(ctx.Stdout, "RC file:\t\t%s\n", configPath)
@@ -36,12 +36,13 @@ func RunInfo(ctx CommandContext, args InfoArgs) error {
 	fmt.Fprintf(ctx.Stdout, "CLI Path:\t\t%s\n", cliPath)
 
 	fmt.Fprintln(ctx.Stdout, "")
-	fmt.Fprintf(ctx.Stdout, "Cache:\t\t\t%s\n", ctx.API.Cache.Path)
-	fmt.Fprintf(ctx.Stdout, "  Servers:\t\t%d\n", ctx.API.Cache.GetNbServers())
-	fmt.Fprintf(ctx.Stdout, "  Images:\t\t%d\n", ctx.API.Cache.GetNbImages())
-	fmt.Fprintf(ctx.Stdout, "  Snapshots:\t\t%d\n", ctx.API.Cache.GetNbSnapshots())
-	fmt.Fprintf(ctx.Stdout, "  Volumes:\t\t%d\n", ctx.API.Cache.GetNbVolumes())
-	fmt.Fprintf(ctx.Stdout, "  Bootscripts:\t\t%d\n", ctx.API.Cache.GetNbBootscripts())
+	fmt.Fprintf(ctx.Stdout, "Cache:\t\t\t%s\n", ctx.API.ResolverCache().Path)
+	fmt.Fprintf(ctx.Stdout, "  Servers:\t\t%d\n", ctx.API.ResolverCache().GetNbServers())
+	fmt.Fprintf(ctx.Stdout, "  Images:\t\t%d\n", ctx.API.ResolverCache().GetNbImages())
+	fmt.Fprintf(ctx.Stdout, "  Snapshots:\t\t%d\n", ctx.API.ResolverCache().GetNbSnapshots())
+	fmt.Fprintf(ctx.Stdout, "  Volumes:\t\t%d\n", ctx.API.ResolverCache().GetNbVolumes())
+	fmt.Fprintf(ctx.Stdout, "  Bootscripts:\t\t%d\n", ctx.API.ResolverCache().GetNbBootscripts())
+	fmt.Fprintf(ctx.Stdout, "  IPs:\t\t\t%d\n", ctx.API.ResolverCache().GetNbIPs())
 
 	user, err := ctx.API.GetUser()
 	if err != nil {
@@ -97,7 +98,7 @@ func RunInfo(ctx CommandContext, args InfoArgs) error {
 	fmt.Fprintf(ctx.Stdout, "\n")
 	fmt.Fprintln(ctx.Stdout, "Urls:")
 	// TODO: add endpoint API by region
-	fmt.Fprintf(ctx.Stdout, "  account: %s\n", api.AccountAPI)
+	fmt.Fprintf(ctx.Stdout, "  account: %s\n", ctx.API.AccountAPIURL())
 	fmt.Fprintf(ctx.Stdout, "  metadata: %s\n", api.MetadataAPI)
 	fmt.Fprintf(ctx.Stdout, "  marketplace: %s\n", api.MarketplaceAPI)
 	return nil
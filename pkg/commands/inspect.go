@@ -7,9 +7,12 @@ package commands
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"text/template"
+	"time"
 
 	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/scaleway/scaleway-cli/pkg/utils"
 	"github.com/sirupsen/logrus"
 	"github.com/skratchdot/open-golang/open"
 )
@@ -38,30 +41,31 @@ func RunInspect(ctx CommandContext, args InspectArgs) error {
 				break
 			}
 
-			switch data.Type {
-			case api.IdentifierServer:
-				err := open.Start(fmt.Sprintf("https://cloud.scaleway.com/#/servers/%s", data.Object.(*api.ScalewayServer).Identifier))
-				if err != nil {
-					return fmt.Errorf("cannot open browser: %v", err)
-				}
-				nbInspected++
-			case api.IdentifierImage:
-				err := open.Start(fmt.Sprintf("https://cloud.scaleway.com/#/images/%s", data.Object.(*api.ScalewayImage).Identifier))
-				if err != nil {
-					return fmt.Errorf("cannot open browser: %v", err)
-				}
-				nbInspected++
-			case api.IdentifierVolume:
-				err := open.Start(fmt.Sprintf("https://cloud.scaleway.com/#/volumes/%s", data.Object.(*api.ScalewayVolume).Identifier))
-				if err != nil {
-					return fmt.Errorf("cannot open browser: %v", err)
-				}
-				nbInspected++
-			case api.IdentifierSnapshot:
-				logrus.Errorf("Cannot use '--browser' option for snapshots")
-			case api.IdentifierBootscript:
+			var identifier string
+			switch object := data.Object.(type) {
+			case *api.ScalewayServer:
+				identifier = object.Identifier
+			case *api.ScalewayImage:
+				identifier = object.Identifier
+			case *api.ScalewaySnapshot:
+				identifier = object.Identifier
+			case *api.ScalewayVolume:
+				identifier = object.Identifier
+			case *api.ScalewayBootscript:
 				logrus.Errorf("Cannot use '--browser' option for bootscripts")
+				continue
+			default:
+				logrus.Errorf("Cannot use '--browser' option for IPs")
+				continue
 			}
+			url, err := consoleURL(data.Type, identifier)
+			if err != nil {
+				return err
+			}
+			if err = open.Start(url); err != nil {
+				return api.Wrapf(err, "cannot open browser")
+			}
+			nbInspected++
 		}
 
 	} else {
@@ -73,7 +77,7 @@ func RunInspect(ctx CommandContext, args InspectArgs) error {
 				break
 			}
 			if args.Format == "" {
-				dataB, err := json.MarshalIndent(data.Object, "", "  ")
+				dataB, err := marshalInspected(data.Object, ctx.TimeFormat)
 				if err == nil {
 					if nbInspected != 0 {
 						res += ",\n"
@@ -84,12 +88,12 @@ func RunInspect(ctx CommandContext, args InspectArgs) error {
 			} else {
 				tmpl, err := template.New("").Funcs(api.FuncMap).Parse(args.Format)
 				if err != nil {
-					return fmt.Errorf("format parsing error: %v", err)
+					return api.Wrapf(err, "format parsing error")
 				}
 
 				err = tmpl.Execute(ctx.Stdout, data.Object)
 				if err != nil {
-					return fmt.Errorf("format execution error: %v", err)
+					return api.Wrapf(err, "format execution error")
 				}
 				fmt.Fprint(ctx.Stdout, "\n")
 				nbInspected++
@@ -112,3 +116,46 @@ func RunInspect(ctx CommandContext, args InspectArgs) error {
 	}
 	return nil
 }
+
+// marshalInspected indents the JSON representation of object, dates are kept
+// as returned by the API unless a --time-format is explicitly requested
+func marshalInspected(object interface{}, timeFormat string) ([]byte, error) {
+	if timeFormat == "" {
+		return json.MarshalIndent(object, "", "  ")
+	}
+	raw, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err = json.Unmarshal(raw, &generic); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(formatInspectedDates(generic, timeFormat), "", "  ")
+}
+
+// formatInspectedDates walks a decoded JSON document and formats the date fields
+func formatInspectedDates(value interface{}, timeFormat string) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, field := range typed {
+			date, isString := field.(string)
+			if isString && (strings.HasSuffix(key, "_date") || strings.HasSuffix(key, "_at")) {
+				if parsed, err := time.Parse(time.RFC3339Nano, date); err == nil {
+					if timeFormat == utils.TimeFormatUnix {
+						typed[key] = parsed.Unix()
+					} else {
+						typed[key] = utils.FormatTime(parsed, timeFormat)
+					}
+				}
+				continue
+			}
+			typed[key] = formatInspectedDates(field, timeFormat)
+		}
+	case []interface{}:
+		for i, item := range typed {
+			typed[i] = formatInspectedDates(item, timeFormat)
+		}
+	}
+	return value
+}
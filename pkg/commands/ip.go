@@ -0,0 +1,201 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+)
+
+// States of a reserved IP in 'scw ip ls'
+const (
+	IPStateFree     = "free"
+	IPStateAttached = "attached"
+	IPStateOrphaned = "orphaned"
+)
+
+// IPListArgs are flags for the `RunIPList` function
+type IPListArgs struct {
+	Quiet   bool
+	JSON    bool
+	Orphans bool
+}
+
+// IPsArgs are flags for the `RunIPRemove` and `RunIPDetach` functions
+type IPsArgs struct {
+	IPs []string
+}
+
+// IPAttachArgs are flags for the `RunIPAttach` function
+type IPAttachArgs struct {
+	IP     string
+	Server string
+}
+
+// IPSetReverseArgs are flags for the `RunIPSetReverse` function
+type IPSetReverseArgs struct {
+	IP      string
+	Reverse string
+}
+
+// ipEntry is a reserved IP as listed by 'scw ip ls'
+type ipEntry struct {
+	ID         string `json:"id"`
+	Address    string `json:"address"`
+	Reverse    string `json:"reverse,omitempty"`
+	ServerID   string `json:"server_id,omitempty"`
+	ServerName string `json:"server_name,omitempty"`
+	State      string `json:"state"`
+}
+
+// ipEntries returns the entries of ips, an IP attached to a server missing from servers is orphaned
+func ipEntries(ips []api.ScalewayIPDefinition, servers []api.ScalewayServer) []ipEntry {
+	existing := make(map[string]struct{}, len(servers))
+	for _, server := range servers {
+		existing[server.Identifier] = struct{}{}
+	}
+	entries := make([]ipEntry, len(ips))
+	for i, ip := range ips {
+		entries[i] = ipEntry{ID: ip.ID, Address: ip.Address, State: IPStateFree}
+		if ip.Reverse != nil {
+			entries[i].Reverse = *ip.Reverse
+		}
+		if ip.Server == nil || ip.Server.Identifier == "" {
+			continue
+		}
+		entries[i].ServerID = ip.Server.Identifier
+		entries[i].ServerName = ip.Server.Name
+		entries[i].State = IPStateAttached
+		if _, ok := existing[ip.Server.Identifier]; !ok {
+			entries[i].State = IPStateOrphaned
+		}
+	}
+	return entries
+}
+
+// RunIPList is the handler for 'scw ip ls'
+func RunIPList(ctx CommandContext, args IPListArgs) error {
+	ips, err := ctx.API.GetIPS()
+	if err != nil {
+		return api.Wrapf(err, "unable to fetch IPs from the Scaleway API")
+	}
+	servers, err := ctx.API.GetServers(true, 0)
+	if err != nil {
+		return api.Wrapf(err, "unable to fetch servers from the Scaleway API")
+	}
+	entries := []ipEntry{}
+	for _, entry := range ipEntries(ips.IPS, *servers) {
+		if !args.Orphans || entry.State == IPStateOrphaned {
+			entries = append(entries, entry)
+		}
+	}
+
+	if args.JSON {
+		res, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(ctx.Stdout, string(res))
+		return nil
+	}
+	w := tabwriter.NewWriter(ctx.Stdout, 20, 1, 3, ' ', 0)
+	defer w.Flush()
+	if !args.Quiet {
+		fmt.Fprintf(w, "IP ID\tADDRESS\tSTATE\tSERVER\tREVERSE\n")
+	}
+	for _, entry := range entries {
+		if args.Quiet {
+			fmt.Fprintln(w, entry.ID)
+			continue
+		}
+		server := entry.ServerName
+		if server == "" {
+			server = "-"
+		}
+		reverse := entry.Reverse
+		if reverse == "" {
+			reverse = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", entry.ID, entry.Address, entry.State, server, reverse)
+	}
+	return nil
+}
+
+// RunIPCreate is the handler for 'scw ip create'
+func RunIPCreate(ctx CommandContext) error {
+	ip, err := ctx.API.NewIP()
+	if err != nil {
+		return api.Wrapf(err, "cannot reserve an IP")
+	}
+	fmt.Fprintln(ctx.Stdout, ip.IP.ID)
+	return nil
+}
+
+// RunIPRemove is the handler for 'scw ip rm'
+func RunIPRemove(ctx CommandContext, args IPsArgs) error {
+	result := NewBulkResult(ctx, "remove", args.IPs)
+	for _, needle := range args.IPs {
+		done := result.Start(needle)
+		ipID, err := ctx.API.GetIPID(needle)
+		if err != nil {
+			done(err)
+			continue
+		}
+		done(ctx.API.DeleteIP(ipID))
+	}
+	if err := result.Report(); err != nil {
+		return err
+	}
+	if result.Failed() > 0 {
+		return fmt.Errorf("at least 1 IP failed to be removed")
+	}
+	return nil
+}
+
+// RunIPAttach is the handler for 'scw ip attach'
+func RunIPAttach(ctx CommandContext, args IPAttachArgs) error {
+	ipID, err := ctx.API.GetIPID(args.IP)
+	if err != nil {
+		return err
+	}
+	serverID, err := ctx.API.GetServerID(args.Server)
+	if err != nil {
+		return err
+	}
+	return ctx.API.AttachIP(ipID, serverID)
+}
+
+// RunIPDetach is the handler for 'scw ip detach'
+func RunIPDetach(ctx CommandContext, args IPsArgs) error {
+	result := NewBulkResult(ctx, "detach", args.IPs)
+	for _, needle := range args.IPs {
+		done := result.Start(needle)
+		ipID, err := ctx.API.GetIPID(needle)
+		if err != nil {
+			done(err)
+			continue
+		}
+		done(ctx.API.DetachIP(ipID))
+	}
+	if err := result.Report(); err != nil {
+		return err
+	}
+	if result.Failed() > 0 {
+		return fmt.Errorf("at least 1 IP failed to be detached")
+	}
+	return nil
+}
+
+// RunIPSetReverse is the handler for 'scw ip set-reverse'
+func RunIPSetReverse(ctx CommandContext, args IPSetReverseArgs) error {
+	ipID, err := ctx.API.GetIPID(args.IP)
+	if err != nil {
+		return err
+	}
+	return ctx.API.SetIPReverse(ipID, args.Reverse)
+}
@@ -0,0 +1,40 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestIPEntries(t *testing.T) {
+	Convey("Testing ipEntries()", t, func() {
+		reverse := "www.example.com"
+		ips := []api.ScalewayIPDefinition{
+			{ID: "ip-free", Address: "51.15.1.1", Reverse: &reverse},
+			{ID: "ip-used", Address: "51.15.1.2"},
+			{ID: "ip-orphan", Address: "51.15.1.3"},
+		}
+		ips[1].Server = &struct {
+			Identifier string `json:"id,omitempty"`
+			Name       string `json:"name,omitempty"`
+		}{Identifier: "server-1", Name: "web-1"}
+		ips[2].Server = &struct {
+			Identifier string `json:"id,omitempty"`
+			Name       string `json:"name,omitempty"`
+		}{Identifier: "server-2", Name: "web-2"}
+		servers := []api.ScalewayServer{{Identifier: "server-1"}}
+
+		entries := ipEntries(ips, servers)
+		So(len(entries), ShouldEqual, 3)
+		So(entries[0].State, ShouldEqual, IPStateFree)
+		So(entries[0].Reverse, ShouldEqual, "www.example.com")
+		So(entries[1].State, ShouldEqual, IPStateAttached)
+		So(entries[1].ServerName, ShouldEqual, "web-1")
+		So(entries[2].State, ShouldEqual, IPStateOrphaned)
+	})
+}
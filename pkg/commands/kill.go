@@ -5,7 +5,6 @@
 package commands
 
 import (
-	"fmt"
 	"os/exec"
 
 	"github.com/scaleway/scaleway-cli/pkg/api"
@@ -30,7 +29,7 @@ func RunKill(ctx CommandContext, args KillArgs) error {
 	command := "halt"
 	server, err := ctx.API.GetServer(serverID)
 	if err != nil {
-		return fmt.Errorf("failed to get server information for %s: %v", serverID, err)
+		return api.Wrapf(err, "failed to get server information for %s", serverID)
 	}
 
 	// Resolve gateway
@@ -43,7 +42,7 @@ func RunKill(ctx CommandContext, args KillArgs) error {
 	} else {
 		gateway, err = api.ResolveGateway(ctx.API, args.Gateway)
 		if err != nil {
-			return fmt.Errorf("cannot resolve Gateway '%s': %v", args.Gateway, err)
+			return api.Wrapf(err, "cannot resolve Gateway '%s'", args.Gateway)
 		}
 	}
 
@@ -42,7 +42,7 @@ func selectKey(args *LoginArgs) error {
 	dir := filepath.Join(home, ".ssh")
 	files, err := ioutil.ReadDir(dir)
 	if err != nil {
-		return fmt.Errorf("Unable to open your ~/.ssh: %v", err)
+		return api.Wrapf(err, "Unable to open your ~/.ssh")
 	}
 	var pubs []string
 
@@ -78,7 +78,7 @@ func selectKey(args *LoginArgs) error {
 		}
 		buff, err := ioutil.ReadFile(filepath.Join(dir, pubs[id-1]))
 		if err != nil {
-			return fmt.Errorf("Unable to open your key: %v", err)
+			return api.Wrapf(err, "Unable to open your key")
 		}
 		args.SSHKey = string(buff[:])
 		break
@@ -93,7 +93,7 @@ func postToken(connect api.ScalewayConnectInterface) (*http.Response, error) {
 	}
 	FakeConnection.SetPassword(connect.GetPassword())
 
-	resp, err := FakeConnection.PostResponse(api.AccountAPI, "tokens", connect)
+	resp, err := FakeConnection.PostResponse(FakeConnection.AccountAPIURL(), "tokens", connect)
 	return resp, err
 }
 
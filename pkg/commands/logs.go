@@ -5,8 +5,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"github.com/scaleway/scaleway-cli/pkg/api"
 	"github.com/scaleway/scaleway-cli/pkg/utils"
 )
@@ -27,7 +25,7 @@ func RunLogs(ctx CommandContext, args LogsArgs) error {
 	}
 	server, err := ctx.API.GetServer(serverID)
 	if err != nil {
-		return fmt.Errorf("failed to get server information for %s: %v", serverID, err)
+		return api.Wrapf(err, "failed to get server information for %s", serverID)
 	}
 
 	// FIXME: switch to serial history when API is ready
@@ -42,14 +40,14 @@ func RunLogs(ctx CommandContext, args LogsArgs) error {
 	} else {
 		gateway, err = api.ResolveGateway(ctx.API, args.Gateway)
 		if err != nil {
-			return fmt.Errorf("cannot resolve Gateway '%s': %v", args.Gateway, err)
+			return api.Wrapf(err, "cannot resolve Gateway '%s'", args.Gateway)
 		}
 	}
 
 	command := []string{"dmesg"}
 	err = utils.SSHExec(server.PublicAddress.IP, server.PrivateIP, args.SSHUser, args.SSHPort, command, true, gateway, false)
 	if err != nil {
-		return fmt.Errorf("command execution failed: %v", err)
+		return api.Wrapf(err, "command execution failed")
 	}
 	return nil
 }
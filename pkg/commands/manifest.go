@@ -0,0 +1,92 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/moul/anonuuid"
+)
+
+// PackerManifest is the format written by the manifest post-processor of packer,
+// the builds of successive runs are appended to the same file
+type PackerManifest struct {
+	Builds      []PackerBuild `json:"builds"`
+	LastRunUUID string        `json:"last_run_uuid"`
+}
+
+// PackerBuild is an image built in a packer manifest
+type PackerBuild struct {
+	Name          string            `json:"name"`
+	BuilderType   string            `json:"builder_type"`
+	BuildTime     int64             `json:"build_time"`
+	Files         []interface{}     `json:"files"`
+	ArtifactID    string            `json:"artifact_id"`
+	PackerRunUUID string            `json:"packer_run_uuid"`
+	CustomData    map[string]string `json:"custom_data"`
+}
+
+// newPackerBuild describes an image like the scaleway builder of packer, the artifact is REGION:IMAGE
+func newPackerBuild(name, imageID, snapshotID, region, arch string, now time.Time) PackerBuild {
+	return PackerBuild{
+		Name:        name,
+		BuilderType: "scaleway",
+		BuildTime:   now.Unix(),
+		ArtifactID:  fmt.Sprintf("%s:%s", region, imageID),
+		CustomData: map[string]string{
+			"image_id":    imageID,
+			"image_name":  name,
+			"snapshot_id": snapshotID,
+			"region":      region,
+			"arch":        arch,
+		},
+	}
+}
+
+// writePackerManifest appends build to the packer manifest at path, creating it when missing
+func writePackerManifest(path string, build PackerBuild) error {
+	manifest := PackerManifest{Builds: []PackerBuild{}}
+	if content, err := ioutil.ReadFile(path); err == nil {
+		if err = json.Unmarshal(content, &manifest); err != nil {
+			return fmt.Errorf("invalid manifest %s: %v", path, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	runUUID, err := anonuuid.GenerateRandomUUID(32)
+	if err != nil {
+		return err
+	}
+	build.PackerRunUUID = runUUID
+	manifest.Builds = append(manifest.Builds, build)
+	manifest.LastRunUUID = runUUID
+
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".manifest")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(append(content, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,41 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWritePackerManifest(t *testing.T) {
+	Convey("Testing writePackerManifest()", t, func() {
+		dir, err := ioutil.TempDir("", "scw-manifest")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "manifest.json")
+		now := time.Unix(1500000000, 0)
+
+		So(writePackerManifest(path, newPackerBuild("nginx", "image-1", "snapshot-1", "par1", "x86_64", now)), ShouldBeNil)
+		So(writePackerManifest(path, newPackerBuild("nginx", "image-2", "snapshot-2", "ams1", "arm", now)), ShouldBeNil)
+
+		content, err := ioutil.ReadFile(path)
+		So(err, ShouldBeNil)
+		var manifest PackerManifest
+		So(json.Unmarshal(content, &manifest), ShouldBeNil)
+		So(len(manifest.Builds), ShouldEqual, 2)
+		So(manifest.Builds[0].ArtifactID, ShouldEqual, "par1:image-1")
+		So(manifest.Builds[1].ArtifactID, ShouldEqual, "ams1:image-2")
+		So(manifest.Builds[1].BuilderType, ShouldEqual, "scaleway")
+		So(manifest.Builds[1].BuildTime, ShouldEqual, 1500000000)
+		So(manifest.Builds[1].CustomData["arch"], ShouldEqual, "arm")
+		So(manifest.LastRunUUID, ShouldEqual, manifest.Builds[1].PackerRunUUID)
+	})
+}
@@ -0,0 +1,91 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/scaleway/scaleway-cli/pkg/scwversion"
+	"github.com/sirupsen/logrus"
+)
+
+// Events sent to --notify-url
+const (
+	NotifyServerBooted  = "server.booted"
+	NotifyServerStopped = "server.stopped"
+	NotifySnapshotDone  = "snapshot.done"
+	NotifyRescueReady   = "server.rescue"
+)
+
+// NotifyTimeout is the maximum time spent posting a notification
+var NotifyTimeout = 10 * time.Second
+
+// Notification is the JSON payload posted to --notify-url when a waited-on operation finishes
+type Notification struct {
+	Event           string  `json:"event"`
+	Target          string  `json:"target"`
+	Status          string  `json:"status"`
+	Error           string  `json:"error,omitempty"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Date            string  `json:"date"`
+	Command         string  `json:"command"`
+	Hostname        string  `json:"hostname,omitempty"`
+	Text            string  `json:"text"`
+}
+
+// Notify posts the outcome of a waited-on operation to --notify-url, failures are only logged
+func (c *CommandContext) Notify(event, target string, start time.Time, err error) {
+	if c.NotifyURL == "" {
+		return
+	}
+
+	notification := Notification{
+		Event:           event,
+		Target:          target,
+		Status:          "success",
+		DurationSeconds: time.Since(start).Seconds(),
+		Date:            time.Now().UTC().Format(time.RFC3339),
+		Command:         strings.Join(append([]string{"scw"}, os.Args[1:]...), " "),
+	}
+	notification.Hostname, _ = os.Hostname()
+	// `text` is understood by most chat incoming webhooks
+	notification.Text = fmt.Sprintf("%s %s succeeded in %.0fs", event, target, notification.DurationSeconds)
+	if err != nil {
+		notification.Status = "failure"
+		notification.Error = err.Error()
+		notification.Text = fmt.Sprintf("%s %s failed: %v", event, target, err)
+	}
+
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		logrus.Warnf("cannot encode notification: %v", err)
+		return
+	}
+	req, err := http.NewRequest("POST", c.NotifyURL, bytes.NewReader(payload))
+	if err != nil {
+		logrus.Warnf("cannot send notification to %s: %v", c.NotifyURL, err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", scwversion.UserAgent())
+	client := http.Client{Timeout: NotifyTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		logrus.Warnf("cannot send notification to %s: %v", c.NotifyURL, err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		logrus.Warnf("notification to %s rejected: %s", c.NotifyURL, resp.Status)
+		return
+	}
+	logrus.Debugf("notification %s %s sent to %s", event, target, c.NotifyURL)
+}
@@ -0,0 +1,47 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+)
+
+// OrgMembersArgs are flags for the `RunOrgMembers` function
+type OrgMembersArgs struct {
+	Role  string
+	Quiet bool
+}
+
+// RunOrgMembers is the handler for 'scw _org members'
+func RunOrgMembers(ctx CommandContext, args OrgMembersArgs) error {
+	members, err := ctx.API.GetOrganizationMembers(ctx.API.OrganizationID())
+	if err != nil {
+		return api.Wrapf(err, "unable to fetch the members of the organization")
+	}
+
+	w := tabwriter.NewWriter(ctx.Stdout, 20, 1, 3, ' ', 0)
+	defer w.Flush()
+	if !args.Quiet {
+		fmt.Fprintf(w, "EMAIL\tNAME\tROLE\tSSH KEYS\tUSER ID\n")
+	}
+	for _, member := range members {
+		if args.Role != "" && args.Role != member.Role {
+			continue
+		}
+		if args.Quiet {
+			fmt.Fprintln(w, member.User.Email)
+			continue
+		}
+		role := member.Role
+		if role == "" {
+			role = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", member.User.Email, member.User.Fullname, role, len(member.User.SSHPublicKeys), member.User.ID)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/scaleway/scaleway-cli/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// PingArgs are flags for the `RunPing` function
+type PingArgs struct {
+	Servers []string
+	Count   int
+}
+
+type pingStats struct {
+	min, max, total time.Duration
+	success, failed int
+
+	// err is the last failure, the failed attempts are not measurements
+	err error
+}
+
+func (p *pingStats) add(duration time.Duration, err error) {
+	if err != nil {
+		p.failed++
+		p.err = err
+		return
+	}
+	if p.success == 0 || duration < p.min {
+		p.min = duration
+	}
+	if duration > p.max {
+		p.max = duration
+	}
+	p.total += duration
+	p.success++
+}
+
+func (p *pingStats) columns() string {
+	if p.success == 0 {
+		return fmt.Sprintf("-\t-\t-\t%d/%d", p.failed, p.failed)
+	}
+	avg := p.total / time.Duration(p.success)
+	return fmt.Sprintf("%s\t%s\t%s\t%d/%d", roundMs(p.min), roundMs(avg), roundMs(p.max), p.failed, p.success+p.failed)
+}
+
+func roundMs(d time.Duration) time.Duration {
+	return (d / (100 * time.Microsecond)) * (100 * time.Microsecond)
+}
+
+// RunPing is the handler for 'scw _ping'
+func RunPing(ctx CommandContext, args PingArgs) error {
+	if args.Count < 1 {
+		args.Count = 1
+	}
+
+	w := tabwriter.NewWriter(ctx.Stdout, 10, 1, 3, ' ', 0)
+	defer w.Flush()
+	fmt.Fprintf(w, "TARGET\tADDRESS\tMIN\tAVG\tMAX\tLOST\n")
+
+	endpoints := []struct {
+		name, url, resource string
+	}{
+		{"api/account", ctx.API.AccountAPIURL(), "tokens"},
+		{"api/par1", ctx.API.VersionedURL(api.ComputeAPIPar1), "servers"},
+		{"api/ams1", ctx.API.VersionedURL(api.ComputeAPIAms1), "servers"},
+	}
+	hasError := false
+	var failures []string
+	for _, endpoint := range endpoints {
+		var stats pingStats
+		for i := 0; i < args.Count; i++ {
+			stats.add(ctx.API.Ping(endpoint.url, endpoint.resource))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", endpoint.name, endpoint.url, stats.columns())
+		if stats.err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", endpoint.name, stats.err))
+		}
+	}
+
+	for _, needle := range args.Servers {
+		serverID, err := ctx.API.GetServerID(needle)
+		if err != nil {
+			logrus.Errorf("%s", err)
+			hasError = true
+			continue
+		}
+		server, err := ctx.API.GetServer(serverID)
+		if err != nil {
+			logrus.Errorf("%s", err)
+			hasError = true
+			continue
+		}
+		ip := server.PublicAddress.IP
+		if ip == "" && server.EnableIPV6 && server.IPV6 != nil {
+			ip = fmt.Sprintf("[%s]", server.IPV6.Address)
+		}
+		if ip == "" {
+			logrus.Warnf("server %s has no public IP address", server.Name)
+			continue
+		}
+		dest := fmt.Sprintf("%s:22", ip)
+		var stats pingStats
+		for i := 0; i < args.Count; i++ {
+			stats.add(utils.TCPHandshakeDuration(dest))
+		}
+		fmt.Fprintf(w, "server/%s\t%s\t%s\n", server.Name, dest, stats.columns())
+		if stats.err != nil {
+			failures = append(failures, fmt.Sprintf("server/%s: %v", server.Name, stats.err))
+		}
+	}
+	// the errors follow the table
+	w.Flush()
+	for _, failure := range failures {
+		logrus.Errorf("%s", failure)
+	}
+	if hasError {
+		return fmt.Errorf("at least 1 server failed to be resolved")
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("at least 1 target failed to answer")
+	}
+	return nil
+}
@@ -28,7 +28,7 @@ func RunProducts(ctx CommandContext, args ProductsArgs) error {
 		case "servers":
 			products, err := ctx.API.GetProductsServers()
 			if err != nil {
-				return fmt.Errorf("Unable to fetch products from the Scaleway API: %v", err)
+				return api.Wrapf(err, "Unable to fetch products from the Scaleway API")
 			}
 
 			var displayFunc displayServerFunc = DisplayServerFull
@@ -0,0 +1,37 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+)
+
+// ProtectedTag is the server tag which makes rm, stop -t and rmi refuse to destroy
+// the server or its volumes unless --force-protected is passed
+const ProtectedTag = "protected"
+
+// isProtected returns true if tags contains ProtectedTag
+func isProtected(tags []string) bool {
+	for _, tag := range tags {
+		if tag == ProtectedTag {
+			return true
+		}
+	}
+	return false
+}
+
+// checkProtected returns an error if the server serverID carries ProtectedTag
+func checkProtected(ctx CommandContext, serverID string) error {
+	server, err := ctx.API.GetServer(serverID)
+	if err != nil {
+		return api.Wrapf(err, "cannot fetch server")
+	}
+	if isProtected(server.Tags) {
+		return fmt.Errorf("server %s is tagged %q, use --force-protected", server.Name, ProtectedTag)
+	}
+	return nil
+}
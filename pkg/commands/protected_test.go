@@ -0,0 +1,19 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestIsProtected(t *testing.T) {
+	Convey("Testing isProtected()", t, func() {
+		So(isProtected([]string{"db", ProtectedTag}), ShouldBeTrue)
+		So(isProtected([]string{"db", "protected-later"}), ShouldBeFalse)
+		So(isProtected(nil), ShouldBeFalse)
+	})
+}
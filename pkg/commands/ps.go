@@ -6,27 +6,38 @@ package commands
 
 import (
 	"fmt"
+	"math/big"
 	"sort"
 	"strings"
+	"sync"
 	"text/tabwriter"
-	"time"
 
-	"github.com/docker/go-units"
 	"github.com/renstrom/fuzzysearch/fuzzy"
 	"github.com/sirupsen/logrus"
 
 	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/scaleway/scaleway-cli/pkg/config"
+	"github.com/scaleway/scaleway-cli/pkg/pricing"
+	"github.com/scaleway/scaleway-cli/pkg/scwversion"
 	"github.com/scaleway/scaleway-cli/pkg/utils"
 )
 
 // PsArgs are flags for the `RunPs` function
 type PsArgs struct {
-	NLast   int
-	All     bool
-	Latest  bool
-	NoTrunc bool
-	Quiet   bool
-	Filters map[string]string
+	NLast       int
+	All         bool
+	Latest      bool
+	NoTrunc     bool
+	Quiet       bool
+	AllProfiles bool
+	GroupBy     string
+	Filters     map[string]string
+}
+
+// psEntry is a listed server with the profile/region it was fetched from
+type psEntry struct {
+	source string
+	server api.ScalewayServer
 }
 
 // RunPs is the handler for 'scw ps'
@@ -36,27 +47,235 @@ func RunPs(ctx CommandContext, args PsArgs) error {
 		limit = 1
 	}
 
+	switch args.GroupBy {
+	case "", "tag", "image", "type", "state":
+	default:
+		return fmt.Errorf("invalid --group-by %q, expected 'tag', 'image', 'type' or 'state'", args.GroupBy)
+	}
+
 	filterState := args.Filters["state"]
 
+	if _, err := parseDateFilters(args.Filters); err != nil {
+		return err
+	}
+	for key, value := range args.Filters {
+		if !isServerFilter(key) {
+			logrus.Warnf("Unknown filter: '%s=%s'", key, value)
+		}
+	}
+
 	// FIXME: if filter state is defined, try to optimize the query
 	all := args.All || limit > 0 || filterState != ""
-	servers, err := ctx.API.GetServers(all, 0)
-	if err != nil {
-		return fmt.Errorf("Unable to fetch servers from the Scaleway API: %v", err)
+	var entries []psEntry
+	if args.AllProfiles {
+		var err error
+		if entries, err = listAllProfilesServers(ctx, all, args.Filters); err != nil {
+			return err
+		}
+	} else {
+		servers, err := ctx.API.GetServers(all, 0)
+		if err != nil {
+			return api.Wrapf(err, "Unable to fetch servers from the Scaleway API")
+		}
+		for _, server := range filterServers(ctx.API, *servers, args.Filters) {
+			entries = append(entries, psEntry{server: server})
+		}
 	}
 
-	for key, value := range args.Filters {
-		switch key {
-		case "state", "name", "tags", "image", "ip", "arch", "server-type", "zone":
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[j].server.CreationDate.Value().Before(entries[i].server.CreationDate.Value())
+	})
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	w := tabwriter.NewWriter(ctx.Stdout, 20, 1, 3, ' ', 0)
+	defer w.Flush()
+	if args.Quiet {
+		for _, entry := range entries {
+			fmt.Fprintf(w, "%s\n", entry.server.Identifier)
+		}
+		return nil
+	}
+	if args.GroupBy != "" {
+		groups, keys := groupPsEntries(entries, args.GroupBy)
+		for _, key := range keys {
+			fmt.Fprintf(w, "%s: %s (%d)\n", strings.ToUpper(args.GroupBy), key, len(groups[key]))
+			printPsEntries(ctx, w, groups[key], args)
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintln(w, psSummary(entries))
+		return nil
+	}
+	printPsEntries(ctx, w, entries, args)
+	return nil
+}
+
+// printPsEntries prints a header followed by a line per server
+func printPsEntries(ctx CommandContext, w *tabwriter.Writer, entries []psEntry, args PsArgs) {
+	if args.AllProfiles {
+		fmt.Fprintf(w, "PROFILE/REGION\t")
+	}
+	fmt.Fprintf(w, "SERVER ID\tIMAGE\tZONE\tCREATED\tSTATUS\tPORTS\tNAME\tCOMMERCIAL TYPE\n")
+	for _, entry := range entries {
+		server := entry.server
+		if args.AllProfiles {
+			fmt.Fprintf(w, "%s\t", entry.source)
+		}
+		shortID := utils.TruncIf(server.Identifier, 8, !args.NoTrunc)
+		shortImage := utils.TruncIf(utils.Wordify(server.Image.Name), 25, !args.NoTrunc)
+		shortName := utils.TruncIf(utils.Wordify(server.Name), 25, !args.NoTrunc)
+		shortCreationDate := ctx.FormatTime(server.CreationDate.Value())
+		port := server.PublicAddress.IP
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", shortID, shortImage, server.Location.ZoneID, shortCreationDate, server.State, port, shortName, server.CommercialType)
+	}
+}
+
+// groupPsEntries splits entries by tag, image, type or state and returns the groups with their sorted names,
+// a server with several tags is listed in each of them
+func groupPsEntries(entries []psEntry, groupBy string) (map[string][]psEntry, []string) {
+	groups := make(map[string][]psEntry)
+	for _, entry := range entries {
+		var names []string
+		switch groupBy {
+		case "tag":
+			names = entry.server.Tags
+			if len(names) == 0 {
+				names = []string{storageReportUntagged}
+			}
+		case "image":
+			names = []string{entry.server.Image.Name}
+		case "type":
+			names = []string{entry.server.CommercialType}
+		case "state":
+			names = []string{entry.server.State}
+		}
+		for _, name := range names {
+			groups[name] = append(groups[name], entry)
+		}
+	}
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return groups, keys
+}
+
+// psSummary returns the number of servers per state and their estimated monthly price,
+// archived servers (state "stopped") are not billed for compute
+func psSummary(entries []psEntry) string {
+	states := make(map[string]int)
+	total := new(big.Rat)
+	currency := "EUR"
+	unknown := 0
+	for _, entry := range entries {
+		server := entry.server
+		states[server.State]++
+		if server.State == "stopped" {
 			continue
-		default:
-			logrus.Warnf("Unknown filter: '%s=%s'", key, value)
 		}
+		price := pricing.CurrentPricing.GetByPath("/compute/" + strings.ToLower(server.CommercialType) + "/run")
+		if price == nil {
+			unknown++
+			continue
+		}
+		total.Add(total, price.MonthPrice(price.UnitQuantity))
+		currency = price.Currency
+	}
+	names := make([]string, 0, len(states))
+	for state := range states {
+		names = append(names, state)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, state := range names {
+		parts[i] = fmt.Sprintf("%d %s", states[state], state)
+	}
+	summary := fmt.Sprintf("%d servers", len(entries))
+	if len(parts) > 0 {
+		summary += ": " + strings.Join(parts, ", ")
+	}
+	summary += fmt.Sprintf(", estimated %s/month", pricing.PriceString(total, currency))
+	if unknown > 0 {
+		summary += fmt.Sprintf(" (%d servers of unknown price)", unknown)
+	}
+	return summary
+}
+
+// listAllProfilesServers fetches concurrently the servers of every configured profile and region
+func listAllProfilesServers(ctx CommandContext, all bool, filters map[string]string) ([]psEntry, error) {
+	cfg, err := config.GetConfig(ctx.ConfigPath)
+	if err != nil {
+		return nil, api.Wrapf(err, "unable to open .scwrc config file")
+	}
+	names, profiles := cfg.AllProfiles()
+
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	var entries []psEntry
+	hasError := false
+	for _, name := range names {
+		profile := profiles[name]
+		regions := profile.Regions
+		if len(regions) == 0 {
+			regions = api.Regions
+		}
+		for _, region := range regions {
+			wg.Add(1)
+			go func(name, region string, profile config.Profile) {
+				defer wg.Done()
+				source := name + "/" + region
+				client, err := api.NewScalewayAPI(profile.Organization, profile.Token, scwversion.UserAgent(), region, func(s *api.ScalewayAPI) {
+					s.Logger = ctx.API
+				})
+				if err == nil {
+					var servers *[]api.ScalewayServer
+					if servers, err = client.GetServers(all, 0); err == nil {
+						filtered := filterServers(client, *servers, filters)
+						lock.Lock()
+						for _, server := range filtered {
+							entries = append(entries, psEntry{source: source, server: server})
+						}
+						lock.Unlock()
+						return
+					}
+				}
+				logrus.Errorf("Unable to fetch servers of %s: %v", source, err)
+				lock.Lock()
+				hasError = true
+				lock.Unlock()
+			}(name, region, profile)
+		}
+	}
+	wg.Wait()
+	if hasError && len(entries) == 0 {
+		return nil, fmt.Errorf("unable to fetch servers from any profile")
 	}
-	filtered := make([]api.ScalewayServer, 0, len(*servers))
-	for _, server := range *servers {
+	return entries, nil
+}
+
+// isServerFilter returns true if key is a filter known by filterServers
+func isServerFilter(key string) bool {
+	switch key {
+	case "state", "name", "tags", "image", "ip", "arch", "server-type", "zone",
+		"created-before", "created-after", "modified-before", "modified-after":
+		return true
+	}
+	return false
+}
+
+// filterServers returns the servers matching every filter
+func filterServers(client api.ScalewayAPIClient, servers []api.ScalewayServer, filters map[string]string) []api.ScalewayServer {
+	filtered := make([]api.ScalewayServer, 0, len(servers))
+	// the date filters are validated by RunPs
+	dates, _ := parseDateFilters(filters)
+	for _, server := range servers {
+		if !dates.match(server.CreationDate.Value(), server.ModificationDate.Value()) {
+			continue
+		}
 		// filtering
-		for key, value := range args.Filters {
+		for key, value := range filters {
 			switch key {
 			case "state":
 				if value != server.State {
@@ -78,7 +297,7 @@ func RunPs(ctx CommandContext, args PsArgs) error {
 					goto skipServer
 				}
 			case "image":
-				imageID, err := ctx.API.GetImageID(value, "*")
+				imageID, err := client.GetImageID(value, "*")
 				if err != nil {
 					goto skipServer
 				}
@@ -107,27 +326,39 @@ func RunPs(ctx CommandContext, args PsArgs) error {
 	skipServer:
 		continue
 	}
-	w := tabwriter.NewWriter(ctx.Stdout, 20, 1, 3, ' ', 0)
-	defer w.Flush()
-	if !args.Quiet {
-		fmt.Fprintf(w, "SERVER ID\tIMAGE\tZONE\tCREATED\tSTATUS\tPORTS\tNAME\tCOMMERCIAL TYPE\n")
-	}
-	sort.Sort(api.ScalewaySortServers(filtered))
-	for i, server := range filtered {
-		if limit > 0 && i >= limit {
-			break
-		}
-		if args.Quiet {
-			fmt.Fprintf(w, "%s\n", server.Identifier)
-		} else {
-			shortID := utils.TruncIf(server.Identifier, 8, !args.NoTrunc)
-			shortImage := utils.TruncIf(utils.Wordify(server.Image.Name), 25, !args.NoTrunc)
-			shortName := utils.TruncIf(utils.Wordify(server.Name), 25, !args.NoTrunc)
-			creationTime, _ := time.Parse("2006-01-02T15:04:05.000000+00:00", server.CreationDate)
-			shortCreationDate := units.HumanDuration(time.Now().UTC().Sub(creationTime))
-			port := server.PublicAddress.IP
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", shortID, shortImage, server.Location.ZoneID, shortCreationDate, server.State, port, shortName, server.CommercialType)
+	return filtered
+}
+
+// selectServers returns the servers matching needles, or the servers matching filters when there are filters,
+// only running servers are considered unless a state filter is given
+func selectServers(ctx CommandContext, needles []string, filters map[string]string) ([]api.ScalewayServer, error) {
+	if len(filters) > 0 {
+		// an ignored filter would select every server
+		for key := range filters {
+			if !isServerFilter(key) {
+				return nil, fmt.Errorf("unknown filter %q, see 'scw ps -h'", key)
+			}
+		}
+		if _, err := parseDateFilters(filters); err != nil {
+			return nil, err
+		}
+		servers, err := ctx.API.GetServers(filters["state"] != "", 0)
+		if err != nil {
+			return nil, api.Wrapf(err, "unable to fetch servers from the Scaleway API")
 		}
+		return filterServers(ctx.API, *servers, filters), nil
 	}
-	return nil
+	servers := make([]api.ScalewayServer, 0, len(needles))
+	for _, needle := range needles {
+		serverID, err := ctx.API.GetServerID(needle)
+		if err != nil {
+			return nil, err
+		}
+		server, err := ctx.API.GetServer(serverID)
+		if err != nil {
+			return nil, api.Wrapf(err, "cannot fetch server %s", needle)
+		}
+		servers = append(servers, *server)
+	}
+	return servers, nil
 }
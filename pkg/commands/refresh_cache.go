@@ -0,0 +1,137 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/sirupsen/logrus"
+)
+
+// RefreshCacheArgs are flags for the `RunRefreshCache` function
+type RefreshCacheArgs struct {
+	Types []string
+	Prune bool
+}
+
+// cacheFetcher fetches every object of a type and returns how many were inserted in the cache
+type cacheFetcher struct {
+	kind  int
+	fetch func(api.ScalewayAPIClient) (int, error)
+}
+
+// CacheTypes are the resource types handled by `RunRefreshCache`, in refresh order
+var CacheTypes = []string{"servers", "images", "snapshots", "volumes", "bootscripts", "ips"}
+
+var cacheFetchers = map[string]cacheFetcher{
+	"servers": {api.IdentifierServer, func(client api.ScalewayAPIClient) (int, error) {
+		servers, err := client.GetServers(true, 0)
+		if err != nil {
+			return 0, err
+		}
+		return len(*servers), nil
+	}},
+	"images": {api.IdentifierImage, func(client api.ScalewayAPIClient) (int, error) {
+		images, err := client.GetImages()
+		if err != nil {
+			return 0, err
+		}
+		return len(*images), nil
+	}},
+	"snapshots": {api.IdentifierSnapshot, func(client api.ScalewayAPIClient) (int, error) {
+		snapshots, err := client.GetSnapshots()
+		if err != nil {
+			return 0, err
+		}
+		return len(*snapshots), nil
+	}},
+	"volumes": {api.IdentifierVolume, func(client api.ScalewayAPIClient) (int, error) {
+		volumes, err := client.GetVolumes()
+		if err != nil {
+			return 0, err
+		}
+		return len(*volumes), nil
+	}},
+	"bootscripts": {api.IdentifierBootscript, func(client api.ScalewayAPIClient) (int, error) {
+		bootscripts, err := client.GetBootscripts()
+		if err != nil {
+			return 0, err
+		}
+		return len(*bootscripts), nil
+	}},
+	"ips": {api.IdentifierIP, func(client api.ScalewayAPIClient) (int, error) {
+		ips, err := client.GetIPS()
+		if err != nil {
+			return 0, err
+		}
+		return len(ips.IPS), nil
+	}},
+}
+
+// RunRefreshCache is the handler for 'scw _refresh-cache'
+func RunRefreshCache(ctx CommandContext, args RefreshCacheArgs) error {
+	types := args.Types
+	if len(types) == 0 {
+		types = CacheTypes
+	}
+	for _, kind := range types {
+		if _, ok := cacheFetchers[kind]; !ok {
+			return fmt.Errorf("unknown resource type %q, must be one of %s", kind, strings.Join(CacheTypes, ", "))
+		}
+	}
+
+	type refreshed struct {
+		count    int
+		duration time.Duration
+		err      error
+	}
+	results := make([]refreshed, len(types))
+	// bootscripts and marketplace images are fetched again instead of being read from the cached responses
+	ctx.API.SetRefreshResponses(true)
+	var wg sync.WaitGroup
+	for i, kind := range types {
+		wg.Add(1)
+		go func(i int, fetcher cacheFetcher) {
+			defer wg.Done()
+			var previous map[string][api.CacheMaxfield]string
+			if args.Prune {
+				// start from an empty set so deleted objects disappear, restored on failure,
+				// the objects of the other regions are not fetched and are kept
+				previous = ctx.API.ResolverCache().SwapRegion(fetcher.kind, ctx.API.CurrentRegion())
+			}
+			start := time.Now()
+			count, err := fetcher.fetch(ctx.API)
+			if err != nil && args.Prune {
+				ctx.API.ResolverCache().SwapType(fetcher.kind, previous)
+			}
+			results[i] = refreshed{count: count, duration: time.Since(start), err: err}
+		}(i, cacheFetchers[kind])
+	}
+	wg.Wait()
+
+	failed := 0
+	for i, kind := range types {
+		if results[i].err != nil {
+			logrus.Errorf("failed to refresh %s: %v", kind, results[i].err)
+			failed++
+			continue
+		}
+		logrus.Debugf("refreshed %d %s in %v", results[i].count, kind, results[i].duration)
+		fmt.Fprintf(ctx.Stdout, "%-12s %d\n", kind, results[i].count)
+	}
+
+	ctx.API.ResolverCache().MarkModified()
+	if err := ctx.API.ResolverCache().Save(); err != nil {
+		return fmt.Errorf("cannot write cache file %s: %v", ctx.API.ResolverCache().Path, err)
+	}
+	if failed > 0 {
+		return fmt.Errorf("at least 1 resource type failed to be refreshed")
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/scaleway/scaleway-cli/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// remoteServer runs commands on a server through SSH, for 'scw cp', 'scw _deploy' and 'scw _rolling'
+type remoteServer struct {
+	server  *api.ScalewayServer
+	gateway string
+	user    string
+	port    int
+}
+
+// command returns a command running remoteCommand on the server
+func (r *remoteServer) command(remoteCommand string) *exec.Cmd {
+	sshCommand := utils.NewSSHExecCmd(r.server.PublicAddress.IP, r.server.PrivateIP, r.user, r.port, false, []string{remoteCommand}, r.gateway, false)
+	logrus.Debugf("Executing: %s", sshCommand)
+	return exec.Command("ssh", sshCommand.Slice()[1:]...)
+}
+
+// run runs remoteCommand on the server, its output is only returned on failure
+func (r *remoteServer) run(step, remoteCommand string) error {
+	logrus.Debugf("%s: %s", r.server.Name, remoteCommand)
+	if out, err := r.command(remoteCommand).CombinedOutput(); err != nil {
+		return fmt.Errorf("%s failed: %v: %s", step, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
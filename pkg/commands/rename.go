@@ -5,8 +5,6 @@
 package commands
 
 import (
-	"fmt"
-
 	"github.com/scaleway/scaleway-cli/pkg/api"
 )
 
@@ -26,10 +24,10 @@ func RunRename(ctx CommandContext, args RenameArgs) error {
 		api.ScalewayServerPatchDefinition{
 			Name: &args.NewName,
 		}); err != nil {
-		return fmt.Errorf("cannot rename server: %v", err)
+		return api.Wrapf(err, "cannot rename server")
 	}
 	if server, err := ctx.API.GetServer(serverID); err == nil {
-		ctx.API.Cache.InsertServer(serverID, server.Location.ZoneID, server.Arch, server.Organization, server.Name)
+		ctx.API.ResolverCache().InsertServer(serverID, server.Location.ZoneID, server.Arch, server.Organization, server.Name)
 	}
 	return nil
 }
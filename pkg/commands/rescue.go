@@ -0,0 +1,146 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/sirupsen/logrus"
+)
+
+// RescueArgs are flags for the `RunRescue` function
+type RescueArgs struct {
+	Server     string
+	Disable    bool
+	Bootscript string
+	Gateway    string
+	SSHUser    string
+	SSHPort    int
+}
+
+// rescueTagPrefix prefixes the tag storing the boot configuration to restore with --disable,
+// i.e: "scw-rescue:local:" or "scw-rescue:bootscript:<bootscript id>"
+const rescueTagPrefix = "scw-rescue:"
+
+// RunRescue is the handler for 'scw _rescue'
+func RunRescue(ctx CommandContext, args RescueArgs) error {
+	serverID, err := ctx.API.GetServerID(args.Server)
+	if err != nil {
+		return err
+	}
+	server, err := ctx.API.GetServer(serverID)
+	if err != nil {
+		return api.Wrapf(err, "unable to fetch server %s", serverID)
+	}
+	gateway, err := api.ResolveGateway(ctx.API, args.Gateway)
+	if err != nil {
+		return api.Wrapf(err, "cannot resolve Gateway '%s'", args.Gateway)
+	}
+
+	if args.Disable {
+		err = disableRescue(ctx, server)
+	} else {
+		err = enableRescue(ctx, server, args.Bootscript)
+	}
+	if err != nil {
+		return err
+	}
+
+	// a stopped server is started, a running one is rebooted on the new bootscript
+	action := "reboot"
+	if server.State == "stopped" {
+		action = "poweron"
+	}
+	logrus.Infof("Sending %s to server %s", action, server.Name)
+	if err = ctx.API.PostServerAction(serverID, action); err != nil {
+		return api.Wrapf(err, "failed to %s server %s", action, serverID)
+	}
+	logrus.Info("Waiting for SSH to be available")
+	start := time.Now()
+	server, err = api.WaitForServerReady(ctx.API, serverID, gateway)
+	event := NotifyRescueReady
+	if args.Disable {
+		event = NotifyServerBooted
+	}
+	ctx.Notify(event, args.Server, start, err)
+	if err != nil {
+		return api.Wrapf(err, "cannot get access to server %s", serverID)
+	}
+
+	if args.Disable {
+		fmt.Fprintf(ctx.Stdout, "Server %s (%s) left rescue mode and is booted normally\n", server.Name, serverID)
+		return nil
+	}
+	sshCommand := fmt.Sprintf("ssh %s@%s", args.SSHUser, server.PublicAddress.IP)
+	if args.SSHPort != 22 {
+		sshCommand += fmt.Sprintf(" -p %d", args.SSHPort)
+	}
+	if gateway != "" {
+		sshCommand += fmt.Sprintf(" -J %s@%s", args.SSHUser, gateway)
+	}
+	fmt.Fprintf(ctx.Stdout, "Server %s (%s) is in rescue mode\n\n", server.Name, serverID)
+	fmt.Fprintf(ctx.Stdout, "Connect with:\n    %s\n    scw exec %s /bin/sh\n\n", sshCommand, serverID)
+	fmt.Fprintf(ctx.Stdout, "The volumes of the server are not mounted, list them with lsblk\n\n")
+	fmt.Fprintf(ctx.Stdout, "Leave rescue mode with:\n    scw _rescue --disable %s\n", serverID)
+	return nil
+}
+
+// enableRescue switches server to the rescue bootscript and remembers its boot configuration in a tag
+func enableRescue(ctx CommandContext, server *api.ScalewayServer, bootscript string) error {
+	for _, tag := range server.Tags {
+		if strings.HasPrefix(tag, rescueTagPrefix) {
+			return fmt.Errorf("server %s is already in rescue mode, use --disable to leave it", server.Identifier)
+		}
+	}
+	rescueID, err := ctx.API.GetBootscriptID(bootscript, server.Arch)
+	if err != nil {
+		return err
+	}
+
+	previousBootscript := ""
+	if server.Bootscript != nil {
+		previousBootscript = server.Bootscript.Identifier
+	}
+	bootType := "bootscript"
+	tags := append(server.Tags, rescueTagPrefix+server.BootType+":"+previousBootscript)
+	logrus.Debugf("Switching server %s to bootscript %s", server.Identifier, rescueID)
+	return ctx.API.PatchServer(server.Identifier, api.ScalewayServerPatchDefinition{
+		BootType:   &bootType,
+		Bootscript: &rescueID,
+		Tags:       &tags,
+	})
+}
+
+// disableRescue restores the boot configuration saved by enableRescue
+func disableRescue(ctx CommandContext, server *api.ScalewayServer) error {
+	saved := ""
+	tags := []string{}
+	for _, tag := range server.Tags {
+		if strings.HasPrefix(tag, rescueTagPrefix) {
+			saved = strings.TrimPrefix(tag, rescueTagPrefix)
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	if saved == "" {
+		return fmt.Errorf("server %s is not in rescue mode", server.Identifier)
+	}
+
+	payload := api.ScalewayServerPatchDefinition{Tags: &tags}
+	parts := strings.SplitN(saved, ":", 2)
+	bootType := parts[0]
+	if bootType == "" {
+		bootType = "local"
+	}
+	payload.BootType = &bootType
+	if len(parts) == 2 && parts[1] != "" {
+		payload.Bootscript = &parts[1]
+	}
+	logrus.Debugf("Restoring boot type %s on server %s", saved, server.Identifier)
+	return ctx.API.PatchServer(server.Identifier, payload)
+}
@@ -20,38 +20,20 @@ type RestartArgs struct {
 	Servers []string
 }
 
-// restartIdentifiers resolves server IDs, restarts, and waits for them to be ready (-w)
-func restartIdentifiers(ctx CommandContext, wait bool, servers []string, cr chan string) {
-	var wg sync.WaitGroup
-	for _, needle := range servers {
-		wg.Add(1)
-		go func(needle string) {
-			res := ""
-
-			defer wg.Done()
-			server, err := ctx.API.GetServerID(needle)
-			if err != nil {
-				logrus.Error(err)
-			} else {
-				res = server
-				err := ctx.API.PostServerAction(server, "reboot")
-				if err != nil {
-					if err.Error() != "server is being stopped or rebooted" {
-						logrus.Errorf("failed to restart server %s: %s", server, err)
-					}
-					res = ""
-				} else {
-					if wait {
-						// FIXME: handle gateway
-						api.WaitForServerReady(ctx.API, server, "")
-					}
-				}
-			}
-			cr <- res
-		}(needle)
+// restartIdentifier resolves a server ID, restarts it, and waits for it to be ready (-w)
+func restartIdentifier(ctx CommandContext, wait bool, needle string) error {
+	server, err := ctx.API.GetServerID(needle)
+	if err != nil {
+		return err
 	}
-	wg.Wait()
-	close(cr)
+	if err = ctx.API.PostServerAction(server, "reboot"); err != nil {
+		return err
+	}
+	if wait {
+		// FIXME: handle gateway
+		api.WaitForServerReady(ctx.API, server, "")
+	}
+	return nil
 }
 
 // RunRestart is the handler for 'scw restart'
@@ -64,23 +46,22 @@ func RunRestart(ctx CommandContext, args RestartArgs) error {
 		}()
 	}
 
-	cr := make(chan string)
-	go restartIdentifiers(ctx, args.Wait, args.Servers, cr)
-	hasError := false
-
-	for {
-		uuid, more := <-cr
-		if !more {
-			break
-		}
-		if len(uuid) > 0 {
-			fmt.Fprintln(ctx.Stdout, uuid)
-		} else {
-			hasError = true
-		}
+	result := NewBulkResult(ctx, "restart server", args.Servers)
+	var wg sync.WaitGroup
+	for _, needle := range args.Servers {
+		wg.Add(1)
+		go func(needle string) {
+			defer wg.Done()
+			done := result.Start(needle)
+			done(restartIdentifier(ctx, args.Wait, needle))
+		}(needle)
 	}
+	wg.Wait()
 
-	if hasError {
+	if err := result.Report(); err != nil {
+		return err
+	}
+	if result.Failed() > 0 {
 		return fmt.Errorf("at least 1 server failed to restart")
 	}
 	return nil
@@ -6,37 +6,118 @@ package commands
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
-	"github.com/sirupsen/logrus"
+	"github.com/dustin/go-humanize"
+	"github.com/scaleway/scaleway-cli/pkg/api"
 )
 
 // RmArgs are flags for the `RunRm` function
 type RmArgs struct {
-	Servers []string
-	Force   bool
+	Servers        []string
+	Force          bool
+	ForceProtected bool
+	WithVolumes    bool
+	WithIP         bool
+}
+
+// rmCascade holds the resources deleted along a server by --with-volumes and --with-ip
+type rmCascade struct {
+	volumes []api.ScalewayVolume
+	ip      *api.ScalewayIPAddress
+}
+
+// newRmCascade returns the attached volumes and the reserved IP of server selected by args
+func newRmCascade(server *api.ScalewayServer, args RmArgs) rmCascade {
+	cascade := rmCascade{}
+	if args.WithVolumes {
+		keys := make([]string, 0, len(server.Volumes))
+		for key := range server.Volumes {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			cascade.volumes = append(cascade.volumes, server.Volumes[key])
+		}
+	}
+	// a dynamic IP is released with the server
+	reserved := server.PublicAddress.Dynamic == nil || !*server.PublicAddress.Dynamic
+	if args.WithIP && server.PublicAddress.Identifier != "" && reserved {
+		cascade.ip = &server.PublicAddress
+	}
+	return cascade
+}
+
+// preview describes the resources deleted along the server, empty when there is none
+func (c rmCascade) preview() string {
+	parts := []string{}
+	for _, volume := range c.volumes {
+		parts = append(parts, fmt.Sprintf("volume %s (%s, %s)", volume.Name, volume.Identifier, humanize.Bytes(volume.Size)))
+	}
+	if c.ip != nil {
+		parts = append(parts, fmt.Sprintf("IP %s (%s)", c.ip.IP, c.ip.Identifier))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// delete deletes the volumes and releases the IP, the ones already gone, i.e: with a terminated server, are skipped
+func (c rmCascade) delete(ctx CommandContext) error {
+	for _, volume := range c.volumes {
+		if err := ctx.API.DeleteVolume(volume.Identifier); err != nil && !api.IsNotFound(err) {
+			return api.Wrapf(err, "server deleted but not its volume %s", volume.Name)
+		}
+	}
+	if c.ip != nil {
+		if err := ctx.API.DeleteIP(c.ip.Identifier); err != nil && !api.IsNotFound(err) {
+			return api.Wrapf(err, "server deleted but its IP %s is not released", c.ip.IP)
+		}
+	}
+	return nil
 }
 
 // RunRm is the handler for 'scw rm'
 func RunRm(ctx CommandContext, args RmArgs) error {
-	hasError := false
+	result := NewBulkResult(ctx, "delete server", args.Servers)
 	for _, needle := range args.Servers {
+		done := result.Start(needle)
 		server, err := ctx.API.GetServerID(needle)
 		if err != nil {
-			return err
+			done(err)
+			continue
+		}
+		if !args.ForceProtected {
+			if err = checkProtected(ctx, server); err != nil {
+				done(err)
+				continue
+			}
+		}
+		cascade := rmCascade{}
+		if args.WithVolumes || args.WithIP {
+			definition, err := ctx.API.GetServer(server)
+			if err != nil {
+				done(api.Wrapf(err, "cannot fetch server"))
+				continue
+			}
+			cascade = newRmCascade(definition, args)
+			if preview := cascade.preview(); preview != "" {
+				fmt.Fprintf(ctx.Stderr, "Deleting %s with %s\n", definition.Name, preview)
+			}
 		}
 		if args.Force {
 			err = ctx.API.DeleteServerForce(server)
 		} else {
 			err = ctx.API.DeleteServer(server)
 		}
-		if err != nil {
-			logrus.Errorf("failed to delete server %s: %s", server, err)
-			hasError = true
-		} else {
-			fmt.Fprintln(ctx.Stdout, needle)
+		if err == nil {
+			err = cascade.delete(ctx)
 		}
+		done(err)
+	}
+	if err := result.Report(); err != nil {
+		return err
 	}
-	if hasError {
+	if result.Failed() > 0 {
 		return fmt.Errorf("at least 1 server failed to be removed")
 	}
 	return nil
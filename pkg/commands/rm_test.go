@@ -0,0 +1,67 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRunRm(t *testing.T) {
+	Convey("Testing RunRm() against a FakeScalewayAPI", t, func() {
+		fake := api.NewFakeScalewayAPI("orga")
+		fake.Servers = []api.ScalewayServer{
+			{Identifier: "11111111-1111-1111-1111-111111111111", Name: "web", State: "stopped"},
+			{Identifier: "22222222-2222-2222-2222-222222222222", Name: "db", State: "stopped", Tags: []string{ProtectedTag}},
+		}
+		stdout := bytes.Buffer{}
+		ctx := CommandContext{
+			Streams: Streams{Stdout: &stdout, Stderr: &bytes.Buffer{}},
+			API:     fake,
+		}
+
+		err := RunRm(ctx, RmArgs{Servers: []string{"web", "db"}})
+		So(err, ShouldNotBeNil)
+		So(len(fake.Servers), ShouldEqual, 1)
+		So(fake.Servers[0].Name, ShouldEqual, "db")
+		So(stdout.String(), ShouldContainSubstring, "web")
+
+		err = RunRm(ctx, RmArgs{Servers: []string{"2222"}, ForceProtected: true})
+		So(err, ShouldBeNil)
+		So(len(fake.Servers), ShouldEqual, 0)
+	})
+}
+
+func TestRunRmWithVolumesAndIP(t *testing.T) {
+	Convey("Testing RunRm() with --with-volumes and --with-ip", t, func() {
+		fake := api.NewFakeScalewayAPI("orga")
+		volume := api.ScalewayVolume{Identifier: "33333333-3333-3333-3333-333333333333", Name: "web-data", Size: 50000000000}
+		fake.Volumes = []api.ScalewayVolume{volume, {Identifier: "44444444-4444-4444-4444-444444444444", Name: "other"}}
+		fake.IPs = []api.ScalewayIPDefinition{{ID: "55555555-5555-5555-5555-555555555555", Address: "51.15.1.2"}}
+		fake.Servers = []api.ScalewayServer{{
+			Identifier:    "11111111-1111-1111-1111-111111111111",
+			Name:          "web",
+			State:         "stopped",
+			Volumes:       map[string]api.ScalewayVolume{"0": volume},
+			PublicAddress: api.ScalewayIPAddress{Identifier: "55555555-5555-5555-5555-555555555555", IP: "51.15.1.2"},
+		}}
+		stderr := bytes.Buffer{}
+		ctx := CommandContext{
+			Streams: Streams{Stdout: &bytes.Buffer{}, Stderr: &stderr},
+			API:     fake,
+		}
+
+		err := RunRm(ctx, RmArgs{Servers: []string{"web"}, WithVolumes: true, WithIP: true})
+		So(err, ShouldBeNil)
+		So(stderr.String(), ShouldEqual, "Deleting web with volume web-data (33333333-3333-3333-3333-333333333333, 50 GB), IP 51.15.1.2 (55555555-5555-5555-5555-555555555555)\n")
+		So(len(fake.Servers), ShouldEqual, 0)
+		So(len(fake.Volumes), ShouldEqual, 1)
+		So(fake.Volumes[0].Name, ShouldEqual, "other")
+		So(len(fake.IPs), ShouldEqual, 0)
+	})
+}
@@ -6,49 +6,50 @@ package commands
 
 import (
 	"fmt"
-
-	"github.com/sirupsen/logrus"
 )
 
 // RmiArgs are flags for the `RunRmi` function
 type RmiArgs struct {
-	Identifier []string // images/volumes/snapshots
+	Identifier     []string // images/volumes/snapshots
+	ForceProtected bool
 }
 
 // RunRmi is the handler for 'scw rmi'
 func RunRmi(ctx CommandContext, args RmiArgs) error {
-	hasError := false
+	result := NewBulkResult(ctx, "remove", args.Identifier)
 	for _, needle := range args.Identifier {
+		done := result.Start(needle)
 		if image, err := ctx.API.GetImageID(needle, "*"); err == nil {
-			if err = ctx.API.DeleteImage(image.Identifier); err != nil {
-				logrus.Errorf("failed to delete image %s: %s", image.Identifier, err)
-				hasError = true
-			} else {
-				fmt.Fprintln(ctx.Stdout, needle)
-			}
+			done(ctx.API.DeleteImage(image.Identifier))
 			continue
 		}
 		if snapshotID, err := ctx.API.GetSnapshotID(needle); err == nil {
-			if err = ctx.API.DeleteSnapshot(snapshotID); err != nil {
-				logrus.Errorf("failed to delete snapshot %s: %s", snapshotID, err)
-				hasError = true
-			} else {
-				fmt.Fprintln(ctx.Stdout, needle)
-			}
+			done(ctx.API.DeleteSnapshot(snapshotID))
 			continue
 		}
 		if volumeID, err := ctx.API.GetVolumeID(needle); err == nil {
-			if err = ctx.API.DeleteVolume(volumeID); err != nil {
-				logrus.Errorf("failed to delete volume %s: %s", volumeID, err)
-				hasError = true
-			} else {
-				fmt.Fprintln(ctx.Stdout, needle)
+			if !args.ForceProtected {
+				volume, err := ctx.API.GetVolume(volumeID)
+				if err != nil {
+					done(err)
+					continue
+				}
+				if volume.Server != nil {
+					if err = checkProtected(ctx, volume.Server.Identifier); err != nil {
+						done(err)
+						continue
+					}
+				}
 			}
+			done(ctx.API.DeleteVolume(volumeID))
 			continue
 		}
-		hasError = true
+		done(fmt.Errorf("no such image, snapshot or volume"))
+	}
+	if err := result.Report(); err != nil {
+		return err
 	}
-	if hasError {
+	if result.Failed() > 0 {
 		return fmt.Errorf("at least 1 image/snapshot/volume failed to be removed")
 	}
 	return nil
@@ -0,0 +1,175 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/sirupsen/logrus"
+)
+
+// RollingArgs are arguments passed to `RunRolling`
+type RollingArgs struct {
+	Servers     []string
+	Filters     map[string]string
+	Batch       int
+	Pause       time.Duration
+	Action      []string
+	HealthCheck string
+	Gateway     string
+	SSHUser     string
+	SSHPort     int
+}
+
+// rollingBatches splits servers in batches of size servers
+func rollingBatches(servers []api.ScalewayServer, size int) [][]api.ScalewayServer {
+	batches := [][]api.ScalewayServer{}
+	for start := 0; start < len(servers); start += size {
+		end := start + size
+		if end > len(servers) {
+			end = len(servers)
+		}
+		batches = append(batches, servers[start:end])
+	}
+	return batches
+}
+
+// rollingServer applies the action to server then runs the health check
+func rollingServer(ctx CommandContext, args RollingArgs, server api.ScalewayServer, gateway string) error {
+	var err error
+	switch args.Action[0] {
+	case "restart":
+		if err = ctx.API.PostServerAction(server.Identifier, "reboot"); err != nil {
+			return err
+		}
+	case "start":
+		if err = api.StartServer(ctx.API, server.Identifier, false); err != nil {
+			return err
+		}
+	case "stop":
+		if err = ctx.API.PostServerAction(server.Identifier, "poweroff"); err != nil {
+			return err
+		}
+		_, err = api.WaitForServerStopped(ctx.API, server.Identifier)
+		return err
+	}
+	if args.Action[0] != "exec" {
+		updated, err := api.WaitForServerReady(ctx.API, server.Identifier, gateway)
+		if err != nil {
+			return api.Wrapf(err, "server did not come back")
+		}
+		server = *updated
+	}
+
+	remote := &remoteServer{
+		server:  &server,
+		gateway: gateway,
+		user:    args.SSHUser,
+		port:    args.SSHPort,
+	}
+	if args.Action[0] == "exec" {
+		if err = remote.run("exec", strings.Join(args.Action[1:], " ")); err != nil {
+			return err
+		}
+	}
+	if args.HealthCheck != "" {
+		return remote.run("health check", args.HealthCheck)
+	}
+	return nil
+}
+
+// RunRolling is the handler for 'scw _rolling'
+func RunRolling(ctx CommandContext, args RollingArgs) error {
+	if len(args.Action) == 0 {
+		return fmt.Errorf("missing action, expected restart, start, stop or exec COMMAND")
+	}
+	switch args.Action[0] {
+	case "restart", "start":
+	case "stop":
+		if args.HealthCheck != "" {
+			return fmt.Errorf("--health-check cannot be used with stop")
+		}
+	case "exec":
+		if len(args.Action) < 2 {
+			return fmt.Errorf("missing command, i.e: exec systemctl restart nginx")
+		}
+	default:
+		return fmt.Errorf("invalid action %q, expected restart, start, stop or exec COMMAND", args.Action[0])
+	}
+	if len(args.Action) > 1 && args.Action[0] != "exec" {
+		return fmt.Errorf("action %s takes no argument", args.Action[0])
+	}
+	if args.Batch < 1 {
+		return fmt.Errorf("invalid --batch %d, expected at least 1", args.Batch)
+	}
+
+	servers, err := selectServers(ctx, args.Servers, args.Filters)
+	if err != nil {
+		return err
+	}
+	if len(servers) == 0 {
+		return fmt.Errorf("no server matches the filters")
+	}
+	if args.Gateway == "" {
+		args.Gateway = ctx.Getenv("SCW_GATEWAY")
+	}
+	gateway, err := api.ResolveGateway(ctx.API, args.Gateway)
+	if err != nil {
+		return api.Wrapf(err, "cannot resolve Gateway '%s'", args.Gateway)
+	}
+
+	targets := make([]string, len(servers))
+	for i, server := range servers {
+		targets[i] = server.Name
+	}
+	if ctx.ReportFormat == "" {
+		ctx.ReportFormat = ReportFormatTable
+	}
+	result := NewBulkResult(ctx, args.Action[0], targets)
+	batches := rollingBatches(servers, args.Batch)
+	aborted := false
+	for i, batch := range batches {
+		if aborted {
+			for _, server := range batch {
+				result.Skip(server.Name, "aborted")
+			}
+			continue
+		}
+		logrus.Infof("Batch %d/%d: %s on %d servers ...", i+1, len(batches), args.Action[0], len(batch))
+		failed := result.Failed()
+		wg := sync.WaitGroup{}
+		for _, server := range batch {
+			wg.Add(1)
+			go func(server api.ScalewayServer) {
+				defer wg.Done()
+				done := result.Start(server.Name)
+				done(rollingServer(ctx, args, server, gateway))
+			}(server)
+		}
+		wg.Wait()
+
+		if result.Failed() > failed {
+			logrus.Errorf("Batch %d/%d failed, aborting", i+1, len(batches))
+			aborted = true
+			continue
+		}
+		if i < len(batches)-1 && args.Pause > 0 {
+			logrus.Infof("Pausing %s ...", args.Pause)
+			time.Sleep(args.Pause)
+		}
+	}
+
+	if err := result.Report(); err != nil {
+		return err
+	}
+	if aborted {
+		return fmt.Errorf("rolling %s aborted after %d failure(s)", args.Action[0], result.Failed())
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRollingBatches(t *testing.T) {
+	Convey("Testing rollingBatches()", t, func() {
+		servers := []api.ScalewayServer{{Name: "web1"}, {Name: "web2"}, {Name: "web3"}, {Name: "web4"}, {Name: "web5"}}
+		batches := rollingBatches(servers, 2)
+		So(len(batches), ShouldEqual, 3)
+		So(batches[0][1].Name, ShouldEqual, "web2")
+		So(len(batches[2]), ShouldEqual, 1)
+		So(batches[2][0].Name, ShouldEqual, "web5")
+		So(len(rollingBatches(servers, 10)), ShouldEqual, 1)
+	})
+}
+
+func TestRunRolling_invalidAction(t *testing.T) {
+	Convey("Testing RunRolling() argument validation", t, func() {
+		ctx := CommandContext{}
+		So(RunRolling(ctx, RollingArgs{Action: []string{"reboot"}, Batch: 1}), ShouldNotBeNil)
+		So(RunRolling(ctx, RollingArgs{Action: []string{"exec"}, Batch: 1}), ShouldNotBeNil)
+		So(RunRolling(ctx, RollingArgs{Action: []string{"stop"}, HealthCheck: "true", Batch: 1}), ShouldNotBeNil)
+		So(RunRolling(ctx, RollingArgs{Action: []string{"restart"}, Batch: 0}), ShouldNotBeNil)
+	})
+}
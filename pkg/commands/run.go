@@ -5,6 +5,8 @@
 package commands
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -12,6 +14,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/mattn/go-isatty"
 	"github.com/scaleway/scaleway-cli/pkg/api"
 	"github.com/scaleway/scaleway-cli/pkg/config"
 	"github.com/scaleway/scaleway-cli/pkg/utils"
@@ -20,27 +23,54 @@ import (
 
 // RunArgs are flags for the `Run` function
 type RunArgs struct {
-	Bootscript     string
-	Command        []string
-	Gateway        string
-	Image          string
-	Name           string
-	IP             string
-	Tags           []string
-	Volumes        []string
-	Userdata       string
-	CommercialType string
-	State          string
-	SSHUser        string
-	BootType       string
-	Timeout        int64
-	SSHPort        int
-	AutoRemove     bool
-	TmpSSHKey      bool
-	ShowBoot       bool
-	Detach         bool
-	Attach         bool
-	IPV6           bool
+	Bootscript      string
+	Command         []string
+	Gateway         string
+	Image           string
+	Name            string
+	NamePrefix      string
+	IP              string
+	Tags            []string
+	Volumes         []string
+	Userdata        string
+	CommercialType  string
+	State           string
+	SSHUser         string
+	BootType        string
+	Timeout         int64
+	SSHPort         int
+	AutoRemove      bool
+	TmpSSHKey       bool
+	ShowBoot        bool
+	Detach          bool
+	Attach          bool
+	AttachStdin     bool
+	IPV6            bool
+	ForceBootscript bool
+	PullPolicy      string
+	InitScript      string
+	DefinitionFile  string
+	FromSnapshot    string
+}
+
+// initScriptUserdataKey is the user_data key the init script is uploaded to
+const initScriptUserdataKey = "init-script"
+
+// initScriptCommand fetches the init script from the metadata API and executes it
+var initScriptCommand = fmt.Sprintf(
+	"(scw-userdata %[1]s 2>/dev/null || curl -sf --local-port 1-1024 http://169.254.42.42/user_data/%[1]s) > /tmp/scw-%[1]s && chmod +x /tmp/scw-%[1]s && /tmp/scw-%[1]s",
+	initScriptUserdataKey)
+
+// payloadCommand returns the command receiving a payload piped with --attach-stdin when none is given,
+// tarballs are extracted in / and anything else is executed by sh
+func payloadCommand(header []byte) []string {
+	switch {
+	case bytes.HasPrefix(header, []byte{0x1f, 0x8b}):
+		return []string{"tar", "-xzf", "-", "-C", "/"}
+	case len(header) >= 262 && bytes.Equal(header[257:262], []byte("ustar")):
+		return []string{"tar", "-xf", "-", "-C", "/"}
+	}
+	return []string{"sh", "-s"}
 }
 
 // AddSSHKeyToTags adds the ssh key in the tags
@@ -103,10 +133,10 @@ func addUserData(ctx CommandContext, userdatas []string, serverID string) {
 func runShowBoot(ctx CommandContext, args RunArgs, serverID, region string, closeTimeout chan struct{}, timeoutExit chan struct{}) error {
 	// Attach to server serial
 	logrus.Info("Attaching to server console ...")
-	gottycli, done, err := utils.AttachToSerial(serverID, ctx.API.Token, ctx.API.ResolveTTYUrl())
+	gottycli, done, err := utils.AttachToSerial(serverID, ctx.API.AuthToken(), ctx.API.ResolveTTYUrl(), utils.SerialOptions{})
 	if err != nil {
 		close(closeTimeout)
-		return fmt.Errorf("cannot attach to server serial: %v", err)
+		return api.Wrapf(err, "cannot attach to server serial")
 	}
 	utils.Quiet(true)
 	notif, gateway, err := waitSSHConnection(ctx, args, serverID)
@@ -138,7 +168,7 @@ func runShowBoot(ctx CommandContext, args RunArgs, serverID, region string, clos
 		server := sshConnection.server
 		logrus.Info("Connecting to server ...")
 		if err = utils.SSHExec(server.PublicAddress.IP, server.PrivateIP, args.SSHUser, args.SSHPort, []string{}, false, gateway, false); err != nil {
-			return fmt.Errorf("Connection to server failed: %v", err)
+			return api.Wrapf(err, "Connection to server failed")
 		}
 	}
 	return nil
@@ -150,12 +180,51 @@ func Run(ctx CommandContext, args RunArgs) error {
 		args.Gateway = ctx.Getenv("SCW_GATEWAY")
 	}
 
+	var definition *api.ServerDefinitionFile
+	if args.DefinitionFile != "" {
+		var err error
+		if definition, err = api.LoadServerDefinitionFile(args.DefinitionFile); err != nil {
+			return err
+		}
+		if args.Image == "" && args.FromSnapshot == "" && definition.Image != nil {
+			args.Image = *definition.Image
+		}
+		if len(args.Tags) == 0 {
+			args.Tags = definition.Tags
+		}
+		if args.IP == "" {
+			if definition.PublicIP != "" {
+				args.IP = definition.PublicIP
+			} else if definition.DynamicIPRequired != nil && !*definition.DynamicIPRequired {
+				args.IP = "none"
+			}
+		}
+	}
+
+	var payload *bufio.Reader
+	if args.AttachStdin {
+		if file, ok := ctx.Stdin.(*os.File); ok && isatty.IsTerminal(file.Fd()) {
+			return fmt.Errorf("--attach-stdin expects a payload piped on stdin, i.e: cat site.tgz | scw run --attach-stdin IMAGE")
+		}
+		payload = bufio.NewReaderSize(ctx.Stdin, 512)
+	}
+
 	if args.TmpSSHKey {
 		err := AddSSHKeyToTags(ctx, &args.Tags, args.Image)
 		if err != nil {
 			return err
 		}
 	}
+	var initScript []byte
+	if args.InitScript != "" {
+		var err error
+		if initScript, err = ioutil.ReadFile(args.InitScript); err != nil {
+			return api.Wrapf(err, "cannot read init script")
+		}
+		if !bytes.HasPrefix(initScript, []byte("#!")) {
+			initScript = append([]byte("#!/bin/sh\n"), initScript...)
+		}
+	}
 	env := strings.Join(args.Tags, " ")
 	volume := strings.Join(args.Volumes, " ")
 
@@ -164,6 +233,7 @@ func Run(ctx CommandContext, args RunArgs) error {
 	config := api.ConfigCreateServer{
 		ImageName:         args.Image,
 		Name:              args.Name,
+		NamePrefix:        args.NamePrefix,
 		Bootscript:        args.Bootscript,
 		Env:               env,
 		AdditionalVolumes: volume,
@@ -172,6 +242,10 @@ func Run(ctx CommandContext, args RunArgs) error {
 		CommercialType:    args.CommercialType,
 		EnableIPV6:        args.IPV6,
 		BootType:          args.BootType,
+		ForceBootscript:   args.ForceBootscript,
+		PullPolicy:        args.PullPolicy,
+		FromSnapshot:      args.FromSnapshot,
+		Definition:        definition,
 	}
 	if args.IP == "dynamic" || (args.IP == "" && args.Gateway == "") {
 		config.DynamicIPRequired = true
@@ -181,7 +255,7 @@ func Run(ctx CommandContext, args RunArgs) error {
 	}
 	serverID, err := api.CreateServer(ctx.API, &config)
 	if err != nil {
-		return fmt.Errorf("failed to create server: %v", err)
+		return api.Wrapf(err, "failed to create server")
 	}
 	logrus.Infof("Server created: %s", serverID)
 	logrus.Debugf("PublicDNS %s", serverID+api.URLPublicDNS)
@@ -191,10 +265,17 @@ func Run(ctx CommandContext, args RunArgs) error {
 		defer ctx.API.DeleteServerForce(serverID)
 	}
 
+	if initScript != nil {
+		logrus.Info("Uploading init script ...")
+		if err = ctx.API.PatchUserdata(serverID, initScriptUserdataKey, initScript, false); err != nil {
+			return api.Wrapf(err, "failed to upload init script")
+		}
+	}
+
 	// start SERVER
 	logrus.Info("Server start requested ...")
 	if err = api.StartServer(ctx.API, serverID, false); err != nil {
-		return fmt.Errorf("failed to start server %s: %v", serverID, err)
+		return api.Wrapf(err, "failed to start server %s", serverID)
 	}
 	logrus.Info("Server is starting, this may take up to a minute ...")
 
@@ -239,19 +320,19 @@ func Run(ctx CommandContext, args RunArgs) error {
 					}
 					return
 				}
-				time.Sleep(1 * time.Second)
+				time.Sleep(api.WaitPollInterval)
 			}
 		}()
 	}
 	if args.ShowBoot {
-		return runShowBoot(ctx, args, serverID, ctx.API.Region, closeTimeout, timeoutExit)
+		return runShowBoot(ctx, args, serverID, ctx.API.CurrentRegion(), closeTimeout, timeoutExit)
 	} else if args.Attach {
 		// Attach to server serial
 		logrus.Info("Attaching to server console ...")
-		gottycli, done, err := utils.AttachToSerial(serverID, ctx.API.Token, ctx.API.ResolveTTYUrl())
+		gottycli, done, err := utils.AttachToSerial(serverID, ctx.API.AuthToken(), ctx.API.ResolveTTYUrl(), utils.SerialOptions{})
 		close(closeTimeout)
 		if err != nil {
-			return fmt.Errorf("cannot attach to server serial: %v", err)
+			return api.Wrapf(err, "cannot attach to server serial")
 		}
 		<-done
 		gottycli.Close()
@@ -275,16 +356,41 @@ func Run(ctx CommandContext, args RunArgs) error {
 				}
 			}
 			server := sshConnection.server
+			if initScript != nil {
+				logrus.Infof("Executing init script %s ...", args.InitScript)
+				if err = utils.SSHExec(server.PublicAddress.IP, server.PrivateIP, args.SSHUser, args.SSHPort, []string{initScriptCommand}, false, gateway, false); err != nil {
+					return api.Wrapf(err, "init script failed")
+				}
+				logrus.Info("Init script successfully executed")
+				if len(args.Command) < 1 && payload == nil {
+					fmt.Fprintln(ctx.Stdout, serverID)
+					return nil
+				}
+			}
+			if payload != nil {
+				command := args.Command
+				if len(command) < 1 {
+					header, _ := payload.Peek(512)
+					command = payloadCommand(header)
+				}
+				logrus.Infof("Piping stdin into: %s ...", strings.Join(command, " "))
+				if err = utils.SSHExecStdin(server.PublicAddress.IP, server.PrivateIP, args.SSHUser, args.SSHPort, command, false, gateway, payload); err != nil {
+					return api.Wrapf(err, "provisioning from stdin failed")
+				}
+				logrus.Info("Payload successfully provisioned")
+				fmt.Fprintln(ctx.Stdout, serverID)
+				return nil
+			}
 			// exec -w SERVER COMMAND ARGS...
 			if len(args.Command) < 1 {
 				logrus.Info("Connecting to server ...")
 				if err = utils.SSHExec(server.PublicAddress.IP, server.PrivateIP, args.SSHUser, args.SSHPort, []string{}, false, gateway, false); err != nil {
-					return fmt.Errorf("Connection to server failed: %v", err)
+					return api.Wrapf(err, "Connection to server failed")
 				}
 			} else {
 				logrus.Infof("Executing command: %s ...", args.Command)
 				if err = utils.SSHExec(server.PublicAddress.IP, server.PrivateIP, args.SSHUser, args.SSHPort, args.Command, false, gateway, false); err != nil {
-					return fmt.Errorf("command execution failed: %v", err)
+					return api.Wrapf(err, "command execution failed")
 				}
 				logrus.Info("Command successfully executed")
 			}
@@ -303,17 +409,19 @@ func waitSSHConnection(ctx CommandContext, args RunArgs, serverID string) (chan
 	// Resolve gateway
 	gateway, err := api.ResolveGateway(ctx.API, args.Gateway)
 	if err != nil {
-		return nil, "", fmt.Errorf("cannot resolve Gateway '%s': %v", args.Gateway, err)
+		return nil, "", api.Wrapf(err, "cannot resolve Gateway '%s'", args.Gateway)
 	}
 
 	// waiting for server to be ready
 	logrus.Debug("Waiting for server to be ready")
 	// We wait for 30 seconds, which is the minimal amount of time needed by a server to boot
+	start := time.Now()
 	go func() {
 		server, err := api.WaitForServerReady(ctx.API, serverID, gateway)
+		ctx.Notify(NotifyServerBooted, serverID, start, err)
 		if err != nil {
 			notif <- notifSSHConnection{
-				err: fmt.Errorf("cannot get access to server %s: %v", serverID, err),
+				err: api.Wrapf(err, "cannot get access to server %s", serverID),
 			}
 			return
 		}
@@ -33,7 +33,7 @@ func RunSearch(ctx CommandContext, args SearchArgs) error {
 
 	images, err := ctx.API.GetImages()
 	if err != nil {
-		return fmt.Errorf("unable to fetch images from the Scaleway API: %v", err)
+		return api.Wrapf(err, "unable to fetch images from the Scaleway API")
 	}
 	for _, val := range *images {
 		if fuzzy.Match(term, strings.ToLower(val.Name)) {
@@ -47,7 +47,7 @@ func RunSearch(ctx CommandContext, args SearchArgs) error {
 
 	snapshots, err := ctx.API.GetSnapshots()
 	if err != nil {
-		return fmt.Errorf("unable to fetch snapshots from the Scaleway API: %v", err)
+		return api.Wrapf(err, "unable to fetch snapshots from the Scaleway API")
 	}
 	for _, val := range *snapshots {
 		if fuzzy.Match(term, strings.ToLower(val.Name)) {
@@ -0,0 +1,217 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/sirupsen/logrus"
+)
+
+// ServeArgs are flags for the `RunServe` function
+type ServeArgs struct {
+	Listen string
+	TTL    time.Duration
+}
+
+// ServedServer is the simplified server exposed by `scw _serve`
+type ServedServer struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	State          string   `json:"state"`
+	PublicIP       string   `json:"public_ip,omitempty"`
+	PrivateIP      string   `json:"private_ip,omitempty"`
+	CommercialType string   `json:"commercial_type"`
+	Arch           string   `json:"arch"`
+	Image          string   `json:"image,omitempty"`
+	Tags           []string `json:"tags"`
+	Zone           string   `json:"zone,omitempty"`
+}
+
+// serveHandler answers the read-only endpoints of `scw _serve` from a list of servers refreshed every ttl
+type serveHandler struct {
+	fetch func() ([]api.ScalewayServer, error)
+	ttl   time.Duration
+
+	lock      sync.Mutex
+	servers   []ServedServer
+	fetchedAt time.Time
+}
+
+func newServeHandler(fetch func() ([]api.ScalewayServer, error), ttl time.Duration) *serveHandler {
+	return &serveHandler{
+		fetch: fetch,
+		ttl:   ttl,
+	}
+}
+
+// list returns the servers, fetched again when older than ttl
+func (h *serveHandler) list() ([]ServedServer, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.servers != nil && time.Since(h.fetchedAt) < h.ttl {
+		return h.servers, nil
+	}
+	servers, err := h.fetch()
+	if err != nil {
+		// a stale list is better than nothing for dashboards
+		if h.servers != nil {
+			logrus.Warnf("cannot refresh servers, serving a list fetched at %s: %v", h.fetchedAt.Format(time.RFC3339), err)
+			return h.servers, nil
+		}
+		return nil, err
+	}
+	served := make([]ServedServer, len(servers))
+	for i, server := range servers {
+		served[i] = ServedServer{
+			ID:             server.Identifier,
+			Name:           server.Name,
+			State:          server.State,
+			PublicIP:       server.PublicAddress.IP,
+			PrivateIP:      server.PrivateIP,
+			CommercialType: server.CommercialType,
+			Arch:           server.Arch,
+			Image:          server.Image.Name,
+			Tags:           server.Tags,
+			Zone:           server.Location.ZoneID,
+		}
+		if served[i].Tags == nil {
+			served[i].Tags = []string{}
+		}
+	}
+	sort.SliceStable(served, func(i, j int) bool { return served[i].Name < served[j].Name })
+	h.servers = served
+	h.fetchedAt = time.Now()
+	return served, nil
+}
+
+// lookup returns the server named or identified by needle
+func (h *serveHandler) lookup(servers []ServedServer, needle string) (*ServedServer, error) {
+	var matches []*ServedServer
+	for i := range servers {
+		if servers[i].ID == needle || servers[i].Name == needle {
+			return &servers[i], nil
+		}
+		if len(needle) >= 4 && strings.HasPrefix(servers[i].ID, needle) {
+			matches = append(matches, &servers[i])
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+		return matches[0], nil
+	}
+	return nil, fmt.Errorf("%q matches %d servers", needle, len(matches))
+}
+
+func (h *serveHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	status := h.serve(w, r)
+	logrus.Infof("%s %s %d %v", r.Method, r.URL.Path, status, time.Since(start))
+}
+
+func (h *serveHandler) serve(w http.ResponseWriter, r *http.Request) int {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return serveError(w, http.StatusMethodNotAllowed, "read-only endpoint")
+	}
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if parts[0] == "healthz" && len(parts) == 1 {
+		w.Write([]byte("ok\n"))
+		return http.StatusOK
+	}
+	if parts[0] != "servers" || len(parts) > 3 || (len(parts) == 3 && parts[2] != "ip") {
+		return serveError(w, http.StatusNotFound, "unknown endpoint, available: /servers, /servers/NAME, /servers/NAME/ip")
+	}
+
+	servers, err := h.list()
+	if err != nil {
+		return serveError(w, http.StatusBadGateway, err.Error())
+	}
+
+	if len(parts) == 1 {
+		state, tag := r.URL.Query().Get("state"), r.URL.Query().Get("tag")
+		filtered := []ServedServer{}
+		for _, server := range servers {
+			if state != "" && server.State != state {
+				continue
+			}
+			if tag != "" && !hasTag(server.Tags, tag) {
+				continue
+			}
+			filtered = append(filtered, server)
+		}
+		return serveJSON(w, filtered)
+	}
+
+	server, err := h.lookup(servers, parts[1])
+	if err != nil {
+		return serveError(w, http.StatusConflict, err.Error())
+	}
+	if server == nil {
+		return serveError(w, http.StatusNotFound, fmt.Sprintf("no such server: %s", parts[1]))
+	}
+	if len(parts) == 2 {
+		return serveJSON(w, server)
+	}
+	ip := server.PublicIP
+	if ip == "" || r.URL.Query().Get("private") == "1" {
+		ip = server.PrivateIP
+	}
+	if ip == "" {
+		return serveError(w, http.StatusNotFound, fmt.Sprintf("%s has no IP address", server.Name))
+	}
+	w.Header().Set("Content-Type", "text/plain")
+	fmt.Fprintln(w, ip)
+	return http.StatusOK
+}
+
+func hasTag(tags []string, needle string) bool {
+	for _, tag := range tags {
+		if tag == needle {
+			return true
+		}
+	}
+	return false
+}
+
+func serveJSON(w http.ResponseWriter, value interface{}) int {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(value)
+	return http.StatusOK
+}
+
+func serveError(w http.ResponseWriter, status int, message string) int {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+	return status
+}
+
+// RunServe is the handler for 'scw _serve'
+func RunServe(ctx CommandContext, args ServeArgs) error {
+	handler := newServeHandler(func() ([]api.ScalewayServer, error) {
+		servers, err := ctx.API.GetServers(true, 0)
+		if err != nil {
+			return nil, err
+		}
+		// keeps the resolver cache warm for the other commands of the bastion
+		ctx.API.Sync()
+		return *servers, nil
+	}, args.TTL)
+
+	fmt.Fprintf(ctx.Stdout, "Serving read-only endpoints on http://%s/servers\n", args.Listen)
+	return http.ListenAndServe(args.Listen, handler)
+}
@@ -0,0 +1,52 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestServeHandler(t *testing.T) {
+	Convey("Testing serveHandler", t, func() {
+		fetches := 0
+		handler := newServeHandler(func() ([]api.ScalewayServer, error) {
+			fetches++
+			web := api.ScalewayServer{Identifier: "11111111-1111-1111-1111-111111111111", Name: "web", State: "running", Tags: []string{"prod"}}
+			web.PublicAddress.IP = "51.15.1.2"
+			db := api.ScalewayServer{Identifier: "22222222-2222-2222-2222-222222222222", Name: "db", State: "stopped", PrivateIP: "10.1.2.3"}
+			return []api.ScalewayServer{web, db}, nil
+		}, time.Minute)
+
+		get := func(path string) *httptest.ResponseRecorder {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest("GET", path, nil))
+			return recorder
+		}
+
+		response := get("/servers?state=running")
+		So(response.Code, ShouldEqual, http.StatusOK)
+		var servers []ServedServer
+		So(json.Unmarshal(response.Body.Bytes(), &servers), ShouldBeNil)
+		So(len(servers), ShouldEqual, 1)
+		So(servers[0].Name, ShouldEqual, "web")
+
+		So(get("/servers/web/ip").Body.String(), ShouldEqual, "51.15.1.2\n")
+		So(get("/servers/2222/ip").Body.String(), ShouldEqual, "10.1.2.3\n")
+		So(get("/servers/unknown").Code, ShouldEqual, http.StatusNotFound)
+		So(get("/volumes").Code, ShouldEqual, http.StatusNotFound)
+		So(fetches, ShouldEqual, 1)
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("DELETE", "/servers/web", nil))
+		So(recorder.Code, ShouldEqual, http.StatusMethodNotAllowed)
+	})
+}
@@ -0,0 +1,186 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/scaleway/scaleway-cli/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultSnapshotName is the name template of the snapshots created without --name
+const DefaultSnapshotName = "{server}-{volume}-{date}"
+
+// SnapshotCreateArgs are flags for the `RunSnapshotCreate` function
+type SnapshotCreateArgs struct {
+	Server     string
+	Volume     int
+	AllVolumes bool
+	Name       string
+	Wait       bool
+}
+
+// SnapshotListArgs are flags for the `RunSnapshotList` function
+type SnapshotListArgs struct {
+	Quiet   bool
+	NoTrunc bool
+}
+
+// SnapshotsArgs are flags for the `RunSnapshotRemove` and `RunSnapshotInspect` functions
+type SnapshotsArgs struct {
+	Snapshots []string
+}
+
+// snapshotName expands the {server}, {volume}, {date} and {time} placeholders of template
+func snapshotName(template, server, volume string, now time.Time) string {
+	return strings.NewReplacer(
+		"{server}", server,
+		"{volume}", volume,
+		"{date}", now.Format("2006-01-02"),
+		"{time}", now.Format("150405"),
+	).Replace(template)
+}
+
+// RunSnapshotCreate is the handler for 'scw snapshot create'
+func RunSnapshotCreate(ctx CommandContext, args SnapshotCreateArgs) error {
+	serverID, err := ctx.API.GetServerID(args.Server)
+	if err != nil {
+		return err
+	}
+	server, err := ctx.API.GetServer(serverID)
+	if err != nil {
+		return api.Wrapf(err, "cannot fetch server")
+	}
+
+	slots := []string{strconv.Itoa(args.Volume)}
+	if args.AllVolumes {
+		slots = []string{}
+		for slot := range server.Volumes {
+			slots = append(slots, slot)
+		}
+		sort.Strings(slots)
+	}
+	template := args.Name
+	if template == "" {
+		template = DefaultSnapshotName
+	}
+
+	now := time.Now()
+	for _, slot := range slots {
+		volume, ok := server.Volumes[slot]
+		if !ok {
+			return fmt.Errorf("server %s has no volume %s", server.Name, slot)
+		}
+		name := snapshotName(template, server.Name, slot, now)
+		snapshotID, err := ctx.API.PostSnapshot(volume.Identifier, name)
+		if err != nil {
+			return api.Wrapf(err, "cannot create snapshot of volume %s", slot)
+		}
+		if args.Wait {
+			logrus.Infof("Waiting for snapshot %s to be done", snapshotID)
+			start := time.Now()
+			_, err = api.WaitForSnapshotState(ctx.API, snapshotID, "snapshotted", api.SnapshotTimeout)
+			ctx.Notify(NotifySnapshotDone, name, start, err)
+			if err != nil {
+				return api.Wrapf(err, "cannot wait for snapshot %s", snapshotID)
+			}
+		}
+		fmt.Fprintln(ctx.Stdout, snapshotID)
+	}
+	return nil
+}
+
+// RunSnapshotList is the handler for 'scw snapshot ls'
+func RunSnapshotList(ctx CommandContext, args SnapshotListArgs) error {
+	snapshots, err := ctx.API.GetSnapshots()
+	if err != nil {
+		return api.Wrapf(err, "unable to fetch snapshots from the Scaleway API")
+	}
+	owned := []api.ScalewaySnapshot{}
+	for _, snapshot := range *snapshots {
+		if snapshot.Organization == ctx.API.OrganizationID() {
+			owned = append(owned, snapshot)
+		}
+	}
+	sort.Slice(owned, func(i, j int) bool {
+		return owned[i].CreationDate.Value().After(owned[j].CreationDate.Value())
+	})
+
+	w := tabwriter.NewWriter(ctx.Stdout, 20, 1, 3, ' ', 0)
+	defer w.Flush()
+	if !args.Quiet {
+		fmt.Fprintf(w, "SNAPSHOT ID\tNAME\tSIZE\tSTATE\tVOLUME\tCREATED\n")
+	}
+	for _, snapshot := range owned {
+		if args.Quiet {
+			fmt.Fprintln(w, snapshot.Identifier)
+			continue
+		}
+		volume := "-"
+		if snapshot.BaseVolume.Identifier != "" {
+			volume = utils.TruncIf(snapshot.BaseVolume.Identifier, 8, !args.NoTrunc)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			utils.TruncIf(snapshot.Identifier, 8, !args.NoTrunc),
+			utils.TruncIf(snapshot.Name, 25, !args.NoTrunc),
+			units.HumanSize(float64(snapshot.Size)),
+			snapshot.State,
+			volume,
+			ctx.FormatTime(snapshot.CreationDate.Value()),
+		)
+	}
+	return nil
+}
+
+// RunSnapshotRemove is the handler for 'scw snapshot rm'
+func RunSnapshotRemove(ctx CommandContext, args SnapshotsArgs) error {
+	result := NewBulkResult(ctx, "remove", args.Snapshots)
+	for _, needle := range args.Snapshots {
+		done := result.Start(needle)
+		snapshotID, err := ctx.API.GetSnapshotID(needle)
+		if err != nil {
+			done(err)
+			continue
+		}
+		done(ctx.API.DeleteSnapshot(snapshotID))
+	}
+	if err := result.Report(); err != nil {
+		return err
+	}
+	if result.Failed() > 0 {
+		return fmt.Errorf("at least 1 snapshot failed to be removed")
+	}
+	return nil
+}
+
+// RunSnapshotInspect is the handler for 'scw snapshot inspect'
+func RunSnapshotInspect(ctx CommandContext, args SnapshotsArgs) error {
+	snapshots := []api.ScalewaySnapshot{}
+	for _, needle := range args.Snapshots {
+		snapshotID, err := ctx.API.GetSnapshotID(needle)
+		if err != nil {
+			return err
+		}
+		snapshot, err := ctx.API.GetSnapshot(snapshotID)
+		if err != nil {
+			return api.Wrapf(err, "cannot fetch snapshot %s", needle)
+		}
+		snapshots = append(snapshots, *snapshot)
+	}
+	res, err := marshalInspected(snapshots, ctx.TimeFormat)
+	if err != nil {
+		return api.Wrapf(err, "cannot marshal snapshots")
+	}
+	fmt.Fprintln(ctx.Stdout, string(res))
+	return nil
+}
@@ -0,0 +1,21 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSnapshotName(t *testing.T) {
+	Convey("Testing snapshotName()", t, func() {
+		now := time.Date(2017, 3, 1, 12, 30, 5, 0, time.UTC)
+		So(snapshotName(DefaultSnapshotName, "web-1", "0", now), ShouldEqual, "web-1-0-2017-03-01")
+		So(snapshotName("backup-{date}-{time}", "web-1", "1", now), ShouldEqual, "backup-2017-03-01-123005")
+		So(snapshotName("static", "web-1", "1", now), ShouldEqual, "static")
+	})
+}
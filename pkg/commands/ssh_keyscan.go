@@ -0,0 +1,184 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/scaleway/scaleway-cli/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+)
+
+// SSHKeyscanArgs are arguments passed to `RunSSHKeyscan`
+type SSHKeyscanArgs struct {
+	Servers []string
+	Filters map[string]string
+	Output  string
+	Port    int
+	Scan    bool
+}
+
+// sshKeyscanTimeout bounds the SSH handshake used to collect the host keys of a server
+const sshKeyscanTimeout = 10 * time.Second
+
+var errHostKeyCollected = errors.New("host key collected")
+
+// knownHostsHosts returns the host patterns of a known_hosts line for server
+func knownHostsHosts(server api.ScalewayServer, port int) []string {
+	names := []string{}
+	if server.PublicAddress.IP != "" {
+		names = append(names, server.PublicAddress.IP)
+	}
+	if server.DNSPublic != "" {
+		names = append(names, server.DNSPublic)
+	}
+	if port != 22 {
+		for i, name := range names {
+			names[i] = "[" + name + "]:" + strconv.Itoa(port)
+		}
+	}
+	return names
+}
+
+// serverHostKeys returns the host keys published by the server in its ssh-host-fingerprints user data
+func serverHostKeys(ctx CommandContext, serverID string) []ssh.PublicKey {
+	keys := []ssh.PublicKey{}
+	value, err := ctx.API.GetUserdata(serverID, "ssh-host-fingerprints", false)
+	if err != nil {
+		return keys
+	}
+	for _, line := range strings.Split(string(*value), "\n") {
+		if key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(line)); err == nil {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// scanHostKey connects to address and returns the host key presented during the handshake
+func scanHostKey(address string) (ssh.PublicKey, error) {
+	conn, err := utils.DialTCP(address, sshKeyscanTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(sshKeyscanTimeout))
+
+	var hostKey ssh.PublicKey
+	_, _, _, err = ssh.NewClientConn(conn, address, &ssh.ClientConfig{
+		User: "root",
+		HostKeyCallback: func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			hostKey = key
+			return errHostKeyCollected
+		},
+	})
+	if hostKey != nil {
+		return hostKey, nil
+	}
+	return nil, err
+}
+
+// mergeKnownHosts drops the lines of existing about one of hosts, then appends lines
+func mergeKnownHosts(existing []byte, hosts []string, lines []string) []byte {
+	replaced := make(map[string]struct{}, len(hosts))
+	for _, host := range hosts {
+		replaced[host] = struct{}{}
+	}
+	var merged bytes.Buffer
+	for _, line := range strings.Split(string(existing), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		stale := false
+		if !strings.HasPrefix(fields[0], "#") {
+			for _, host := range strings.Split(fields[0], ",") {
+				if _, ok := replaced[host]; ok {
+					stale = true
+					break
+				}
+			}
+		}
+		if !stale {
+			merged.WriteString(line + "\n")
+		}
+	}
+	for _, line := range lines {
+		merged.WriteString(line + "\n")
+	}
+	return merged.Bytes()
+}
+
+// RunSSHKeyscan is the handler for 'scw _ssh-keyscan'
+func RunSSHKeyscan(ctx CommandContext, args SSHKeyscanArgs) error {
+	var servers []api.ScalewayServer
+	if len(args.Servers) == 0 && len(args.Filters) == 0 {
+		all, err := ctx.API.GetServers(false, 0)
+		if err != nil {
+			return api.Wrapf(err, "unable to fetch servers from the Scaleway API")
+		}
+		servers = *all
+	} else {
+		var err error
+		if servers, err = selectServers(ctx, args.Servers, args.Filters); err != nil {
+			return err
+		}
+	}
+
+	hosts := []string{}
+	lines := []string{}
+	for _, server := range servers {
+		names := knownHostsHosts(server, args.Port)
+		if len(names) == 0 {
+			logrus.Warnf("Server %s has no public address, skipping", server.Name)
+			continue
+		}
+		keys := serverHostKeys(ctx, server.Identifier)
+		if len(keys) == 0 && args.Scan {
+			key, err := scanHostKey(net.JoinHostPort(server.PublicAddress.IP, strconv.Itoa(args.Port)))
+			if err != nil {
+				logrus.Warnf("Cannot scan the host key of %s: %v", server.Name, err)
+				continue
+			}
+			keys = append(keys, key)
+		}
+		if len(keys) == 0 {
+			logrus.Warnf("Server %s does not publish its host keys, use --scan to connect to it", server.Name)
+			continue
+		}
+		hosts = append(hosts, names...)
+		for _, key := range keys {
+			lines = append(lines, strings.Join(names, ",")+" "+strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key))))
+		}
+	}
+	sort.Strings(lines)
+
+	if args.Output == "" {
+		for _, line := range lines {
+			fmt.Fprintln(ctx.Stdout, line)
+		}
+		return nil
+	}
+	existing, err := ioutil.ReadFile(args.Output)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err = ioutil.WriteFile(args.Output, mergeKnownHosts(existing, hosts, lines), 0600); err != nil {
+		return err
+	}
+	logrus.Infof("%d host keys of %d servers written to %s", len(lines), len(servers), args.Output)
+	return nil
+}
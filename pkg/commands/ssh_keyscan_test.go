@@ -0,0 +1,29 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestKnownHostsHosts(t *testing.T) {
+	Convey("Testing knownHostsHosts()", t, func() {
+		server := api.ScalewayServer{DNSPublic: "uuid.pub.cloud.scaleway.com"}
+		server.PublicAddress.IP = "212.47.229.26"
+		So(knownHostsHosts(server, 22), ShouldResemble, []string{"212.47.229.26", "uuid.pub.cloud.scaleway.com"})
+		So(knownHostsHosts(server, 2222), ShouldResemble, []string{"[212.47.229.26]:2222", "[uuid.pub.cloud.scaleway.com]:2222"})
+	})
+}
+
+func TestMergeKnownHosts(t *testing.T) {
+	Convey("Testing mergeKnownHosts()", t, func() {
+		existing := []byte("github.com ssh-rsa AAAAgithub\n212.47.229.26,old.pub.cloud.scaleway.com ssh-ed25519 AAAAold\n")
+		merged := mergeKnownHosts(existing, []string{"212.47.229.26"}, []string{"212.47.229.26 ssh-ed25519 AAAAnew"})
+		So(string(merged), ShouldEqual, "github.com ssh-rsa AAAAgithub\n212.47.229.26 ssh-ed25519 AAAAnew\n")
+	})
+}
@@ -6,10 +6,12 @@ package commands
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/scaleway/scaleway-cli/pkg/utils"
 	"github.com/sirupsen/logrus"
 )
 
@@ -19,17 +21,49 @@ type StartArgs struct {
 	Wait     bool
 	Timeout  float64
 	SetState string
+
+	// RetryCapacity retries the power on for this long while the API has no capacity for the server type
+	RetryCapacity time.Duration
+}
+
+// Backoff between two power on attempts rejected for lack of capacity
+var (
+	capacityRetryMin = 15 * time.Second
+	capacityRetryMax = 2 * time.Minute
+)
+
+// powerOnServer powers on a server, retrying with backoff during retryCapacity when the API has no capacity for it
+func powerOnServer(ctx CommandContext, serverID string, retryCapacity time.Duration) error {
+	deadline := time.Now().Add(retryCapacity)
+	delay := capacityRetryMin
+	for {
+		err := ctx.API.PostServerAction(serverID, "poweron")
+		if err == nil || !api.IsCapacityError(err) || time.Now().Add(delay).After(deadline) {
+			return err
+		}
+		logrus.Warnf("No capacity to start server %s, retrying in %s", serverID, delay)
+		time.Sleep(delay)
+		if delay *= 2; delay > capacityRetryMax {
+			delay = capacityRetryMax
+		}
+	}
 }
 
 // RunStart is the handler for 'scw start'
 func RunStart(ctx CommandContext, args StartArgs) error {
-	hasError := false
-	errChan := make(chan error)
-	successChan := make(chan string)
-	remainingItems := len(args.Servers)
+	result := NewBulkResult(ctx, "start server", args.Servers)
+	if args.Wait {
+		result.Event = NotifyServerBooted
+	}
+	var started sync.WaitGroup
 
 	for _, needle := range args.Servers {
-		go api.StartServerOnce(ctx.API, needle, args.Wait, successChan, errChan)
+		started.Add(1)
+		go func(needle string) {
+			defer started.Done()
+			done := result.Start(needle)
+			done(startServer(ctx, needle, args.Wait, args.RetryCapacity))
+		}(needle)
 	}
 
 	if args.Timeout > 0 {
@@ -40,21 +74,7 @@ func RunStart(ctx CommandContext, args StartArgs) error {
 		}()
 	}
 
-	for {
-		select {
-		case name := <-successChan:
-			fmt.Println(name)
-			remainingItems--
-		case err := <-errChan:
-			logrus.Errorf("%s", err)
-			remainingItems--
-			hasError = true
-		}
-
-		if remainingItems == 0 {
-			break
-		}
-	}
+	started.Wait()
 	if args.SetState != "" {
 		var wg sync.WaitGroup
 
@@ -82,14 +102,120 @@ func RunStart(ctx CommandContext, args StartArgs) error {
 						}
 						return
 					}
-					time.Sleep(1 * time.Second)
+					time.Sleep(api.WaitPollInterval)
 				}
 			}(needle)
 		}
 		wg.Wait()
 	}
-	if hasError {
+	if err := result.Report(); err != nil {
+		return err
+	}
+	if result.Failed() > 0 {
 		return fmt.Errorf("at least 1 server failed to start")
 	}
 	return nil
 }
+
+// startServer starts a server, then reports its boot stages until SSH is ready when wait is set
+func startServer(ctx CommandContext, needle string, wait bool, retryCapacity time.Duration) error {
+	serverID, err := ctx.API.GetServerID(needle)
+	if err != nil {
+		return err
+	}
+	if err = powerOnServer(ctx, serverID, retryCapacity); err != nil {
+		return err
+	}
+	if !wait {
+		return nil
+	}
+	if err = watchServerBoot(ctx, needle, serverID); err != nil {
+		return fmt.Errorf("failed to wait for server %s to be ready, %v", needle, err)
+	}
+	return nil
+}
+
+// watchServerBoot polls the server state, its boot task and its serial console to display
+// the boot stages, and fails fast if the console shows a kernel panic
+func watchServerBoot(ctx CommandContext, needle, serverID string) error {
+	var console <-chan string
+
+	// stops the console reader, which would otherwise block on its next line once we return
+	done := make(chan struct{})
+	defer close(done)
+	gottycli, lines, err := utils.ReadSerial(serverID, ctx.API.AuthToken(), ctx.API.ResolveTTYUrl(), done)
+	if err != nil {
+		logrus.Debugf("cannot read server console, boot stages will be less accurate: %v", err)
+	} else {
+		defer gottycli.Close()
+		console = lines
+	}
+
+	stage := ""
+	report := func(newStage, detail string) {
+		if newStage == stage {
+			return
+		}
+		stage = newStage
+		if detail != "" {
+			utils.LogQuiet(fmt.Sprintf("%s: %s (%s)\n", needle, stage, detail))
+		} else {
+			utils.LogQuiet(fmt.Sprintf("%s: %s\n", needle, stage))
+		}
+	}
+
+	ticker := time.NewTicker(api.WaitPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case line, ok := <-console:
+			if !ok {
+				console = nil
+				continue
+			}
+			switch {
+			case strings.Contains(line, "Kernel panic"):
+				return fmt.Errorf("kernel panic detected on console: %s", strings.TrimSpace(line))
+			case strings.Contains(strings.ToLower(line), "cloud-init") && stage != api.BootStageSSHWait:
+				report(api.BootStageCloudInit, "")
+			}
+		case <-ticker.C:
+			server, err := ctx.API.GetServer(serverID)
+			if err != nil {
+				return err
+			}
+			if server.State == "stopped" {
+				return fmt.Errorf("The server has been stopped")
+			}
+			newStage := api.ServerBootStage(server)
+			if newStage == api.BootStageAllocating || newStage == api.BootStageBooting {
+				// never go back once the console told us cloud-init is running
+				if stage == api.BootStageCloudInit {
+					continue
+				}
+			}
+			detail := server.StateDetail
+			if server.State == "starting" {
+				if task, err := ctx.API.GetServerPendingTask(serverID); err == nil && task != nil {
+					detail = fmt.Sprintf("%s %d%%", task.Description, task.Progress)
+				}
+			}
+			report(newStage, detail)
+			if server.State == "running" {
+				ip := server.PublicAddress.IP
+				if ip == "" && server.EnableIPV6 && server.IPV6 != nil {
+					ip = fmt.Sprintf("[%s]", server.IPV6.Address)
+				}
+				if ip == "" {
+					// only reachable through a gateway, dialing ":22" would check the local host
+					logrus.Warnf("%s has no public address, cannot wait for SSH", needle)
+					return nil
+				}
+				if utils.IsTCPPortOpen(fmt.Sprintf("%s:22", ip)) {
+					report(api.BootStageSSHReady, "")
+					return nil
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWatchServerBoot(t *testing.T) {
+	Convey("Testing watchServerBoot() on a server without public address", t, func() {
+		defer func(interval time.Duration) { api.WaitPollInterval = interval }(api.WaitPollInterval)
+		api.WaitPollInterval = time.Millisecond
+
+		fake := api.NewFakeScalewayAPI("orga")
+		fake.Servers = []api.ScalewayServer{
+			{Identifier: "11111111-1111-1111-1111-111111111111", Name: "private", State: "running"},
+		}
+		ctx := CommandContext{
+			Streams: Streams{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}},
+			API:     fake,
+		}
+
+		So(watchServerBoot(ctx, "private", fake.Servers[0].Identifier), ShouldBeNil)
+	})
+}
@@ -9,52 +9,78 @@ import (
 	"time"
 
 	"github.com/scaleway/scaleway-cli/pkg/api"
-	"github.com/sirupsen/logrus"
 )
 
 // StopArgs are flags for the `RunStop` function
 type StopArgs struct {
-	Terminate bool
-	Wait      bool
-	Servers   []string
+	Terminate      bool
+	ForceProtected bool
+	Wait           bool
+	Servers        []string
 }
 
 // RunStop is the handler for 'scw stop'
 func RunStop(ctx CommandContext, args StopArgs) error {
 	// FIXME: parallelize stop when stopping multiple servers
-	hasError := false
+	result := NewBulkResult(ctx, "stop server", args.Servers)
 	for _, needle := range args.Servers {
+		done := result.Start(needle)
 		serverID, err := ctx.API.GetServerID(needle)
 		if err != nil {
-			return err
+			done(err)
+			continue
 		}
 		action := "poweroff"
 		if args.Terminate {
 			action = "terminate"
+			if !args.ForceProtected {
+				if err = checkProtected(ctx, serverID); err != nil {
+					done(err)
+					continue
+				}
+			}
 		}
-		if err = ctx.API.PostServerAction(serverID, action); err != nil {
-			if err.Error() != "server should be running" && err.Error() != "server is being stopped or rebooted" {
-				logrus.Warningf("failed to stop server %s: %s", serverID, err)
-				hasError = true
+		task, err := ctx.API.PostServerActionTask(serverID, action)
+		if err != nil {
+			if stopSkipped(err) {
+				result.Skip(needle, err.Error())
+			} else {
+				done(err)
 			}
 		} else {
-			if args.Wait {
+			if args.Wait && task != nil {
+				if _, err = api.WaitForTask(ctx.API, task.Identifier, 0); err != nil {
+					done(api.Wrapf(err, "failed to wait for server %s", serverID))
+					continue
+				}
+			} else if args.Wait {
 				// We wait for 10 seconds which is the minimal amount of time needed for a server to stop
 				time.Sleep(10 * time.Second)
 				if _, err = api.WaitForServerStopped(ctx.API, serverID); err != nil {
-					logrus.Errorf("failed to wait for server %s: %v", serverID, err)
-					hasError = true
+					done(api.Wrapf(err, "failed to wait for server %s", serverID))
+					continue
 				}
 			}
 			if args.Terminate {
-				ctx.API.Cache.RemoveServer(serverID)
+				ctx.API.ResolverCache().RemoveServer(serverID)
 			}
-			fmt.Fprintln(ctx.Stdout, needle)
+			done(nil)
 		}
 	}
 
-	if hasError {
+	if err := result.Report(); err != nil {
+		return err
+	}
+	if result.Failed() > 0 {
 		return fmt.Errorf("at least 1 server failed to be stopped")
 	}
 	return nil
 }
+
+// stopSkipped reports whether err rejects stopping a server which is already stopped or stopping
+func stopSkipped(err error) bool {
+	if transition, ok := err.(api.ServerTransitionError); ok {
+		return transition.Server.State == "stopping"
+	}
+	return err.Error() == "server should be running" || err.Error() == "server is being stopped or rebooted"
+}
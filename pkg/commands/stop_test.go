@@ -0,0 +1,41 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestStopSkipped(t *testing.T) {
+	Convey("Testing stopSkipped()", t, func() {
+		So(stopSkipped(api.ServerTransitionError{Server: &api.ScalewayServer{State: "stopping"}, Action: "poweroff"}), ShouldBeTrue)
+		So(stopSkipped(api.ServerTransitionError{Server: &api.ScalewayServer{State: "starting"}, Action: "poweroff"}), ShouldBeFalse)
+		So(stopSkipped(errors.New("server should be running")), ShouldBeTrue)
+		So(stopSkipped(errors.New("server not found")), ShouldBeFalse)
+	})
+}
+
+func TestRunStop(t *testing.T) {
+	Convey("Testing RunStop() against a FakeScalewayAPI", t, func() {
+		fake := api.NewFakeScalewayAPI("orga")
+		fake.Servers = []api.ScalewayServer{
+			{Identifier: "11111111-1111-1111-1111-111111111111", Name: "web", State: "running"},
+		}
+		ctx := CommandContext{
+			Streams: Streams{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}},
+			API:     fake,
+		}
+
+		// an unknown server does not prevent the next ones from being stopped
+		err := RunStop(ctx, StopArgs{Servers: []string{"unknown", "web"}})
+		So(err, ShouldNotBeNil)
+		So(fake.Servers[0].State, ShouldEqual, "stopped")
+	})
+}
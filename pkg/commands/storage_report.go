@@ -0,0 +1,163 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/scaleway/scaleway-cli/pkg/pricing"
+)
+
+const storageReportUntagged = "<untagged>"
+
+// StorageReportArgs are flags for the `RunStorageReport` function
+type StorageReportArgs struct {
+	GroupBy   string
+	Separator string
+}
+
+// storageUsage holds the bytes used by a group of resources
+type storageUsage struct {
+	Volumes   uint64
+	Snapshots uint64
+	Images    uint64
+}
+
+func (u *storageUsage) total() uint64 {
+	return u.Volumes + u.Snapshots + u.Images
+}
+
+// StorageReportPrefix returns the name prefix used to group resources, i.e: "web-1-root" -> "web"
+func StorageReportPrefix(name, separators string) string {
+	if index := strings.IndexAny(name, separators); index > 0 {
+		return name[:index]
+	}
+	if name == "" {
+		return "<unnamed>"
+	}
+	return name
+}
+
+// RunStorageReport is the handler for 'scw _storage-report'
+func RunStorageReport(ctx CommandContext, args StorageReportArgs) error {
+	if args.Separator == "" {
+		args.Separator = "-_."
+	}
+	if args.GroupBy != "prefix" && args.GroupBy != "tag" {
+		return fmt.Errorf("invalid --group-by %q, expected 'prefix' or 'tag'", args.GroupBy)
+	}
+
+	volumes, err := ctx.API.GetVolumes()
+	if err != nil {
+		return api.Wrapf(err, "unable to fetch volumes from the Scaleway API")
+	}
+	snapshots, err := ctx.API.GetSnapshots()
+	if err != nil {
+		return api.Wrapf(err, "unable to fetch snapshots from the Scaleway API")
+	}
+	images, err := ctx.API.GetOrganizationImages()
+	if err != nil {
+		return api.Wrapf(err, "unable to fetch images from the Scaleway API")
+	}
+
+	// tags are only set on servers, resources inherit the tags of the server they come from
+	volumeTags := make(map[string][]string)
+	if args.GroupBy == "tag" {
+		servers, err := ctx.API.GetServers(true, 0)
+		if err != nil {
+			return api.Wrapf(err, "unable to fetch servers from the Scaleway API")
+		}
+		for _, server := range *servers {
+			for _, volume := range server.Volumes {
+				volumeTags[volume.Identifier] = server.Tags
+			}
+		}
+	}
+	snapshotVolumes := make(map[string]string)
+	for _, snapshot := range *snapshots {
+		snapshotVolumes[snapshot.Identifier] = snapshot.BaseVolume.Identifier
+	}
+
+	groups := make(map[string]*storageUsage)
+	groupsOf := func(name, volumeID string) []*storageUsage {
+		var keys []string
+		if args.GroupBy == "prefix" {
+			keys = []string{StorageReportPrefix(name, args.Separator)}
+		} else if keys = volumeTags[volumeID]; len(keys) == 0 {
+			keys = []string{storageReportUntagged}
+		}
+		usages := make([]*storageUsage, 0, len(keys))
+		for _, key := range keys {
+			if _, ok := groups[key]; !ok {
+				groups[key] = &storageUsage{}
+			}
+			usages = append(usages, groups[key])
+		}
+		return usages
+	}
+
+	var total storageUsage
+	for _, volume := range *volumes {
+		for _, usage := range groupsOf(volume.Name, volume.Identifier) {
+			usage.Volumes += volume.Size
+		}
+		total.Volumes += volume.Size
+	}
+	// an image is backed by its root snapshot, count it once as an image
+	imageSnapshots := make(map[string]bool)
+	for _, image := range *images {
+		imageSnapshots[image.RootVolume.Identifier] = true
+		for _, usage := range groupsOf(image.Name, snapshotVolumes[image.RootVolume.Identifier]) {
+			usage.Images += image.RootVolume.Size
+		}
+		total.Images += image.RootVolume.Size
+	}
+	for _, snapshot := range *snapshots {
+		if imageSnapshots[snapshot.Identifier] {
+			continue
+		}
+		for _, usage := range groupsOf(snapshot.Name, snapshot.BaseVolume.Identifier) {
+			usage.Snapshots += snapshot.Size
+		}
+		total.Snapshots += snapshot.Size
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	// biggest consumers first
+	sort.Slice(keys, func(i, j int) bool {
+		if groups[keys[i]].total() != groups[keys[j]].total() {
+			return groups[keys[i]].total() > groups[keys[j]].total()
+		}
+		return keys[i] < keys[j]
+	})
+
+	storage := pricing.CurrentPricing.GetByPath("/storage/local/ssd/storage")
+	w := tabwriter.NewWriter(ctx.Stdout, 20, 1, 3, ' ', 0)
+	defer w.Flush()
+	fmt.Fprintf(w, "%s\tVOLUMES\tSNAPSHOTS\tIMAGES\tTOTAL\tMONTH PRICE\n", strings.ToUpper(args.GroupBy))
+	printRow := func(name string, usage *storageUsage) {
+		price := storage.MonthPrice(big.NewRat(int64(usage.total()/api.Giga), 1))
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", name,
+			storageReportGB(usage.Volumes), storageReportGB(usage.Snapshots), storageReportGB(usage.Images),
+			storageReportGB(usage.total()), pricing.PriceString(price, storage.Currency))
+	}
+	for _, key := range keys {
+		printRow(key, groups[key])
+	}
+	printRow("TOTAL", &total)
+	return nil
+}
+
+func storageReportGB(size uint64) string {
+	return fmt.Sprintf("%d GB", size/api.Giga)
+}
@@ -6,8 +6,10 @@ package commands
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/moul/anonuuid"
+	"github.com/scaleway/scaleway-cli/pkg/api"
 )
 
 // TagArgs are flags for the `RunTag` function
@@ -16,6 +18,7 @@ type TagArgs struct {
 	Bootscript string
 	Name       string
 	Arch       string
+	Manifest   string
 }
 
 // RunTag is the handler for 'scw tag'
@@ -26,7 +29,7 @@ func RunTag(ctx CommandContext, args TagArgs) error {
 	}
 	snapshot, err := ctx.API.GetSnapshot(snapshotID)
 	if err != nil {
-		return fmt.Errorf("cannot fetch snapshot: %v", err)
+		return api.Wrapf(err, "cannot fetch snapshot")
 	}
 
 	bootscriptID := ""
@@ -42,7 +45,13 @@ func RunTag(ctx CommandContext, args TagArgs) error {
 	}
 	image, err := ctx.API.PostImage(snapshot.Identifier, args.Name, bootscriptID, args.Arch)
 	if err != nil {
-		return fmt.Errorf("cannot create image: %v", err)
+		return api.Wrapf(err, "cannot create image")
+	}
+	if args.Manifest != "" {
+		build := newPackerBuild(args.Name, image, snapshot.Identifier, ctx.API.CurrentRegion(), args.Arch, time.Now())
+		if err = writePackerManifest(args.Manifest, build); err != nil {
+			return api.Wrapf(err, "cannot write manifest")
+		}
 	}
 	fmt.Fprintln(ctx.Stdout, image)
 	return nil
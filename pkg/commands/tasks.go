@@ -0,0 +1,109 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/scaleway/scaleway-cli/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// TasksArgs are flags for the `RunTasks` function
+type TasksArgs struct {
+	Action  string
+	Tasks   []string
+	All     bool
+	NoTrunc bool
+}
+
+// RunTasks is the handler for 'scw _tasks'
+func RunTasks(ctx CommandContext, args TasksArgs) error {
+	switch args.Action {
+	case "", "ls":
+		return listTasks(ctx, args)
+	case "cancel":
+		return cancelTasks(ctx, args)
+	}
+	return fmt.Errorf("unknown action %q, expected ls or cancel", args.Action)
+}
+
+// isTaskRunning returns true if a task can still be cancelled
+func isTaskRunning(task api.ScalewayTask) bool {
+	return task.Status == "pending" || task.Status == "started"
+}
+
+func listTasks(ctx CommandContext, args TasksArgs) error {
+	tasks, err := ctx.API.GetTasks()
+	if err != nil {
+		return api.Wrapf(err, "unable to fetch tasks from the Scaleway API")
+	}
+
+	w := tabwriter.NewWriter(ctx.Stdout, 20, 1, 3, ' ', 0)
+	defer w.Flush()
+	fmt.Fprintf(w, "TASK ID\tDESCRIPTION\tSTATUS\tPROGRESS\tSTARTED\tFROM\n")
+	for _, task := range *tasks {
+		if !args.All && !isTaskRunning(task) {
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d%%\t%s\t%s\n", utils.TruncIf(task.Identifier, 8, !args.NoTrunc), task.Description, task.Status, task.Progress, ctx.FormatTime(task.StartDate.Value()), task.HrefFrom)
+	}
+	return nil
+}
+
+// resolveTask returns the task whose identifier starts with needle
+func resolveTask(tasks []api.ScalewayTask, needle string) (*api.ScalewayTask, error) {
+	var matches []api.ScalewayTask
+	for _, task := range tasks {
+		if strings.HasPrefix(task.Identifier, needle) {
+			matches = append(matches, task)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no such task: %s", needle)
+	case 1:
+		return &matches[0], nil
+	}
+	return nil, fmt.Errorf("too many candidates for %s (%d)", needle, len(matches))
+}
+
+func cancelTasks(ctx CommandContext, args TasksArgs) error {
+	if len(args.Tasks) == 0 {
+		return fmt.Errorf("cancel needs at least 1 task")
+	}
+	tasks, err := ctx.API.GetTasks()
+	if err != nil {
+		return api.Wrapf(err, "unable to fetch tasks from the Scaleway API")
+	}
+
+	hasError := false
+	for _, needle := range args.Tasks {
+		task, err := resolveTask(*tasks, needle)
+		if err != nil {
+			logrus.Errorf("%s", err)
+			hasError = true
+			continue
+		}
+		if !isTaskRunning(*task) {
+			logrus.Errorf("task %s is already %s", task.Identifier, task.Status)
+			hasError = true
+			continue
+		}
+		if err = ctx.API.DeleteTask(task.Identifier); err != nil {
+			logrus.Errorf("failed to cancel task %s (%s): %v", task.Identifier, task.Description, err)
+			hasError = true
+			continue
+		}
+		fmt.Fprintln(ctx.Stdout, task.Identifier)
+	}
+	if hasError {
+		return fmt.Errorf("at least 1 task failed to be cancelled")
+	}
+	return nil
+}
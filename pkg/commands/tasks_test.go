@@ -0,0 +1,33 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestListTasks(t *testing.T) {
+	Convey("Testing listTasks() against a FakeScalewayAPI", t, func() {
+		fake := api.NewFakeScalewayAPI("orga")
+		fake.Tasks = []api.ScalewayTask{
+			{Identifier: "11111111-1111-1111-1111-111111111111", Description: "server_poweron", Status: "started"},
+			{Identifier: "short", Description: "server_poweroff", Status: "pending"},
+		}
+		stdout := &bytes.Buffer{}
+		ctx := CommandContext{
+			Streams: Streams{Stdout: stdout, Stderr: &bytes.Buffer{}},
+			API:     fake,
+		}
+
+		So(listTasks(ctx, TasksArgs{}), ShouldBeNil)
+		So(stdout.String(), ShouldContainSubstring, "11111111 ")
+		So(stdout.String(), ShouldNotContainSubstring, "11111111-")
+		So(stdout.String(), ShouldContainSubstring, "short ")
+	})
+}
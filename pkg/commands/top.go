@@ -30,7 +30,7 @@ func RunTop(ctx CommandContext, args TopArgs) error {
 	command := "ps"
 	server, err := ctx.API.GetServer(serverID)
 	if err != nil {
-		return fmt.Errorf("failed to get server information for %s: %v", serverID, err)
+		return api.Wrapf(err, "failed to get server information for %s", serverID)
 	}
 
 	// Resolve gateway
@@ -43,7 +43,7 @@ func RunTop(ctx CommandContext, args TopArgs) error {
 	} else {
 		gateway, err = api.ResolveGateway(ctx.API, args.Gateway)
 		if err != nil {
-			return fmt.Errorf("cannot resolve Gateway '%s': %v", args.Gateway, err)
+			return api.Wrapf(err, "cannot resolve Gateway '%s'", args.Gateway)
 		}
 	}
 
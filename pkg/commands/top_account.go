@@ -0,0 +1,186 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/scaleway/scaleway-cli/pkg/utils"
+)
+
+// TopAccountArgs are flags for the `RunTopAccount` function
+type TopAccountArgs struct {
+	Interval   time.Duration
+	Iterations int
+	Tasks      int
+}
+
+// accountSnapshot holds what is displayed on one refresh of the dashboard
+type accountSnapshot struct {
+	date      time.Time
+	latency   time.Duration
+	servers   []api.ScalewayServer
+	tasks     []api.ScalewayTask
+	dashboard *api.ScalewayDashboard
+	quotas    api.ScalewayQuota
+	errors    []string
+}
+
+// RunTopAccount is the handler for 'scw _top-account'
+func RunTopAccount(ctx CommandContext, args TopAccountArgs) error {
+	if args.Interval < time.Second {
+		args.Interval = time.Second
+	}
+	for i := 0; args.Iterations <= 0 || i < args.Iterations; i++ {
+		if i > 0 {
+			time.Sleep(args.Interval)
+		}
+		var buf bytes.Buffer
+		// move to the top-left corner and clear the screen
+		buf.WriteString("\033[H\033[2J")
+		renderAccountSnapshot(ctx, &buf, fetchAccountSnapshot(ctx), args)
+		if _, err := buf.WriteTo(ctx.Stdout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func fetchAccountSnapshot(ctx CommandContext) *accountSnapshot {
+	snapshot := accountSnapshot{date: time.Now()}
+
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	fail := func(what string, err error) {
+		lock.Lock()
+		defer lock.Unlock()
+		snapshot.errors = append(snapshot.errors, fmt.Sprintf("%s: %v", what, err))
+	}
+	wg.Add(5)
+	go func() {
+		defer wg.Done()
+		latency, err := ctx.API.Ping(ctx.API.ComputeAPIURL(), "servers")
+		if err != nil {
+			fail("latency", err)
+		}
+		snapshot.latency = latency
+	}()
+	go func() {
+		defer wg.Done()
+		servers, err := ctx.API.GetServers(true, 0)
+		if err != nil {
+			fail("servers", err)
+			return
+		}
+		snapshot.servers = *servers
+	}()
+	go func() {
+		defer wg.Done()
+		tasks, err := ctx.API.GetTasks()
+		if err != nil {
+			fail("tasks", err)
+			return
+		}
+		snapshot.tasks = *tasks
+	}()
+	go func() {
+		defer wg.Done()
+		dashboard, err := ctx.API.GetDashboard()
+		if err != nil {
+			fail("dashboard", err)
+			return
+		}
+		snapshot.dashboard = dashboard
+	}()
+	go func() {
+		defer wg.Done()
+		quotas, err := ctx.API.GetQuotas()
+		if err != nil {
+			fail("quotas", err)
+			return
+		}
+		snapshot.quotas = quotas.Quotas
+	}()
+	wg.Wait()
+	return &snapshot
+}
+
+func renderAccountSnapshot(ctx CommandContext, buf *bytes.Buffer, snapshot *accountSnapshot, args TopAccountArgs) {
+	fmt.Fprintf(buf, "scw _top-account - %s - region %s - API latency %s - refresh every %s\n\n",
+		snapshot.date.Format("15:04:05"), ctx.API.CurrentRegion(), snapshot.latency/time.Millisecond*time.Millisecond, args.Interval)
+
+	// servers by state
+	states := make(map[string]int)
+	for _, server := range snapshot.servers {
+		states[server.State]++
+	}
+	names := make([]string, 0, len(states))
+	for state := range states {
+		names = append(names, state)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, state := range names {
+		parts = append(parts, fmt.Sprintf("%d %s", states[state], state))
+	}
+	fmt.Fprintf(buf, "Servers: %d total", len(snapshot.servers))
+	if len(parts) > 0 {
+		fmt.Fprintf(buf, ", %s", strings.Join(parts, ", "))
+	}
+	fmt.Fprintf(buf, "\n\n")
+
+	// quotas usage
+	if snapshot.dashboard != nil {
+		w := tabwriter.NewWriter(buf, 10, 1, 3, ' ', 0)
+		fmt.Fprintf(w, "RESOURCE\tUSED\tQUOTA\tUSAGE\n")
+		usages := []struct {
+			name  string
+			count int
+		}{
+			{"servers", snapshot.dashboard.ServersCount},
+			{"volumes", snapshot.dashboard.VolumesCount},
+			{"snapshots", snapshot.dashboard.SnapshotsCount},
+			{"images", snapshot.dashboard.ImagesCount},
+			{"ips", snapshot.dashboard.IPsCount},
+		}
+		for _, usage := range usages {
+			limit, ok := snapshot.quotas[usage.name]
+			if !ok || limit <= 0 {
+				fmt.Fprintf(w, "%s\t%d\t-\t-\n", usage.name, usage.count)
+				continue
+			}
+			fmt.Fprintf(w, "%s\t%d\t%d\t%d%%\n", usage.name, usage.count, limit, usage.count*100/limit)
+		}
+		w.Flush()
+		fmt.Fprintf(buf, "\n")
+	}
+
+	// recent tasks, most recent first
+	tasks := snapshot.tasks
+	sort.Slice(tasks, func(i, j int) bool { return tasks[j].StartDate.Value().Before(tasks[i].StartDate.Value()) })
+	if args.Tasks > 0 && len(tasks) > args.Tasks {
+		tasks = tasks[:args.Tasks]
+	}
+	w := tabwriter.NewWriter(buf, 10, 1, 3, ' ', 0)
+	fmt.Fprintf(w, "TASK ID\tDESCRIPTION\tSTATUS\tPROGRESS\tSTARTED\n")
+	for _, task := range tasks {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d%%\t%s\n", utils.TruncIf(task.Identifier, 8, true), task.Description, task.Status, task.Progress, ctx.FormatTime(task.StartDate.Value()))
+	}
+	w.Flush()
+
+	for _, err := range snapshot.errors {
+		fmt.Fprintf(buf, "\nerror: %s", err)
+	}
+	if len(snapshot.errors) > 0 {
+		fmt.Fprintf(buf, "\n")
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRenderAccountSnapshot(t *testing.T) {
+	Convey("Testing renderAccountSnapshot()", t, func() {
+		ctx := CommandContext{
+			Streams: Streams{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}},
+			API:     api.NewFakeScalewayAPI("orga"),
+		}
+		snapshot := &accountSnapshot{
+			servers: []api.ScalewayServer{{State: "running"}, {State: "running"}, {State: "stopped"}},
+			tasks: []api.ScalewayTask{
+				{Identifier: "11111111-1111-1111-1111-111111111111", Description: "server_poweron", Status: "success"},
+				{Identifier: "short", Description: "server_poweroff", Status: "pending"},
+			},
+		}
+
+		buf := &bytes.Buffer{}
+		renderAccountSnapshot(ctx, buf, snapshot, TopAccountArgs{})
+		So(buf.String(), ShouldContainSubstring, "Servers: 3 total, 2 running, 1 stopped")
+		So(buf.String(), ShouldContainSubstring, "11111111 ")
+		So(buf.String(), ShouldContainSubstring, "short ")
+	})
+}
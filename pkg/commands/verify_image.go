@@ -0,0 +1,165 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/scaleway/scaleway-cli/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// VerifyImageArgs are arguments passed to `RunVerifyImage`
+type VerifyImageArgs struct {
+	Image          string
+	CommercialType string
+	Command        []string
+	Gateway        string
+	SSHUser        string
+	SSHPort        int
+	Timeout        time.Duration
+}
+
+// smallestCommercialType returns the non baremetal commercial type of arch with the least RAM then CPUs
+func smallestCommercialType(products *api.ScalewayProductsServers, arch string) (string, error) {
+	names := []string{}
+	for name, product := range products.Servers {
+		if product.Baremetal || (arch != "" && product.Arch != arch) {
+			continue
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return "", fmt.Errorf("no commercial type available for arch %q", arch)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := products.Servers[names[i]], products.Servers[names[j]]
+		if a.Ram != b.Ram {
+			return a.Ram < b.Ram
+		}
+		if a.Ncpus != b.Ncpus {
+			return a.Ncpus < b.Ncpus
+		}
+		return names[i] < names[j]
+	})
+	return names[0], nil
+}
+
+// resolveCommercialType returns commercialType, or the smallest commercial type able to run image when it is empty
+func resolveCommercialType(ctx CommandContext, image, commercialType string) (string, error) {
+	if commercialType != "" {
+		return commercialType, nil
+	}
+	imageID, err := ctx.API.GetImageID(image, "*")
+	if err != nil {
+		return "", err
+	}
+	products, err := ctx.API.GetProductsServers()
+	if err != nil {
+		return "", api.Wrapf(err, "unable to fetch products from the Scaleway API")
+	}
+	return smallestCommercialType(products, imageID.Arch)
+}
+
+// RunVerifyImage is the handler for 'scw _verify-image'
+func RunVerifyImage(ctx CommandContext, args VerifyImageArgs) error {
+	start := time.Now()
+	err := verifyImage(ctx, args)
+	elapsed := time.Since(start).Truncate(time.Second)
+	if err != nil {
+		fmt.Fprintf(ctx.Stdout, "FAIL %s (%s): %v\n", args.Image, elapsed, err)
+		return fmt.Errorf("image %s failed verification", args.Image)
+	}
+	fmt.Fprintf(ctx.Stdout, "PASS %s (%s)\n", args.Image, elapsed)
+	return nil
+}
+
+func verifyImage(ctx CommandContext, args VerifyImageArgs) error {
+	commercialType, err := resolveCommercialType(ctx, args.Image, args.CommercialType)
+	if err != nil {
+		return err
+	}
+
+	gateway, err := api.ResolveGateway(ctx.API, args.Gateway)
+	if err != nil {
+		return api.Wrapf(err, "cannot resolve Gateway '%s'", args.Gateway)
+	}
+
+	logrus.Infof("Creating a %s server from %s ...", commercialType, args.Image)
+	config := api.ConfigCreateServer{
+		ImageName:         args.Image,
+		Name:              "scw-verify-" + utils.Wordify(args.Image),
+		CommercialType:    commercialType,
+		DynamicIPRequired: gateway == "",
+		BootType:          "auto",
+	}
+	serverID, err := api.CreateServer(ctx.API, &config)
+	if err != nil {
+		return api.Wrapf(err, "failed to create server")
+	}
+	defer destroyServerAndVolumes(ctx, serverID)
+
+	if err = api.StartServer(ctx.API, serverID, false); err != nil {
+		return api.Wrapf(err, "failed to start server %s", serverID)
+	}
+	logrus.Info("Waiting for SSH ...")
+	ready := make(chan error, 1)
+	var server *api.ScalewayServer
+	go func() {
+		var err error
+		server, err = api.WaitForServerReady(ctx.API, serverID, gateway)
+		ready <- err
+	}()
+	var timeout <-chan time.Time
+	if args.Timeout > 0 {
+		timeout = time.After(args.Timeout)
+	}
+	select {
+	case err = <-ready:
+		if err != nil {
+			return api.Wrapf(err, "server did not boot")
+		}
+	case <-timeout:
+		return fmt.Errorf("server did not boot within %s", args.Timeout)
+	}
+
+	if len(args.Command) > 0 {
+		logrus.Infof("Running health command: %s ...", strings.Join(args.Command, " "))
+		if err = utils.SSHExec(server.PublicAddress.IP, server.PrivateIP, args.SSHUser, args.SSHPort, args.Command, false, gateway, false); err != nil {
+			return api.Wrapf(err, "health command failed")
+		}
+	}
+	return nil
+}
+
+// destroyServerAndVolumes removes a temporary server with its volumes, terminate does both for a started server
+func destroyServerAndVolumes(ctx CommandContext, serverID string) {
+	logrus.Infof("Destroying server %s ...", serverID)
+	server, err := ctx.API.GetServer(serverID)
+	if err != nil {
+		logrus.Errorf("Failed to destroy server %s: %v", serverID, err)
+		return
+	}
+	if server.State != "stopped" {
+		if err = ctx.API.PostServerAction(serverID, "terminate"); err != nil {
+			logrus.Errorf("Failed to terminate server %s: %v", serverID, err)
+			logrus.Errorf("Try to run 'scw rm -f %s' later", serverID)
+		}
+		return
+	}
+	if err = ctx.API.DeleteServer(serverID); err != nil {
+		logrus.Errorf("Failed to delete server %s: %v", serverID, err)
+		return
+	}
+	for _, volume := range server.Volumes {
+		if err = ctx.API.DeleteVolume(volume.Identifier); err != nil {
+			logrus.Errorf("Failed to delete volume %s: %v", volume.Identifier, err)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSmallestCommercialType(t *testing.T) {
+	Convey("Testing smallestCommercialType()", t, func() {
+		products := &api.ScalewayProductsServers{
+			Servers: map[string]api.ProductServer{
+				"C1":        {Arch: "arm", Ncpus: 4, Ram: 2 << 30, Baremetal: true},
+				"DEV1-M":    {Arch: "x86_64", Ncpus: 3, Ram: 4 << 30},
+				"DEV1-S":    {Arch: "x86_64", Ncpus: 2, Ram: 2 << 30},
+				"X64-2GB":   {Arch: "x86_64", Ncpus: 6, Ram: 2 << 30},
+				"ARM64-2GB": {Arch: "arm64", Ncpus: 4, Ram: 2 << 30},
+			},
+		}
+		name, err := smallestCommercialType(products, "x86_64")
+		So(err, ShouldBeNil)
+		So(name, ShouldEqual, "DEV1-S")
+		name, err = smallestCommercialType(products, "arm64")
+		So(err, ShouldBeNil)
+		So(name, ShouldEqual, "ARM64-2GB")
+
+		_, err = smallestCommercialType(products, "arm")
+		So(err, ShouldNotBeNil)
+	})
+}
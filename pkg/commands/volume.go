@@ -0,0 +1,274 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/docker/go-units"
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/scaleway/scaleway-cli/pkg/utils"
+)
+
+// VolumeCreateArgs are flags for the `RunVolumeCreate` function
+type VolumeCreateArgs struct {
+	Name     string
+	Size     string
+	Type     string
+	Snapshot string
+}
+
+// VolumeListArgs are flags for the `RunVolumeList` function
+type VolumeListArgs struct {
+	Quiet   bool
+	NoTrunc bool
+}
+
+// VolumesArgs are flags for the `RunVolumeRemove` and `RunVolumeInspect` functions
+type VolumesArgs struct {
+	Volumes []string
+}
+
+// VolumeAttachArgs are flags for the `RunVolumeAttach` function
+type VolumeAttachArgs struct {
+	Volume string
+	Server string
+}
+
+// VolumeDetachArgs are flags for the `RunVolumeDetach` function
+type VolumeDetachArgs struct {
+	Volume string
+	Force  bool
+}
+
+// sortedVolumeSlots returns the slots of volumes in numeric order
+func sortedVolumeSlots(volumes map[string]api.ScalewayVolume) []string {
+	slots := make([]string, 0, len(volumes))
+	for slot := range volumes {
+		slots = append(slots, slot)
+	}
+	sort.Slice(slots, func(i, j int) bool {
+		a, _ := strconv.Atoi(slots[i])
+		b, _ := strconv.Atoi(slots[j])
+		return a < b
+	})
+	return slots
+}
+
+// attachedVolumes returns the volumes payload of a server patch, volumeID is added after the
+// current volumes or removed from them, the slots are kept contiguous as expected by the API
+func attachedVolumes(volumes map[string]api.ScalewayVolume, addID, removeID string) map[string]api.ScalewayVolume {
+	patch := make(map[string]api.ScalewayVolume, len(volumes)+1)
+	for _, slot := range sortedVolumeSlots(volumes) {
+		if volumes[slot].Identifier == removeID {
+			continue
+		}
+		patch[strconv.Itoa(len(patch))] = api.ScalewayVolume{Identifier: volumes[slot].Identifier}
+	}
+	if addID != "" {
+		patch[strconv.Itoa(len(patch))] = api.ScalewayVolume{Identifier: addID}
+	}
+	return patch
+}
+
+// RunVolumeCreate is the handler for 'scw volume create'
+func RunVolumeCreate(ctx CommandContext, args VolumeCreateArgs) error {
+	definition := api.ScalewayVolumeDefinition{
+		Name: args.Name,
+		Type: args.Type,
+	}
+	if args.Snapshot != "" {
+		snapshotID, err := ctx.API.GetSnapshotID(args.Snapshot)
+		if err != nil {
+			return err
+		}
+		snapshot, err := ctx.API.GetSnapshot(snapshotID)
+		if err != nil {
+			return api.Wrapf(err, "cannot fetch snapshot")
+		}
+		definition.BaseSnapshot = snapshotID
+		definition.Size = snapshot.Size
+		if definition.Type == "" {
+			definition.Type = snapshot.VolumeType
+		}
+	}
+	if args.Size != "" {
+		size, err := utils.ParseSize(args.Size)
+		if err != nil {
+			return err
+		}
+		if definition.BaseSnapshot != "" && size < definition.Size {
+			return fmt.Errorf("size %s is smaller than the snapshot (%s)", args.Size, units.HumanSize(float64(definition.Size)))
+		}
+		definition.Size = size
+	}
+	if definition.Size == 0 {
+		return fmt.Errorf("missing --size")
+	}
+	volumeID, err := ctx.API.PostVolume(definition)
+	if err != nil {
+		return api.Wrapf(err, "cannot create volume")
+	}
+	fmt.Fprintln(ctx.Stdout, volumeID)
+	return nil
+}
+
+// RunVolumeList is the handler for 'scw volume ls'
+func RunVolumeList(ctx CommandContext, args VolumeListArgs) error {
+	volumes, err := ctx.API.GetVolumes()
+	if err != nil {
+		return api.Wrapf(err, "unable to fetch volumes from the Scaleway API")
+	}
+	owned := []api.ScalewayVolume{}
+	for _, volume := range *volumes {
+		if volume.Organization == ctx.API.OrganizationID() {
+			owned = append(owned, volume)
+		}
+	}
+	sort.Slice(owned, func(i, j int) bool {
+		return owned[i].CreationDate.Value().After(owned[j].CreationDate.Value())
+	})
+
+	w := tabwriter.NewWriter(ctx.Stdout, 20, 1, 3, ' ', 0)
+	defer w.Flush()
+	if !args.Quiet {
+		fmt.Fprintf(w, "VOLUME ID\tNAME\tSIZE\tTYPE\tSERVER\tCREATED\n")
+	}
+	for _, volume := range owned {
+		if args.Quiet {
+			fmt.Fprintln(w, volume.Identifier)
+			continue
+		}
+		server := "-"
+		if volume.Server != nil {
+			server = utils.TruncIf(volume.Server.Name, 25, !args.NoTrunc)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			utils.TruncIf(volume.Identifier, 8, !args.NoTrunc),
+			utils.TruncIf(volume.Name, 25, !args.NoTrunc),
+			units.HumanSize(float64(volume.Size)),
+			volume.VolumeType,
+			server,
+			ctx.FormatTime(volume.CreationDate.Value()),
+		)
+	}
+	return nil
+}
+
+// RunVolumeRemove is the handler for 'scw volume rm'
+func RunVolumeRemove(ctx CommandContext, args VolumesArgs) error {
+	result := NewBulkResult(ctx, "remove", args.Volumes)
+	for _, needle := range args.Volumes {
+		done := result.Start(needle)
+		volumeID, err := ctx.API.GetVolumeID(needle)
+		if err != nil {
+			done(err)
+			continue
+		}
+		volume, err := ctx.API.GetVolume(volumeID)
+		if err != nil {
+			done(err)
+			continue
+		}
+		if volume.Server != nil {
+			done(fmt.Errorf("volume is attached to server %s, detach it first", volume.Server.Name))
+			continue
+		}
+		done(ctx.API.DeleteVolume(volumeID))
+	}
+	if err := result.Report(); err != nil {
+		return err
+	}
+	if result.Failed() > 0 {
+		return fmt.Errorf("at least 1 volume failed to be removed")
+	}
+	return nil
+}
+
+// RunVolumeInspect is the handler for 'scw volume inspect'
+func RunVolumeInspect(ctx CommandContext, args VolumesArgs) error {
+	volumes := []api.ScalewayVolume{}
+	for _, needle := range args.Volumes {
+		volumeID, err := ctx.API.GetVolumeID(needle)
+		if err != nil {
+			return err
+		}
+		volume, err := ctx.API.GetVolume(volumeID)
+		if err != nil {
+			return api.Wrapf(err, "cannot fetch volume %s", needle)
+		}
+		volumes = append(volumes, *volume)
+	}
+	res, err := marshalInspected(volumes, ctx.TimeFormat)
+	if err != nil {
+		return api.Wrapf(err, "cannot marshal volumes")
+	}
+	fmt.Fprintln(ctx.Stdout, string(res))
+	return nil
+}
+
+// RunVolumeAttach is the handler for 'scw volume attach'
+func RunVolumeAttach(ctx CommandContext, args VolumeAttachArgs) error {
+	volumeID, err := ctx.API.GetVolumeID(args.Volume)
+	if err != nil {
+		return err
+	}
+	volume, err := ctx.API.GetVolume(volumeID)
+	if err != nil {
+		return api.Wrapf(err, "cannot fetch volume")
+	}
+	if volume.Server != nil {
+		return fmt.Errorf("volume %s is already attached to server %s", volume.Name, volume.Server.Name)
+	}
+	serverID, err := ctx.API.GetServerID(args.Server)
+	if err != nil {
+		return err
+	}
+	server, err := ctx.API.GetServer(serverID)
+	if err != nil {
+		return api.Wrapf(err, "cannot fetch server")
+	}
+	if server.State != "stopped" {
+		return fmt.Errorf("server %s is %s, volumes can only be attached to a stopped server", server.Name, server.State)
+	}
+	volumes := attachedVolumes(server.Volumes, volumeID, "")
+	if err = ctx.API.PatchServer(serverID, api.ScalewayServerPatchDefinition{Volumes: &volumes}); err != nil {
+		return api.Wrapf(err, "cannot attach volume")
+	}
+	return nil
+}
+
+// RunVolumeDetach is the handler for 'scw volume detach'
+func RunVolumeDetach(ctx CommandContext, args VolumeDetachArgs) error {
+	volumeID, err := ctx.API.GetVolumeID(args.Volume)
+	if err != nil {
+		return err
+	}
+	volume, err := ctx.API.GetVolume(volumeID)
+	if err != nil {
+		return api.Wrapf(err, "cannot fetch volume")
+	}
+	if volume.Server == nil {
+		return fmt.Errorf("volume %s is not attached", volume.Name)
+	}
+	server, err := ctx.API.GetServer(volume.Server.Identifier)
+	if err != nil {
+		return api.Wrapf(err, "cannot fetch server")
+	}
+	if server.State != "stopped" {
+		return fmt.Errorf("server %s is %s, the volume may be mounted: stop the server first", server.Name, server.State)
+	}
+	if root, ok := server.Volumes["0"]; ok && root.Identifier == volumeID && !args.Force {
+		return fmt.Errorf("volume %s is the root volume of server %s, use --force to detach it anyway", volume.Name, server.Name)
+	}
+	volumes := attachedVolumes(server.Volumes, "", volumeID)
+	if err = ctx.API.PatchServer(server.Identifier, api.ScalewayServerPatchDefinition{Volumes: &volumes}); err != nil {
+		return api.Wrapf(err, "cannot detach volume")
+	}
+	return nil
+}
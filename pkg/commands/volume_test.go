@@ -0,0 +1,34 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAttachedVolumes(t *testing.T) {
+	Convey("Testing attachedVolumes()", t, func() {
+		volumes := map[string]api.ScalewayVolume{
+			"0":  {Identifier: "root"},
+			"1":  {Identifier: "data"},
+			"10": {Identifier: "logs"},
+			"2":  {Identifier: "cache"},
+		}
+		So(sortedVolumeSlots(volumes), ShouldResemble, []string{"0", "1", "2", "10"})
+
+		attached := attachedVolumes(volumes, "backup", "")
+		So(len(attached), ShouldEqual, 5)
+		So(attached["4"].Identifier, ShouldEqual, "backup")
+
+		detached := attachedVolumes(volumes, "", "data")
+		So(len(detached), ShouldEqual, 3)
+		So(detached["0"].Identifier, ShouldEqual, "root")
+		So(detached["1"].Identifier, ShouldEqual, "cache")
+		So(detached["2"].Identifier, ShouldEqual, "logs")
+	})
+}
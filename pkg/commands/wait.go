@@ -5,35 +5,190 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/scaleway/scaleway-cli/pkg/utils"
 	"github.com/sirupsen/logrus"
 )
 
 // WaitArgs are flags for the `RunWait` function
 type WaitArgs struct {
 	Servers []string
+
+	// Conditions must all be satisfied, see parseWaitConditions, the servers are waited until stopped when empty
+	Conditions []string
+
+	// Any returns as soon as one of the servers satisfies the conditions
+	Any bool
+
+	// JSON prints a WaitMatch per satisfied server
+	JSON bool
+}
+
+// WaitMatch is a server satisfying the conditions of 'scw wait'
+type WaitMatch struct {
+	Server     string   `json:"server"`
+	ID         string   `json:"id"`
+	Conditions []string `json:"conditions"`
+	ElapsedMS  int64    `json:"elapsed_ms"`
+}
+
+// waitCondition is a check on the state of a server
+type waitCondition struct {
+	Name  string
+	Check func(server *api.ScalewayServer) bool
+}
+
+// parseWaitConditions parses conditions like "state=running" or "ssh", the SSH port being reachable on the public IP
+func parseWaitConditions(specs []string) ([]waitCondition, error) {
+	if len(specs) == 0 {
+		specs = []string{"state=stopped"}
+	}
+	conditions := make([]waitCondition, 0, len(specs))
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		switch {
+		case strings.HasPrefix(spec, "state="):
+			state := strings.TrimPrefix(spec, "state=")
+			if state == "" {
+				return nil, fmt.Errorf("invalid condition %q, expected state=STATE", spec)
+			}
+			conditions = append(conditions, waitCondition{
+				Name:  spec,
+				Check: func(server *api.ScalewayServer) bool { return server.State == state },
+			})
+		case spec == "ssh":
+			conditions = append(conditions, waitCondition{
+				Name:  spec,
+				Check: isSSHReachable,
+			})
+		default:
+			return nil, fmt.Errorf("invalid condition %q, expected state=STATE or ssh", spec)
+		}
+	}
+	return conditions, nil
+}
+
+// isSSHReachable returns true when the SSH port of a running server accepts connections
+func isSSHReachable(server *api.ScalewayServer) bool {
+	if server.State != "running" {
+		return false
+	}
+	ip := server.PublicAddress.IP
+	if ip == "" && server.EnableIPV6 && server.IPV6 != nil {
+		ip = fmt.Sprintf("[%s]", server.IPV6.Address)
+	}
+	if ip == "" {
+		return false
+	}
+	return utils.IsTCPPortOpen(fmt.Sprintf("%s:22", ip))
+}
+
+// waitForConditions asks API in a loop until one of the servers satisfies every condition
+func waitForConditions(scw api.ScalewayAPIClient, serverIDs []string, conditions []waitCondition) (*WaitMatch, error) {
+	start := time.Now()
+	states := make(map[string]string, len(serverIDs))
+	for {
+		for _, serverID := range serverIDs {
+			server, err := scw.GetServer(serverID)
+			if err != nil {
+				return nil, err
+			}
+			if states[serverID] != server.State {
+				logrus.Infof("Server %s changed state to '%s'", server.Name, server.State)
+				states[serverID] = server.State
+			}
+			match := &WaitMatch{
+				Server:    server.Name,
+				ID:        server.Identifier,
+				ElapsedMS: int64(time.Since(start) / time.Millisecond),
+			}
+			for _, condition := range conditions {
+				if !condition.Check(server) {
+					match = nil
+					break
+				}
+				match.Conditions = append(match.Conditions, condition.Name)
+			}
+			if match != nil {
+				return match, nil
+			}
+		}
+		time.Sleep(api.WaitPollInterval)
+	}
+}
+
+// printWaitMatch prints the server satisfying the conditions, as JSON with args.JSON
+func printWaitMatch(ctx CommandContext, args WaitArgs, match *WaitMatch) error {
+	if !args.JSON {
+		fmt.Fprintln(ctx.Stdout, match.Server)
+		return nil
+	}
+	out, err := json.Marshal(match)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(ctx.Stdout, string(out))
+	return nil
 }
 
 // RunWait is the handler for 'scw wait'
 func RunWait(ctx CommandContext, args WaitArgs) error {
-	hasError := false
+	conditions, err := parseWaitConditions(args.Conditions)
+	if err != nil {
+		return err
+	}
+	if args.Any {
+		return runWaitAny(ctx, args, conditions)
+	}
+
+	result := NewBulkResult(ctx, "wait for server", args.Servers)
+	result.Quiet = true
+	if len(args.Conditions) == 0 {
+		result.Event = NotifyServerStopped
+	}
 	for _, needle := range args.Servers {
+		done := result.Start(needle)
 		serverIdentifier, err := ctx.API.GetServerID(needle)
-		if err != nil {
-			logrus.Error(err)
-			hasError = true
-		} else {
-			if _, err := api.WaitForServerStopped(ctx.API, serverIdentifier); err != nil {
-				logrus.Errorf("failed to wait for server %s: %v", serverIdentifier, err)
-				hasError = true
+		if err == nil {
+			var match *WaitMatch
+			match, err = waitForConditions(ctx.API, []string{serverIdentifier}, conditions)
+			if err == nil && args.JSON {
+				err = printWaitMatch(ctx, args, match)
 			}
 		}
+		done(err)
 	}
 
-	if hasError {
-		return fmt.Errorf("at least 1 server failed to be stopped")
+	if err := result.Report(); err != nil {
+		return err
+	}
+	if result.Failed() > 0 {
+		if len(args.Conditions) == 0 {
+			return fmt.Errorf("at least 1 server failed to be stopped")
+		}
+		return fmt.Errorf("at least 1 server failed to satisfy the conditions")
 	}
 	return nil
 }
+
+// runWaitAny waits until the first of the servers satisfies the conditions and prints it
+func runWaitAny(ctx CommandContext, args WaitArgs, conditions []waitCondition) error {
+	serverIDs := make([]string, 0, len(args.Servers))
+	for _, needle := range args.Servers {
+		serverID, err := ctx.API.GetServerID(needle)
+		if err != nil {
+			return err
+		}
+		serverIDs = append(serverIDs, serverID)
+	}
+	match, err := waitForConditions(ctx.API, serverIDs, conditions)
+	if err != nil {
+		return err
+	}
+	return printWaitMatch(ctx, args, match)
+}
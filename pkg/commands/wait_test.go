@@ -0,0 +1,36 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseWaitConditions(t *testing.T) {
+	Convey("Testing parseWaitConditions()", t, func() {
+		conditions, err := parseWaitConditions(nil)
+		So(err, ShouldBeNil)
+		So(len(conditions), ShouldEqual, 1)
+		So(conditions[0].Name, ShouldEqual, "state=stopped")
+		So(conditions[0].Check(&api.ScalewayServer{State: "stopped"}), ShouldBeTrue)
+
+		conditions, err = parseWaitConditions([]string{"state=running", " ssh"})
+		So(err, ShouldBeNil)
+		So(len(conditions), ShouldEqual, 2)
+		So(conditions[0].Check(&api.ScalewayServer{State: "stopped"}), ShouldBeFalse)
+		So(conditions[1].Name, ShouldEqual, "ssh")
+		So(conditions[1].Check(&api.ScalewayServer{State: "stopped"}), ShouldBeFalse)
+		// IPv6 enabled but not allocated yet
+		So(conditions[1].Check(&api.ScalewayServer{State: "running", EnableIPV6: true}), ShouldBeFalse)
+
+		_, err = parseWaitConditions([]string{"state="})
+		So(err, ShouldNotBeNil)
+		_, err = parseWaitConditions([]string{"running"})
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,136 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	"github.com/scaleway/scaleway-cli/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// WatchTasksArgs are flags for the `RunWatchTasks` function
+type WatchTasksArgs struct {
+	Interval time.Duration
+	Server   string
+	Type     string
+	Status   string
+	Format   string
+	Existing bool
+}
+
+// TaskEvent is a task which appeared or changed of status between two polls
+type TaskEvent struct {
+	Event       string `json:"event"`
+	Identifier  string `json:"id"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+	Progress    int    `json:"progress"`
+	StartedAt   string `json:"started_at,omitempty"`
+	HrefFrom    string `json:"href_from"`
+}
+
+// taskFilter selects the watched tasks
+type taskFilter struct {
+	serverID string
+	kind     string
+	status   string
+}
+
+func (f taskFilter) match(task api.ScalewayTask) bool {
+	if f.serverID != "" && !strings.Contains(task.HrefFrom, f.serverID) {
+		return false
+	}
+	if f.kind != "" && !strings.Contains(task.Description, f.kind) {
+		return false
+	}
+	return f.status == "" || task.Status == f.status
+}
+
+// diffTasks returns the events of the tasks matching filter which are unknown from seen or changed of status,
+// seen is updated with the status of every task
+func diffTasks(seen map[string]string, tasks []api.ScalewayTask, filter taskFilter) []TaskEvent {
+	var events []TaskEvent
+	for _, task := range tasks {
+		previous, known := seen[task.Identifier]
+		seen[task.Identifier] = task.Status
+		if known && previous == task.Status {
+			continue
+		}
+		if !filter.match(task) {
+			continue
+		}
+		event := TaskEvent{
+			Event:       "new",
+			Identifier:  task.Identifier,
+			Description: task.Description,
+			Status:      task.Status,
+			Progress:    task.Progress,
+			HrefFrom:    task.HrefFrom,
+		}
+		if known {
+			event.Event = "update"
+		}
+		if !task.StartDate.Value().IsZero() {
+			event.StartedAt = task.StartDate.Value().Format(time.RFC3339)
+		}
+		events = append(events, event)
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].StartedAt < events[j].StartedAt })
+	return events
+}
+
+// RunWatchTasks is the handler for 'scw _watch-tasks'
+func RunWatchTasks(ctx CommandContext, args WatchTasksArgs) error {
+	if args.Format != "" && args.Format != ReportFormatTable && args.Format != ReportFormatJSON {
+		return fmt.Errorf("invalid format %q, expected table or json", args.Format)
+	}
+	filter := taskFilter{
+		kind:   args.Type,
+		status: args.Status,
+	}
+	if args.Server != "" {
+		serverID, err := ctx.API.GetServerID(args.Server)
+		if err != nil {
+			return err
+		}
+		filter.serverID = serverID
+	}
+
+	seen := make(map[string]string)
+	encoder := json.NewEncoder(ctx.Stdout)
+	if args.Format != ReportFormatJSON {
+		fmt.Fprintf(ctx.Stdout, "%-20s %-6s %-8s %-32s %-10s %-8s %s\n", "TIME", "EVENT", "TASK ID", "DESCRIPTION", "STATUS", "PROGRESS", "FROM")
+	}
+	for first := true; ; first = false {
+		tasks, err := ctx.API.GetTasks()
+		if err != nil {
+			// a big migration shouldn't lose its monitoring on a transient error
+			logrus.Warnf("unable to fetch tasks from the Scaleway API: %v", err)
+			time.Sleep(args.Interval)
+			continue
+		}
+		events := diffTasks(seen, *tasks, filter)
+		if first && !args.Existing {
+			events = nil
+		}
+		now := time.Now()
+		for _, event := range events {
+			if args.Format == ReportFormatJSON {
+				if err = encoder.Encode(event); err != nil {
+					return err
+				}
+				continue
+			}
+			fmt.Fprintf(ctx.Stdout, "%-20s %-6s %-8s %-32s %-10s %7d%% %s\n", ctx.FormatTime(now), event.Event, utils.TruncIf(event.Identifier, 8, true), event.Description, event.Status, event.Progress, event.HrefFrom)
+		}
+		time.Sleep(args.Interval)
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDiffTasks(t *testing.T) {
+	Convey("Testing diffTasks()", t, func() {
+		seen := make(map[string]string)
+		filter := taskFilter{serverID: "1111"}
+		tasks := []api.ScalewayTask{
+			{Identifier: "aaaaaaaa", Description: "server_poweron", Status: "pending", HrefFrom: "/servers/1111/action"},
+			{Identifier: "bbbbbbbb", Description: "server_poweron", Status: "pending", HrefFrom: "/servers/2222/action"},
+		}
+		events := diffTasks(seen, tasks, filter)
+		So(len(events), ShouldEqual, 1)
+		So(events[0].Event, ShouldEqual, "new")
+		So(len(diffTasks(seen, tasks, filter)), ShouldEqual, 0)
+
+		tasks[0].Status = "success"
+		events = diffTasks(seen, tasks, filter)
+		So(len(events), ShouldEqual, 1)
+		So(events[0].Event, ShouldEqual, "update")
+		So(events[0].Status, ShouldEqual, "success")
+	})
+}
@@ -0,0 +1,79 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package commands
+
+import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/scaleway/scaleway-cli/pkg/api"
+)
+
+// WhoamiArgs are flags for the `RunWhoami` function
+type WhoamiArgs struct{}
+
+// tokenExpiry returns a human representation of the expiration date of a token
+func tokenExpiry(expires string, now time.Time) string {
+	if expires == "" {
+		return "never"
+	}
+	date, err := time.Parse(time.RFC3339, expires)
+	if err != nil {
+		return expires
+	}
+	if date.Before(now) {
+		return fmt.Sprintf("%s (expired)", date.UTC().Format(time.RFC3339))
+	}
+	return fmt.Sprintf("%s (in %v)", date.UTC().Format(time.RFC3339), date.Sub(now).Round(time.Minute))
+}
+
+// RunWhoami is the handler for 'scw _whoami'
+func RunWhoami(ctx CommandContext, args WhoamiArgs) error {
+	token, err := ctx.API.GetToken()
+	if err != nil {
+		return api.Wrapf(err, "unable to fetch the token")
+	}
+	user, err := ctx.API.GetUser()
+	if err != nil {
+		return api.Wrapf(err, "unable to fetch the user")
+	}
+	organizationName := "?"
+	if organizations, err := ctx.API.GetOrganization(); err == nil {
+		for _, organization := range organizations.Organizations {
+			if organization.ID == ctx.API.OrganizationID() {
+				organizationName = organization.Name
+			}
+		}
+	}
+
+	scope := "user permissions"
+	if !token.InheritsUsersPerms {
+		scope = "restricted"
+		if token.Roles.Role != "" {
+			scope = fmt.Sprintf("%s of %s", token.Roles.Role, token.Roles.Organization.Name)
+		}
+	}
+	// the token ID is the secret itself, only its first characters are shown without --sensitive
+	tokenID := token.ID
+	if ctx.Getenv("SCW_SENSITIVE") != "1" && len(tokenID) > 8 {
+		tokenID = tokenID[:8] + "-xxxx-xxxx-xxxx-xxxxxxxxxxxx"
+	}
+
+	w := tabwriter.NewWriter(ctx.Stdout, 20, 1, 3, ' ', 0)
+	defer w.Flush()
+	fmt.Fprintf(w, "User:\t%s (%s)\n", user.Email, user.Fullname)
+	fmt.Fprintf(w, "Organization:\t%s (%s)\n", organizationName, ctx.API.OrganizationID())
+	fmt.Fprintf(w, "Token:\t%s\n", tokenID)
+	if token.Description != "" {
+		fmt.Fprintf(w, "  Description:\t%s\n", token.Description)
+	}
+	fmt.Fprintf(w, "  Scope:\t%s\n", scope)
+	fmt.Fprintf(w, "  Expires:\t%s\n", tokenExpiry(token.Expires, time.Now()))
+	fmt.Fprintf(w, "Region:\t%s\n", ctx.API.CurrentRegion())
+	fmt.Fprintf(w, "Compute API:\t%s\n", ctx.API.ComputeAPIURL())
+	fmt.Fprintf(w, "Account API:\t%s\n", ctx.API.AccountAPIURL())
+	return nil
+}
@@ -13,6 +13,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 
 	"github.com/scaleway/scaleway-cli/pkg/scwversion"
@@ -28,6 +29,103 @@ type Config struct {
 
 	// Version is the actual version of scw
 	Version string `json:"version"`
+
+	// Region is the default region (par1, ams1), overridden by SCW_REGION and --region
+	Region string `json:"region,omitempty"`
+
+	// Profiles are additional named accounts, used by commands iterating over every account
+	Profiles map[string]Profile `json:"profiles,omitempty"`
+
+	// ImageAliases are team-defined image names resolved before the other images, i.e: "ubuntu",
+	// each alias maps "REGION/ARCH", "REGION", "ARCH" or "*" to an image UUID or name, the most specific key wins
+	ImageAliases map[string]map[string]string `json:"image_aliases,omitempty"`
+
+	// NotifyURL receives a JSON payload when a waited-on operation finishes, overridden by --notify-url
+	NotifyURL string `json:"notify_url,omitempty"`
+
+	// Filters are named filter expressions, i.e: "prod": "tags=prod state=running", used as --filter @prod
+	Filters map[string]string `json:"filters,omitempty"`
+
+	// SharedCacheURL is a cache file published by the team (http, https or s3://BUCKET/KEY),
+	// merged read-only with the local cache to resolve names, overridden by SCW_SHARED_CACHE_URL
+	SharedCacheURL string `json:"shared_cache_url,omitempty"`
+
+	// Timeouts override the limits and delays of the API requests, the SSH connections and the waits
+	Timeouts *Timeouts `json:"timeouts,omitempty"`
+
+	// APIVersion prefixes the paths of the compute and account APIs, i.e: "v2", overridden by SCW_API_VERSION and --api-version
+	APIVersion string `json:"api_version,omitempty"`
+
+	// UniqueNames makes creating a server or an image named like an existing one fail instead of warning,
+	// same as --unique-names
+	UniqueNames bool `json:"unique_names,omitempty"`
+}
+
+// Timeouts are the limits and delays of the config file, each one is overridden by a flag.
+// The durations are written like "500ms", "30s" or "2m"
+type Timeouts struct {
+	API  APITimeouts  `json:"api"`
+	SSH  SSHTimeouts  `json:"ssh"`
+	Wait WaitTimeouts `json:"wait"`
+}
+
+// APITimeouts are the limits of the API requests
+type APITimeouts struct {
+	// Retries is the number of attempts of a request failing transiently, overridden by --api-retries
+	Retries int `json:"retries,omitempty"`
+
+	// Timeout is the time limit of each request, overridden by --timeout
+	Timeout string `json:"timeout,omitempty"`
+
+	// MaxRateWait is the time spent waiting when the API rate limits a request, overridden by --max-rate-wait
+	MaxRateWait string `json:"max_rate_wait,omitempty"`
+}
+
+// SSHTimeouts are the limits of the SSH connections
+type SSHTimeouts struct {
+	// ConnectTimeout is the time limit to open a connection, overridden by --ssh-connect-timeout
+	ConnectTimeout string `json:"connect_timeout,omitempty"`
+}
+
+// WaitTimeouts are the delays of the commands waiting for a state
+type WaitTimeouts struct {
+	// PollInterval is the delay between two requests, overridden by --wait-poll-interval
+	PollInterval string `json:"poll_interval,omitempty"`
+}
+
+// Profile is a named Scaleway account
+type Profile struct {
+	// Organization is the identifier of the Scaleway orgnization
+	Organization string `json:"organization"`
+
+	// Token is the authentication token for the Scaleway organization
+	Token string `json:"token"`
+
+	// Regions are the regions to query, all the regions when empty
+	Regions []string `json:"regions,omitempty"`
+}
+
+// DefaultProfile is the name of the profile built from the top-level credentials
+const DefaultProfile = "default"
+
+// AllProfiles returns the named profiles and the default one, sorted by name
+func (c *Config) AllProfiles() ([]string, map[string]Profile) {
+	profiles := make(map[string]Profile, len(c.Profiles)+1)
+	for name, profile := range c.Profiles {
+		profiles[name] = profile
+	}
+	if _, ok := profiles[DefaultProfile]; !ok && c.Token != "" {
+		profiles[DefaultProfile] = Profile{
+			Organization: c.Organization,
+			Token:        c.Token,
+		}
+	}
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, profiles
 }
 
 // Save write the config file
@@ -113,3 +113,19 @@ func TestGetHomeDir(t *testing.T) {
 		So(homedir, ShouldNotEqual, "")
 	})
 }
+
+func TestAllProfiles(t *testing.T) {
+	Convey("Testing Config.AllProfiles()", t, func() {
+		cfg := Config{
+			Organization: "orga-default",
+			Token:        "token-default",
+			Profiles: map[string]Profile{
+				"staging": {Organization: "orga-staging", Token: "token-staging", Regions: []string{"ams1"}},
+			},
+		}
+		names, profiles := cfg.AllProfiles()
+		So(names, ShouldResemble, []string{"default", "staging"})
+		So(profiles["default"].Token, ShouldEqual, "token-default")
+		So(profiles["staging"].Regions, ShouldResemble, []string{"ams1"})
+	})
+}
@@ -139,3 +139,13 @@ func (pl *List) GetByIdentifier(identifier string) *Object {
 	}
 	return nil
 }
+
+// MonthPrice returns the monthly price of a quantity billed per unit at the capped price
+// i.e: 120 GB of "/storage/local/ssd/storage" are billed as 3 units of 50 GB
+func (o *Object) MonthPrice(quantity *big.Rat) *big.Rat {
+	if quantity.Cmp(ratZero) < 1 {
+		return big.NewRat(0, 1)
+	}
+	units := new(big.Rat).SetInt(ratCeil(new(big.Rat).Quo(quantity, o.UnitQuantity)))
+	return units.Mul(units, o.UnitPriceCap)
+}
@@ -1,6 +1,7 @@
 package pricing
 
 import (
+	"math/big"
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
@@ -31,3 +32,15 @@ func TestGetByIdentifier(t *testing.T) {
 		So(object, ShouldBeNil)
 	})
 }
+
+func TestObject_MonthPrice(t *testing.T) {
+	Convey("Testing Object.MonthPrice", t, func() {
+		object := CurrentPricing.GetByPath("/storage/local/ssd/storage")
+		So(object, ShouldNotBeNil)
+
+		So(object.MonthPrice(big.NewRat(0, 1)), ShouldEqualBigRat, big.NewRat(0, 1))
+		So(object.MonthPrice(big.NewRat(1, 1)), ShouldEqualBigRat, big.NewRat(1, 1))
+		So(object.MonthPrice(big.NewRat(50, 1)), ShouldEqualBigRat, big.NewRat(1, 1))
+		So(object.MonthPrice(big.NewRat(120, 1)), ShouldEqualBigRat, big.NewRat(3, 1))
+	})
+}
@@ -1,6 +1,9 @@
 package scwversion
 
-import "fmt"
+import (
+	"fmt"
+	"runtime"
+)
 
 var (
 	// VERSION represents the semver version of the package
@@ -10,7 +13,7 @@ var (
 	GITCOMMIT string
 )
 
-// UserAgent returns a string to be used by API
+// UserAgent returns a string to be used by API, i.e: scw/v1.19 (linux; amd64)
 func UserAgent() string {
-	return fmt.Sprintf("scw/%v", VERSION)
+	return fmt.Sprintf("scw/%v (%s; %s)", VERSION, runtime.GOOS, runtime.GOARCH)
 }
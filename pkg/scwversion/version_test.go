@@ -1,6 +1,7 @@
 package scwversion
 
 import (
+	"runtime"
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
@@ -12,3 +13,9 @@ func TestInit(t *testing.T) {
 		// So(GITCOMMIT, ShouldNotEqual, "")
 	})
 }
+
+func TestUserAgent(t *testing.T) {
+	Convey("Testing UserAgent()", t, func() {
+		So(UserAgent(), ShouldEqual, "scw/"+VERSION+" ("+runtime.GOOS+"; "+runtime.GOARCH+")")
+	})
+}
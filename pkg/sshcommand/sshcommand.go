@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"runtime"
 	"strings"
+	"time"
 )
 
 // Command contains settings to build a ssh command
@@ -21,6 +22,17 @@ type Command struct {
 	AllocateTTY            bool
 	EnableSSHKeyForwarding bool
 
+	// ControlPath enables connection multiplexing, commands sharing a ControlPath reuse the same connection
+	ControlPath string
+	// ControlPersist is how long the master connection stays open after the last command (default 60s)
+	ControlPersist time.Duration
+
+	// ProxyCommand connects to the host when there is no Gateway, i.e: through a SOCKS5 proxy
+	ProxyCommand string
+
+	// ConnectTimeout is the time limit to open the connection, ssh's default when 0
+	ConnectTimeout time.Duration
+
 	isGateway bool
 }
 
@@ -42,6 +54,10 @@ func (c *Command) applyDefaults() {
 		c.Port = 22
 	}
 
+	if c.ControlPath != "" && c.ControlPersist == 0 {
+		c.ControlPersist = time.Minute
+	}
+
 	if c.isGateway {
 		c.SSHOptions = []string{"-W", "%h:%p"}
 	}
@@ -65,6 +81,14 @@ func (c *Command) Slice() []string {
 		slice = append(slice, "-o", "UserKnownHostsFile=/dev/null", "-o", "StrictHostKeyChecking=no")
 	}
 
+	if c.ControlPath != "" {
+		slice = append(slice, "-o", "ControlMaster=auto", "-o", "ControlPath="+c.ControlPath, "-o", fmt.Sprintf("ControlPersist=%d", int(c.ControlPersist.Seconds())))
+	}
+
+	if c.ConnectTimeout > 0 {
+		slice = append(slice, "-o", fmt.Sprintf("ConnectTimeout=%d", int((c.ConnectTimeout+time.Second-1)/time.Second)))
+	}
+
 	if len(c.SSHOptions) > 0 {
 		slice = append(slice, c.SSHOptions...)
 	}
@@ -72,6 +96,8 @@ func (c *Command) Slice() []string {
 	if c.Gateway != nil {
 		c.Gateway.isGateway = true
 		slice = append(slice, "-o", "ProxyCommand="+c.Gateway.String())
+	} else if c.ProxyCommand != "" {
+		slice = append(slice, "-o", "ProxyCommand="+c.ProxyCommand)
 	}
 
 	if c.User != "" {
@@ -101,7 +127,8 @@ func (c *Command) Slice() []string {
 				escapedCommand = append(escapedCommand, fmt.Sprintf("%q", part))
 			}
 		}
-		slice = append(slice, fmt.Sprintf("%q", strings.Join(escapedCommand, " ")))
+		// the remote login shell sees a double-quoted string, keep it from expanding what the command quotes
+		slice = append(slice, loginShellEscaper.Replace(fmt.Sprintf("%q", strings.Join(escapedCommand, " "))))
 	}
 	if runtime.GOOS == "windows" {
 		slice[len(slice)-1] = slice[len(slice)-1] + " " // Why ?
@@ -109,6 +136,8 @@ func (c *Command) Slice() []string {
 	return slice
 }
 
+var loginShellEscaper = strings.NewReplacer("$", `\$`, "`", "\\`")
+
 // String returns a copy-pasteable command, useful for debugging
 func (c *Command) String() string {
 	slice := c.Slice()
@@ -2,6 +2,8 @@ package sshcommand
 
 import (
 	"fmt"
+	"os/exec"
+	"strings"
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
@@ -133,6 +135,17 @@ func ExampleCommand_Slice_options() {
 	// ["ssh" "-q" "-o" "UserKnownHostsFile=/dev/null" "-o" "StrictHostKeyChecking=no" "-l" "root" "1.2.3.4" "-t" "-t" "-p" "22" "--" "/bin/sh" "-e" "-x" "-c" "\"\\\"echo\\\" \\\"hello world\\\"\""]
 }
 
+func ExampleCommand_Slice_multiplexing() {
+	command := Command{
+		Host:        "1.2.3.4",
+		ControlPath: "/root/.scw/ssh/%C",
+	}
+	fmt.Printf("%q\n", command.Slice())
+
+	// Output:
+	// ["ssh" "-o" "ControlMaster=auto" "-o" "ControlPath=/root/.scw/ssh/%C" "-o" "ControlPersist=60" "1.2.3.4" "-p" "22"]
+}
+
 func ExampleCommand_Slice_gateway() {
 	command := Command{
 		Host:    "1.2.3.4",
@@ -236,3 +249,21 @@ func TestCommand_applyDefaults(t *testing.T) {
 		})
 	})
 }
+
+func TestCommand_Slice_loginShell(t *testing.T) {
+	Convey("Testing the command seen by the remote login shell", t, func() {
+		command := Command{
+			Host:            "1.2.3.4",
+			Command:         []string{"echo '$(echo injected)' \"`echo injected`\" \"$0\""},
+			NoEscapeCommand: true,
+		}
+		slice := command.Slice()
+		words := slice[len(slice)-4:]
+		So(words[:3], ShouldResemble, []string{"/bin/sh", "-e", "-c"})
+
+		// ssh hands the words to the login shell joined by spaces
+		out, err := exec.Command("sh", "-c", strings.Join(words, " ")).Output()
+		So(err, ShouldBeNil)
+		So(string(out), ShouldEqual, "$(echo injected) injected /bin/sh\n")
+	})
+}
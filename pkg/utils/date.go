@@ -0,0 +1,44 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateUnits maps the day and week units, which time.ParseDuration does not know, to their duration
+var dateUnits = map[string]time.Duration{
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+}
+
+var dateDurationRegexp = regexp.MustCompile(`^([0-9]+)([dw])$`)
+
+// ParseDate parses an absolute date (RFC3339, 2006-01-02 or a unix timestamp) or a duration
+// relative to now such as "72h", "30d" or "2w", in which case the returned date is now minus the duration
+func ParseDate(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, nil
+	}
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date, nil
+	}
+	if timestamp, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(timestamp, 0).UTC(), nil
+	}
+	if matches := dateDurationRegexp.FindStringSubmatch(value); matches != nil {
+		count, _ := strconv.Atoi(matches[1])
+		return now.Add(-time.Duration(count) * dateUnits[matches[2]]), nil
+	}
+	if duration, err := time.ParseDuration(value); err == nil && duration >= 0 {
+		return now.Add(-duration), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q, expected a date (2006-01-02, RFC3339, unix timestamp) or a duration (72h, 30d, 2w)", value)
+}
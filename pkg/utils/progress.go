@@ -0,0 +1,98 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package utils
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// ProgressReader reports the bytes read through it on a terminal
+type ProgressReader struct {
+	reader io.Reader
+	output io.Writer
+
+	// Total is the expected number of bytes, the percentage is not displayed when <= 0
+	Total int64
+
+	// Delay is the time to wait before displaying anything, so quick transfers stay silent
+	Delay time.Duration
+
+	read    int64
+	start   time.Time
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+// NewProgressReader returns a ProgressReader reading reader and reporting on output
+func NewProgressReader(reader io.Reader, total int64, output io.Writer) *ProgressReader {
+	p := &ProgressReader{
+		reader:  reader,
+		output:  output,
+		Total:   total,
+		Delay:   time.Second,
+		start:   time.Now(),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go p.loop()
+	return p
+}
+
+// Read implements io.Reader
+func (p *ProgressReader) Read(buf []byte) (int, error) {
+	n, err := p.reader.Read(buf)
+	atomic.AddInt64(&p.read, int64(n))
+	return n, err
+}
+
+// Done stops the report and prints the final line if anything was displayed
+func (p *ProgressReader) Done() {
+	close(p.done)
+	<-p.stopped
+}
+
+// String returns the current state of the transfer
+func (p *ProgressReader) String() string {
+	read := atomic.LoadInt64(&p.read)
+	line := FormatSize(uint64(read))
+	if p.Total > 0 {
+		percent := read * 100 / p.Total
+		// the tar stream is a bit larger than the files it contains
+		if percent > 100 {
+			percent = 100
+		}
+		line = fmt.Sprintf("%s / %s (%d%%)", line, FormatSize(uint64(p.Total)), percent)
+	}
+	if elapsed := time.Since(p.start).Seconds(); elapsed >= 1 {
+		line = fmt.Sprintf("%s %s/s", line, FormatSize(uint64(float64(read)/elapsed)))
+	}
+	return line
+}
+
+func (p *ProgressReader) loop() {
+	defer close(p.stopped)
+
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	displayed := false
+	for {
+		select {
+		case <-p.done:
+			if displayed {
+				fmt.Fprintf(p.output, "\r\033[K%s\n", p)
+			}
+			return
+		case <-ticker.C:
+			if time.Since(p.start) < p.Delay {
+				continue
+			}
+			displayed = true
+			fmt.Fprintf(p.output, "\r\033[K%s", p)
+		}
+	}
+}
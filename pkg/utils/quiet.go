@@ -28,3 +28,8 @@ func LogQuiet(str string) {
 		fmt.Fprintf(os.Stderr, "%s", str)
 	}
 }
+
+// IsQuiet returns true if quiet mode is enabled
+func IsQuiet() bool {
+	return instanceQuiet.quiet
+}
@@ -0,0 +1,280 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/moby/moby/pkg/term"
+	"github.com/moul/gotty-client"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultDetachKeys is the default key sequence detaching from a serial console
+const DefaultDetachKeys = "ctrl-p,ctrl-q"
+
+// SerialOptions are the options of `AttachToSerial`
+type SerialOptions struct {
+	// DetachKeys is a key sequence (i.e: "ctrl-p,ctrl-q") detaching from the console, `~.` at the beginning of a line always detaches
+	DetachKeys string
+
+	// NoStdin only streams the console output
+	NoStdin bool
+}
+
+// EscapeDetector looks for detach sequences in the keys typed by the user
+type EscapeDetector struct {
+	keys      []byte
+	pending   []byte
+	lineStart bool
+	tilde     bool
+}
+
+// NewEscapeDetector returns an EscapeDetector for the detach keys and the `~.` sequence
+func NewEscapeDetector(keys []byte) *EscapeDetector {
+	return &EscapeDetector{
+		keys:      keys,
+		lineStart: true,
+	}
+}
+
+// Feed processes a typed key, it returns the keys which must be forwarded to the server
+// and whether a detach sequence was typed
+func (e *EscapeDetector) Feed(key byte) ([]byte, bool) {
+	if e.tilde {
+		e.tilde = false
+		switch key {
+		case '.':
+			return nil, true
+		case '~':
+			// `~~` sends a single `~`
+			e.lineStart = false
+			return []byte{'~'}, false
+		}
+		out, detach := e.feedKeys(key)
+		return append([]byte{'~'}, out...), detach
+	}
+	if key == '~' && e.lineStart && len(e.pending) == 0 {
+		e.tilde = true
+		return nil, false
+	}
+	return e.feedKeys(key)
+}
+
+func (e *EscapeDetector) feedKeys(key byte) ([]byte, bool) {
+	e.lineStart = key == '\r' || key == '\n'
+	if len(e.keys) == 0 {
+		return []byte{key}, false
+	}
+	if key == e.keys[len(e.pending)] {
+		e.pending = append(e.pending, key)
+		if len(e.pending) == len(e.keys) {
+			e.pending = nil
+			return nil, true
+		}
+		return nil, false
+	}
+	// not a detach sequence, release the held keys
+	out := e.pending
+	e.pending = nil
+	if key == e.keys[0] {
+		e.pending = []byte{key}
+		return out, false
+	}
+	return append(out, key), false
+}
+
+// SerialSession is a serial console attached to the local terminal
+type SerialSession struct {
+	client    *gottyclient.Client
+	options   SerialOptions
+	detach    []byte
+	quit      chan struct{}
+	quitOnce  sync.Once
+	writeLock sync.Mutex
+}
+
+// ExitLoop detaches from the console
+func (s *SerialSession) ExitLoop() {
+	s.quitOnce.Do(func() { close(s.quit) })
+}
+
+// Close closes the connection to the console
+func (s *SerialSession) Close() {
+	s.client.Close()
+}
+
+func (s *SerialSession) messageTypes() (output, input, ping, resize byte) {
+	if s.client.V2 {
+		return gottyclient.Output, gottyclient.Input, gottyclient.Ping, gottyclient.ResizeTerminal
+	}
+	return gottyclient.OutputV1, gottyclient.InputV1, gottyclient.PingV1, gottyclient.ResizeTerminalV1
+}
+
+func (s *SerialSession) write(data []byte) error {
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+	return s.client.Conn.WriteMessage(websocket.TextMessage, data)
+}
+
+func (s *SerialSession) sendSize(fd uintptr) {
+	_, _, _, resize := s.messageTypes()
+	ws, err := term.GetWinsize(fd)
+	if err != nil {
+		logrus.Debugf("cannot get terminal size: %v", err)
+		return
+	}
+	size, _ := json.Marshal(struct {
+		Columns uint16 `json:"columns"`
+		Rows    uint16 `json:"rows"`
+	}{ws.Width, ws.Height})
+	if err := s.write(append([]byte{resize}, size...)); err != nil {
+		logrus.Debugf("cannot send terminal size: %v", err)
+	}
+}
+
+// loop streams the console until the connection is closed, a detach sequence is typed or ExitLoop is called
+func (s *SerialSession) loop() {
+	output, input, ping, _ := s.messageTypes()
+
+	inFd, inIsTerm := term.GetFdInfo(os.Stdin)
+	if inIsTerm && !s.options.NoStdin {
+		state, err := term.SetRawTerminal(inFd)
+		if err != nil {
+			logrus.Warnf("cannot set raw terminal: %v", err)
+		} else {
+			// restored on every way out, including a SIGTERM
+			defer term.RestoreTerminal(inFd, state)
+			stop := notifyTermination(func() {
+				term.RestoreTerminal(inFd, state)
+				os.Exit(1)
+			})
+			defer stop()
+		}
+	}
+
+	// output
+	go func() {
+		defer s.ExitLoop()
+		for {
+			_, data, err := s.client.Conn.ReadMessage()
+			if err != nil {
+				logrus.Debugf("serial connection closed: %v", err)
+				return
+			}
+			if len(data) == 0 || data[0] != output {
+				continue
+			}
+			buf, err := base64.StdEncoding.DecodeString(string(data[1:]))
+			if err != nil {
+				continue
+			}
+			os.Stdout.Write(buf)
+		}
+	}()
+
+	// keep-alive
+	go func() {
+		ticker := time.NewTicker(30 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.quit:
+				return
+			case <-ticker.C:
+				if err := s.write([]byte{ping}); err != nil {
+					s.ExitLoop()
+					return
+				}
+			}
+		}
+	}()
+
+	if inIsTerm {
+		// window size, sent now and on every resize
+		s.sendSize(inFd)
+		stop := notifyResize(func() { s.sendSize(inFd) })
+		defer stop()
+	}
+
+	if !s.options.NoStdin {
+		go func() {
+			detector := NewEscapeDetector(s.detach)
+			buf := make([]byte, 128)
+			for {
+				n, err := os.Stdin.Read(buf)
+				if err == io.EOF {
+					// forwards EOF as Ctrl-D
+					s.write([]byte{input, 4})
+					return
+				}
+				if err != nil {
+					s.ExitLoop()
+					return
+				}
+				var keys []byte
+				for _, key := range buf[:n] {
+					out, detach := detector.Feed(key)
+					if detach {
+						s.ExitLoop()
+						return
+					}
+					keys = append(keys, out...)
+				}
+				if len(keys) > 0 {
+					if err := s.write(append([]byte{input}, keys...)); err != nil {
+						s.ExitLoop()
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	<-s.quit
+}
+
+// newSerialSession connects to a server serial console
+func newSerialSession(serverID, apiToken, url string, options SerialOptions) (*SerialSession, error) {
+	if options.DetachKeys == "" {
+		options.DetachKeys = DefaultDetachKeys
+	}
+	detach, err := term.ToBytes(options.DetachKeys)
+	if err != nil {
+		return nil, fmt.Errorf("invalid detach keys %q: %v", options.DetachKeys, err)
+	}
+
+	gottyURL := os.Getenv("SCW_GOTTY_URL")
+	if gottyURL == "" {
+		gottyURL = url
+	}
+	URL := fmt.Sprintf("%s?arg=%s&arg=%s", gottyURL, apiToken, serverID)
+
+	logrus.Debug("Connection to ", URL)
+	gottycli, err := gottyclient.NewClient(URL)
+	if err != nil {
+		return nil, err
+	}
+	if os.Getenv("SCW_TLSVERIFY") == "0" {
+		gottycli.SkipTLSVerify = true
+	}
+	gottycli.UseProxyFromEnv = true
+	if err = gottycli.Connect(); err != nil {
+		return nil, err
+	}
+	return &SerialSession{
+		client:  gottycli,
+		options: options,
+		detach:  detach,
+		quit:    make(chan struct{}),
+	}, nil
+}
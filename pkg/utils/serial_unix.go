@@ -0,0 +1,44 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+//go:build !windows
+// +build !windows
+
+package utils
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyResize calls fn on every terminal resize until the returned function is called
+func notifyResize(fn func()) func() {
+	return notifySignals(fn, syscall.SIGWINCH)
+}
+
+// notifyTermination calls fn when the process is asked to terminate until the returned function is called
+func notifyTermination(fn func()) func() {
+	return notifySignals(fn, syscall.SIGTERM, syscall.SIGHUP)
+}
+
+func notifySignals(fn func(), signals ...os.Signal) func() {
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, signals...)
+	go func() {
+		for {
+			select {
+			case <-ch:
+				fn()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
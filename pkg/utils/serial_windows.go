@@ -0,0 +1,15 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package utils
+
+// notifyResize is a no-op, Windows consoles don't notify resizes with a signal
+func notifyResize(fn func()) func() {
+	return func() {}
+}
+
+// notifyTermination is a no-op on Windows
+func notifyTermination(fn func()) func() {
+	return func() {}
+}
@@ -0,0 +1,79 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package utils
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// sizeUnits maps the accepted size units to their number of bytes, Scaleway
+// volumes are sized with decimal units so G and GB are both 10^9 bytes
+var sizeUnits = map[string]uint64{
+	"b":   1,
+	"k":   1000,
+	"kb":  1000,
+	"m":   1000 * 1000,
+	"mb":  1000 * 1000,
+	"g":   1000 * 1000 * 1000,
+	"gb":  1000 * 1000 * 1000,
+	"t":   1000 * 1000 * 1000 * 1000,
+	"tb":  1000 * 1000 * 1000 * 1000,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+}
+
+var sizeRegexp = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?)\s*([a-zA-Z]*)$`)
+
+// ParseSize parses a human readable size such as "50G", "250GB" or "1T" and returns a number of bytes
+func ParseSize(size string) (uint64, error) {
+	matches := sizeRegexp.FindStringSubmatch(strings.TrimSpace(size))
+	if matches == nil {
+		return 0, fmt.Errorf("invalid size %q, expected a number followed by a unit (e.g. 50G, 250GB, 1T)", size)
+	}
+	if matches[2] == "" {
+		return 0, fmt.Errorf("invalid size %q, missing unit (e.g. %sG)", size, matches[1])
+	}
+	unit, ok := sizeUnits[strings.ToLower(matches[2])]
+	if !ok {
+		return 0, fmt.Errorf("invalid size %q, unknown unit %q (expected B, K, M, G, T, KB, MB, GB, TB, KiB, MiB, GiB or TiB)", size, matches[2])
+	}
+	value, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q: %v", size, err)
+	}
+	bytes := value * float64(unit)
+	if bytes >= math.MaxUint64 {
+		return 0, fmt.Errorf("invalid size %q, too large", size)
+	}
+	return uint64(bytes), nil
+}
+
+// FormatSize returns a human readable representation of size, using the same decimal units as ParseSize
+func FormatSize(size uint64) string {
+	for _, unit := range []struct {
+		name  string
+		bytes uint64
+	}{
+		{"TB", sizeUnits["tb"]},
+		{"GB", sizeUnits["gb"]},
+		{"MB", sizeUnits["mb"]},
+		{"KB", sizeUnits["kb"]},
+	} {
+		if size < unit.bytes {
+			continue
+		}
+		if size%unit.bytes == 0 {
+			return fmt.Sprintf("%d%s", size/unit.bytes, unit.name)
+		}
+		return fmt.Sprintf("%.1f%s", float64(size)/float64(unit.bytes), unit.name)
+	}
+	return fmt.Sprintf("%dB", size)
+}
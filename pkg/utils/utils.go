@@ -9,23 +9,29 @@ package utils
 
 import (
 	"crypto/md5"
+	"crypto/rand"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
-	"net"
 	"os"
 	"os/exec"
 	"path"
 	"path/filepath"
 	"reflect"
 	"regexp"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/crypto/ssh"
 
+	"github.com/docker/go-units"
 	"github.com/mattn/go-isatty"
 	"github.com/moul/gotty-client"
+	"github.com/scaleway/scaleway-cli/pkg/config"
 	"github.com/scaleway/scaleway-cli/pkg/sshcommand"
 	"github.com/sirupsen/logrus"
 	log "github.com/sirupsen/logrus"
@@ -38,8 +44,44 @@ type SpawnRedirection struct {
 	Stderr io.Writer
 }
 
+// SSHTimeouts bounds an SSH command, a zero value disables the limit
+type SSHTimeouts struct {
+	// Timeout is the maximum duration of the command
+	Timeout time.Duration
+	// Idle is the maximum duration without any output from the command
+	Idle time.Duration
+}
+
+var (
+	// ErrSSHTimeout is returned when the SSH command is killed after SSHTimeouts.Timeout
+	ErrSSHTimeout = errors.New("command timed out")
+	// ErrSSHIdleTimeout is returned when the SSH command is killed after SSHTimeouts.Idle without output
+	ErrSSHIdleTimeout = errors.New("command produced no output for too long")
+)
+
 // SSHExec executes a command over SSH and redirects file-descriptors
 func SSHExec(publicIPAddress, privateIPAddress, user string, port int, command []string, checkConnection bool, gateway string, enableSSHKeyForwarding bool) error {
+	return SSHExecWithTimeouts(publicIPAddress, privateIPAddress, user, port, command, checkConnection, gateway, enableSSHKeyForwarding, SSHTimeouts{})
+}
+
+// SSHExecWithTimeouts is SSHExec killing the SSH session when one of the timeouts expires
+func SSHExecWithTimeouts(publicIPAddress, privateIPAddress, user string, port int, command []string, checkConnection bool, gateway string, enableSSHKeyForwarding bool, timeouts SSHTimeouts) error {
+	// a TTY merges the remote stderr into stdout, only allocate one when both are a terminal
+	allocateTTY := isatty.IsTerminal(os.Stdin.Fd()) && isatty.IsTerminal(os.Stdout.Fd())
+	return sshExec(publicIPAddress, privateIPAddress, user, port, command, checkConnection, gateway, enableSSHKeyForwarding, allocateTTY, os.Stdin, timeouts)
+}
+
+// SSHExecStdin executes a command over SSH without TTY, feeding its standard input from stdin
+func SSHExecStdin(publicIPAddress, privateIPAddress, user string, port int, command []string, checkConnection bool, gateway string, stdin io.Reader) error {
+	return SSHExecStdinWithTimeouts(publicIPAddress, privateIPAddress, user, port, command, checkConnection, gateway, stdin, SSHTimeouts{})
+}
+
+// SSHExecStdinWithTimeouts is SSHExecStdin killing the SSH session when one of the timeouts expires
+func SSHExecStdinWithTimeouts(publicIPAddress, privateIPAddress, user string, port int, command []string, checkConnection bool, gateway string, stdin io.Reader, timeouts SSHTimeouts) error {
+	return sshExec(publicIPAddress, privateIPAddress, user, port, command, checkConnection, gateway, false, false, stdin, timeouts)
+}
+
+func sshExec(publicIPAddress, privateIPAddress, user string, port int, command []string, checkConnection bool, gateway string, enableSSHKeyForwarding, allocateTTY bool, stdin io.Reader, timeouts SSHTimeouts) error {
 	gatewayUser := "root"
 	gatewayIPAddress := gateway
 	if strings.Contains(gateway, "@") {
@@ -69,15 +111,149 @@ func SSHExec(publicIPAddress, privateIPAddress, user string, port int, command [
 		}
 	}
 
-	sshCommand := NewSSHExecCmd(publicIPAddress, privateIPAddress, user, port, isatty.IsTerminal(os.Stdin.Fd()), command, gateway, enableSSHKeyForwarding)
+	var killRemote func()
+	if timeouts.Timeout > 0 || timeouts.Idle > 0 {
+		// killing ssh does not stop the remote command without TTY, nor when the connection is shared,
+		// so the command records its process group on the server and a second ssh kills it there
+		pgidFile, err := remotePGIDFile()
+		if err != nil {
+			return err
+		}
+		command = append([]string{remotePGIDCommand(pgidFile)}, command...)
+		killRemote = func() {
+			kill := NewSSHExecCmd(publicIPAddress, privateIPAddress, user, port, false, []string{remoteKillCommand(pgidFile)}, gateway, false)
+			log.Debugf("Executing: %s", kill)
+			if err := runWithTimeouts(exec.Command("ssh", kill.Slice()[1:]...), SSHTimeouts{Timeout: remoteKillTimeout}, nil); err != nil {
+				log.Warnf("Unable to kill the remote command: %v", err)
+			}
+		}
+	}
+
+	sshCommand := NewSSHExecCmd(publicIPAddress, privateIPAddress, user, port, allocateTTY, command, gateway, enableSSHKeyForwarding)
 
 	log.Debugf("Executing: %s", sshCommand)
 
 	spawn := exec.Command("ssh", sshCommand.Slice()[1:]...)
 	spawn.Stdout = os.Stdout
-	spawn.Stdin = os.Stdin
+	spawn.Stdin = stdin
 	spawn.Stderr = os.Stderr
-	return spawn.Run()
+	return runWithTimeouts(spawn, timeouts, killRemote)
+}
+
+// remoteKillTimeout bounds the ssh command killing the remote command once a timeout expired
+const remoteKillTimeout = 10 * time.Second
+
+// remotePGIDFile returns a unique path on the server to record the process group of a command
+func remotePGIDFile() (string, error) {
+	random := make([]byte, 8)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("/tmp/scw-exec-%x.pgid", random), nil
+}
+
+// remotePGIDCommand writes the process group of the remote shell to pgidFile, removed when the shell exits,
+// sshd starts each session in its own process group so it holds the command and its children
+func remotePGIDCommand(pgidFile string) string {
+	return fmt.Sprintf("ps -o pgid= -p $$ | tr -d ' ' > %s; trap 'rm -f %s' EXIT;", pgidFile, pgidFile)
+}
+
+// remoteKillCommand kills the process group recorded in pgidFile, through sudo when the command runs as root
+func remoteKillCommand(pgidFile string) string {
+	group := fmt.Sprintf("-$(cat %s)", pgidFile)
+	return fmt.Sprintf("kill -KILL %s 2>/dev/null || sudo -n kill -KILL %s; rm -f %s", group, group, pgidFile)
+}
+
+// activityWriter records the time of the last write to w
+type activityWriter struct {
+	w    io.Writer
+	last *int64
+}
+
+func (a activityWriter) Write(p []byte) (int, error) {
+	atomic.StoreInt64(a.last, time.Now().UnixNano())
+	if a.w == nil {
+		return len(p), nil
+	}
+	return a.w.Write(p)
+}
+
+// runWithTimeouts runs spawn and kills it when one of the timeouts expires, after calling killRemote when not nil
+func runWithTimeouts(spawn *exec.Cmd, timeouts SSHTimeouts, killRemote func()) error {
+	if timeouts.Timeout <= 0 && timeouts.Idle <= 0 {
+		return spawn.Run()
+	}
+	last := time.Now().UnixNano()
+	spawn.Stdout = activityWriter{w: spawn.Stdout, last: &last}
+	spawn.Stderr = activityWriter{w: spawn.Stderr, last: &last}
+	if err := spawn.Start(); err != nil {
+		return err
+	}
+	exited := make(chan error, 1)
+	go func() {
+		exited <- spawn.Wait()
+	}()
+
+	var deadline, check <-chan time.Time
+	if timeouts.Timeout > 0 {
+		timer := time.NewTimer(timeouts.Timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+	if timeouts.Idle > 0 {
+		interval := timeouts.Idle / 10
+		if interval > time.Second {
+			interval = time.Second
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		check = ticker.C
+	}
+	for {
+		select {
+		case err := <-exited:
+			return err
+		case <-deadline:
+			if killRemote != nil {
+				killRemote()
+			}
+			spawn.Process.Kill()
+			<-exited
+			return ErrSSHTimeout
+		case <-check:
+			if time.Since(time.Unix(0, atomic.LoadInt64(&last))) >= timeouts.Idle {
+				if killRemote != nil {
+					killRemote()
+				}
+				spawn.Process.Kill()
+				<-exited
+				return ErrSSHIdleTimeout
+			}
+		}
+	}
+}
+
+// sshControlPath returns the socket shared by ssh commands targeting the same host when SCW_SSH_MULTIPLEXING=1,
+// the sockets are kept in ~/.scw/ssh so other users can neither create nor reach them
+func sshControlPath() string {
+	if runtime.GOOS == "windows" || os.Getenv("SCW_SSH_MULTIPLEXING") != "1" {
+		return ""
+	}
+	home, err := config.GetHomeDir()
+	if err != nil {
+		log.Debugf("SSH multiplexing disabled: %v", err)
+		return ""
+	}
+	dir := filepath.Join(home, ".scw", "ssh")
+	if err = os.MkdirAll(dir, 0700); err == nil {
+		// MkdirAll keeps the mode of an existing directory
+		err = os.Chmod(dir, 0700)
+	}
+	if err != nil {
+		log.Debugf("SSH multiplexing disabled: %v", err)
+		return ""
+	}
+	return filepath.Join(dir, "%C")
 }
 
 // NewSSHExecCmd computes execve compatible arguments to run a command via ssh
@@ -94,6 +270,8 @@ func NewSSHExecCmd(publicIPAddress, privateIPAddress, user string, port int, all
 		NoEscapeCommand:        true,
 		Port:                   port,
 		EnableSSHKeyForwarding: enableSSHKeyForwarding,
+		ControlPath:            sshControlPath(),
+		ConnectTimeout:         SSHConnectTimeout,
 	}
 	if gatewayIPAddress != "" {
 		sshCommand.Host = privateIPAddress
@@ -104,6 +282,16 @@ func NewSSHExecCmd(publicIPAddress, privateIPAddress, user string, port int, all
 			Quiet:               quiet,
 			User:                user,
 			Port:                port,
+			ConnectTimeout:      SSHConnectTimeout,
+		}
+	}
+	if SSHProxy != "" {
+		if sshCommand.Gateway != nil {
+			// the %h and %p of a nested ProxyCommand would be the ones of the final host
+			gatewayHost := gatewayIPAddress[strings.LastIndex(gatewayIPAddress, "@")+1:]
+			sshCommand.Gateway.ProxyCommand = sshProxyCommand(gatewayHost, strconv.Itoa(port))
+		} else {
+			sshCommand.ProxyCommand = sshProxyCommand("%h", "%p")
 		}
 	}
 
@@ -132,6 +320,18 @@ func GeneratingAnSSHKey(cfg SpawnRedirection, path string, name string) (string,
 	return args[5], spawn.Run()
 }
 
+// SSHConnectTimeout is the time limit to open the SSH connections, passed to ssh as ConnectTimeout,
+// 0 keeps the default of ssh and checks the SSH ports during 2s
+var SSHConnectTimeout time.Duration
+
+// portCheckTimeout returns the time limit to open a TCP connection when checking a port
+func portCheckTimeout() time.Duration {
+	if SSHConnectTimeout > 0 {
+		return SSHConnectTimeout
+	}
+	return time.Duration(2000) * time.Millisecond
+}
+
 // WaitForTCPPortOpen calls IsTCPPortOpen in a loop
 func WaitForTCPPortOpen(dest string) error {
 	for {
@@ -145,13 +345,30 @@ func WaitForTCPPortOpen(dest string) error {
 
 // IsTCPPortOpen returns true if a TCP communication with "host:port" can be initialized
 func IsTCPPortOpen(dest string) bool {
-	conn, err := net.DialTimeout("tcp", dest, time.Duration(2000)*time.Millisecond)
+	conn, err := DialTCP(dest, portCheckTimeout())
 	if err == nil {
 		defer conn.Close()
 	}
 	return err == nil
 }
 
+// TCPHandshakeDuration returns the time needed to open a TCP connection with "host:port"
+func TCPHandshakeDuration(dest string) (time.Duration, error) {
+	start := time.Now()
+	conn, err := DialTCP(dest, portCheckTimeout())
+	if err != nil {
+		return 0, err
+	}
+	duration := time.Since(start)
+	conn.Close()
+	return duration, nil
+}
+
+// ShellQuote quotes a string to be used as a single word in a POSIX shell command
+func ShellQuote(word string) string {
+	return "'" + strings.Replace(word, "'", `'\''`, -1) + "'"
+}
+
 // TruncIf ensures the input string does not exceed max size if cond is met
 func TruncIf(str string, max int, cond bool) string {
 	if cond && len(str) > max {
@@ -168,6 +385,36 @@ func Wordify(str string) string {
 	return str
 }
 
+// Time formats accepted by FormatTime
+const (
+	TimeFormatRelative = "relative"
+	TimeFormatISO      = "iso"
+	TimeFormatUnix     = "unix"
+)
+
+// CheckTimeFormat returns an error if format is not a known time format
+func CheckTimeFormat(format string) error {
+	switch format {
+	case "", TimeFormatRelative, TimeFormatISO, TimeFormatUnix:
+		return nil
+	}
+	return fmt.Errorf("invalid time format %q, expected one of relative, iso or unix", format)
+}
+
+// FormatTime displays a date using format, independently of the locale, the zero time is displayed as n/a
+func FormatTime(t time.Time, format string) string {
+	if t.IsZero() {
+		return "n/a"
+	}
+	switch format {
+	case TimeFormatISO:
+		return t.UTC().Format(time.RFC3339)
+	case TimeFormatUnix:
+		return strconv.FormatInt(t.Unix(), 10)
+	}
+	return units.HumanDuration(time.Now().UTC().Sub(t))
+}
+
 // PathToTARPathparts returns the two parts of a unix path
 func PathToTARPathparts(fullPath string) (string, string) {
 	fullPath = strings.TrimRight(fullPath, "/")
@@ -192,7 +439,29 @@ func RemoveDuplicates(elements []string) []string {
 }
 
 // AttachToSerial tries to connect to server serial using 'gotty-client' and fallback with a help message
-func AttachToSerial(serverID, apiToken, url string) (*gottyclient.Client, chan bool, error) {
+func AttachToSerial(serverID, apiToken, url string, options SerialOptions) (*SerialSession, chan bool, error) {
+	session, err := newSerialSession(serverID, apiToken, url, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	done := make(chan bool)
+
+	if options.NoStdin {
+		fmt.Println("You are connected, type 'Ctrl+c' to quit.")
+	} else {
+		fmt.Printf("You are connected, type '~.' or '%s' to detach.\n", strings.Replace(session.options.DetachKeys, ",", " ", -1))
+	}
+	go func() {
+		session.loop()
+		session.Close()
+		done <- true
+	}()
+	return session, done, nil
+}
+
+// ReadSerial connects to a server serial console in read-only mode and streams its output line by line,
+// until the connection is closed or done is closed
+func ReadSerial(serverID, apiToken, url string, done <-chan struct{}) (*gottyclient.Client, <-chan string, error) {
 	gottyURL := os.Getenv("SCW_GOTTY_URL")
 	if gottyURL == "" {
 		gottyURL = url
@@ -204,25 +473,50 @@ func AttachToSerial(serverID, apiToken, url string) (*gottyclient.Client, chan b
 	if err != nil {
 		return nil, nil, err
 	}
-
 	if os.Getenv("SCW_TLSVERIFY") == "0" {
 		gottycli.SkipTLSVerify = true
 	}
-
 	gottycli.UseProxyFromEnv = true
-
 	if err = gottycli.Connect(); err != nil {
 		return nil, nil, err
 	}
-	done := make(chan bool)
 
-	fmt.Println("You are connected, type 'Ctrl+q' to quit.")
+	var output byte = gottyclient.OutputV1
+	if gottycli.V2 {
+		output = gottyclient.Output
+	}
+	lines := make(chan string)
 	go func() {
-		gottycli.Loop()
-		gottycli.Close()
-		done <- true
+		defer close(lines)
+		var pending string
+		for {
+			_, data, err := gottycli.Conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if len(data) == 0 || data[0] != output {
+				continue
+			}
+			buf, err := base64.StdEncoding.DecodeString(string(data[1:]))
+			if err != nil {
+				continue
+			}
+			pending += strings.Replace(string(buf), "\r", "", -1)
+			for {
+				i := strings.Index(pending, "\n")
+				if i < 0 {
+					break
+				}
+				select {
+				case lines <- pending[:i]:
+				case <-done:
+					return
+				}
+				pending = pending[i+1:]
+			}
+		}
 	}()
-	return gottycli, done, nil
+	return gottycli, lines, nil
 }
 
 func rfc4716hex(data []byte) string {
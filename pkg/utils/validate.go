@@ -0,0 +1,346 @@
+// Copyright (C) 2015 Scaleway. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE.md file.
+
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ValidationError is a field-level error found by ValidateJSON
+type ValidationError struct {
+	Line    int
+	Column  int
+	Field   string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message)
+	}
+	return fmt.Sprintf("line %d, column %d: %s: %s", e.Line, e.Column, e.Field, e.Message)
+}
+
+// ValidationErrors holds every error found by ValidateJSON
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// ValidateJSON checks that data can be decoded into schema, a pointer to the
+// expected struct, without unknown fields nor type mismatches. It reports
+// every problem with its line and column instead of stopping at the first one
+func ValidateJSON(data []byte, schema interface{}) error {
+	var document interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&document); err != nil {
+		if syntaxErr, ok := err.(*json.SyntaxError); ok {
+			line, column := offsetPosition(data, syntaxErr.Offset-1)
+			return ValidationErrors{{Line: line, Column: column, Message: syntaxErr.Error()}}
+		}
+		return err
+	}
+
+	positions := make(map[string]int64)
+	recordPositions(&jsonScanner{data: data}, "", positions)
+
+	v := &validator{data: data, positions: positions}
+	v.validate(document, reflect.TypeOf(schema), "")
+	if len(v.errors) > 0 {
+		return v.errors
+	}
+	return nil
+}
+
+type validator struct {
+	data      []byte
+	positions map[string]int64
+	errors    ValidationErrors
+}
+
+func (v *validator) fail(path, format string, args ...interface{}) {
+	line, column := offsetPosition(v.data, v.positions[path])
+	v.errors = append(v.errors, ValidationError{
+		Line:    line,
+		Column:  column,
+		Field:   path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) validate(value interface{}, typ reflect.Type, path string) {
+	if value == nil {
+		return
+	}
+	if typ.Implements(jsonUnmarshalerType) || reflect.PtrTo(typ).Implements(jsonUnmarshalerType) {
+		// custom decoders, i.e: dates, are trusted
+		return
+	}
+	switch typ.Kind() {
+	case reflect.Ptr:
+		v.validate(value, typ.Elem(), path)
+	case reflect.Interface:
+		return
+	case reflect.Struct:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			v.fail(path, "expected an object, got %s", jsonKind(value))
+			return
+		}
+		fields := jsonFields(typ)
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			field, ok := fields[key]
+			if !ok {
+				if suggestion := closestField(key, fields); suggestion != "" {
+					v.fail(joinPath(path, key), "unknown field, did you mean %q?", suggestion)
+				} else {
+					v.fail(joinPath(path, key), "unknown field")
+				}
+				continue
+			}
+			v.validate(object[key], field, joinPath(path, key))
+		}
+	case reflect.Map:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			v.fail(path, "expected an object, got %s", jsonKind(value))
+			return
+		}
+		for key, item := range object {
+			v.validate(item, typ.Elem(), joinPath(path, key))
+		}
+	case reflect.Slice, reflect.Array:
+		array, ok := value.([]interface{})
+		if !ok {
+			v.fail(path, "expected an array, got %s", jsonKind(value))
+			return
+		}
+		for i, item := range array {
+			v.validate(item, typ.Elem(), fmt.Sprintf("%s[%d]", path, i))
+		}
+	case reflect.String:
+		if _, ok := value.(string); !ok {
+			v.fail(path, "expected a string, got %s", jsonKind(value))
+		}
+	case reflect.Bool:
+		if _, ok := value.(bool); !ok {
+			v.fail(path, "expected a boolean, got %s", jsonKind(value))
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		number, ok := value.(json.Number)
+		if _, err := number.Int64(); !ok || err != nil {
+			v.fail(path, "expected an integer, got %s", jsonKind(value))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		number, ok := value.(json.Number)
+		if parsed, err := number.Int64(); !ok || err != nil || parsed < 0 {
+			v.fail(path, "expected a positive integer, got %s", jsonKind(value))
+		}
+	case reflect.Float32, reflect.Float64:
+		if _, ok := value.(json.Number); !ok {
+			v.fail(path, "expected a number, got %s", jsonKind(value))
+		}
+	}
+}
+
+// jsonFields returns the fields of a struct indexed by their JSON name, embedded structs included
+func jsonFields(typ reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for embeddedName, embeddedType := range jsonFields(embedded) {
+					fields[embeddedName] = embeddedType
+				}
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			// unexported
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// closestField returns the known field the closest to an unknown one, if it looks like a typo
+func closestField(key string, fields map[string]reflect.Type) string {
+	best, bestDistance := "", 3
+	for name := range fields {
+		if distance := levenshtein(strings.ToLower(key), strings.ToLower(name)); distance < bestDistance || (distance == bestDistance && name < best) {
+			best, bestDistance = name, distance
+		}
+	}
+	return best
+}
+
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(minInt(previous[j]+1, current[j-1]+1), previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func jsonKind(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "an object"
+	case []interface{}:
+		return "an array"
+	case string:
+		return "a string"
+	case bool:
+		return "a boolean"
+	case json.Number:
+		return "a number"
+	}
+	return "null"
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// jsonScanner walks a valid JSON document byte by byte to find the offset of its values
+type jsonScanner struct {
+	data   []byte
+	offset int64
+}
+
+func (s *jsonScanner) skip(separators string) {
+	for s.offset < int64(len(s.data)) && strings.IndexByte(separators, s.data[s.offset]) >= 0 {
+		s.offset++
+	}
+}
+
+func (s *jsonScanner) peek() byte {
+	if s.offset < int64(len(s.data)) {
+		return s.data[s.offset]
+	}
+	return 0
+}
+
+// skipString moves after the string starting at the offset and returns it decoded
+func (s *jsonScanner) skipString() string {
+	start := s.offset
+	for s.offset++; s.offset < int64(len(s.data)) && s.data[s.offset] != '"'; s.offset++ {
+		if s.data[s.offset] == '\\' {
+			s.offset++
+		}
+	}
+	s.offset++
+	var value string
+	if s.offset <= int64(len(s.data)) {
+		json.Unmarshal(s.data[start:s.offset], &value)
+	}
+	return value
+}
+
+// recordPositions walks the JSON values and stores the offset of every value, indexed by its path
+func recordPositions(s *jsonScanner, path string, positions map[string]int64) {
+	s.skip(" \t\r\n")
+	positions[path] = s.offset
+	switch s.peek() {
+	case '{':
+		s.offset++
+		for {
+			s.skip(" \t\r\n,")
+			if s.peek() != '"' {
+				break
+			}
+			keyOffset := s.offset
+			childPath := joinPath(path, s.skipString())
+			s.skip(" \t\r\n:")
+			recordPositions(s, childPath, positions)
+			// report errors on keys rather than on values
+			positions[childPath] = keyOffset
+		}
+		s.offset++
+	case '[':
+		s.offset++
+		for i := 0; ; i++ {
+			s.skip(" \t\r\n,")
+			if s.peek() == ']' || s.peek() == 0 {
+				break
+			}
+			recordPositions(s, fmt.Sprintf("%s[%d]", path, i), positions)
+		}
+		s.offset++
+	case '"':
+		s.skipString()
+	default:
+		for s.offset < int64(len(s.data)) && strings.IndexByte(" \t\r\n,]}", s.data[s.offset]) < 0 {
+			s.offset++
+		}
+	}
+}
+
+// offsetPosition converts a byte offset into a 1-based line and column
+func offsetPosition(data []byte, offset int64) (int, int) {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	line := 1 + bytes.Count(data[:offset], []byte("\n"))
+	column := int(offset) - bytes.LastIndexByte(data[:offset], '\n')
+	return line, column
+}